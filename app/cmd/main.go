@@ -2,6 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"os"
 	"os/signal"
 	"pr-service/internal/app"
@@ -13,6 +16,19 @@ import (
 	"go.uber.org/zap"
 )
 
+const usage = `usage: main [command]
+
+commands:
+  serve [--no-migrate]      run the HTTP server (default); migrates up first unless --no-migrate
+  migrate up                apply all pending migrations
+  migrate down [N]          roll back N migrations (default 1)
+  migrate to <version>      migrate up or down to the given version
+  migrate version           print the applied version
+  migrate force <version>   set the version without migrating and clear the dirty flag
+`
+
+var errUsage = errors.New("invalid arguments")
+
 func main() {
 	configFilePath := os.Getenv("CONFIG_PATH")
 	if configFilePath == "" {
@@ -26,21 +42,58 @@ func main() {
 	log := logger.NewLogger(cfg.App.LogLevel)
 	defer log.Sync()
 
-	err = database.Migrate(cfg.App.MirgationDir, cfg.DatabaseURL)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	args := os.Args[1:]
+	cmd := "serve"
+	if len(args) > 0 {
+		cmd, args = args[0], args[1:]
+	}
+
+	switch cmd {
+	case "serve":
+		err = serve(ctx, cfg, log, args)
+	case "migrate":
+		err = runMigrate(ctx, cfg, log, args)
+	default:
+		err = errUsage
+	}
+
+	if errors.Is(err, errUsage) {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
 	if err != nil {
-		log.Fatal("error on migrating database", zap.Error(err))
+		log.Error("command failed", zap.String("command", cmd), zap.Error(err))
+		log.Sync()
+		os.Exit(1)
 	}
+}
 
-	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
-	defer stop()
+func serve(ctx context.Context, cfg *config.Config, log *zap.Logger, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	noMigrate := fs.Bool("no-migrate", false, "do not apply migrations before serving")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 0 {
+		return errUsage
+	}
 
-	prApp := app.NewPRApp(cfg, log)
+	// sqlite applies its embedded migrations on open; memory has no schema
+	if !*noMigrate && (cfg.Storage == config.StoragePostgres || cfg.Storage == "") {
+		if err := database.Migrate(cfg.App.MirgationDir, cfg.DatabaseURL); err != nil {
+			return fmt.Errorf("error on migrating database: %w", err)
+		}
+	}
+
+	prApp, err := app.NewPRApp(cfg, log)
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
 
 	if err := prApp.Run(ctx); err != nil {
-		if ctx.Err() != nil {
-			log.Info("app stopped by context")
-		} else {
-			log.Error("app exited with error", zap.Error(err))
-		}
+		return fmt.Errorf("app exited with error: %w", err)
 	}
+
+	log.Info("app stopped")
+	return nil
 }
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"pr-service/internal/config"
+	"pr-service/internal/database"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+// runMigrate executes a "migrate" subcommand under the migration advisory lock.
+func runMigrate(ctx context.Context, cfg *config.Config, log *zap.Logger, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	if cfg.Storage != config.StoragePostgres && cfg.Storage != "" {
+		return fmt.Errorf("migrate supports only postgres storage, got %q", cfg.Storage)
+	}
+
+	op, err := parseMigrateOp(args[0], args[1:])
+	if err != nil {
+		return err
+	}
+
+	m, err := database.NewMigrator(ctx, cfg.App.MirgationDir, cfg.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := m.Close(); err != nil {
+			log.Warn("failed to close migrator", zap.Error(err))
+		}
+	}()
+
+	if err := op(m); err != nil {
+		return err
+	}
+
+	version, dirty, err := m.Version()
+	if err != nil {
+		return err
+	}
+
+	log.Info("migrations done",
+		zap.String("command", args[0]),
+		zap.Uint("version", version),
+		zap.Bool("dirty", dirty),
+	)
+
+	return nil
+}
+
+// parseMigrateOp validates the subcommand arguments before any connection is opened.
+func parseMigrateOp(name string, args []string) (func(*database.Migrator) error, error) {
+	switch name {
+	case "up":
+		if len(args) != 0 {
+			return nil, errUsage
+		}
+		return (*database.Migrator).Up, nil
+
+	case "version":
+		if len(args) != 0 {
+			return nil, errUsage
+		}
+		// The version is logged after every command.
+		return func(*database.Migrator) error { return nil }, nil
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			return nil, errUsage
+		}
+		if len(args) == 1 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n <= 0 {
+				return nil, errUsage
+			}
+			steps = n
+		}
+		return func(m *database.Migrator) error { return m.Down(steps) }, nil
+
+	case "to":
+		if len(args) != 1 {
+			return nil, errUsage
+		}
+		version, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return nil, errUsage
+		}
+		return func(m *database.Migrator) error { return m.To(uint(version)) }, nil
+
+	case "force":
+		if len(args) != 1 {
+			return nil, errUsage
+		}
+		version, err := strconv.Atoi(args[0])
+		if err != nil || version < -1 {
+			return nil, errUsage
+		}
+		return func(m *database.Migrator) error { return m.Force(version) }, nil
+
+	default:
+		return nil, fmt.Errorf("%w: unknown migrate command %q", errUsage, name)
+	}
+}
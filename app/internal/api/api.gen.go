@@ -14,12 +14,14 @@ import (
 
 // Defines values for ErrorResponseErrorCode.
 const (
-	NOCANDIDATE ErrorResponseErrorCode = "NO_CANDIDATE"
-	NOTASSIGNED ErrorResponseErrorCode = "NOT_ASSIGNED"
-	NOTFOUND    ErrorResponseErrorCode = "NOT_FOUND"
-	PREXISTS    ErrorResponseErrorCode = "PR_EXISTS"
-	PRMERGED    ErrorResponseErrorCode = "PR_MERGED"
-	TEAMEXISTS  ErrorResponseErrorCode = "TEAM_EXISTS"
+	NOCANDIDATE       ErrorResponseErrorCode = "NO_CANDIDATE"
+	NOTASSIGNED       ErrorResponseErrorCode = "NOT_ASSIGNED"
+	NOTFOUND          ErrorResponseErrorCode = "NOT_FOUND"
+	POLICYUNSATISFIED ErrorResponseErrorCode = "POLICY_UNSATISFIED"
+	PREXISTS          ErrorResponseErrorCode = "PR_EXISTS"
+	PRMERGED          ErrorResponseErrorCode = "PR_MERGED"
+	TEAMEXISTS        ErrorResponseErrorCode = "TEAM_EXISTS"
+	VERSIONCONFLICT   ErrorResponseErrorCode = "VERSION_CONFLICT"
 )
 
 // Defines values for PullRequestStatus.
@@ -34,6 +36,62 @@ const (
 	PullRequestShortStatusOPEN   PullRequestShortStatus = "OPEN"
 )
 
+// Defines values for ReviewSLAAction.
+const (
+	Escalate ReviewSLAAction = "escalate"
+	Reassign ReviewSLAAction = "reassign"
+)
+
+// Defines values for ExportQuery.
+const (
+	ExportQueryCsv    ExportQuery = "csv"
+	ExportQueryNdjson ExportQuery = "ndjson"
+)
+
+// Defines values for GetStatsPrsParamsExport.
+const (
+	GetStatsPrsParamsExportCsv    GetStatsPrsParamsExport = "csv"
+	GetStatsPrsParamsExportNdjson GetStatsPrsParamsExport = "ndjson"
+)
+
+// Availability defines model for Availability.
+type Availability struct {
+	AvailabilityId string `json:"availability_id"`
+
+	// EndsAt Конец отсутствия (не включительно)
+	EndsAt time.Time `json:"ends_at"`
+	Reason string    `json:"reason"`
+
+	// StartsAt Начало отсутствия (включительно)
+	StartsAt time.Time `json:"starts_at"`
+	UserId   string    `json:"user_id"`
+}
+
+// CodeOwnerRule defines model for CodeOwnerRule.
+type CodeOwnerRule struct {
+	// Owners Владельцы в том виде, в каком они записаны в файле
+	Owners  []string `json:"owners"`
+	Pattern string   `json:"pattern"`
+}
+
+// DigestSchedule defines model for DigestSchedule.
+type DigestSchedule struct {
+	// Time Местное время отправки дайджеста, HH:MM
+	Time string `json:"time"`
+
+	// Timezone Часовой пояс IANA
+	Timezone string `json:"timezone"`
+}
+
+// DurationPercentiles defines model for DurationPercentiles.
+type DurationPercentiles struct {
+	// Count Количество значений в выборке
+	Count      int     `json:"count"`
+	P50Seconds float64 `json:"p50_seconds"`
+	P75Seconds float64 `json:"p75_seconds"`
+	P90Seconds float64 `json:"p90_seconds"`
+}
+
 // ErrorResponse defines model for ErrorResponse.
 type ErrorResponse struct {
 	Error struct {
@@ -45,16 +103,65 @@ type ErrorResponse struct {
 // ErrorResponseErrorCode defines model for ErrorResponse.Error.Code.
 type ErrorResponseErrorCode string
 
+// ExternalUserLink defines model for ExternalUserLink.
+type ExternalUserLink struct {
+	// Login Логин на кодовом хостинге, без учёта регистра
+	Login  string `json:"login"`
+	UserId string `json:"user_id"`
+}
+
+// GitLabUserLink defines model for GitLabUserLink.
+type GitLabUserLink struct {
+	// GitlabUserId ID пользователя GitLab, в отличие от username не меняется
+	GitlabUserId int64  `json:"gitlab_user_id"`
+	UserId       string `json:"user_id"`
+}
+
+// PRCycleGroup defines model for PRCycleGroup.
+type PRCycleGroup struct {
+	Created int                  `json:"created"`
+	Merged  int                  `json:"merged"`
+	Open    int                  `json:"open"`
+	OpenAge *DurationPercentiles `json:"open_age,omitempty"`
+
+	// TeamName Команда автора
+	TeamName string `json:"team_name"`
+
+	// TimeToFirstAssignment От создания до первого назначения ревьювера. Это не время до первого ревью — самих ревью сервис не видит
+	TimeToFirstAssignment *DurationPercentiles `json:"time_to_first_assignment,omitempty"`
+	TimeToMerge           *DurationPercentiles `json:"time_to_merge,omitempty"`
+
+	// WeekStart Понедельник недели создания PR, 00:00 UTC
+	WeekStart time.Time `json:"week_start"`
+}
+
+// PRCycleRow defines model for PRCycleRow.
+type PRCycleRow struct {
+	AuthorId        string     `json:"author_id"`
+	CreatedAt       time.Time  `json:"created_at"`
+	FirstAssignedAt *time.Time `json:"first_assigned_at"`
+	MergedAt        *time.Time `json:"merged_at"`
+	PullRequestId   string     `json:"pull_request_id"`
+	PullRequestName string     `json:"pull_request_name"`
+
+	// Status OPEN или MERGED
+	Status   string `json:"status"`
+	TeamName string `json:"team_name"`
+}
+
 // PullRequest defines model for PullRequest.
 type PullRequest struct {
 	// AssignedReviewers user_id назначенных ревьюверов (0..2)
-	AssignedReviewers []string          `json:"assigned_reviewers"`
-	AuthorId          string            `json:"author_id"`
-	CreatedAt         *time.Time        `json:"createdAt"`
-	MergedAt          *time.Time        `json:"mergedAt"`
-	PullRequestId     string            `json:"pull_request_id"`
-	PullRequestName   string            `json:"pull_request_name"`
-	Status            PullRequestStatus `json:"status"`
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	AuthorId          string     `json:"author_id"`
+	CreatedAt         *time.Time `json:"createdAt"`
+	MergedAt          *time.Time `json:"mergedAt"`
+	PullRequestId     string     `json:"pull_request_id"`
+	PullRequestName   string     `json:"pull_request_name"`
+
+	// ReviewerPools Откуда взят каждый ревьювер, в порядке assigned_reviewers
+	ReviewerPools *[]ReviewerPool   `json:"reviewer_pools,omitempty"`
+	Status        PullRequestStatus `json:"status"`
 }
 
 // PullRequestStatus defines model for PullRequest.Status.
@@ -66,43 +173,250 @@ type PullRequestShort struct {
 	PullRequestId   string                 `json:"pull_request_id"`
 	PullRequestName string                 `json:"pull_request_name"`
 	Status          PullRequestShortStatus `json:"status"`
+
+	// Version Версия PR, её можно передать в If-Match изменяющих запросов
+	Version int64 `json:"version"`
 }
 
 // PullRequestShortStatus defines model for PullRequestShort.Status.
 type PullRequestShortStatus string
 
+// ReviewLoad defines model for ReviewLoad.
+type ReviewLoad struct {
+	// AssignedTotal Назначения за окно, включая позже переназначенные
+	AssignedTotal int `json:"assigned_total"`
+
+	// DeclineRate Доля отказов, declined / assigned_total; null без назначений за окно
+	DeclineRate *float64 `json:"decline_rate"`
+
+	// Declined Переназначения за окно, вызванные отказом ревьювера от ревью
+	Declined int `json:"declined"`
+
+	// MedianTimeToMergeSeconds Медиана времени от назначения до мерджа для назначений за окно
+	MedianTimeToMergeSeconds *float64 `json:"median_time_to_merge_seconds"`
+
+	// OpenAssigned Открытые PR, назначенные сейчас (без учёта окна)
+	OpenAssigned int `json:"open_assigned"`
+
+	// ReassignedAway Переназначения с ревьювера на другого за окно
+	ReassignedAway int `json:"reassigned_away"`
+}
+
+// ReviewPolicy defines model for ReviewPolicy.
+type ReviewPolicy struct {
+	// MinRole Хотя бы один ревьювер каждого PR команды должен иметь этот уровень или выше
+	MinRole string `json:"min_role"`
+}
+
+// ReviewSLA defines model for ReviewSLA.
+type ReviewSLA struct {
+	// Action Что делать с просроченным ревью открытого PR: reassign — переназначить на другого участника
+	// команды (эскалация, если заменить некем), escalate — оставить ревьювера и отправить эскалацию
+	Action ReviewSLAAction `json:"action"`
+
+	// TimeoutSeconds Время на ревью от назначения ревьювера
+	TimeoutSeconds int `json:"timeout_seconds"`
+}
+
+// ReviewSLAAction Что делать с просроченным ревью открытого PR: reassign — переназначить на другого участника
+// команды (эскалация, если заменить некем), escalate — оставить ревьювера и отправить эскалацию
+type ReviewSLAAction string
+
+// ReviewerOverride defines model for ReviewerOverride.
+type ReviewerOverride struct {
+	PullRequestId string `json:"pull_request_id"`
+
+	// ReviewerIds user_id ревьюверов без повторов
+	ReviewerIds []string `json:"reviewer_ids"`
+}
+
+// ReviewerPool defines model for ReviewerPool.
+type ReviewerPool struct {
+	// Pool team — команда автора, codeowners — владельцы изменённых файлов, fallback — резервная команда
+	Pool string `json:"pool"`
+
+	// TeamName Резервная команда, только для pool = fallback
+	TeamName *string `json:"team_name,omitempty"`
+	UserId   string  `json:"user_id"`
+}
+
 // Team defines model for Team.
 type Team struct {
-	Members  []TeamMember `json:"members"`
-	TeamName string       `json:"team_name"`
+	DigestSchedule *DigestSchedule `json:"digest_schedule,omitempty"`
+
+	// FallbackTeams Команды, из которых по порядку берутся ревьюверы, когда в команде никого не осталось
+	FallbackTeams *[]string    `json:"fallback_teams,omitempty"`
+	Members       []TeamMember `json:"members"`
+
+	// ParentTeam Родительская команда, например отдел; нет у команды верхнего уровня
+	ParentTeam   *string       `json:"parent_team,omitempty"`
+	ReviewPolicy *ReviewPolicy `json:"review_policy,omitempty"`
+	ReviewSla    *ReviewSLA    `json:"review_sla,omitempty"`
+
+	// SubTeams Подкоманды со своими участниками, только в /team/get с include_subteams
+	SubTeams *[]Team `json:"sub_teams,omitempty"`
+	TeamName string  `json:"team_name"`
+}
+
+// TeamCodeOwners defines model for TeamCodeOwners.
+type TeamCodeOwners struct {
+	Rules    []CodeOwnerRule `json:"rules"`
+	TeamName string          `json:"team_name"`
 }
 
 // TeamMember defines model for TeamMember.
 type TeamMember struct {
-	IsActive bool   `json:"is_active"`
-	UserId   string `json:"user_id"`
-	Username string `json:"username"`
+	// Email Адрес для дайджеста ревью
+	Email    *string `json:"email,omitempty"`
+	IsActive bool    `json:"is_active"`
+
+	// Role Уровень ревьювера по возрастанию — junior, senior, maintainer; по умолчанию senior
+	Role     *string `json:"role,omitempty"`
+	UserId   string  `json:"user_id"`
+	Username string  `json:"username"`
+}
+
+// TeamReviewStats defines model for TeamReviewStats.
+type TeamReviewStats struct {
+	// AssignedTotal Назначения за окно, включая позже переназначенные
+	AssignedTotal int `json:"assigned_total"`
+
+	// DeclineRate Доля отказов, declined / assigned_total; null без назначений за окно
+	DeclineRate *float64 `json:"decline_rate"`
+
+	// Declined Переназначения за окно, вызванные отказом ревьювера от ревью
+	Declined int `json:"declined"`
+
+	// MedianTimeToMergeSeconds Медиана времени от назначения до мерджа для назначений за окно
+	MedianTimeToMergeSeconds *float64 `json:"median_time_to_merge_seconds"`
+
+	// OpenAssigned Открытые PR, назначенные сейчас (без учёта окна)
+	OpenAssigned   int     `json:"open_assigned"`
+	ParentTeamName *string `json:"parent_team_name,omitempty"`
+
+	// ReassignedAway Переназначения с ревьювера на другого за окно
+	ReassignedAway int `json:"reassigned_away"`
+
+	// Subtree Нагрузка команды вместе со всеми подкомандами
+	Subtree  ReviewLoad `json:"subtree"`
+	TeamName string     `json:"team_name"`
 }
 
 // User defines model for User.
 type User struct {
-	IsActive bool   `json:"is_active"`
-	TeamName string `json:"team_name"`
-	UserId   string `json:"user_id"`
-	Username string `json:"username"`
+	IsActive bool `json:"is_active"`
+
+	// Role Уровень ревьювера — junior, senior или maintainer
+	Role     *string `json:"role,omitempty"`
+	TeamName string  `json:"team_name"`
+	UserId   string  `json:"user_id"`
+	Username string  `json:"username"`
 }
 
+// UserReviewStats defines model for UserReviewStats.
+type UserReviewStats struct {
+	// AssignedTotal Назначения за окно, включая позже переназначенные
+	AssignedTotal int `json:"assigned_total"`
+
+	// DeclineRate Доля отказов, declined / assigned_total; null без назначений за окно
+	DeclineRate *float64 `json:"decline_rate"`
+
+	// Declined Переназначения за окно, вызванные отказом ревьювера от ревью
+	Declined int `json:"declined"`
+
+	// MedianTimeToMergeSeconds Медиана времени от назначения до мерджа для назначений за окно
+	MedianTimeToMergeSeconds *float64 `json:"median_time_to_merge_seconds"`
+
+	// OpenAssigned Открытые PR, назначенные сейчас (без учёта окна)
+	OpenAssigned int `json:"open_assigned"`
+
+	// ReassignedAway Переназначения с ревьювера на другого за окно
+	ReassignedAway int    `json:"reassigned_away"`
+	TeamName       string `json:"team_name"`
+	UserId         string `json:"user_id"`
+	Username       string `json:"username"`
+}
+
+// WebhookResult defines model for WebhookResult.
+type WebhookResult struct {
+	// Result Что изменило событие: created — PR создан, merged — PR замерджен, unchanged — PR уже
+	// в нужном состоянии (повторная доставка), ignored — событие не требует действий
+	Result string `json:"result"`
+}
+
+// AvailabilityIdQuery defines model for AvailabilityIdQuery.
+type AvailabilityIdQuery = string
+
+// ExportQuery defines model for ExportQuery.
+type ExportQuery string
+
+// FromQuery defines model for FromQuery.
+type FromQuery = time.Time
+
+// IfMatchHeader defines model for IfMatchHeader.
+type IfMatchHeader = string
+
+// IncludeSubteamsQuery defines model for IncludeSubteamsQuery.
+type IncludeSubteamsQuery = bool
+
 // TeamNameQuery defines model for TeamNameQuery.
 type TeamNameQuery = string
 
+// TeamScopeQuery defines model for TeamScopeQuery.
+type TeamScopeQuery = string
+
+// ToQuery defines model for ToQuery.
+type ToQuery = time.Time
+
 // UserIdQuery defines model for UserIdQuery.
 type UserIdQuery = string
 
+// VersionConflict defines model for VersionConflict.
+type VersionConflict = ErrorResponse
+
+// PostIntegrationsGithubWebhookJSONBody defines parameters for PostIntegrationsGithubWebhook.
+type PostIntegrationsGithubWebhookJSONBody = map[string]interface{}
+
+// PostIntegrationsGithubWebhookParams defines parameters for PostIntegrationsGithubWebhook.
+type PostIntegrationsGithubWebhookParams struct {
+	XGitHubEvent     *string `json:"X-GitHub-Event,omitempty"`
+	XHubSignature256 *string `json:"X-Hub-Signature-256,omitempty"`
+}
+
+// PostIntegrationsGitlabWebhookJSONBody defines parameters for PostIntegrationsGitlabWebhook.
+type PostIntegrationsGitlabWebhookJSONBody = map[string]interface{}
+
+// PostIntegrationsGitlabWebhookParams defines parameters for PostIntegrationsGitlabWebhook.
+type PostIntegrationsGitlabWebhookParams struct {
+	XGitlabEvent *string `json:"X-Gitlab-Event,omitempty"`
+	XGitlabToken *string `json:"X-Gitlab-Token,omitempty"`
+}
+
 // PostPullRequestCreateJSONBody defines parameters for PostPullRequestCreate.
 type PostPullRequestCreateJSONBody struct {
-	AuthorId        string `json:"author_id"`
-	PullRequestId   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
+	AuthorId string `json:"author_id"`
+
+	// Files Изменённые пути от корня репозитория, по ним выбираются владельцы из CODEOWNERS
+	Files           *[]string `json:"files,omitempty"`
+	PullRequestId   string    `json:"pull_request_id"`
+	PullRequestName string    `json:"pull_request_name"`
+}
+
+// PostPullRequestDeclineJSONBody defines parameters for PostPullRequestDecline.
+type PostPullRequestDeclineJSONBody struct {
+	PullRequestId string `json:"pull_request_id"`
+
+	// Reason busy — занят, wrong_expertise — не та экспертиза, conflict — конфликт интересов
+	Reason string `json:"reason"`
+
+	// UserId Отказывающийся ревьювер
+	UserId string `json:"user_id"`
+}
+
+// PostPullRequestDeclineParams defines parameters for PostPullRequestDecline.
+type PostPullRequestDeclineParams struct {
+	// IfMatch ETag PR из предыдущего ответа; если PR с тех пор изменился, ответ — 412
+	IfMatch *IfMatchHeader `json:"If-Match,omitempty"`
 }
 
 // PostPullRequestMergeJSONBody defines parameters for PostPullRequestMerge.
@@ -110,16 +424,147 @@ type PostPullRequestMergeJSONBody struct {
 	PullRequestId string `json:"pull_request_id"`
 }
 
+// PostPullRequestMergeParams defines parameters for PostPullRequestMerge.
+type PostPullRequestMergeParams struct {
+	// IfMatch ETag PR из предыдущего ответа; если PR с тех пор изменился, ответ — 412
+	IfMatch *IfMatchHeader `json:"If-Match,omitempty"`
+}
+
 // PostPullRequestReassignJSONBody defines parameters for PostPullRequestReassign.
 type PostPullRequestReassignJSONBody struct {
-	OldUserId     string `json:"old_user_id"`
-	PullRequestId string `json:"pull_request_id"`
+	// ExcludeUserIds Кого не выбирать при автоматическом подборе; с new_user_id не используется
+	ExcludeUserIds *[]string `json:"exclude_user_ids,omitempty"`
+
+	// NewUserId Кого назначить вместо old_user_id; проверяется как при ручном назначении. Без него замена подбирается автоматически
+	NewUserId     *string `json:"new_user_id,omitempty"`
+	OldUserId     string  `json:"old_user_id"`
+	PullRequestId string  `json:"pull_request_id"`
+}
+
+// PostPullRequestReassignParams defines parameters for PostPullRequestReassign.
+type PostPullRequestReassignParams struct {
+	// IfMatch ETag PR из предыдущего ответа; если PR с тех пор изменился, ответ — 412
+	IfMatch *IfMatchHeader `json:"If-Match,omitempty"`
+}
+
+// PostPullRequestReviewersAddParams defines parameters for PostPullRequestReviewersAdd.
+type PostPullRequestReviewersAddParams struct {
+	// IfMatch ETag PR из предыдущего ответа; если PR с тех пор изменился, ответ — 412
+	IfMatch *IfMatchHeader `json:"If-Match,omitempty"`
+}
+
+// PostPullRequestReviewersRemoveParams defines parameters for PostPullRequestReviewersRemove.
+type PostPullRequestReviewersRemoveParams struct {
+	// IfMatch ETag PR из предыдущего ответа; если PR с тех пор изменился, ответ — 412
+	IfMatch *IfMatchHeader `json:"If-Match,omitempty"`
+}
+
+// PostPullRequestReviewersSetParams defines parameters for PostPullRequestReviewersSet.
+type PostPullRequestReviewersSetParams struct {
+	// IfMatch ETag PR из предыдущего ответа; если PR с тех пор изменился, ответ — 412
+	IfMatch *IfMatchHeader `json:"If-Match,omitempty"`
+}
+
+// GetStatsPrsParams defines parameters for GetStatsPrs.
+type GetStatsPrsParams struct {
+	// From Начало окна (включительно), по умолчанию to минус 30 дней
+	From *FromQuery `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Конец окна (не включительно), по умолчанию текущее время
+	To *ToQuery `form:"to,omitempty" json:"to,omitempty"`
+
+	// TeamName Ограничить статистику командой и всеми её подкомандами
+	TeamName *TeamScopeQuery `form:"team_name,omitempty" json:"team_name,omitempty"`
+
+	// Export Вместо агрегатов потоково выгрузить сырые строки PR в CSV или NDJSON
+	Export *GetStatsPrsParamsExport `form:"export,omitempty" json:"export,omitempty"`
+}
+
+// GetStatsPrsParamsExport defines parameters for GetStatsPrs.
+type GetStatsPrsParamsExport string
+
+// GetStatsReviewersParams defines parameters for GetStatsReviewers.
+type GetStatsReviewersParams struct {
+	// From Начало окна (включительно), по умолчанию to минус 30 дней
+	From *FromQuery `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Конец окна (не включительно), по умолчанию текущее время
+	To *ToQuery `form:"to,omitempty" json:"to,omitempty"`
+
+	// TeamName Ограничить статистику командой и всеми её подкомандами
+	TeamName *TeamScopeQuery `form:"team_name,omitempty" json:"team_name,omitempty"`
+}
+
+// GetTeamCodeOwnersParams defines parameters for GetTeamCodeOwners.
+type GetTeamCodeOwnersParams struct {
+	// TeamName Уникальное имя команды
+	TeamName TeamNameQuery `form:"team_name" json:"team_name"`
 }
 
 // GetTeamGetParams defines parameters for GetTeamGet.
 type GetTeamGetParams struct {
 	// TeamName Уникальное имя команды
 	TeamName TeamNameQuery `form:"team_name" json:"team_name"`
+
+	// IncludeSubteams Вернуть и всё поддерево команды в sub_teams
+	IncludeSubteams *IncludeSubteamsQuery `form:"include_subteams,omitempty" json:"include_subteams,omitempty"`
+}
+
+// PostTeamSetCodeOwnersJSONBody defines parameters for PostTeamSetCodeOwners.
+type PostTeamSetCodeOwnersJSONBody struct {
+	// Codeowners Содержимое файла в синтаксисе CODEOWNERS GitHub
+	Codeowners string `json:"codeowners"`
+	TeamName   string `json:"team_name"`
+}
+
+// PostTeamSetDigestScheduleJSONBody defines parameters for PostTeamSetDigestSchedule.
+type PostTeamSetDigestScheduleJSONBody struct {
+	DigestSchedule *DigestSchedule `json:"digest_schedule,omitempty"`
+	TeamName       string          `json:"team_name"`
+}
+
+// PostTeamSetFallbackPoolsJSONBody defines parameters for PostTeamSetFallbackPools.
+type PostTeamSetFallbackPoolsJSONBody struct {
+	FallbackTeams []string `json:"fallback_teams"`
+	TeamName      string   `json:"team_name"`
+}
+
+// PostTeamSetParentJSONBody defines parameters for PostTeamSetParent.
+type PostTeamSetParentJSONBody struct {
+	ParentTeam *string `json:"parent_team,omitempty"`
+	TeamName   string  `json:"team_name"`
+}
+
+// PostTeamSetReviewPolicyJSONBody defines parameters for PostTeamSetReviewPolicy.
+type PostTeamSetReviewPolicyJSONBody struct {
+	ReviewPolicy *ReviewPolicy `json:"review_policy,omitempty"`
+	TeamName     string        `json:"team_name"`
+}
+
+// PostTeamSetReviewSLAJSONBody defines parameters for PostTeamSetReviewSLA.
+type PostTeamSetReviewSLAJSONBody struct {
+	ReviewSla *ReviewSLA `json:"review_sla,omitempty"`
+	TeamName  string     `json:"team_name"`
+}
+
+// DeleteUsersAvailabilityParams defines parameters for DeleteUsersAvailability.
+type DeleteUsersAvailabilityParams struct {
+	// AvailabilityId Идентификатор окна отсутствия
+	AvailabilityId AvailabilityIdQuery `form:"availability_id" json:"availability_id"`
+}
+
+// GetUsersAvailabilityParams defines parameters for GetUsersAvailability.
+type GetUsersAvailabilityParams struct {
+	// UserId Идентификатор пользователя
+	UserId UserIdQuery `form:"user_id" json:"user_id"`
+}
+
+// PostUsersAvailabilityJSONBody defines parameters for PostUsersAvailability.
+type PostUsersAvailabilityJSONBody struct {
+	EndsAt   time.Time `json:"ends_at"`
+	Reason   *string   `json:"reason,omitempty"`
+	StartsAt time.Time `json:"starts_at"`
+	UserId   string    `json:"user_id"`
 }
 
 // GetUsersGetReviewParams defines parameters for GetUsersGetReview.
@@ -134,44 +579,162 @@ type PostUsersSetIsActiveJSONBody struct {
 	UserId   string `json:"user_id"`
 }
 
+// PostUsersSetRoleJSONBody defines parameters for PostUsersSetRole.
+type PostUsersSetRoleJSONBody struct {
+	// Role junior, senior или maintainer
+	Role   string `json:"role"`
+	UserId string `json:"user_id"`
+}
+
+// PostIntegrationsGithubUsersJSONRequestBody defines body for PostIntegrationsGithubUsers for application/json ContentType.
+type PostIntegrationsGithubUsersJSONRequestBody = ExternalUserLink
+
+// PostIntegrationsGithubWebhookJSONRequestBody defines body for PostIntegrationsGithubWebhook for application/json ContentType.
+type PostIntegrationsGithubWebhookJSONRequestBody = PostIntegrationsGithubWebhookJSONBody
+
+// PostIntegrationsGitlabUsersJSONRequestBody defines body for PostIntegrationsGitlabUsers for application/json ContentType.
+type PostIntegrationsGitlabUsersJSONRequestBody = GitLabUserLink
+
+// PostIntegrationsGitlabWebhookJSONRequestBody defines body for PostIntegrationsGitlabWebhook for application/json ContentType.
+type PostIntegrationsGitlabWebhookJSONRequestBody = PostIntegrationsGitlabWebhookJSONBody
+
 // PostPullRequestCreateJSONRequestBody defines body for PostPullRequestCreate for application/json ContentType.
 type PostPullRequestCreateJSONRequestBody PostPullRequestCreateJSONBody
 
+// PostPullRequestDeclineJSONRequestBody defines body for PostPullRequestDecline for application/json ContentType.
+type PostPullRequestDeclineJSONRequestBody PostPullRequestDeclineJSONBody
+
 // PostPullRequestMergeJSONRequestBody defines body for PostPullRequestMerge for application/json ContentType.
 type PostPullRequestMergeJSONRequestBody PostPullRequestMergeJSONBody
 
 // PostPullRequestReassignJSONRequestBody defines body for PostPullRequestReassign for application/json ContentType.
 type PostPullRequestReassignJSONRequestBody PostPullRequestReassignJSONBody
 
+// PostPullRequestReviewersAddJSONRequestBody defines body for PostPullRequestReviewersAdd for application/json ContentType.
+type PostPullRequestReviewersAddJSONRequestBody = ReviewerOverride
+
+// PostPullRequestReviewersRemoveJSONRequestBody defines body for PostPullRequestReviewersRemove for application/json ContentType.
+type PostPullRequestReviewersRemoveJSONRequestBody = ReviewerOverride
+
+// PostPullRequestReviewersSetJSONRequestBody defines body for PostPullRequestReviewersSet for application/json ContentType.
+type PostPullRequestReviewersSetJSONRequestBody = ReviewerOverride
+
 // PostTeamAddJSONRequestBody defines body for PostTeamAdd for application/json ContentType.
 type PostTeamAddJSONRequestBody = Team
 
+// PostTeamSetCodeOwnersJSONRequestBody defines body for PostTeamSetCodeOwners for application/json ContentType.
+type PostTeamSetCodeOwnersJSONRequestBody PostTeamSetCodeOwnersJSONBody
+
+// PostTeamSetDigestScheduleJSONRequestBody defines body for PostTeamSetDigestSchedule for application/json ContentType.
+type PostTeamSetDigestScheduleJSONRequestBody PostTeamSetDigestScheduleJSONBody
+
+// PostTeamSetFallbackPoolsJSONRequestBody defines body for PostTeamSetFallbackPools for application/json ContentType.
+type PostTeamSetFallbackPoolsJSONRequestBody PostTeamSetFallbackPoolsJSONBody
+
+// PostTeamSetParentJSONRequestBody defines body for PostTeamSetParent for application/json ContentType.
+type PostTeamSetParentJSONRequestBody PostTeamSetParentJSONBody
+
+// PostTeamSetReviewPolicyJSONRequestBody defines body for PostTeamSetReviewPolicy for application/json ContentType.
+type PostTeamSetReviewPolicyJSONRequestBody PostTeamSetReviewPolicyJSONBody
+
+// PostTeamSetReviewSLAJSONRequestBody defines body for PostTeamSetReviewSLA for application/json ContentType.
+type PostTeamSetReviewSLAJSONRequestBody PostTeamSetReviewSLAJSONBody
+
+// PostUsersAvailabilityJSONRequestBody defines body for PostUsersAvailability for application/json ContentType.
+type PostUsersAvailabilityJSONRequestBody PostUsersAvailabilityJSONBody
+
 // PostUsersSetIsActiveJSONRequestBody defines body for PostUsersSetIsActive for application/json ContentType.
 type PostUsersSetIsActiveJSONRequestBody PostUsersSetIsActiveJSONBody
 
+// PostUsersSetRoleJSONRequestBody defines body for PostUsersSetRole for application/json ContentType.
+type PostUsersSetRoleJSONRequestBody PostUsersSetRoleJSONBody
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// Связать логин GitHub с пользователем
+	// (POST /integrations/github/users)
+	PostIntegrationsGithubUsers(ctx echo.Context) error
+	// Принять вебхук pull_request из GitHub
+	// (POST /integrations/github/webhook)
+	PostIntegrationsGithubWebhook(ctx echo.Context, params PostIntegrationsGithubWebhookParams) error
+	// Связать пользователя GitLab с пользователем
+	// (POST /integrations/gitlab/users)
+	PostIntegrationsGitlabUsers(ctx echo.Context) error
+	// Принять вебхук merge request из GitLab
+	// (POST /integrations/gitlab/webhook)
+	PostIntegrationsGitlabWebhook(ctx echo.Context, params PostIntegrationsGitlabWebhookParams) error
 	// Создать PR и автоматически назначить до 2 ревьюверов из команды автора
 	// (POST /pullRequest/create)
 	PostPullRequestCreate(ctx echo.Context) error
+	// Отказаться от ревью PR с причиной
+	// (POST /pullRequest/decline)
+	PostPullRequestDecline(ctx echo.Context, params PostPullRequestDeclineParams) error
 	// Пометить PR как MERGED (идемпотентная операция)
 	// (POST /pullRequest/merge)
-	PostPullRequestMerge(ctx echo.Context) error
+	PostPullRequestMerge(ctx echo.Context, params PostPullRequestMergeParams) error
 	// Переназначить конкретного ревьювера на другого из его команды
 	// (POST /pullRequest/reassign)
-	PostPullRequestReassign(ctx echo.Context) error
+	PostPullRequestReassign(ctx echo.Context, params PostPullRequestReassignParams) error
+	// Добавить ревьюверов PR вручную
+	// (POST /pullRequest/reviewers/add)
+	PostPullRequestReviewersAdd(ctx echo.Context, params PostPullRequestReviewersAddParams) error
+	// Снять ревьюверов PR
+	// (POST /pullRequest/reviewers/remove)
+	PostPullRequestReviewersRemove(ctx echo.Context, params PostPullRequestReviewersRemoveParams) error
+	// Задать ревьюверов PR вручную
+	// (POST /pullRequest/reviewers/set)
+	PostPullRequestReviewersSet(ctx echo.Context, params PostPullRequestReviewersSetParams) error
+	// Время цикла PR по командам и неделям
+	// (GET /stats/prs)
+	GetStatsPrs(ctx echo.Context, params GetStatsPrsParams) error
+	// Нагрузка ревьюверов по пользователям и командам
+	// (GET /stats/reviewers)
+	GetStatsReviewers(ctx echo.Context, params GetStatsReviewersParams) error
 	// Создать команду с участниками (создаёт/обновляет пользователей)
 	// (POST /team/add)
 	PostTeamAdd(ctx echo.Context) error
+	// Получить правила CODEOWNERS команды
+	// (GET /team/codeOwners)
+	GetTeamCodeOwners(ctx echo.Context, params GetTeamCodeOwnersParams) error
 	// Получить команду с участниками
 	// (GET /team/get)
 	GetTeamGet(ctx echo.Context, params GetTeamGetParams) error
+	// Загрузить CODEOWNERS команды
+	// (POST /team/setCodeOwners)
+	PostTeamSetCodeOwners(ctx echo.Context) error
+	// Установить расписание дайджеста ревью команды
+	// (POST /team/setDigestSchedule)
+	PostTeamSetDigestSchedule(ctx echo.Context) error
+	// Установить резервные команды для подбора ревьюверов
+	// (POST /team/setFallbackPools)
+	PostTeamSetFallbackPools(ctx echo.Context) error
+	// Перенести команду под родительскую команду
+	// (POST /team/setParent)
+	PostTeamSetParent(ctx echo.Context) error
+	// Установить политику ревью команды
+	// (POST /team/setReviewPolicy)
+	PostTeamSetReviewPolicy(ctx echo.Context) error
+	// Установить SLA ревью команды
+	// (POST /team/setReviewSLA)
+	PostTeamSetReviewSLA(ctx echo.Context) error
+	// Удалить окно отсутствия
+	// (DELETE /users/availability)
+	DeleteUsersAvailability(ctx echo.Context, params DeleteUsersAvailabilityParams) error
+	// Получить окна отсутствия пользователя
+	// (GET /users/availability)
+	GetUsersAvailability(ctx echo.Context, params GetUsersAvailabilityParams) error
+	// Добавить окно отсутствия
+	// (POST /users/availability)
+	PostUsersAvailability(ctx echo.Context) error
 	// Получить PR'ы, где пользователь назначен ревьювером
 	// (GET /users/getReview)
 	GetUsersGetReview(ctx echo.Context, params GetUsersGetReviewParams) error
 	// Установить флаг активности пользователя
 	// (POST /users/setIsActive)
 	PostUsersSetIsActive(ctx echo.Context) error
+	// Установить уровень ревьювера
+	// (POST /users/setRole)
+	PostUsersSetRole(ctx echo.Context) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -179,6 +742,112 @@ type ServerInterfaceWrapper struct {
 	Handler ServerInterface
 }
 
+// PostIntegrationsGithubUsers converts echo context to params.
+func (w *ServerInterfaceWrapper) PostIntegrationsGithubUsers(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostIntegrationsGithubUsers(ctx)
+	return err
+}
+
+// PostIntegrationsGithubWebhook converts echo context to params.
+func (w *ServerInterfaceWrapper) PostIntegrationsGithubWebhook(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostIntegrationsGithubWebhookParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "X-GitHub-Event" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-GitHub-Event")]; found {
+		var XGitHubEvent string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for X-GitHub-Event, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-GitHub-Event", valueList[0], &XGitHubEvent, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter X-GitHub-Event: %s", err))
+		}
+
+		params.XGitHubEvent = &XGitHubEvent
+	}
+	// ------------- Optional header parameter "X-Hub-Signature-256" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Hub-Signature-256")]; found {
+		var XHubSignature256 string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for X-Hub-Signature-256, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-Hub-Signature-256", valueList[0], &XHubSignature256, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter X-Hub-Signature-256: %s", err))
+		}
+
+		params.XHubSignature256 = &XHubSignature256
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostIntegrationsGithubWebhook(ctx, params)
+	return err
+}
+
+// PostIntegrationsGitlabUsers converts echo context to params.
+func (w *ServerInterfaceWrapper) PostIntegrationsGitlabUsers(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostIntegrationsGitlabUsers(ctx)
+	return err
+}
+
+// PostIntegrationsGitlabWebhook converts echo context to params.
+func (w *ServerInterfaceWrapper) PostIntegrationsGitlabWebhook(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostIntegrationsGitlabWebhookParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "X-Gitlab-Event" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Gitlab-Event")]; found {
+		var XGitlabEvent string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for X-Gitlab-Event, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-Gitlab-Event", valueList[0], &XGitlabEvent, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter X-Gitlab-Event: %s", err))
+		}
+
+		params.XGitlabEvent = &XGitlabEvent
+	}
+	// ------------- Optional header parameter "X-Gitlab-Token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Gitlab-Token")]; found {
+		var XGitlabToken string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for X-Gitlab-Token, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-Gitlab-Token", valueList[0], &XGitlabToken, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter X-Gitlab-Token: %s", err))
+		}
+
+		params.XGitlabToken = &XGitlabToken
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostIntegrationsGitlabWebhook(ctx, params)
+	return err
+}
+
 // PostPullRequestCreate converts echo context to params.
 func (w *ServerInterfaceWrapper) PostPullRequestCreate(ctx echo.Context) error {
 	var err error
@@ -188,12 +857,61 @@ func (w *ServerInterfaceWrapper) PostPullRequestCreate(ctx echo.Context) error {
 	return err
 }
 
+// PostPullRequestDecline converts echo context to params.
+func (w *ServerInterfaceWrapper) PostPullRequestDecline(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostPullRequestDeclineParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatchHeader
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostPullRequestDecline(ctx, params)
+	return err
+}
+
 // PostPullRequestMerge converts echo context to params.
 func (w *ServerInterfaceWrapper) PostPullRequestMerge(ctx echo.Context) error {
 	var err error
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostPullRequestMergeParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatchHeader
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.PostPullRequestMerge(ctx)
+	err = w.Handler.PostPullRequestMerge(ctx, params)
 	return err
 }
 
@@ -201,8 +919,186 @@ func (w *ServerInterfaceWrapper) PostPullRequestMerge(ctx echo.Context) error {
 func (w *ServerInterfaceWrapper) PostPullRequestReassign(ctx echo.Context) error {
 	var err error
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostPullRequestReassignParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatchHeader
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostPullRequestReassign(ctx, params)
+	return err
+}
+
+// PostPullRequestReviewersAdd converts echo context to params.
+func (w *ServerInterfaceWrapper) PostPullRequestReviewersAdd(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostPullRequestReviewersAddParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatchHeader
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostPullRequestReviewersAdd(ctx, params)
+	return err
+}
+
+// PostPullRequestReviewersRemove converts echo context to params.
+func (w *ServerInterfaceWrapper) PostPullRequestReviewersRemove(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostPullRequestReviewersRemoveParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatchHeader
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostPullRequestReviewersRemove(ctx, params)
+	return err
+}
+
+// PostPullRequestReviewersSet converts echo context to params.
+func (w *ServerInterfaceWrapper) PostPullRequestReviewersSet(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostPullRequestReviewersSetParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatchHeader
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostPullRequestReviewersSet(ctx, params)
+	return err
+}
+
+// GetStatsPrs converts echo context to params.
+func (w *ServerInterfaceWrapper) GetStatsPrs(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStatsPrsParams
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", ctx.QueryParams(), &params.From)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter from: %s", err))
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", ctx.QueryParams(), &params.To)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter to: %s", err))
+	}
+
+	// ------------- Optional query parameter "team_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_name", ctx.QueryParams(), &params.TeamName)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter team_name: %s", err))
+	}
+
+	// ------------- Optional query parameter "export" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "export", ctx.QueryParams(), &params.Export)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter export: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetStatsPrs(ctx, params)
+	return err
+}
+
+// GetStatsReviewers converts echo context to params.
+func (w *ServerInterfaceWrapper) GetStatsReviewers(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStatsReviewersParams
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", ctx.QueryParams(), &params.From)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter from: %s", err))
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", ctx.QueryParams(), &params.To)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter to: %s", err))
+	}
+
+	// ------------- Optional query parameter "team_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_name", ctx.QueryParams(), &params.TeamName)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter team_name: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.PostPullRequestReassign(ctx)
+	err = w.Handler.GetStatsReviewers(ctx, params)
 	return err
 }
 
@@ -215,6 +1111,24 @@ func (w *ServerInterfaceWrapper) PostTeamAdd(ctx echo.Context) error {
 	return err
 }
 
+// GetTeamCodeOwners converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTeamCodeOwners(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTeamCodeOwnersParams
+	// ------------- Required query parameter "team_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "team_name", ctx.QueryParams(), &params.TeamName)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter team_name: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetTeamCodeOwners(ctx, params)
+	return err
+}
+
 // GetTeamGet converts echo context to params.
 func (w *ServerInterfaceWrapper) GetTeamGet(ctx echo.Context) error {
 	var err error
@@ -228,11 +1142,117 @@ func (w *ServerInterfaceWrapper) GetTeamGet(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter team_name: %s", err))
 	}
 
+	// ------------- Optional query parameter "include_subteams" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "include_subteams", ctx.QueryParams(), &params.IncludeSubteams)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter include_subteams: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.GetTeamGet(ctx, params)
 	return err
 }
 
+// PostTeamSetCodeOwners converts echo context to params.
+func (w *ServerInterfaceWrapper) PostTeamSetCodeOwners(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostTeamSetCodeOwners(ctx)
+	return err
+}
+
+// PostTeamSetDigestSchedule converts echo context to params.
+func (w *ServerInterfaceWrapper) PostTeamSetDigestSchedule(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostTeamSetDigestSchedule(ctx)
+	return err
+}
+
+// PostTeamSetFallbackPools converts echo context to params.
+func (w *ServerInterfaceWrapper) PostTeamSetFallbackPools(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostTeamSetFallbackPools(ctx)
+	return err
+}
+
+// PostTeamSetParent converts echo context to params.
+func (w *ServerInterfaceWrapper) PostTeamSetParent(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostTeamSetParent(ctx)
+	return err
+}
+
+// PostTeamSetReviewPolicy converts echo context to params.
+func (w *ServerInterfaceWrapper) PostTeamSetReviewPolicy(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostTeamSetReviewPolicy(ctx)
+	return err
+}
+
+// PostTeamSetReviewSLA converts echo context to params.
+func (w *ServerInterfaceWrapper) PostTeamSetReviewSLA(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostTeamSetReviewSLA(ctx)
+	return err
+}
+
+// DeleteUsersAvailability converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteUsersAvailability(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteUsersAvailabilityParams
+	// ------------- Required query parameter "availability_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "availability_id", ctx.QueryParams(), &params.AvailabilityId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter availability_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteUsersAvailability(ctx, params)
+	return err
+}
+
+// GetUsersAvailability converts echo context to params.
+func (w *ServerInterfaceWrapper) GetUsersAvailability(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetUsersAvailabilityParams
+	// ------------- Required query parameter "user_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "user_id", ctx.QueryParams(), &params.UserId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter user_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetUsersAvailability(ctx, params)
+	return err
+}
+
+// PostUsersAvailability converts echo context to params.
+func (w *ServerInterfaceWrapper) PostUsersAvailability(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostUsersAvailability(ctx)
+	return err
+}
+
 // GetUsersGetReview converts echo context to params.
 func (w *ServerInterfaceWrapper) GetUsersGetReview(ctx echo.Context) error {
 	var err error
@@ -260,6 +1280,15 @@ func (w *ServerInterfaceWrapper) PostUsersSetIsActive(ctx echo.Context) error {
 	return err
 }
 
+// PostUsersSetRole converts echo context to params.
+func (w *ServerInterfaceWrapper) PostUsersSetRole(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostUsersSetRole(ctx)
+	return err
+}
+
 // This is a simple interface which specifies echo.Route addition functions which
 // are present on both echo.Echo and echo.Group, since we want to allow using
 // either of them for path registration
@@ -288,12 +1317,33 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 		Handler: si,
 	}
 
+	router.POST(baseURL+"/integrations/github/users", wrapper.PostIntegrationsGithubUsers)
+	router.POST(baseURL+"/integrations/github/webhook", wrapper.PostIntegrationsGithubWebhook)
+	router.POST(baseURL+"/integrations/gitlab/users", wrapper.PostIntegrationsGitlabUsers)
+	router.POST(baseURL+"/integrations/gitlab/webhook", wrapper.PostIntegrationsGitlabWebhook)
 	router.POST(baseURL+"/pullRequest/create", wrapper.PostPullRequestCreate)
+	router.POST(baseURL+"/pullRequest/decline", wrapper.PostPullRequestDecline)
 	router.POST(baseURL+"/pullRequest/merge", wrapper.PostPullRequestMerge)
 	router.POST(baseURL+"/pullRequest/reassign", wrapper.PostPullRequestReassign)
+	router.POST(baseURL+"/pullRequest/reviewers/add", wrapper.PostPullRequestReviewersAdd)
+	router.POST(baseURL+"/pullRequest/reviewers/remove", wrapper.PostPullRequestReviewersRemove)
+	router.POST(baseURL+"/pullRequest/reviewers/set", wrapper.PostPullRequestReviewersSet)
+	router.GET(baseURL+"/stats/prs", wrapper.GetStatsPrs)
+	router.GET(baseURL+"/stats/reviewers", wrapper.GetStatsReviewers)
 	router.POST(baseURL+"/team/add", wrapper.PostTeamAdd)
+	router.GET(baseURL+"/team/codeOwners", wrapper.GetTeamCodeOwners)
 	router.GET(baseURL+"/team/get", wrapper.GetTeamGet)
+	router.POST(baseURL+"/team/setCodeOwners", wrapper.PostTeamSetCodeOwners)
+	router.POST(baseURL+"/team/setDigestSchedule", wrapper.PostTeamSetDigestSchedule)
+	router.POST(baseURL+"/team/setFallbackPools", wrapper.PostTeamSetFallbackPools)
+	router.POST(baseURL+"/team/setParent", wrapper.PostTeamSetParent)
+	router.POST(baseURL+"/team/setReviewPolicy", wrapper.PostTeamSetReviewPolicy)
+	router.POST(baseURL+"/team/setReviewSLA", wrapper.PostTeamSetReviewSLA)
+	router.DELETE(baseURL+"/users/availability", wrapper.DeleteUsersAvailability)
+	router.GET(baseURL+"/users/availability", wrapper.GetUsersAvailability)
+	router.POST(baseURL+"/users/availability", wrapper.PostUsersAvailability)
 	router.GET(baseURL+"/users/getReview", wrapper.GetUsersGetReview)
 	router.POST(baseURL+"/users/setIsActive", wrapper.PostUsersSetIsActive)
+	router.POST(baseURL+"/users/setRole", wrapper.PostUsersSetRole)
 
 }
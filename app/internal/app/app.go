@@ -2,94 +2,298 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
 
 	"pr-service/internal/api"
+	"pr-service/internal/clock"
 	"pr-service/internal/config"
-	"pr-service/internal/database"
+	"pr-service/internal/events"
 	"pr-service/internal/handler"
-	"pr-service/internal/repository"
+	"pr-service/internal/scheduler"
 	"pr-service/internal/service"
 
-	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
-	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 )
 
+// Worker is a background process that runs alongside the HTTP server.
+// Run must return once ctx is cancelled, after draining in-flight work.
+type Worker interface {
+	Name() string
+	Run(ctx context.Context) error
+}
+
 // PRApp represents the application with its dependencies.
 type PRApp struct {
 	cfg *config.Config
 
-	db *pgxpool.Pool
-	r  *echo.Echo
+	storage *storage
+	r       *echo.Echo
+	health  *handler.HealthHandler
+	workers []Worker
+	addr    atomic.Pointer[net.Addr]
 
 	log *zap.Logger
 }
 
-// New creates a new App instance, initializes database, services, handlers and routes.
-func NewPRApp(cfg *config.Config, log *zap.Logger) *PRApp {
-	db, err := database.Connect(context.Background(), cfg.DatabaseURL)
+// NewPRApp creates a new App instance, initializes storage, services, handlers and routes.
+func NewPRApp(cfg *config.Config, log *zap.Logger) (*PRApp, error) {
+	clk := clock.Real()
+
+	store, err := newStorage(cfg, clk, log)
 	if err != nil {
-		log.Fatal("failed to connect to database", zap.Error(err))
+		return nil, err
 	}
 
 	r := echo.New()
+	r.HideBanner = true
+	r.HidePort = true
 
-	retrier := newRepoRetrier(cfg.Retry, isRetryableFunc)
+	// services register post-commit work, e.g. pushing reviewers to code hosts
+	trManager := service.NewCommitHooks(store.trManager)
 
-	teamRepo := repository.NewTeamRepository(db, trmpgx.DefaultCtxGetter, retrier)
-	userRepo := repository.NewUserRepository(db, trmpgx.DefaultCtxGetter, retrier)
-	prRepo := repository.NewPRRepository(db, trmpgx.DefaultCtxGetter, retrier)
+	clients, err := newCodeHostClients(cfg.Integrations, log)
+	if err != nil {
+		store.close()
+		return nil, err
+	}
+
+	var (
+		reviewers    service.ReviewerPublisher
+		reviewerSync *service.ReviewerSync
+	)
+	if len(clients) > 0 {
+		reviewerSync = service.NewReviewerSync(store.integrationRepo, clients, newCodeHostRetrier(log), log)
+		reviewers = reviewerSync
+	}
 
 	prService := service.NewPRService(
-		teamRepo,
-		userRepo,
-		prRepo,
-		manager.Must(trmpgx.NewDefaultFactory(db)),
+		store.teamRepo,
+		store.userRepo,
+		store.prRepo,
+		reviewers,
+		trManager,
+		clk,
+		log,
+	)
+
+	statsService := service.NewStatsService(store.statsRepo, store.teamRepo, clk, log)
+
+	availabilityService := service.NewAvailabilityService(
+		store.availRepo,
+		store.userRepo,
+		trManager,
+		clk,
+		log,
+	)
+
+	slaService := service.NewSLAService(
+		store.slaRepo,
+		prService,
+		events.NewLogPublisher(log),
+		trManager,
+		clk,
 		log,
 	)
 
-	prHandler := handler.NewPRHandler(prService, log)
+	notifier, err := newNotifier(cfg.Notify, log)
+	if err != nil {
+		store.close()
+		return nil, err
+	}
+
+	digestService := service.NewDigestService(
+		store.digestRepo,
+		store.userRepo,
+		store.prRepo,
+		notifier,
+		trManager,
+		clk,
+		log,
+	)
 
-	api.RegisterHandlers(r, prHandler)
+	integrationService := service.NewIntegrationService(
+		store.integrationRepo,
+		store.userRepo,
+		prService,
+		trManager,
+		log,
+	)
+	if cfg.Integrations.GitHub.WebhookSecret == "" {
+		log.Warn("github webhook secret is not set, github webhooks are rejected")
+	}
+	if cfg.Integrations.GitLab.WebhookToken == "" {
+		log.Warn("gitlab webhook token is not set, gitlab webhooks are rejected")
+	}
+
+	server := handler.NewServer(
+		handler.NewPRHandler(prService, log),
+		handler.NewStatsHandler(statsService, log),
+		handler.NewAvailabilityHandler(availabilityService, log),
+		handler.NewIntegrationHandler(integrationService, handler.WebhookSecrets{
+			GitHub: cfg.Integrations.GitHub.WebhookSecret,
+			GitLab: cfg.Integrations.GitLab.WebhookToken,
+		}, log),
+	)
+
+	api.RegisterHandlers(r, server)
+
+	healthHandler := handler.NewHealthHandler(log, store.checks...)
+	healthHandler.Register(r)
 
 	r.Use(middleware.Recover())
 
+	var workers []Worker
+	if reviewerSync != nil {
+		workers = append(workers, reviewerSync)
+	}
+	if cfg.Jobs.AvailabilityRestore > 0 {
+		workers = append(workers, scheduler.NewJob(
+			"availability-restore",
+			cfg.Jobs.AvailabilityRestore,
+			cfg.App.ShutdownTimeout,
+			func(ctx context.Context) error {
+				if _, err := availabilityService.DeactivateStarted(ctx); err != nil {
+					return err
+				}
+				_, err := availabilityService.RestoreEnded(ctx)
+				return err
+			},
+			clk,
+			log,
+		))
+	}
+	if cfg.Jobs.ReviewSLA > 0 {
+		workers = append(workers, scheduler.NewJob(
+			"review-sla",
+			cfg.Jobs.ReviewSLA,
+			cfg.App.ShutdownTimeout,
+			func(ctx context.Context) error {
+				_, err := slaService.Enforce(ctx)
+				return err
+			},
+			clk,
+			log,
+		))
+	}
+	if cfg.Jobs.ReviewDigest > 0 {
+		workers = append(workers, scheduler.NewJob(
+			"review-digest",
+			cfg.Jobs.ReviewDigest,
+			cfg.App.ShutdownTimeout,
+			func(ctx context.Context) error {
+				_, err := digestService.SendDue(ctx)
+				return err
+			},
+			clk,
+			log,
+		))
+	}
+
 	return &PRApp{
-		cfg: cfg,
-		db:  db,
-		r:   r,
-		log: log,
+		cfg:     cfg,
+		storage: store,
+		r:       r,
+		health:  healthHandler,
+		workers: workers,
+		log:     log,
+	}, nil
+}
+
+// Addr returns the address the HTTP server listens on, or nil if it is not listening yet.
+func (a *PRApp) Addr() net.Addr {
+	if addr := a.addr.Load(); addr != nil {
+		return *addr
 	}
+	return nil
 }
 
-// Run starts the HTTP server and waits for context cancellation.
+// Run starts the HTTP server and background workers and blocks until ctx is
+// cancelled or one of them fails. Readiness is reported once the port is bound.
+// It then shuts everything down in order: readiness is failed, the server keeps
+// serving for the shutdown delay and drains in-flight requests, workers are
+// stopped and drained, and the storage is closed last.
+// Returns the first server or worker error, or nil on a clean shutdown.
 func (a *PRApp) Run(ctx context.Context) error {
-	go func() {
-		if err := a.r.Start(":" + a.cfg.App.Port); err != nil {
-			a.log.Fatal("failed to start server", zap.Error(err))
+	ln, err := net.Listen("tcp", ":"+a.cfg.App.Port)
+	if err != nil {
+		a.closeResources()
+		return fmt.Errorf("failed to start server: %w", err)
+	}
+	a.r.Listener = ln
+	addr := ln.Addr()
+	a.addr.Store(&addr)
+
+	g, gCtx := errgroup.WithContext(ctx)
+
+	// Workers must keep draining while the server shuts down,
+	// so they are stopped explicitly rather than by ctx.
+	workersCtx, stopWorkers := context.WithCancel(context.WithoutCancel(ctx))
+	defer stopWorkers()
+
+	g.Go(func() error {
+		a.log.Info("starting server", zap.String("port", a.cfg.App.Port))
+		if err := a.r.Start(":" + a.cfg.App.Port); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("failed to start server: %w", err)
 		}
-	}()
+		return nil
+	})
+
+	for _, w := range a.workers {
+		g.Go(func() error {
+			a.log.Info("starting worker", zap.String("worker", w.Name()))
+			if err := w.Run(workersCtx); err != nil {
+				return fmt.Errorf("worker %s: %w", w.Name(), err)
+			}
+			a.log.Info("worker stopped", zap.String("worker", w.Name()))
+			return nil
+		})
+	}
+
+	g.Go(func() error {
+		<-gCtx.Done()
+		defer stopWorkers()
+		return a.shutdownServer()
+	})
+
+	a.health.SetReady(true)
 
-	<-ctx.Done()
-	return a.Shutdown()
+	err = g.Wait()
+	a.closeResources()
+
+	return err
 }
 
-// Shutdown closes database connections and other resources.
-func (a *PRApp) Shutdown() error {
+// shutdownServer fails readiness and gracefully stops the HTTP server.
+func (a *PRApp) shutdownServer() error {
+	// Fail readiness first and keep serving until the orchestrator notices
+	// and stops routing new traffic here.
+	a.health.SetReady(false)
+	if a.cfg.App.ShutdownDelay > 0 {
+		a.log.Info("readiness failed, waiting before shutdown", zap.Duration("delay", a.cfg.App.ShutdownDelay))
+		time.Sleep(a.cfg.App.ShutdownDelay)
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), a.cfg.App.ShutdownTimeout)
 	defer cancel()
 
 	if err := a.r.Shutdown(ctx); err != nil {
-		a.log.Fatal("failed to shutdown server",
-			zap.Error(err),
-		)
-		return err
+		a.log.Error("failed to shutdown server", zap.Error(err))
+		return fmt.Errorf("failed to shutdown server: %w", err)
 	}
 
-	a.db.Close()
-
 	return nil
 }
+
+// closeResources releases resources once the server and workers are stopped.
+func (a *PRApp) closeResources() {
+	a.storage.close()
+	a.log.Info("resources closed")
+}
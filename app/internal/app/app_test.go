@@ -0,0 +1,129 @@
+//go:build integration
+// +build integration
+
+package app_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"pr-service/internal/app"
+	"pr-service/internal/config"
+	"pr-service/internal/database"
+
+	"github.com/stretchr/testify/require"
+	tc "github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"go.uber.org/zap"
+)
+
+const migrationDir = "../../../migrations"
+
+var dsn string
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+
+	pgContainer, err := postgres.Run(ctx,
+		"postgres:15.3-alpine",
+		postgres.WithDatabase("test_db"),
+		postgres.WithUsername("postgres"),
+		postgres.WithPassword("postgres"),
+		tc.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).WithStartupTimeout(10*time.Second)),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dsn, err = pgContainer.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := database.Migrate(migrationDir, dsn); err != nil {
+		log.Fatal(err)
+	}
+
+	code := m.Run()
+
+	_ = pgContainer.Terminate(ctx)
+
+	os.Exit(code)
+}
+
+func testConfig(port string) *config.Config {
+	return &config.Config{
+		App: config.App{
+			Port:            port,
+			MirgationDir:    migrationDir,
+			ShutdownTimeout: 5 * time.Second,
+		},
+		Retry: config.Retry{
+			MaxAttempts: 1,
+		},
+		DatabaseURL: dsn,
+	}
+}
+
+func TestPRApp_Run(t *testing.T) {
+	prApp, err := app.NewPRApp(testConfig("0"), zap.NewNop())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- prApp.Run(ctx) }()
+
+	require.Eventually(t, func() bool { return prApp.Addr() != nil }, 5*time.Second, 10*time.Millisecond)
+	baseURL := fmt.Sprintf("http://%s", prApp.Addr())
+
+	for _, path := range []string{"/healthz", "/readyz"} {
+		resp, err := http.Get(baseURL + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode, path)
+	}
+
+	cancel()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("app did not stop")
+	}
+
+	_, err = http.Get(baseURL + "/healthz")
+	require.Error(t, err)
+}
+
+func TestPRApp_Run_PortInUse(t *testing.T) {
+	l, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	port := fmt.Sprint(l.Addr().(*net.TCPAddr).Port)
+
+	prApp, err := app.NewPRApp(testConfig(port), zap.NewNop())
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() { done <- prApp.Run(t.Context()) }()
+
+	select {
+	case err := <-done:
+		require.ErrorContains(t, err, "failed to start server")
+	case <-time.After(10 * time.Second):
+		t.Fatal("app did not report the listen error")
+	}
+}
@@ -0,0 +1,481 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"pr-service/internal/api"
+	"pr-service/internal/app"
+	"pr-service/internal/config"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// startApp boots PRApp with the given storage config on a random port
+// and returns its base URL and a function that stops it.
+func startApp(t *testing.T, cfg config.Config) (string, func() error) {
+	t.Helper()
+
+	cfg.App.Port = "0"
+	cfg.App.ShutdownTimeout = 5 * time.Second
+	prApp, err := app.NewPRApp(&cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() { done <- prApp.Run(ctx) }()
+
+	require.Eventually(t, func() bool { return prApp.Addr() != nil }, 5*time.Second, 10*time.Millisecond)
+
+	stop := sync.OnceValue(func() error {
+		// a connection dialed but never used is not idle for the server
+		// and would hold up the shutdown
+		http.DefaultClient.CloseIdleConnections()
+		cancel()
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(10 * time.Second):
+			return fmt.Errorf("app did not stop")
+		}
+	})
+	t.Cleanup(func() { _ = stop() })
+
+	return fmt.Sprintf("http://%s", prApp.Addr()), stop
+}
+
+func postJSON(t *testing.T, url string, body any, out any) int {
+	t.Helper()
+
+	raw, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	resp, err := http.Post(url, "application/json", bytes.NewReader(raw))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	if out != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+	return resp.StatusCode
+}
+
+func TestPRApp_RunMemory(t *testing.T) {
+	baseURL, stop := startApp(t, config.Config{Storage: config.StorageMemory})
+
+	resp, err := http.Get(baseURL + "/readyz")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var teamResp struct {
+		Team api.Team `json:"team"`
+	}
+	code := postJSON(t, baseURL+"/team/add", api.Team{
+		TeamName: "backend",
+		Members: []api.TeamMember{
+			{UserId: uuid.NewString(), Username: "Alice", IsActive: true},
+			{UserId: uuid.NewString(), Username: "Bob", IsActive: true},
+			{UserId: uuid.NewString(), Username: "Carol", IsActive: true},
+		},
+	}, &teamResp)
+	require.Equal(t, http.StatusCreated, code)
+	require.Len(t, teamResp.Team.Members, 3)
+
+	var pr api.PullRequest
+	code = postJSON(t, baseURL+"/pullRequest/create", api.PostPullRequestCreateJSONBody{
+		PullRequestId:   uuid.NewString(),
+		PullRequestName: "Add search",
+		AuthorId:        teamResp.Team.Members[0].UserId,
+	}, &pr)
+	require.Equal(t, http.StatusCreated, code)
+	require.Equal(t, api.PullRequestStatusOPEN, pr.Status)
+
+	resp, err = http.Get(baseURL + "/stats/reviewers")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var stats struct {
+		Users []api.UserReviewStats `json:"users"`
+		Teams []api.TeamReviewStats `json:"teams"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&stats))
+	require.Len(t, stats.Users, 3)
+	require.Len(t, stats.Teams, 1)
+	require.Equal(t, 2, stats.Teams[0].OpenAssigned)
+
+	t.Run("pr stats", func(t *testing.T) {
+		resp, err := http.Get(baseURL + "/stats/prs")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var report struct {
+			Groups []api.PRCycleGroup `json:"groups"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+		require.Len(t, report.Groups, 1)
+		require.Equal(t, "backend", report.Groups[0].TeamName)
+		require.Equal(t, 1, report.Groups[0].Open)
+	})
+
+	t.Run("csv export", func(t *testing.T) {
+		resp, err := http.Get(baseURL + "/stats/prs?export=csv")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Contains(t, resp.Header.Get("Content-Type"), "text/csv")
+
+		records, err := csv.NewReader(resp.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		require.Equal(t, "pull_request_id", records[0][0])
+		require.Equal(t, pr.PullRequestId, records[1][0])
+	})
+
+	t.Run("ndjson export", func(t *testing.T) {
+		resp, err := http.Get(baseURL + "/stats/prs?export=ndjson")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var row api.PRCycleRow
+		dec := json.NewDecoder(resp.Body)
+		require.NoError(t, dec.Decode(&row))
+		require.Equal(t, pr.PullRequestId, row.PullRequestId)
+		require.Equal(t, "OPEN", row.Status)
+		require.False(t, dec.More())
+	})
+
+	t.Run("invalid window", func(t *testing.T) {
+		resp, err := http.Get(baseURL + "/stats/prs?export=csv&from=2025-10-02T00:00:00Z&to=2025-10-01T00:00:00Z")
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	require.NoError(t, stop())
+
+	_, err = http.Get(baseURL + "/healthz")
+	require.Error(t, err)
+}
+
+func TestPRApp_ShutdownDelay(t *testing.T) {
+	baseURL, stop := startApp(t, config.Config{
+		Storage: config.StorageMemory,
+		App:     config.App{ShutdownDelay: 500 * time.Millisecond},
+	})
+
+	// a spare keep-alive connection dialed during shutdown would hold it up
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- stop() }()
+
+	// the server keeps answering while readiness is failed
+	require.Eventually(t, func() bool {
+		resp, err := client.Get(baseURL + "/readyz")
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusServiceUnavailable
+	}, time.Second, 10*time.Millisecond)
+
+	resp, err := client.Get(baseURL + "/healthz")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.NoError(t, <-stopped)
+
+	_, err = client.Get(baseURL + "/healthz")
+	require.Error(t, err)
+}
+
+func TestPRApp_RunSQLite(t *testing.T) {
+	cfg := config.Config{
+		Storage:    config.StorageSQLite,
+		SQLitePath: filepath.Join(t.TempDir(), "pr.db"),
+	}
+
+	baseURL, stop := startApp(t, cfg)
+
+	code := postJSON(t, baseURL+"/team/add", api.Team{
+		TeamName: "backend",
+		Members: []api.TeamMember{
+			{UserId: uuid.NewString(), Username: "Alice", IsActive: true},
+		},
+	}, nil)
+	require.Equal(t, http.StatusCreated, code)
+	require.NoError(t, stop())
+
+	// data survives a restart on the same file
+	baseURL, _ = startApp(t, cfg)
+
+	resp, err := http.Get(baseURL + "/team/get?team_name=backend")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var team api.Team
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&team))
+	require.Len(t, team.Members, 1)
+}
+
+func TestPRApp_RunAvailability(t *testing.T) {
+	baseURL, _ := startApp(t, config.Config{
+		Storage: config.StorageMemory,
+		Jobs:    config.Jobs{AvailabilityRestore: 20 * time.Millisecond},
+	})
+
+	var teamResp struct {
+		Team api.Team `json:"team"`
+	}
+	code := postJSON(t, baseURL+"/team/add", api.Team{
+		TeamName: "backend",
+		Members: []api.TeamMember{
+			{UserId: uuid.NewString(), Username: "Alice", IsActive: true},
+			{UserId: uuid.NewString(), Username: "Bob", IsActive: true},
+			{UserId: uuid.NewString(), Username: "Carol", IsActive: true},
+		},
+	}, &teamResp)
+	require.Equal(t, http.StatusCreated, code)
+	alice, bob, carol := teamResp.Team.Members[0], teamResp.Team.Members[1], teamResp.Team.Members[2]
+
+	now := time.Now()
+	var added struct {
+		Availability api.Availability `json:"availability"`
+	}
+	code = postJSON(t, baseURL+"/users/availability", api.PostUsersAvailabilityJSONBody{
+		UserId:   bob.UserId,
+		StartsAt: now.Add(-time.Hour),
+		EndsAt:   now.Add(time.Hour),
+	}, &added)
+	require.Equal(t, http.StatusCreated, code)
+	require.Equal(t, bob.UserId, added.Availability.UserId)
+
+	code = postJSON(t, baseURL+"/users/availability", api.PostUsersAvailabilityJSONBody{
+		UserId:   bob.UserId,
+		StartsAt: now,
+		EndsAt:   now,
+	}, nil)
+	require.Equal(t, http.StatusBadRequest, code)
+
+	// Bob is out of office, so only Carol can review
+	var pr api.PullRequest
+	code = postJSON(t, baseURL+"/pullRequest/create", api.PostPullRequestCreateJSONBody{
+		PullRequestId:   uuid.NewString(),
+		PullRequestName: "Add search",
+		AuthorId:        alice.UserId,
+	}, &pr)
+	require.Equal(t, http.StatusCreated, code)
+	require.Equal(t, []string{carol.UserId}, pr.AssignedReviewers)
+
+	resp, err := http.Get(baseURL + "/users/availability?user_id=" + bob.UserId)
+	require.NoError(t, err)
+	var list struct {
+		Windows []api.Availability `json:"windows"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+	resp.Body.Close()
+	require.Len(t, list.Windows, 1)
+
+	deleteWindow := func(id string) int {
+		req, err := http.NewRequest(http.MethodDelete, baseURL+"/users/availability?availability_id="+id, nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	require.Equal(t, http.StatusNoContent, deleteWindow(added.Availability.AvailabilityId))
+	require.Equal(t, http.StatusNotFound, deleteWindow(added.Availability.AvailabilityId))
+
+	// polled from Eventually, so it must not fail the test itself
+	isActive := func(userID string) bool {
+		resp, err := http.Get(baseURL + "/team/get?team_name=backend")
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+
+		var team api.Team
+		if err := json.NewDecoder(resp.Body).Decode(&team); err != nil {
+			return false
+		}
+		for _, m := range team.Members {
+			if m.UserId == userID {
+				return m.IsActive
+			}
+		}
+		return false
+	}
+	require.True(t, isActive(bob.UserId), "deleting the window switches Bob back on")
+
+	// Carol is switched off while her short window lasts and back on when it ends
+	code = postJSON(t, baseURL+"/users/availability", api.PostUsersAvailabilityJSONBody{
+		UserId:   carol.UserId,
+		StartsAt: now.Add(-time.Hour),
+		EndsAt:   time.Now().Add(time.Second),
+	}, nil)
+	require.Equal(t, http.StatusCreated, code)
+
+	require.Eventually(t, func() bool { return !isActive(carol.UserId) }, time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool { return isActive(carol.UserId) }, 5*time.Second, 20*time.Millisecond)
+
+	// an admin switched Alice off, so her window ending leaves her off
+	code = postJSON(t, baseURL+"/users/setIsActive", api.PostUsersSetIsActiveJSONBody{
+		UserId:   alice.UserId,
+		IsActive: false,
+	}, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	code = postJSON(t, baseURL+"/users/availability", api.PostUsersAvailabilityJSONBody{
+		UserId:   alice.UserId,
+		StartsAt: now.Add(-time.Hour),
+		EndsAt:   time.Now().Add(100 * time.Millisecond),
+	}, nil)
+	require.Equal(t, http.StatusCreated, code)
+
+	require.Never(t, func() bool { return isActive(alice.UserId) }, 300*time.Millisecond, 20*time.Millisecond)
+}
+
+func TestPRApp_RunReviewSLA(t *testing.T) {
+	baseURL, _ := startApp(t, config.Config{
+		Storage: config.StorageMemory,
+		Jobs:    config.Jobs{ReviewSLA: 20 * time.Millisecond},
+	})
+
+	var teamResp struct {
+		Team api.Team `json:"team"`
+	}
+	code := postJSON(t, baseURL+"/team/add", api.Team{
+		TeamName: "backend",
+		Members: []api.TeamMember{
+			{UserId: uuid.NewString(), Username: "Alice", IsActive: true},
+			{UserId: uuid.NewString(), Username: "Bob", IsActive: true},
+		},
+	}, &teamResp)
+	require.Equal(t, http.StatusCreated, code)
+	alice, bob := teamResp.Team.Members[0], teamResp.Team.Members[1]
+
+	code = postJSON(t, baseURL+"/team/setReviewSLA", api.PostTeamSetReviewSLAJSONBody{
+		TeamName:  "backend",
+		ReviewSla: &api.ReviewSLA{TimeoutSeconds: 0, Action: api.Reassign},
+	}, nil)
+	require.Equal(t, http.StatusBadRequest, code)
+
+	code = postJSON(t, baseURL+"/team/setReviewSLA", api.PostTeamSetReviewSLAJSONBody{
+		TeamName: "frontend",
+	}, nil)
+	require.Equal(t, http.StatusNotFound, code)
+
+	var slaResp struct {
+		Team api.Team `json:"team"`
+	}
+	code = postJSON(t, baseURL+"/team/setReviewSLA", api.PostTeamSetReviewSLAJSONBody{
+		TeamName:  "backend",
+		ReviewSla: &api.ReviewSLA{TimeoutSeconds: 1, Action: api.Reassign},
+	}, &slaResp)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, &api.ReviewSLA{TimeoutSeconds: 1, Action: api.Reassign}, slaResp.Team.ReviewSla)
+	require.Len(t, slaResp.Team.Members, 2)
+
+	// Bob is the only possible reviewer of the PR
+	var pr api.PullRequest
+	code = postJSON(t, baseURL+"/pullRequest/create", api.PostPullRequestCreateJSONBody{
+		PullRequestId:   uuid.NewString(),
+		PullRequestName: "Add search",
+		AuthorId:        alice.UserId,
+	}, &pr)
+	require.Equal(t, http.StatusCreated, code)
+	require.Equal(t, []string{bob.UserId}, pr.AssignedReviewers)
+
+	// Bob misses the SLA and nobody can take over: the review stays with him
+	time.Sleep(1100 * time.Millisecond)
+
+	resp, err := http.Get(baseURL + "/users/getReview?user_id=" + bob.UserId)
+	require.NoError(t, err)
+	var reviews struct {
+		PullRequests []api.PullRequestShort `json:"pull_requests"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&reviews))
+	resp.Body.Close()
+	require.Len(t, reviews.PullRequests, 1)
+}
+
+func TestPRApp_RunReviewDigest(t *testing.T) {
+	digests := filepath.Join(t.TempDir(), "digests.log")
+	baseURL, _ := startApp(t, config.Config{
+		Storage: config.StorageMemory,
+		Jobs:    config.Jobs{ReviewDigest: 20 * time.Millisecond},
+		Notify:  config.Notify{Notifier: config.NotifierFile, File: digests},
+	})
+
+	bobEmail := "bob@example.com"
+	var teamResp struct {
+		Team api.Team `json:"team"`
+	}
+	code := postJSON(t, baseURL+"/team/add", api.Team{
+		TeamName: "backend",
+		Members: []api.TeamMember{
+			{UserId: uuid.NewString(), Username: "Alice", IsActive: true},
+			{UserId: uuid.NewString(), Username: "Bob", IsActive: true, Email: &bobEmail},
+		},
+	}, &teamResp)
+	require.Equal(t, http.StatusCreated, code)
+	alice := teamResp.Team.Members[0]
+	require.Equal(t, &bobEmail, teamResp.Team.Members[1].Email)
+
+	code = postJSON(t, baseURL+"/pullRequest/create", api.PostPullRequestCreateJSONBody{
+		PullRequestId:   uuid.NewString(),
+		PullRequestName: "Add search",
+		AuthorId:        alice.UserId,
+	}, nil)
+	require.Equal(t, http.StatusCreated, code)
+
+	code = postJSON(t, baseURL+"/team/setDigestSchedule", api.PostTeamSetDigestScheduleJSONBody{
+		TeamName:       "backend",
+		DigestSchedule: &api.DigestSchedule{Time: "09:00", Timezone: "Mars/Olympus"},
+	}, nil)
+	require.Equal(t, http.StatusBadRequest, code)
+
+	code = postJSON(t, baseURL+"/team/setDigestSchedule", api.PostTeamSetDigestScheduleJSONBody{
+		TeamName: "frontend",
+	}, nil)
+	require.Equal(t, http.StatusNotFound, code)
+
+	// midnight has always passed, so the digest is due right away
+	code = postJSON(t, baseURL+"/team/setDigestSchedule", api.PostTeamSetDigestScheduleJSONBody{
+		TeamName:       "backend",
+		DigestSchedule: &api.DigestSchedule{Time: "00:00", Timezone: "UTC"},
+	}, &teamResp)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, &api.DigestSchedule{Time: "00:00", Timezone: "UTC"}, teamResp.Team.DigestSchedule)
+
+	require.Eventually(t, func() bool {
+		data, err := os.ReadFile(digests)
+		return err == nil && strings.Contains(string(data), "- Add search")
+	}, 5*time.Second, 20*time.Millisecond)
+
+	// the day is marked sent, later runs add nothing
+	time.Sleep(100 * time.Millisecond)
+	data, err := os.ReadFile(digests)
+	require.NoError(t, err)
+	require.Equal(t, 1, strings.Count(string(data), "Subject:"))
+	require.Contains(t, string(data), "To: Bob <bob@example.com>")
+}
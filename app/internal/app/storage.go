@@ -0,0 +1,123 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"pr-service/internal/clock"
+	"pr-service/internal/config"
+	"pr-service/internal/database"
+	"pr-service/internal/handler"
+	"pr-service/internal/repository"
+	"pr-service/internal/repository/memory"
+	"pr-service/internal/repository/sqlite"
+	"pr-service/internal/service"
+
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	trmsql "github.com/avito-tech/go-transaction-manager/drivers/sql/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"go.uber.org/zap"
+)
+
+// storage bundles the repositories of the configured backend.
+type storage struct {
+	teamRepo        service.TeamRepository
+	userRepo        service.UserRepository
+	prRepo          service.PRRepository
+	statsRepo       service.StatsRepository
+	availRepo       service.AvailabilityRepository
+	slaRepo         service.SLARepository
+	digestRepo      service.DigestRepository
+	integrationRepo service.IntegrationRepository
+	trManager       service.TxManager
+
+	checks []handler.HealthCheck // readiness checks of the backend
+	close  func()                // releases backend resources
+}
+
+func newStorage(cfg *config.Config, clk clock.Clock, log *zap.Logger) (*storage, error) {
+	switch cfg.Storage {
+	case config.StoragePostgres, "":
+		return newPostgresStorage(cfg, clk, log)
+	case config.StorageSQLite:
+		return newSQLiteStorage(cfg, clk, log)
+	case config.StorageMemory:
+		return newMemoryStorage(clk), nil
+	default:
+		return nil, fmt.Errorf("unknown storage %q", cfg.Storage)
+	}
+}
+
+func newPostgresStorage(cfg *config.Config, clk clock.Clock, log *zap.Logger) (*storage, error) {
+	db, err := database.Connect(context.Background(), cfg.DatabaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	retrier := newRepoRetrier(cfg.Retry, isRetryableFunc, log)
+
+	return &storage{
+		teamRepo:        repository.NewTeamRepository(db, trmpgx.DefaultCtxGetter, retrier),
+		userRepo:        repository.NewUserRepository(db, trmpgx.DefaultCtxGetter, retrier),
+		prRepo:          repository.NewPRRepository(db, trmpgx.DefaultCtxGetter, retrier, clk),
+		statsRepo:       repository.NewStatsRepository(db, trmpgx.DefaultCtxGetter, retrier),
+		availRepo:       repository.NewAvailabilityRepository(db, trmpgx.DefaultCtxGetter, retrier),
+		slaRepo:         repository.NewSLARepository(db, trmpgx.DefaultCtxGetter, retrier),
+		digestRepo:      repository.NewDigestRepository(db, trmpgx.DefaultCtxGetter, retrier),
+		integrationRepo: repository.NewIntegrationRepository(db, trmpgx.DefaultCtxGetter, retrier),
+		trManager:       manager.Must(trmpgx.NewDefaultFactory(db)),
+		checks:          readinessChecks(cfg, db),
+		close:           db.Close,
+	}, nil
+}
+
+func newSQLiteStorage(cfg *config.Config, clk clock.Clock, log *zap.Logger) (*storage, error) {
+	db, err := sqlite.Open(context.Background(), cfg.SQLitePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	if err := sqlite.Migrate(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error on migrating sqlite database: %w", err)
+	}
+
+	retrier := newRepoRetrier(cfg.Retry, isRetryableFunc, log)
+
+	return &storage{
+		teamRepo:        sqlite.NewTeamRepository(db, trmsql.DefaultCtxGetter, retrier),
+		userRepo:        sqlite.NewUserRepository(db, trmsql.DefaultCtxGetter, retrier),
+		prRepo:          sqlite.NewPRRepository(db, trmsql.DefaultCtxGetter, retrier, clk),
+		statsRepo:       sqlite.NewStatsRepository(db, trmsql.DefaultCtxGetter, retrier),
+		availRepo:       sqlite.NewAvailabilityRepository(db, trmsql.DefaultCtxGetter, retrier),
+		slaRepo:         sqlite.NewSLARepository(db, trmsql.DefaultCtxGetter, retrier),
+		digestRepo:      sqlite.NewDigestRepository(db, trmsql.DefaultCtxGetter, retrier),
+		integrationRepo: sqlite.NewIntegrationRepository(db, trmsql.DefaultCtxGetter, retrier),
+		trManager:       manager.Must(trmsql.NewDefaultFactory(db)),
+		checks: []handler.HealthCheck{
+			{Name: "database", Check: db.PingContext},
+		},
+		close: func() {
+			if err := db.Close(); err != nil {
+				log.Warn("failed to close sqlite database", zap.Error(err))
+			}
+		},
+	}, nil
+}
+
+func newMemoryStorage(clk clock.Clock) *storage {
+	store := memory.NewStore()
+
+	return &storage{
+		teamRepo:        memory.NewTeamRepository(store),
+		userRepo:        memory.NewUserRepository(store),
+		prRepo:          memory.NewPRRepository(store, clk),
+		statsRepo:       memory.NewStatsRepository(store),
+		availRepo:       memory.NewAvailabilityRepository(store),
+		slaRepo:         memory.NewSLARepository(store),
+		digestRepo:      memory.NewDigestRepository(store),
+		integrationRepo: memory.NewIntegrationRepository(store),
+		trManager:       memory.NewTxManager(store),
+		close:           func() {},
+	}
+}
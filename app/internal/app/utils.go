@@ -1,15 +1,37 @@
 package app
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"pr-service/internal/codehost"
 	"pr-service/internal/config"
+	"pr-service/internal/database"
+	"pr-service/internal/handler"
+	"pr-service/internal/models"
+	"pr-service/internal/notify"
 	"pr-service/internal/repository"
 	"pr-service/internal/retry"
+	"pr-service/internal/service"
+	"time"
+
+	trmcontext "github.com/avito-tech/go-transaction-manager/trm/v2/context"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
 )
 
-func newRepoRetrier(cfg config.Retry, retryableFunc retry.IsRetryableFunc) retry.Retrier {
+func newRepoRetrier(cfg config.Retry, retryableFunc retry.IsRetryableFunc, log *zap.Logger) retry.Retrier {
 	opts := []retry.RetryOption{
 		retry.WithMaxAttempts(cfg.MaxAttempts),
+		retry.WithAttemptTimeout(cfg.AttemptTimeout),
+		retry.WithInTx(inTransaction),
+		retry.WithOnRetry(func(attempt int, err error, delay time.Duration) {
+			log.Warn("repository call failed, retrying",
+				zap.Error(err),
+				zap.Int("attempt", attempt+1),
+				zap.Duration("delay", delay),
+			)
+		}),
 	}
 
 	if retryableFunc != nil {
@@ -28,6 +50,76 @@ func newRepoRetrier(cfg config.Retry, retryableFunc retry.IsRetryableFunc) retry
 	return retry.New(opts...)
 }
 
+// newCodeHostRetrier retries code host calls for about half a minute, long enough
+// to ride out a rate limit window or a short outage.
+func newCodeHostRetrier(log *zap.Logger) retry.Retrier {
+	return retry.New(
+		retry.WithMaxAttempts(5),
+		retry.WithBackoff(retry.ExponentialBackoff{
+			Base:   2 * time.Second,
+			Factor: 2,
+			Max:    30 * time.Second,
+			Jitter: 0.2,
+		}),
+		retry.WithIsRetryableFunc(codehost.IsRetryable),
+		retry.WithOnRetry(func(attempt int, err error, delay time.Duration) {
+			log.Warn("code host call failed, retrying",
+				zap.Error(err),
+				zap.Int("attempt", attempt+1),
+				zap.Duration("delay", delay),
+			)
+		}),
+	)
+}
+
+// newCodeHostClients returns the clients of the code hosts reviewers are
+// pushed to; hosts without credentials are left out.
+func newCodeHostClients(cfg config.Integrations, log *zap.Logger) (map[string]service.CodeHostClient, error) {
+	clients := make(map[string]service.CodeHostClient)
+
+	if cfg.GitHub.Token != "" {
+		gh, err := codehost.NewGitHubClient(codehost.GitHubConfig{
+			BaseURL: cfg.GitHub.APIURL,
+			Token:   cfg.GitHub.Token,
+			Timeout: cfg.GitHub.Timeout,
+		})
+		if err != nil {
+			return nil, err
+		}
+		clients[models.ProviderGitHub] = gh
+	} else {
+		log.Info("github token is not set, reviewers are not pushed to github")
+	}
+
+	return clients, nil
+}
+
+func newNotifier(cfg config.Notify, log *zap.Logger) (service.Notifier, error) {
+	switch cfg.Notifier {
+	case config.NotifierLog, "":
+		return notify.NewLogNotifier(log), nil
+	case config.NotifierFile:
+		return notify.NewFileNotifier(cfg.File), nil
+	case config.NotifierSMTP:
+		return notify.NewSMTPNotifier(notify.SMTPConfig{
+			Addr:     cfg.SMTP.Addr,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.SMTP.From,
+			Timeout:  cfg.SMTP.Timeout,
+		}, log)
+	default:
+		return nil, fmt.Errorf("unknown notifier %q", cfg.Notifier)
+	}
+}
+
+// inTransaction reports whether ctx carries an open transaction of the
+// transaction manager, which both SQL storages use.
+func inTransaction(ctx context.Context) bool {
+	tr := trmcontext.DefaultManager.Default(ctx)
+	return tr != nil && tr.IsActive()
+}
+
 func isRetryableFunc(err error) bool {
 	unretryableErrors := []error{
 		repository.ErrDuplicate,
@@ -46,3 +138,23 @@ func isRetryableFunc(err error) bool {
 
 	return true
 }
+
+func readinessChecks(cfg *config.Config, db *pgxpool.Pool) []handler.HealthCheck {
+	wantVersion, versionErr := database.LatestVersion(cfg.App.MirgationDir)
+
+	return []handler.HealthCheck{
+		{
+			Name:  "database",
+			Check: db.Ping,
+		},
+		{
+			Name: "migrations",
+			Check: func(ctx context.Context) error {
+				if versionErr != nil {
+					return versionErr
+				}
+				return database.CheckVersion(ctx, db, wantVersion)
+			},
+		},
+	}
+}
@@ -0,0 +1,29 @@
+// Package clock abstracts the passage of time so that time-dependent code
+// (timestamps, backoff delays, schedulers) can be tested deterministically.
+package clock
+
+import "time"
+
+// Clock provides the current time and timers.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After waits for the duration to elapse and then sends the current time
+	// on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is a Clock backed by the time package.
+type realClock struct{}
+
+// Real returns a Clock that uses the system time.
+func Real() Clock {
+	return realClock{}
+}
+
+// Now returns time.Now().
+func (realClock) Now() time.Time { return time.Now() }
+
+// After returns time.After(d).
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
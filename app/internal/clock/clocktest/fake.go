@@ -0,0 +1,127 @@
+// Package clocktest provides a manually driven clock.Clock for tests.
+package clocktest
+
+import (
+	"sync"
+	"time"
+
+	"pr-service/internal/clock"
+)
+
+var _ clock.Clock = (*Fake)(nil)
+
+// waiter is a pending After call.
+type waiter struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+// Fake is a clock.Clock whose time only moves when Advance or Set is called,
+// or, for an auto-advancing Fake, whenever After is called.
+type Fake struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	auto    bool
+	waiters []*waiter
+	slept   []time.Duration
+}
+
+// NewFake returns a manually driven Fake clock set to now.
+func NewFake(now time.Time) *Fake {
+	f := &Fake{now: now}
+	f.cond = sync.NewCond(&f.mu)
+	return f
+}
+
+// NewAutoFake returns a Fake clock set to now that advances itself by d on
+// every After(d) call, so code that waits between steps runs instantly.
+func NewAutoFake(now time.Time) *Fake {
+	f := NewFake(now)
+	f.auto = true
+	return f
+}
+
+// Now returns the current fake time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// After returns a channel that receives the fake time once the clock has been
+// advanced by at least d. Non-positive durations fire immediately.
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.slept = append(f.slept, d)
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- f.now
+		return ch
+	}
+
+	if f.auto {
+		f.setLocked(f.now.Add(d))
+		ch <- f.now
+		return ch
+	}
+
+	f.waiters = append(f.waiters, &waiter{deadline: f.now.Add(d), ch: ch})
+	f.cond.Broadcast()
+	return ch
+}
+
+// Advance moves the clock forward by d and fires every expired waiter.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.setLocked(f.now.Add(d))
+}
+
+// Set moves the clock to t and fires every expired waiter.
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.setLocked(t)
+}
+
+// BlockUntil blocks until at least n After calls are waiting on the clock.
+// It lets a test synchronize with a goroutine before advancing time.
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for len(f.waiters) < n {
+		f.cond.Wait()
+	}
+}
+
+// Waiters returns the number of pending After calls.
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
+
+// Slept returns the durations passed to After, in call order.
+func (f *Fake) Slept() []time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]time.Duration(nil), f.slept...)
+}
+
+func (f *Fake) setLocked(t time.Time) {
+	f.now = t
+
+	pending := f.waiters[:0]
+	for _, w := range f.waiters {
+		if w.deadline.After(t) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- t
+	}
+	f.waiters = pending
+}
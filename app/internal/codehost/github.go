@@ -0,0 +1,135 @@
+// Package codehost calls code host APIs on behalf of the service.
+package codehost
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"pr-service/internal/models"
+	"pr-service/internal/service"
+)
+
+var _ service.CodeHostClient = (*GitHubClient)(nil)
+
+// DefaultGitHubURL is the GitHub REST API of github.com.
+const DefaultGitHubURL = "https://api.github.com"
+
+// GitHubConfig configures GitHubClient.
+type GitHubConfig struct {
+	BaseURL string        // REST API root, e.g. https://github.example.com/api/v3 for GitHub Enterprise
+	Token   string        // token allowed to write pull requests
+	Timeout time.Duration // deadline per request
+}
+
+// GitHubClient manages review requests through the GitHub REST API.
+type GitHubClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func NewGitHubClient(cfg GitHubConfig) (*GitHubClient, error) {
+	if cfg.Token == "" {
+		return nil, errors.New("github token is empty")
+	}
+
+	base := cfg.BaseURL
+	if base == "" {
+		base = DefaultGitHubURL
+	}
+	u, err := url.Parse(base)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid github api url %q", base)
+	}
+
+	return &GitHubClient{
+		baseURL: strings.TrimSuffix(base, "/"),
+		token:   cfg.Token,
+		http:    &http.Client{Timeout: cfg.Timeout},
+	}, nil
+}
+
+// StatusError is an unsuccessful response of the code host.
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("code host responded %d: %s", e.StatusCode, e.Message)
+}
+
+// IsRetryable reports whether a failed call may succeed later: transport
+// errors, rate limits and server errors are retried, other rejections are not.
+func IsRetryable(err error) bool {
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.StatusCode == http.StatusTooManyRequests || se.StatusCode >= http.StatusInternalServerError
+	}
+	return err != nil
+}
+
+// RequestReviewers requests reviews on the pull request.
+func (c *GitHubClient) RequestReviewers(ctx context.Context, ref models.ExternalPR, logins []string) error {
+	return c.requestedReviewers(ctx, http.MethodPost, ref, logins)
+}
+
+// RemoveReviewers withdraws review requests from the pull request.
+func (c *GitHubClient) RemoveReviewers(ctx context.Context, ref models.ExternalPR, logins []string) error {
+	return c.requestedReviewers(ctx, http.MethodDelete, ref, logins)
+}
+
+func (c *GitHubClient) requestedReviewers(ctx context.Context, method string, ref models.ExternalPR, logins []string) error {
+	owner, repo, ok := strings.Cut(ref.Repository, "/")
+	if !ok || owner == "" || repo == "" {
+		return fmt.Errorf("invalid github repository %q", ref.Repository)
+	}
+
+	body, err := json.Marshal(struct {
+		Reviewers []string `json:"reviewers"`
+	}{Reviewers: logins})
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/pulls/%d/requested_reviewers",
+		c.baseURL, url.PathEscape(owner), url.PathEscape(repo), ref.Number)
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	var apiErr struct {
+		Message string `json:"message"`
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+	if json.Unmarshal(msg, &apiErr) == nil && apiErr.Message != "" {
+		return &StatusError{StatusCode: resp.StatusCode, Message: apiErr.Message}
+	}
+
+	return &StatusError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(msg))}
+}
@@ -0,0 +1,108 @@
+package codehost_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pr-service/internal/codehost"
+	"pr-service/internal/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubClient_RequestedReviewers(t *testing.T) {
+	type call struct {
+		method, path, auth, accept string
+		reviewers                  []string
+	}
+	calls := make(chan call, 10)
+	status := http.StatusCreated
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Reviewers []string `json:"reviewers"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		calls <- call{
+			method:    r.Method,
+			path:      r.URL.EscapedPath(),
+			auth:      r.Header.Get("Authorization"),
+			accept:    r.Header.Get("Accept"),
+			reviewers: body.Reviewers,
+		}
+
+		w.WriteHeader(status)
+		if status >= 300 {
+			w.Write([]byte(`{"message": "Reviews may only be requested from collaborators."}`))
+			return
+		}
+		w.Write([]byte(`{"number": 42}`))
+	}))
+	defer srv.Close()
+
+	client, err := codehost.NewGitHubClient(codehost.GitHubConfig{
+		BaseURL: srv.URL + "/",
+		Token:   "ghp_test",
+		Timeout: 5 * time.Second,
+	})
+	require.NoError(t, err)
+
+	ref := models.ExternalPR{Provider: models.ProviderGitHub, Repository: "octo-org/payments-api", Number: 42}
+
+	require.NoError(t, client.RequestReviewers(t.Context(), ref, []string{"octocat", "hubot"}))
+	require.Equal(t, call{
+		method:    http.MethodPost,
+		path:      "/repos/octo-org/payments-api/pulls/42/requested_reviewers",
+		auth:      "Bearer ghp_test",
+		accept:    "application/vnd.github+json",
+		reviewers: []string{"octocat", "hubot"},
+	}, <-calls)
+
+	status = http.StatusOK
+	require.NoError(t, client.RemoveReviewers(t.Context(), ref, []string{"hubot"}))
+	got := <-calls
+	require.Equal(t, http.MethodDelete, got.method)
+	require.Equal(t, []string{"hubot"}, got.reviewers)
+
+	t.Run("rejected", func(t *testing.T) {
+		status = http.StatusUnprocessableEntity
+		err := client.RequestReviewers(t.Context(), ref, []string{"stranger"})
+		<-calls
+
+		var se *codehost.StatusError
+		require.ErrorAs(t, err, &se)
+		require.Equal(t, http.StatusUnprocessableEntity, se.StatusCode)
+		require.Equal(t, "Reviews may only be requested from collaborators.", se.Message)
+		require.False(t, codehost.IsRetryable(err))
+	})
+
+	t.Run("retryable", func(t *testing.T) {
+		for _, code := range []int{http.StatusTooManyRequests, http.StatusBadGateway} {
+			status = code
+			err := client.RequestReviewers(t.Context(), ref, []string{"octocat"})
+			<-calls
+			require.True(t, codehost.IsRetryable(err), code)
+		}
+		require.True(t, codehost.IsRetryable(errors.New("connection reset")))
+	})
+
+	t.Run("invalid repository", func(t *testing.T) {
+		err := client.RequestReviewers(t.Context(), models.ExternalPR{Repository: "311", Number: 1}, []string{"octocat"})
+		require.Error(t, err)
+	})
+}
+
+func TestNewGitHubClient(t *testing.T) {
+	_, err := codehost.NewGitHubClient(codehost.GitHubConfig{})
+	require.Error(t, err)
+
+	_, err = codehost.NewGitHubClient(codehost.GitHubConfig{Token: "t", BaseURL: "api.github.com"})
+	require.Error(t, err)
+
+	_, err = codehost.NewGitHubClient(codehost.GitHubConfig{Token: "t"})
+	require.NoError(t, err)
+}
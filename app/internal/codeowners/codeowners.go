@@ -0,0 +1,141 @@
+// Package codeowners parses CODEOWNERS files and matches paths against
+// their patterns, following the GitHub flavour of the syntax.
+package codeowners
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Rule is one non-empty line of a CODEOWNERS file. A rule without owners
+// leaves the matching paths unowned.
+type Rule struct {
+	Line    int // 1-based line number in the source
+	Pattern string
+	Owners  []string
+}
+
+// Parse reads the rules of a CODEOWNERS file in source order. Later rules
+// take precedence over earlier ones when several match a path.
+func Parse(src string) ([]Rule, error) {
+	var rules []Rule
+
+	for i, line := range strings.Split(src, "\n") {
+		n := i + 1
+
+		fields := strings.Fields(stripComment(line))
+		if len(fields) == 0 {
+			continue
+		}
+
+		pattern := strings.ReplaceAll(fields[0], `\#`, "#")
+		if _, err := Compile(pattern); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+
+		rules = append(rules, Rule{
+			Line:    n,
+			Pattern: pattern,
+			Owners:  fields[1:],
+		})
+	}
+
+	return rules, nil
+}
+
+// stripComment cuts a line at the first unescaped '#'.
+func stripComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '#' && (i == 0 || line[i-1] != '\\') {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// Pattern is a compiled CODEOWNERS pattern.
+type Pattern struct {
+	re *regexp.Regexp
+}
+
+// Compile compiles a gitignore-style pattern: a pattern without a slash
+// matches at any depth, a leading or inner slash anchors it to the root,
+// a trailing slash matches directories only. "*" and "?" stay within a path
+// segment, "**" spans segments. A pattern ending in a plain name owns
+// everything below it. Negation and character ranges are not supported, as
+// on GitHub.
+func Compile(pattern string) (*Pattern, error) {
+	if strings.HasPrefix(pattern, "!") {
+		return nil, fmt.Errorf("negated pattern %q is not supported", pattern)
+	}
+	if strings.ContainsAny(pattern, "[]") {
+		return nil, fmt.Errorf("character range in pattern %q is not supported", pattern)
+	}
+
+	p := pattern
+	dirOnly := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+	if p == "" {
+		return nil, fmt.Errorf("empty pattern %q", pattern)
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+
+	segments := strings.Split(p, "/")
+	for i, seg := range segments {
+		last := i == len(segments)-1
+
+		if seg == "**" {
+			if last {
+				b.WriteString(".*")
+			} else {
+				b.WriteString("(?:.*/)?")
+			}
+			continue
+		}
+
+		for _, r := range seg {
+			switch r {
+			case '*':
+				b.WriteString("[^/]*")
+			case '?':
+				b.WriteString("[^/]")
+			default:
+				b.WriteString(regexp.QuoteMeta(string(r)))
+			}
+		}
+		if !last {
+			b.WriteString("/")
+		}
+	}
+
+	tail := segments[len(segments)-1]
+	switch {
+	case dirOnly:
+		b.WriteString("/.*$")
+	case tail != "**" && strings.ContainsAny(tail, "*?"):
+		// a wildcard names files of one directory: docs/* skips docs/a/b.md
+		b.WriteString("$")
+	default:
+		b.WriteString("(?:/.*)?$")
+	}
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	return &Pattern{re: re}, nil
+}
+
+// Match reports whether the repository-relative path is owned by the pattern.
+func (p *Pattern) Match(path string) bool {
+	return p.re.MatchString(strings.TrimPrefix(path, "/"))
+}
@@ -0,0 +1,69 @@
+package codeowners_test
+
+import (
+	"testing"
+
+	"pr-service/internal/codeowners"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPattern_Match(t *testing.T) {
+	tests := []struct {
+		pattern string
+		match   []string
+		noMatch []string
+	}{
+		{"*", []string{"README.md", "a/b/c.go"}, nil},
+		{"*.go", []string{"main.go", "internal/app/app.go"}, []string{"main.go.txt", "README.md"}},
+		{"/build/logs/", []string{"build/logs/a.log", "build/logs/x/b.log"}, []string{"build/logs", "src/build/logs/a.log"}},
+		{"docs/*", []string{"docs/getting-started.md"}, []string{"docs/build-app/troubleshooting.md", "x/docs/a.md"}},
+		{"apps/", []string{"apps/a.go", "x/apps/b/c.go"}, []string{"apps"}},
+		{"/docs/", []string{"docs/a.md", "docs/x/b.md"}, []string{"x/docs/a.md"}},
+		{"/scripts", []string{"scripts", "scripts/deploy.sh"}, []string{"x/scripts/deploy.sh"}},
+		{"**/logs", []string{"logs/a.log", "build/logs/a.log", "deeply/nested/logs/x"}, []string{"logsx/a"}},
+		{"internal/**/repo?.go", []string{"internal/repo1.go", "internal/x/y/repoA.go"}, []string{"internal/x/repo10.go", "x/internal/repo1.go"}},
+		{"/internal/payments/**", []string{"internal/payments/a.go", "internal/payments/x/b.go"}, []string{"internal/paymentsx/a.go"}},
+		{"LICENSE", []string{"LICENSE", "third_party/x/LICENSE"}, []string{"LICENSE.md"}},
+		{"a+b(c).txt", []string{"a+b(c).txt"}, []string{"aab(c).txt"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			p, err := codeowners.Compile(tt.pattern)
+			require.NoError(t, err)
+
+			for _, path := range tt.match {
+				require.True(t, p.Match(path), path)
+			}
+			for _, path := range tt.noMatch {
+				require.False(t, p.Match(path), path)
+			}
+		})
+	}
+
+	for _, invalid := range []string{"!*.go", "*.[ch]", "/", ""} {
+		_, err := codeowners.Compile(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestParse(t *testing.T) {
+	rules, err := codeowners.Parse(`# default owners
+*       @payments
+
+/internal/billing/ @billing alice@example.com  # shared
+\#notes.md @docs
+/vendor/
+`)
+	require.NoError(t, err)
+	require.Equal(t, []codeowners.Rule{
+		{Line: 2, Pattern: "*", Owners: []string{"@payments"}},
+		{Line: 4, Pattern: "/internal/billing/", Owners: []string{"@billing", "alice@example.com"}},
+		{Line: 5, Pattern: "#notes.md", Owners: []string{"@docs"}},
+		{Line: 6, Pattern: "/vendor/", Owners: []string{}},
+	}, rules)
+
+	_, err = codeowners.Parse("*.go @a\n!*_test.go @b\n")
+	require.ErrorContains(t, err, "line 2")
+}
@@ -8,11 +8,30 @@ import (
 	"github.com/spf13/viper"
 )
 
+// Storage backends.
+const (
+	StoragePostgres = "postgres"
+	StorageSQLite   = "sqlite"
+	StorageMemory   = "memory"
+)
+
+// Digest notifiers.
+const (
+	NotifierLog  = "log"
+	NotifierFile = "file"
+	NotifierSMTP = "smtp"
+)
+
 // Config holds application configuration.
 type Config struct {
-	App         App    `mapstructure:"app"`
-	Retry       Retry  `mapstructure:"retry"`
-	DatabaseURL string `mapstructure:"database_url"`
+	App          App          `mapstructure:"app"`
+	Retry        Retry        `mapstructure:"retry"`
+	Jobs         Jobs         `mapstructure:"jobs"`
+	Notify       Notify       `mapstructure:"notify"`
+	Integrations Integrations `mapstructure:"integrations"`
+	Storage      string       `mapstructure:"storage"` // Storage backend: postgres, sqlite, memory
+	DatabaseURL  string       `mapstructure:"database_url"`
+	SQLitePath   string       `mapstructure:"sqlite_path"` // SQLite database file, ":memory:" for a private in-memory DB
 }
 
 // App contains general application settings.
@@ -21,16 +40,60 @@ type App struct {
 	MirgationDir    string        `mapstructure:"migration_dir"`    // Directory for DB migrations
 	LogLevel        string        `mapstructure:"log_level"`        // Log level (e.g., debug, info, error)
 	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"` // Timeout for graceful shutdown
+	ShutdownDelay   time.Duration `mapstructure:"shutdown_delay"`   // Time to keep serving after readiness fails
 }
 
 // Retry holds retry strategy configuration.
 type Retry struct {
-	Backoff     string        `mapstructure:"backoff"`      // Backoff type: fixed, linear, exponential
-	Base        time.Duration `mapstructure:"base"`         // Base duration for backoff
-	Factor      float64       `mapstructure:"factor"`       // Exponential factor
-	Max         time.Duration `mapstructure:"max"`          // Maximum wait duration
-	MaxAttempts int           `mapstructure:"max_attempts"` // Max retry attempts
-	Jitter      float64       `mapstructure:"jitter"`       // Random jitter fraction
+	Backoff        string        `mapstructure:"backoff"`         // Backoff type: fixed, linear, exponential
+	Base           time.Duration `mapstructure:"base"`            // Base duration for backoff
+	Factor         float64       `mapstructure:"factor"`          // Exponential factor
+	Max            time.Duration `mapstructure:"max"`             // Maximum wait duration
+	MaxAttempts    int           `mapstructure:"max_attempts"`    // Max retry attempts
+	Jitter         float64       `mapstructure:"jitter"`          // Random jitter fraction
+	AttemptTimeout time.Duration `mapstructure:"attempt_timeout"` // Deadline per attempt (DB statement), 0 = none
+}
+
+// Jobs holds the schedules of background jobs; a zero interval disables a job.
+type Jobs struct {
+	AvailabilityRestore time.Duration `mapstructure:"availability_restore"` // How often users are switched off and back on by out-of-office windows
+	ReviewSLA           time.Duration `mapstructure:"review_sla"`           // How often overdue reviews are reassigned or escalated
+	ReviewDigest        time.Duration `mapstructure:"review_digest"`        // How often team digest schedules are checked
+}
+
+// Notify configures how review digests are delivered.
+type Notify struct {
+	Notifier string `mapstructure:"notifier"` // Digest delivery: log, file, smtp
+	File     string `mapstructure:"file"`     // File the file notifier appends digests to
+	SMTP     SMTP   `mapstructure:"smtp"`
+}
+
+// SMTP holds the mail server settings of the smtp notifier.
+type SMTP struct {
+	Addr     string        `mapstructure:"addr"`     // Mail server host:port
+	Username string        `mapstructure:"username"` // No authentication when empty
+	Password string        `mapstructure:"password"`
+	From     string        `mapstructure:"from"`    // Sender address
+	Timeout  time.Duration `mapstructure:"timeout"` // Deadline per message
+}
+
+// Integrations configures the code host integrations.
+type Integrations struct {
+	GitHub GitHub `mapstructure:"github"`
+	GitLab GitLab `mapstructure:"gitlab"`
+}
+
+// GitHub holds the GitHub integration settings.
+type GitHub struct {
+	WebhookSecret string        `mapstructure:"webhook_secret"` // Secret of X-Hub-Signature-256, webhooks are rejected when empty
+	Token         string        `mapstructure:"token"`          // Token for review requests, reviewers are not pushed when empty
+	APIURL        string        `mapstructure:"api_url"`        // REST API root, differs for GitHub Enterprise
+	Timeout       time.Duration `mapstructure:"timeout"`        // Deadline per API request
+}
+
+// GitLab holds the GitLab integration settings.
+type GitLab struct {
+	WebhookToken string `mapstructure:"webhook_token"` // Expected X-Gitlab-Token, webhooks are rejected when empty
 }
 
 // Load reads configuration from file or environment variables.
@@ -42,6 +105,10 @@ func Load(configFilePath string) (*Config, error) {
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	v.BindEnv("database_url")
 	v.BindEnv("app.migration_dir")
+	v.BindEnv("notify.smtp.password")
+	v.BindEnv("integrations.github.webhook_secret")
+	v.BindEnv("integrations.github.token")
+	v.BindEnv("integrations.gitlab.webhook_token")
 
 	if configFilePath != "" {
 		v.SetConfigFile(configFilePath)
@@ -50,8 +117,19 @@ func Load(configFilePath string) (*Config, error) {
 		}
 	}
 
+	v.SetDefault("storage", StoragePostgres)
+	v.SetDefault("sqlite_path", "pr-service.db")
 	v.SetDefault("app.port", "8080")
 	v.SetDefault("app.shutdown_timeout", "5s")
+	v.SetDefault("app.shutdown_delay", "5s")
+	v.SetDefault("jobs.availability_restore", "1m")
+	v.SetDefault("jobs.review_sla", "5m")
+	v.SetDefault("jobs.review_digest", "1m")
+	v.SetDefault("notify.notifier", NotifierLog)
+	v.SetDefault("notify.file", "digests.log")
+	v.SetDefault("notify.smtp.timeout", "10s")
+	v.SetDefault("integrations.github.api_url", "https://api.github.com")
+	v.SetDefault("integrations.github.timeout", "10s")
 	v.SetDefault("retry.max_attempts", 3)
 	v.SetDefault("retry.backoff", "fixed")
 	v.SetDefault("retry.jitter", 0.0)
@@ -3,12 +3,11 @@ package database
 import (
 	"context"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
 
-	"github.com/golang-migrate/migrate/v4"
 	"github.com/jackc/pgx/v5/pgxpool"
-
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 )
 
 // Connect establishes a connection pool to the PostgreSQL database and verifies it with a ping.
@@ -23,15 +22,63 @@ func Connect(ctx context.Context, dbURL string) (*pgxpool.Pool, error) {
 	return db, nil
 }
 
-// Migrate runs database migrations from the given directory.
-// Ignores ErrNoChange if there are no new migrations to apply.
-func Migrate(migrationDir string, dbURL string) error {
-	m, err := migrate.New("file://"+migrationDir, dbURL)
+// LatestVersion returns the highest migration version found in migrationDir.
+// Migration files are expected to be named <version>_<title>.<up|down>.sql.
+func LatestVersion(migrationDir string) (uint, error) {
+	entries, err := os.ReadDir(migrationDir)
 	if err != nil {
-		return err
+		return 0, fmt.Errorf("read migration dir: %w", err)
+	}
+
+	var latest uint
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".up.sql") {
+			continue
+		}
+
+		prefix, _, ok := strings.Cut(e.Name(), "_")
+		if !ok {
+			continue
+		}
+
+		v, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			continue
+		}
+		latest = max(latest, uint(v))
+	}
+
+	return latest, nil
+}
+
+// SchemaVersion returns the currently applied migration version and whether
+// the last migration left the schema dirty.
+func SchemaVersion(ctx context.Context, db *pgxpool.Pool) (uint, bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+
+	err := db.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").
+		Scan(&version, &dirty)
+	if err != nil {
+		return 0, false, fmt.Errorf("read schema version: %w", err)
 	}
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+
+	return uint(version), dirty, nil
+}
+
+// CheckVersion verifies that the schema is clean and at least at version want.
+func CheckVersion(ctx context.Context, db *pgxpool.Pool, want uint) error {
+	version, dirty, err := SchemaVersion(ctx, db)
+	if err != nil {
 		return err
 	}
+	if dirty {
+		return fmt.Errorf("schema version %d is dirty", version)
+	}
+	if version < want {
+		return fmt.Errorf("schema version %d is behind expected %d", version, want)
+	}
 	return nil
 }
@@ -0,0 +1,109 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/jackc/pgx/v5"
+
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+)
+
+// migrationLockKey is the pg_advisory_lock key held while migrations run,
+// so replicas booting at the same time apply migrations one after another.
+const migrationLockKey int64 = 0x70725f6d696772 // "pr_migr"
+
+// Migrator applies schema migrations while holding a Postgres advisory lock.
+type Migrator struct {
+	m    *migrate.Migrate
+	lock *pgx.Conn
+}
+
+// NewMigrator opens the migration source and database and blocks until the
+// migration advisory lock is acquired or ctx is done. Close releases the lock.
+func NewMigrator(ctx context.Context, migrationDir, dbURL string) (*Migrator, error) {
+	lock, err := pgx.Connect(ctx, dbURL)
+	if err != nil {
+		return nil, fmt.Errorf("migration lock connection error: %w", err)
+	}
+
+	if _, err := lock.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		lock.Close(context.Background())
+		return nil, fmt.Errorf("acquire migration lock: %w", err)
+	}
+
+	m, err := migrate.New("file://"+migrationDir, dbURL)
+	if err != nil {
+		lock.Close(context.Background())
+		return nil, err
+	}
+
+	return &Migrator{m: m, lock: lock}, nil
+}
+
+// Up applies all pending migrations. Ignores ErrNoChange.
+func (m *Migrator) Up() error {
+	return ignoreNoChange(m.m.Up())
+}
+
+// Down rolls back the given number of applied migrations.
+func (m *Migrator) Down(steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("invalid number of steps: %d", steps)
+	}
+	return ignoreNoChange(m.m.Steps(-steps))
+}
+
+// To migrates up or down to the given version.
+func (m *Migrator) To(version uint) error {
+	return ignoreNoChange(m.m.Migrate(version))
+}
+
+// Version returns the applied version and whether it is dirty.
+// A database without applied migrations has version 0.
+func (m *Migrator) Version() (uint, bool, error) {
+	version, dirty, err := m.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+// Force sets the version without running migrations and clears the dirty flag.
+// Use it to recover after a failed migration was fixed by hand.
+func (m *Migrator) Force(version int) error {
+	return m.m.Force(version)
+}
+
+// Close releases the advisory lock and the underlying connections.
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.m.Close()
+
+	ctx := context.Background()
+	_, unlockErr := m.lock.Exec(ctx, "SELECT pg_advisory_unlock($1)", migrationLockKey)
+	closeErr := m.lock.Close(ctx)
+
+	return errors.Join(srcErr, dbErr, unlockErr, closeErr)
+}
+
+// Migrate runs database migrations from the given directory.
+// Ignores ErrNoChange if there are no new migrations to apply.
+func Migrate(migrationDir string, dbURL string) error {
+	m, err := NewMigrator(context.Background(), migrationDir, dbURL)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	return m.Up()
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+	return err
+}
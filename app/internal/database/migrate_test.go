@@ -0,0 +1,114 @@
+//go:build integration
+// +build integration
+
+package database_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"pr-service/internal/database"
+
+	"github.com/stretchr/testify/require"
+	tc "github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"golang.org/x/sync/errgroup"
+)
+
+const migrationDir = "../../../migrations"
+
+func startPostgres(t *testing.T) string {
+	t.Helper()
+	ctx := context.Background()
+
+	pgContainer, err := postgres.Run(ctx,
+		"postgres:15.3-alpine",
+		postgres.WithDatabase("test_db"),
+		postgres.WithUsername("postgres"),
+		postgres.WithPassword("postgres"),
+		tc.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).WithStartupTimeout(10*time.Second)),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = pgContainer.Terminate(ctx) })
+
+	dsn, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
+	require.NoError(t, err)
+
+	return dsn
+}
+
+func TestMigrator(t *testing.T) {
+	dsn := startPostgres(t)
+	ctx := t.Context()
+
+	latest, err := database.LatestVersion(migrationDir)
+	require.NoError(t, err)
+
+	t.Run("concurrent up", func(t *testing.T) {
+		var g errgroup.Group
+		for range 5 {
+			g.Go(func() error {
+				return database.Migrate(migrationDir, dsn)
+			})
+		}
+		require.NoError(t, g.Wait())
+	})
+
+	m, err := database.NewMigrator(ctx, migrationDir, dsn)
+	require.NoError(t, err)
+	defer m.Close()
+
+	t.Run("version", func(t *testing.T) {
+		version, dirty, err := m.Version()
+		require.NoError(t, err)
+		require.False(t, dirty)
+		require.Equal(t, latest, version)
+	})
+
+	t.Run("down one step", func(t *testing.T) {
+		require.NoError(t, m.Down(1))
+
+		version, _, err := m.Version()
+		require.NoError(t, err)
+		require.Equal(t, latest-1, version)
+	})
+
+	t.Run("down to empty and up again", func(t *testing.T) {
+		version, _, err := m.Version()
+		require.NoError(t, err)
+		require.NoError(t, m.Down(int(version)))
+
+		version, _, err = m.Version()
+		require.NoError(t, err)
+		require.Zero(t, version)
+
+		require.NoError(t, m.Up())
+
+		version, _, err = m.Version()
+		require.NoError(t, err)
+		require.Equal(t, latest, version)
+	})
+
+	t.Run("to", func(t *testing.T) {
+		require.NoError(t, m.To(2))
+
+		version, _, err := m.Version()
+		require.NoError(t, err)
+		require.EqualValues(t, 2, version)
+
+		require.NoError(t, m.To(latest))
+	})
+
+	t.Run("force", func(t *testing.T) {
+		require.NoError(t, m.Force(int(latest)))
+
+		version, dirty, err := m.Version()
+		require.NoError(t, err)
+		require.False(t, dirty)
+		require.Equal(t, latest, version)
+	})
+}
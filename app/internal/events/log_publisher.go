@@ -0,0 +1,39 @@
+// Package events delivers domain events produced by the services.
+package events
+
+import (
+	"context"
+
+	"pr-service/internal/models"
+	"pr-service/internal/service"
+
+	"go.uber.org/zap"
+)
+
+var _ service.EscalationPublisher = (*LogPublisher)(nil)
+
+// LogPublisher writes events to the application log, where log-based
+// alerting can pick them up.
+type LogPublisher struct {
+	log *zap.Logger
+}
+
+func NewLogPublisher(log *zap.Logger) *LogPublisher {
+	return &LogPublisher{log: log.Named("events")}
+}
+
+// PublishEscalation logs a review escalation.
+func (p *LogPublisher) PublishEscalation(_ context.Context, e *models.ReviewEscalation) error {
+	p.log.Warn("review escalation",
+		zap.String("event", "review.escalated"),
+		zap.String("pr_id", e.PRID.String()),
+		zap.String("pr_name", e.PRName),
+		zap.String("reviewer_id", e.ReviewerID.String()),
+		zap.String("team_name", e.TeamName),
+		zap.Time("assigned_at", e.AssignedAt),
+		zap.Time("deadline", e.Deadline),
+		zap.Time("escalated_at", e.EscalatedAt),
+		zap.String("reason", e.Reason),
+	)
+	return nil
+}
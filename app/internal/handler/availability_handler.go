@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"pr-service/internal/api"
+	"pr-service/internal/models"
+	"pr-service/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type AvailabilityHandler struct {
+	availabilityService *service.AvailabilityService
+	log                 *zap.Logger
+}
+
+func NewAvailabilityHandler(availabilityService *service.AvailabilityService, log *zap.Logger) *AvailabilityHandler {
+	return &AvailabilityHandler{
+		availabilityService: availabilityService,
+		log:                 log,
+	}
+}
+
+func (h *AvailabilityHandler) GetUsersAvailability(c echo.Context, params api.GetUsersAvailabilityParams) error {
+	userID, err := uuid.Parse(params.UserId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "invalid user_id")
+	}
+
+	windows, err := h.availabilityService.List(c.Request().Context(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			errResp := api.ErrorResponse{}
+			errResp.Error.Code = api.NOTFOUND
+			errResp.Error.Message = "user not found"
+			return c.JSON(http.StatusNotFound, errResp)
+		}
+		return c.JSON(http.StatusInternalServerError, "")
+	}
+
+	resp := struct {
+		UserID  string             `json:"user_id"`
+		Windows []api.Availability `json:"windows"`
+	}{
+		UserID:  userID.String(),
+		Windows: make([]api.Availability, len(windows)),
+	}
+
+	for i, a := range windows {
+		resp.Windows[i] = toAPIAvailability(a)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *AvailabilityHandler) PostUsersAvailability(c echo.Context) error {
+	body := api.PostUsersAvailabilityJSONBody{}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, "bad request")
+	}
+
+	userID, err := uuid.Parse(body.UserId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "invalid user_id")
+	}
+
+	a := &models.Availability{
+		UserID:   userID,
+		StartsAt: body.StartsAt,
+		EndsAt:   body.EndsAt,
+	}
+	if body.Reason != nil {
+		a.Reason = *body.Reason
+	}
+
+	if err := h.availabilityService.Add(c.Request().Context(), a); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidWindow):
+			return c.JSON(http.StatusBadRequest, "invalid window")
+		case errors.Is(err, service.ErrNotFound):
+			errResp := api.ErrorResponse{}
+			errResp.Error.Code = api.NOTFOUND
+			errResp.Error.Message = "user not found"
+			return c.JSON(http.StatusNotFound, errResp)
+		default:
+			return c.JSON(http.StatusInternalServerError, "")
+		}
+	}
+
+	return c.JSON(http.StatusCreated, echo.Map{
+		"availability": toAPIAvailability(a),
+	})
+}
+
+func (h *AvailabilityHandler) DeleteUsersAvailability(c echo.Context, params api.DeleteUsersAvailabilityParams) error {
+	id, err := uuid.Parse(params.AvailabilityId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "invalid availability_id")
+	}
+
+	if err := h.availabilityService.Delete(c.Request().Context(), id); err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			errResp := api.ErrorResponse{}
+			errResp.Error.Code = api.NOTFOUND
+			errResp.Error.Message = "availability window not found"
+			return c.JSON(http.StatusNotFound, errResp)
+		}
+		return c.JSON(http.StatusInternalServerError, "")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func toAPIAvailability(a *models.Availability) api.Availability {
+	return api.Availability{
+		AvailabilityId: a.ID.String(),
+		UserId:         a.UserID.String(),
+		StartsAt:       a.StartsAt.UTC(),
+		EndsAt:         a.EndsAt.UTC(),
+		Reason:         a.Reason,
+	}
+}
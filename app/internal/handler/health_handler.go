@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// readyCheckTimeout bounds every dependency check made by /readyz.
+const readyCheckTimeout = 2 * time.Second
+
+// HealthCheck is a named dependency check used by /readyz.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// HealthResponse is the body returned by /healthz and /readyz.
+type HealthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// HealthHandler serves liveness and readiness probes.
+type HealthHandler struct {
+	checks []HealthCheck
+	ready  atomic.Bool
+	log    *zap.Logger
+}
+
+func NewHealthHandler(log *zap.Logger, checks ...HealthCheck) *HealthHandler {
+	return &HealthHandler{
+		checks: checks,
+		log:    log,
+	}
+}
+
+// Register adds the probe routes to the router.
+func (h *HealthHandler) Register(r *echo.Echo) {
+	r.GET("/healthz", h.Healthz)
+	r.GET("/readyz", h.Readyz)
+}
+
+// SetReady marks the instance as able (or no longer able) to accept traffic.
+func (h *HealthHandler) SetReady(ready bool) {
+	h.ready.Store(ready)
+}
+
+// Healthz reports that the process is alive.
+func (h *HealthHandler) Healthz(c echo.Context) error {
+	return c.JSON(http.StatusOK, HealthResponse{Status: "ok"})
+}
+
+// Readyz reports whether the instance is ready to serve traffic:
+// it must not be shutting down and every dependency check must pass.
+func (h *HealthHandler) Readyz(c echo.Context) error {
+	if !h.ready.Load() {
+		return c.JSON(http.StatusServiceUnavailable, HealthResponse{Status: "not_ready"})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), readyCheckTimeout)
+	defer cancel()
+
+	resp := HealthResponse{
+		Status: "ok",
+		Checks: make(map[string]string, len(h.checks)),
+	}
+	code := http.StatusOK
+
+	for _, check := range h.checks {
+		if err := check.Check(ctx); err != nil {
+			h.log.Warn("readiness check failed",
+				zap.String("check", check.Name),
+				zap.Error(err),
+			)
+			resp.Checks[check.Name] = err.Error()
+			resp.Status = "unavailable"
+			code = http.StatusServiceUnavailable
+			continue
+		}
+		resp.Checks[check.Name] = "ok"
+	}
+
+	return c.JSON(code, resp)
+}
@@ -0,0 +1,85 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pr-service/internal/handler"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHealthHandler(t *testing.T) {
+	dbErr := errors.New("connection refused")
+	dbHealthy := true
+
+	h := handler.NewHealthHandler(zap.NewNop(),
+		handler.HealthCheck{
+			Name: "database",
+			Check: func(context.Context) error {
+				if dbHealthy {
+					return nil
+				}
+				return dbErr
+			},
+		},
+	)
+
+	e := echo.New()
+	h.Register(e)
+
+	get := func(t *testing.T, path string) (int, handler.HealthResponse) {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		var resp handler.HealthResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return rec.Code, resp
+	}
+
+	t.Run("alive while booting", func(t *testing.T) {
+		code, resp := get(t, "/healthz")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "ok", resp.Status)
+	})
+
+	t.Run("not ready while booting", func(t *testing.T) {
+		code, resp := get(t, "/readyz")
+		require.Equal(t, http.StatusServiceUnavailable, code)
+		require.Equal(t, "not_ready", resp.Status)
+	})
+
+	t.Run("ready", func(t *testing.T) {
+		h.SetReady(true)
+
+		code, resp := get(t, "/readyz")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, map[string]string{"database": "ok"}, resp.Checks)
+	})
+
+	t.Run("dependency down", func(t *testing.T) {
+		dbHealthy = false
+		defer func() { dbHealthy = true }()
+
+		code, resp := get(t, "/readyz")
+		require.Equal(t, http.StatusServiceUnavailable, code)
+		require.Equal(t, "unavailable", resp.Status)
+		require.Equal(t, dbErr.Error(), resp.Checks["database"])
+	})
+
+	t.Run("shutting down", func(t *testing.T) {
+		h.SetReady(false)
+
+		code, _ := get(t, "/readyz")
+		require.Equal(t, http.StatusServiceUnavailable, code)
+
+		code, _ = get(t, "/healthz")
+		require.Equal(t, http.StatusOK, code)
+	})
+}
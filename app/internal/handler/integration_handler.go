@@ -0,0 +1,310 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"pr-service/internal/api"
+	"pr-service/internal/models"
+	"pr-service/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// maxWebhookBody is the largest payload the code hosts deliver.
+const maxWebhookBody = 25 << 20
+
+// WebhookSecrets authenticate code host webhooks; an empty secret rejects
+// every delivery of that host.
+type WebhookSecrets struct {
+	GitHub string
+	GitLab string
+}
+
+type IntegrationHandler struct {
+	integrationService *service.IntegrationService
+	secrets            WebhookSecrets
+	log                *zap.Logger
+}
+
+func NewIntegrationHandler(integrationService *service.IntegrationService, secrets WebhookSecrets, log *zap.Logger) *IntegrationHandler {
+	return &IntegrationHandler{
+		integrationService: integrationService,
+		secrets:            secrets,
+		log:                log,
+	}
+}
+
+// githubPullRequestEvent is the part of a GitHub pull_request payload we use.
+type githubPullRequestEvent struct {
+	Action      string `json:"action"`
+	Number      int64  `json:"number"`
+	PullRequest struct {
+		Title  string `json:"title"`
+		Draft  bool   `json:"draft"`
+		Merged bool   `json:"merged"`
+		User   struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+func (h *IntegrationHandler) PostIntegrationsGithubWebhook(c echo.Context, params api.PostIntegrationsGithubWebhookParams) error {
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxWebhookBody))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "bad request")
+	}
+
+	if params.XHubSignature256 == nil || !validGitHubSignature(h.secrets.GitHub, body, *params.XHubSignature256) {
+		h.log.Warn("github webhook rejected, invalid signature")
+		return c.JSON(http.StatusUnauthorized, "invalid signature")
+	}
+
+	if params.XGitHubEvent == nil || *params.XGitHubEvent != "pull_request" {
+		return c.JSON(http.StatusOK, api.WebhookResult{Result: string(service.SyncIgnored)})
+	}
+
+	var payload githubPullRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return c.JSON(http.StatusBadRequest, "invalid payload")
+	}
+	if payload.Repository.FullName == "" || payload.Number <= 0 {
+		return c.JSON(http.StatusBadRequest, "invalid payload")
+	}
+
+	event := &models.PREvent{
+		PR: models.ExternalPR{
+			Provider:   models.ProviderGitHub,
+			Repository: payload.Repository.FullName,
+			Number:     payload.Number,
+		},
+		Title:  payload.PullRequest.Title,
+		Author: payload.PullRequest.User.Login,
+	}
+
+	switch payload.Action {
+	case "opened", "reopened":
+		if payload.PullRequest.Draft {
+			// drafts get reviewers once they are ready_for_review
+			return c.JSON(http.StatusOK, api.WebhookResult{Result: string(service.SyncIgnored)})
+		}
+		event.Kind = models.PREventOpened
+		if payload.Action == "reopened" {
+			event.Kind = models.PREventReopened
+		}
+	case "ready_for_review":
+		event.Kind = models.PREventOpened
+	case "closed":
+		event.Kind = models.PREventClosed
+		if payload.PullRequest.Merged {
+			event.Kind = models.PREventMerged
+		}
+	default:
+		return c.JSON(http.StatusOK, api.WebhookResult{Result: string(service.SyncIgnored)})
+	}
+
+	return h.handlePREvent(c, event)
+}
+
+func (h *IntegrationHandler) PostIntegrationsGithubUsers(c echo.Context) error {
+	body := api.ExternalUserLink{}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, "bad request")
+	}
+
+	userID, err := uuid.Parse(body.UserId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "invalid user_id")
+	}
+	if strings.TrimSpace(body.Login) == "" {
+		return c.JSON(http.StatusBadRequest, "invalid login")
+	}
+
+	if err := h.integrationService.LinkUser(c.Request().Context(), models.ProviderGitHub, body.Login, userID); err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			errResp := api.ErrorResponse{}
+			errResp.Error.Code = api.NOTFOUND
+			errResp.Error.Message = "user not found"
+			return c.JSON(http.StatusNotFound, errResp)
+		}
+		return c.JSON(http.StatusInternalServerError, "")
+	}
+
+	return c.JSON(http.StatusOK, api.ExternalUserLink{
+		Login:  strings.ToLower(body.Login),
+		UserId: userID.String(),
+	})
+}
+
+// gitlabMergeRequestEvent is the part of a GitLab merge request payload we use.
+type gitlabMergeRequestEvent struct {
+	ObjectKind string `json:"object_kind"`
+	Project    struct {
+		ID int64 `json:"id"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID      int64  `json:"iid"`
+		Title    string `json:"title"`
+		Action   string `json:"action"`
+		Draft    bool   `json:"draft"`
+		AuthorID int64  `json:"author_id"`
+	} `json:"object_attributes"`
+	Changes struct {
+		Draft *struct {
+			Previous bool `json:"previous"`
+			Current  bool `json:"current"`
+		} `json:"draft"`
+	} `json:"changes"`
+}
+
+func (h *IntegrationHandler) PostIntegrationsGitlabWebhook(c echo.Context, params api.PostIntegrationsGitlabWebhookParams) error {
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxWebhookBody))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "bad request")
+	}
+
+	if params.XGitlabToken == nil || !validGitLabToken(h.secrets.GitLab, *params.XGitlabToken) {
+		h.log.Warn("gitlab webhook rejected, invalid token")
+		return c.JSON(http.StatusUnauthorized, "invalid token")
+	}
+
+	if params.XGitlabEvent == nil || *params.XGitlabEvent != "Merge Request Hook" {
+		return c.JSON(http.StatusOK, api.WebhookResult{Result: string(service.SyncIgnored)})
+	}
+
+	var payload gitlabMergeRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return c.JSON(http.StatusBadRequest, "invalid payload")
+	}
+	if payload.ObjectKind != "merge_request" || payload.Project.ID <= 0 || payload.ObjectAttributes.IID <= 0 {
+		return c.JSON(http.StatusBadRequest, "invalid payload")
+	}
+
+	attrs := payload.ObjectAttributes
+	event := &models.PREvent{
+		PR: models.ExternalPR{
+			Provider:   models.ProviderGitLab,
+			Repository: strconv.FormatInt(payload.Project.ID, 10),
+			Number:     attrs.IID,
+		},
+		Title:  attrs.Title,
+		Author: strconv.FormatInt(attrs.AuthorID, 10),
+	}
+
+	switch {
+	case attrs.Action == "open" || attrs.Action == "reopen":
+		if attrs.Draft {
+			// drafts get reviewers once they are marked ready
+			return c.JSON(http.StatusOK, api.WebhookResult{Result: string(service.SyncIgnored)})
+		}
+		event.Kind = models.PREventOpened
+		if attrs.Action == "reopen" {
+			event.Kind = models.PREventReopened
+		}
+	case attrs.Action == "update" && payload.Changes.Draft != nil &&
+		payload.Changes.Draft.Previous && !payload.Changes.Draft.Current:
+		event.Kind = models.PREventOpened
+	case attrs.Action == "close":
+		event.Kind = models.PREventClosed
+	case attrs.Action == "merge":
+		event.Kind = models.PREventMerged
+	default:
+		return c.JSON(http.StatusOK, api.WebhookResult{Result: string(service.SyncIgnored)})
+	}
+
+	return h.handlePREvent(c, event)
+}
+
+func (h *IntegrationHandler) PostIntegrationsGitlabUsers(c echo.Context) error {
+	body := api.GitLabUserLink{}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, "bad request")
+	}
+
+	userID, err := uuid.Parse(body.UserId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "invalid user_id")
+	}
+	if body.GitlabUserId <= 0 {
+		return c.JSON(http.StatusBadRequest, "invalid gitlab_user_id")
+	}
+
+	externalID := strconv.FormatInt(body.GitlabUserId, 10)
+	if err := h.integrationService.LinkUser(c.Request().Context(), models.ProviderGitLab, externalID, userID); err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			errResp := api.ErrorResponse{}
+			errResp.Error.Code = api.NOTFOUND
+			errResp.Error.Message = "user not found"
+			return c.JSON(http.StatusNotFound, errResp)
+		}
+		return c.JSON(http.StatusInternalServerError, "")
+	}
+
+	return c.JSON(http.StatusOK, body)
+}
+
+// handlePREvent applies a decoded event and writes the webhook response.
+func (h *IntegrationHandler) handlePREvent(c echo.Context, event *models.PREvent) error {
+	result, err := h.integrationService.HandlePREvent(c.Request().Context(), event)
+	if err != nil {
+		if errors.Is(err, service.ErrUnknownExternalUser) {
+			errResp := api.ErrorResponse{}
+			errResp.Error.Code = api.NOTFOUND
+			errResp.Error.Message = fmt.Sprintf("%s user %s is not linked", event.PR.Provider, event.Author)
+			return c.JSON(http.StatusUnprocessableEntity, errResp)
+		}
+		if errors.Is(err, service.ErrReviewPolicy) {
+			errResp := api.ErrorResponse{}
+			errResp.Error.Code = api.POLICYUNSATISFIED
+			errResp.Error.Message = err.Error()
+			return c.JSON(http.StatusUnprocessableEntity, errResp)
+		}
+		return c.JSON(http.StatusInternalServerError, "")
+	}
+
+	return c.JSON(http.StatusOK, api.WebhookResult{Result: string(result)})
+}
+
+// validGitHubSignature checks a "sha256=<hex>" HMAC of the raw body.
+func validGitHubSignature(secret string, body []byte, signature string) bool {
+	if secret == "" {
+		return false
+	}
+
+	sum, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sum)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// validGitLabToken compares the X-Gitlab-Token in constant time.
+func validGitLabToken(secret, token string) bool {
+	if secret == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(token)) == 1
+}
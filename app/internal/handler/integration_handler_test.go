@@ -0,0 +1,364 @@
+package handler_test
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"pr-service/internal/api"
+	"pr-service/internal/clock/clocktest"
+	"pr-service/internal/handler"
+	"pr-service/internal/models"
+	"pr-service/internal/repository/memory"
+	"pr-service/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const githubSecret = "It's a Secret to Everybody"
+
+func signGitHub(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestIntegrationHandler_GitHub(t *testing.T) {
+	store := memory.NewStore()
+	clk := clocktest.NewFake(time.Date(2025, 10, 24, 12, 0, 0, 0, time.UTC))
+	tx := memory.NewTxManager(store)
+	prRepo := memory.NewPRRepository(store, clk)
+	userRepo := memory.NewUserRepository(store)
+	integrationRepo := memory.NewIntegrationRepository(store)
+
+	prService := service.NewPRService(memory.NewTeamRepository(store), userRepo, prRepo, nil, tx, clk, zap.NewNop())
+	integrationService := service.NewIntegrationService(integrationRepo, userRepo, prService, tx, zap.NewNop())
+
+	newEcho := func(secrets handler.WebhookSecrets) *echo.Echo {
+		e := echo.New()
+		api.RegisterHandlers(e, handler.NewServer(nil, nil, nil,
+			handler.NewIntegrationHandler(integrationService, secrets, zap.NewNop()),
+		))
+		return e
+	}
+	e := newEcho(handler.WebhookSecrets{GitHub: githubSecret})
+
+	team := &models.Team{
+		Name: "payments",
+		Members: []*models.User{
+			{Name: "Alice", IsActive: true},
+			{Name: "Bob", IsActive: true},
+			{Name: "Carol", IsActive: true},
+		},
+	}
+	require.NoError(t, prService.TeamAdd(t.Context(), team))
+	alice := team.Members[0]
+
+	type response struct {
+		code   int
+		result string
+	}
+	send := func(t *testing.T, e *echo.Echo, event string, body []byte, signature string) response {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodPost, "/integrations/github/webhook", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("X-GitHub-Event", event)
+		req.Header.Set("X-GitHub-Delivery", uuid.NewString())
+		if signature != "" {
+			req.Header.Set("X-Hub-Signature-256", signature)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		var res api.WebhookResult
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		}
+		return response{code: rec.Code, result: res.Result}
+	}
+	deliver := func(t *testing.T, event, fixture string) response {
+		t.Helper()
+
+		body, err := os.ReadFile(filepath.Join("testdata", "github", fixture))
+		require.NoError(t, err)
+		return send(t, e, event, body, signGitHub(githubSecret, body))
+	}
+	linkedPR := func(t *testing.T, number int64) *models.PullRequest {
+		t.Helper()
+
+		id, err := integrationRepo.GetPRID(t.Context(), models.ExternalPR{
+			Provider:   models.ProviderGitHub,
+			Repository: "octo-org/payments-api",
+			Number:     number,
+		})
+		require.NoError(t, err)
+		pr, err := prRepo.GetByID(t.Context(), id)
+		require.NoError(t, err)
+		return pr
+	}
+	link := func(t *testing.T, login, userID string) int {
+		t.Helper()
+
+		body, err := json.Marshal(api.ExternalUserLink{Login: login, UserId: userID})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/integrations/github/users", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	t.Run("signature", func(t *testing.T) {
+		body, err := os.ReadFile(filepath.Join("testdata", "github", "pull_request_opened.json"))
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusUnauthorized, send(t, e, "pull_request", body, "").code)
+		require.Equal(t, http.StatusUnauthorized, send(t, e, "pull_request", body, signGitHub("wrong", body)).code)
+		require.Equal(t, http.StatusUnauthorized, send(t, e, "pull_request", body, "sha1=abc").code)
+
+		tampered := bytes.Replace(body, []byte("Retry failed"), []byte("Drop failed"), 1)
+		require.Equal(t, http.StatusUnauthorized, send(t, e, "pull_request", tampered, signGitHub(githubSecret, body)).code)
+
+		// without a configured secret every delivery is rejected
+		unconfigured := newEcho(handler.WebhookSecrets{})
+		require.Equal(t, http.StatusUnauthorized, send(t, unconfigured, "pull_request", body, signGitHub("", body)).code)
+	})
+
+	t.Run("ping", func(t *testing.T) {
+		require.Equal(t, response{http.StatusOK, "ignored"}, deliver(t, "ping", "ping.json"))
+	})
+
+	t.Run("unknown author", func(t *testing.T) {
+		require.Equal(t, http.StatusUnprocessableEntity, deliver(t, "pull_request", "pull_request_opened.json").code)
+	})
+
+	t.Run("link user", func(t *testing.T) {
+		require.Equal(t, http.StatusBadRequest, link(t, "octocat", "not-a-uuid"))
+		require.Equal(t, http.StatusNotFound, link(t, "octocat", uuid.NewString()))
+		require.Equal(t, http.StatusOK, link(t, "octocat", alice.ID.String()))
+	})
+
+	t.Run("opened", func(t *testing.T) {
+		require.Equal(t, response{http.StatusOK, "created"}, deliver(t, "pull_request", "pull_request_opened.json"))
+
+		pr := linkedPR(t, 42)
+		require.Equal(t, "Retry failed provider callbacks", pr.Name)
+		require.Equal(t, alice.ID, pr.AuthorID)
+		require.Equal(t, string(models.PRStatusOpen), pr.Status)
+		require.Len(t, pr.Reviewers, 2)
+
+		// redelivery
+		require.Equal(t, response{http.StatusOK, "unchanged"}, deliver(t, "pull_request", "pull_request_opened.json"))
+		require.Equal(t, response{http.StatusOK, "ignored"}, deliver(t, "pull_request", "pull_request_synchronize.json"))
+	})
+
+	t.Run("draft flow", func(t *testing.T) {
+		require.Equal(t, response{http.StatusOK, "ignored"}, deliver(t, "pull_request", "pull_request_opened_draft.json"))
+		require.Equal(t, response{http.StatusOK, "created"}, deliver(t, "pull_request", "pull_request_ready_for_review.json"))
+		require.Equal(t, "Split ledger writer", linkedPR(t, 43).Name)
+
+		require.Equal(t, response{http.StatusOK, "ignored"}, deliver(t, "pull_request", "pull_request_closed.json"))
+		require.Equal(t, response{http.StatusOK, "unchanged"}, deliver(t, "pull_request", "pull_request_reopened.json"))
+		require.Equal(t, string(models.PRStatusOpen), linkedPR(t, 43).Status)
+	})
+
+	t.Run("reopened before integration", func(t *testing.T) {
+		body, err := os.ReadFile(filepath.Join("testdata", "github", "pull_request_reopened.json"))
+		require.NoError(t, err)
+		body = bytes.ReplaceAll(body, []byte(`"number": 43`), []byte(`"number": 44`))
+
+		require.Equal(t, response{http.StatusOK, "created"}, send(t, e, "pull_request", body, signGitHub(githubSecret, body)))
+		require.Equal(t, string(models.PRStatusOpen), linkedPR(t, 44).Status)
+	})
+
+	t.Run("merged", func(t *testing.T) {
+		require.Equal(t, response{http.StatusOK, "merged"}, deliver(t, "pull_request", "pull_request_closed_merged.json"))
+
+		pr := linkedPR(t, 42)
+		require.Equal(t, string(models.PRStatusMerged), pr.Status)
+		require.NotNil(t, pr.MergedAt)
+	})
+
+	t.Run("invalid payload", func(t *testing.T) {
+		body := []byte(`{"action": "opened", "number": 7`)
+		require.Equal(t, http.StatusBadRequest, send(t, e, "pull_request", body, signGitHub(githubSecret, body)).code)
+
+		body = []byte(`{"action": "opened"}`)
+		require.Equal(t, http.StatusBadRequest, send(t, e, "pull_request", body, signGitHub(githubSecret, body)).code)
+	})
+}
+
+const gitlabToken = "gitlab-hook-token"
+
+func TestIntegrationHandler_GitLab(t *testing.T) {
+	store := memory.NewStore()
+	clk := clocktest.NewFake(time.Date(2025, 10, 24, 12, 0, 0, 0, time.UTC))
+	tx := memory.NewTxManager(store)
+	prRepo := memory.NewPRRepository(store, clk)
+	userRepo := memory.NewUserRepository(store)
+	integrationRepo := memory.NewIntegrationRepository(store)
+
+	prService := service.NewPRService(memory.NewTeamRepository(store), userRepo, prRepo, nil, tx, clk, zap.NewNop())
+	integrationService := service.NewIntegrationService(integrationRepo, userRepo, prService, tx, zap.NewNop())
+
+	newEcho := func(secrets handler.WebhookSecrets) *echo.Echo {
+		e := echo.New()
+		api.RegisterHandlers(e, handler.NewServer(nil, nil, nil,
+			handler.NewIntegrationHandler(integrationService, secrets, zap.NewNop()),
+		))
+		return e
+	}
+	e := newEcho(handler.WebhookSecrets{GitLab: gitlabToken})
+
+	team := &models.Team{
+		Name: "ledger",
+		Members: []*models.User{
+			{Name: "Alice", IsActive: true},
+			{Name: "Bob", IsActive: true},
+			{Name: "Carol", IsActive: true},
+		},
+	}
+	require.NoError(t, prService.TeamAdd(t.Context(), team))
+	alice := team.Members[0]
+
+	type response struct {
+		code   int
+		result string
+	}
+	send := func(t *testing.T, e *echo.Echo, event string, body []byte, token string) response {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodPost, "/integrations/gitlab/webhook", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("X-Gitlab-Event", event)
+		if token != "" {
+			req.Header.Set("X-Gitlab-Token", token)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		var res api.WebhookResult
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		}
+		return response{code: rec.Code, result: res.Result}
+	}
+	deliver := func(t *testing.T, fixture string) response {
+		t.Helper()
+
+		body, err := os.ReadFile(filepath.Join("testdata", "gitlab", fixture))
+		require.NoError(t, err)
+		return send(t, e, "Merge Request Hook", body, gitlabToken)
+	}
+	linkedPR := func(t *testing.T, iid int64) *models.PullRequest {
+		t.Helper()
+
+		id, err := integrationRepo.GetPRID(t.Context(), models.ExternalPR{
+			Provider:   models.ProviderGitLab,
+			Repository: "311",
+			Number:     iid,
+		})
+		require.NoError(t, err)
+		pr, err := prRepo.GetByID(t.Context(), id)
+		require.NoError(t, err)
+		return pr
+	}
+	link := func(t *testing.T, body string) int {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodPost, "/integrations/gitlab/users", bytes.NewReader([]byte(body)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	t.Run("token", func(t *testing.T) {
+		body, err := os.ReadFile(filepath.Join("testdata", "gitlab", "merge_request_open.json"))
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusUnauthorized, send(t, e, "Merge Request Hook", body, "").code)
+		require.Equal(t, http.StatusUnauthorized, send(t, e, "Merge Request Hook", body, "wrong").code)
+
+		// without a configured token every delivery is rejected
+		unconfigured := newEcho(handler.WebhookSecrets{GitHub: githubSecret})
+		require.Equal(t, http.StatusUnauthorized, send(t, unconfigured, "Merge Request Hook", body, gitlabToken).code)
+	})
+
+	t.Run("other events", func(t *testing.T) {
+		body := []byte(`{"object_kind": "push"}`)
+		require.Equal(t, response{http.StatusOK, "ignored"}, send(t, e, "Push Hook", body, gitlabToken))
+	})
+
+	t.Run("unknown author", func(t *testing.T) {
+		require.Equal(t, http.StatusUnprocessableEntity, deliver(t, "merge_request_open.json").code)
+	})
+
+	t.Run("link user", func(t *testing.T) {
+		require.Equal(t, http.StatusBadRequest, link(t, `{"gitlab_user_id": 1042, "user_id": "not-a-uuid"}`))
+		require.Equal(t, http.StatusBadRequest, link(t, `{"gitlab_user_id": 0, "user_id": "`+alice.ID.String()+`"}`))
+		require.Equal(t, http.StatusNotFound, link(t, `{"gitlab_user_id": 1042, "user_id": "`+uuid.NewString()+`"}`))
+		require.Equal(t, http.StatusOK, link(t, `{"gitlab_user_id": 1042, "user_id": "`+alice.ID.String()+`"}`))
+	})
+
+	t.Run("open", func(t *testing.T) {
+		require.Equal(t, response{http.StatusOK, "created"}, deliver(t, "merge_request_open.json"))
+
+		pr := linkedPR(t, 17)
+		require.Equal(t, "Retry failed provider callbacks", pr.Name)
+		require.Equal(t, alice.ID, pr.AuthorID)
+		require.Equal(t, string(models.PRStatusOpen), pr.Status)
+		require.Len(t, pr.Reviewers, 2)
+
+		// redelivery
+		require.Equal(t, response{http.StatusOK, "unchanged"}, deliver(t, "merge_request_open.json"))
+		require.Equal(t, response{http.StatusOK, "ignored"}, deliver(t, "merge_request_update.json"))
+	})
+
+	t.Run("draft flow", func(t *testing.T) {
+		require.Equal(t, response{http.StatusOK, "ignored"}, deliver(t, "merge_request_open_draft.json"))
+		require.Equal(t, response{http.StatusOK, "created"}, deliver(t, "merge_request_update_ready.json"))
+		require.Equal(t, "Split ledger writer", linkedPR(t, 18).Name)
+
+		require.Equal(t, response{http.StatusOK, "ignored"}, deliver(t, "merge_request_close.json"))
+		require.Equal(t, response{http.StatusOK, "unchanged"}, deliver(t, "merge_request_reopen.json"))
+		require.Equal(t, string(models.PRStatusOpen), linkedPR(t, 18).Status)
+	})
+
+	t.Run("reopen before integration", func(t *testing.T) {
+		body, err := os.ReadFile(filepath.Join("testdata", "gitlab", "merge_request_reopen.json"))
+		require.NoError(t, err)
+		body = bytes.ReplaceAll(body, []byte(`"iid": 18`), []byte(`"iid": 19`))
+
+		require.Equal(t, response{http.StatusOK, "created"}, send(t, e, "Merge Request Hook", body, gitlabToken))
+		require.Equal(t, string(models.PRStatusOpen), linkedPR(t, 19).Status)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		require.Equal(t, response{http.StatusOK, "merged"}, deliver(t, "merge_request_merge.json"))
+
+		pr := linkedPR(t, 17)
+		require.Equal(t, string(models.PRStatusMerged), pr.Status)
+		require.NotNil(t, pr.MergedAt)
+	})
+
+	t.Run("invalid payload", func(t *testing.T) {
+		body := []byte(`{"object_kind": "merge_request", "object_attributes": {"iid": 7`)
+		require.Equal(t, http.StatusBadRequest, send(t, e, "Merge Request Hook", body, gitlabToken).code)
+
+		body = []byte(`{"object_kind": "merge_request", "object_attributes": {"action": "open"}}`)
+		require.Equal(t, http.StatusBadRequest, send(t, e, "Merge Request Hook", body, gitlabToken).code)
+	})
+}
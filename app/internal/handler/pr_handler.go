@@ -1,8 +1,12 @@
 package handler
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"time"
+
 	"pr-service/internal/api"
 	"pr-service/internal/models"
 	"pr-service/internal/repository"
@@ -18,8 +22,6 @@ type PRHandler struct {
 	log       *zap.Logger
 }
 
-var _ api.ServerInterface = (*PRHandler)(nil)
-
 func NewPRHandler(prService *service.PRService, log *zap.Logger) *PRHandler {
 	return &PRHandler{
 		prService: prService,
@@ -49,6 +51,9 @@ func (h *PRHandler) PostPullRequestCreate(c echo.Context) error {
 		Name:     prcBody.PullRequestName,
 		Status:   string(models.PRStatusOpen),
 	}
+	if prcBody.Files != nil {
+		pr.Files = *prcBody.Files
+	}
 
 	if err := h.prService.CreatePR(c.Request().Context(), pr); err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
@@ -66,6 +71,13 @@ func (h *PRHandler) PostPullRequestCreate(c echo.Context) error {
 			return c.JSON(http.StatusConflict, errResponse)
 		}
 
+		if errors.Is(err, service.ErrReviewPolicy) {
+			errResponse := api.ErrorResponse{}
+			errResponse.Error.Code = api.POLICYUNSATISFIED
+			errResponse.Error.Message = err.Error()
+			return c.JSON(http.StatusConflict, errResponse)
+		}
+
 		return c.JSON(http.StatusInternalServerError, "")
 	}
 
@@ -81,11 +93,13 @@ func (h *PRHandler) PostPullRequestCreate(c echo.Context) error {
 	for _, reviewer := range pr.Reviewers {
 		prResponse.AssignedReviewers = append(prResponse.AssignedReviewers, reviewer.ID.String())
 	}
+	prResponse.ReviewerPools = toAPIReviewerPools(pr.Reviewers)
 
+	setETag(c, pr)
 	return c.JSON(http.StatusCreated, prResponse)
 }
 
-func (h *PRHandler) PostPullRequestMerge(c echo.Context) error {
+func (h *PRHandler) PostPullRequestMerge(c echo.Context, params api.PostPullRequestMergeParams) error {
 	body := api.PostPullRequestMergeJSONBody{}
 
 	if err := c.Bind(&body); err != nil {
@@ -97,8 +111,16 @@ func (h *PRHandler) PostPullRequestMerge(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, "invalid id")
 	}
 
-	pr, err := h.prService.PRMerge(c.Request().Context(), prID)
+	ctx, ok := withIfMatch(c, params.IfMatch)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, "invalid If-Match")
+	}
+
+	pr, err := h.prService.PRMerge(ctx, prID)
 	if err != nil {
+		if errors.Is(err, service.ErrVersionConflict) {
+			return versionConflict(c, err)
+		}
 		if errors.Is(err, repository.ErrNotFound) {
 			errResponse := api.ErrorResponse{}
 			errResponse.Error.Code = "not_found"
@@ -121,11 +143,13 @@ func (h *PRHandler) PostPullRequestMerge(c echo.Context) error {
 	for _, reviewer := range pr.Reviewers {
 		prResponse.AssignedReviewers = append(prResponse.AssignedReviewers, reviewer.ID.String())
 	}
+	prResponse.ReviewerPools = toAPIReviewerPools(pr.Reviewers)
 
+	setETag(c, pr)
 	return c.JSON(http.StatusOK, prResponse)
 }
 
-func (h *PRHandler) PostPullRequestReassign(c echo.Context) error {
+func (h *PRHandler) PostPullRequestReassign(c echo.Context, params api.PostPullRequestReassignParams) error {
 	body := api.PostPullRequestReassignJSONBody{}
 
 	if err := c.Bind(&body); err != nil {
@@ -142,10 +166,69 @@ func (h *PRHandler) PostPullRequestReassign(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, "invalid old_user_id")
 	}
 
-	pr, err := h.prService.PRReassign(c.Request().Context(), prID, oldUserID)
+	opts := service.ReassignOptions{}
+	if body.NewUserId != nil {
+		opts.NewUserID, err = uuid.Parse(*body.NewUserId)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, "invalid new_user_id")
+		}
+	}
+	if body.ExcludeUserIds != nil {
+		for _, raw := range *body.ExcludeUserIds {
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, "invalid exclude_user_ids")
+			}
+			opts.Exclude = append(opts.Exclude, id)
+		}
+	}
+
+	ctx, ok := withIfMatch(c, params.IfMatch)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, "invalid If-Match")
+	}
+
+	pr, replacement, err := h.prService.PRReassign(ctx, prID, oldUserID, opts)
+	return reassignResponse(c, pr, replacement, err)
+}
+
+func (h *PRHandler) PostPullRequestDecline(c echo.Context, params api.PostPullRequestDeclineParams) error {
+	body := api.PostPullRequestDeclineJSONBody{}
+
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, "bad request")
+	}
+
+	prID, err := uuid.Parse(body.PullRequestId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "invalid pull_request_id")
+	}
+
+	userID, err := uuid.Parse(body.UserId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "invalid user_id")
+	}
+
+	ctx, ok := withIfMatch(c, params.IfMatch)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, "invalid If-Match")
+	}
+
+	pr, replacement, err := h.prService.PRDecline(ctx, prID, userID, models.DeclineReason(body.Reason))
+	if errors.Is(err, service.ErrInvalidDecline) {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	return reassignResponse(c, pr, replacement, err)
+}
+
+// reassignResponse writes the result of replacing a reviewer of the PR;
+// replaced_by is null when the reviewer was removed without a replacement.
+func reassignResponse(c echo.Context, pr *models.PullRequest, replacement *models.PRReviewer, err error) error {
 	if err != nil {
 		errResponse := api.ErrorResponse{}
 		switch {
+		case errors.Is(err, service.ErrInvalidReviewer):
+			return c.JSON(http.StatusBadRequest, err.Error())
 		case errors.Is(err, service.ErrCanNotReassing):
 			errResponse.Error.Code = api.PRMERGED
 			errResponse.Error.Message = "cannot reassign on merged PR"
@@ -158,6 +241,12 @@ func (h *PRHandler) PostPullRequestReassign(c echo.Context) error {
 			errResponse.Error.Code = api.NOCANDIDATE
 			errResponse.Error.Message = "no available reviewer found"
 			return c.JSON(http.StatusConflict, errResponse)
+		case errors.Is(err, service.ErrReviewPolicy):
+			errResponse.Error.Code = api.POLICYUNSATISFIED
+			errResponse.Error.Message = err.Error()
+			return c.JSON(http.StatusConflict, errResponse)
+		case errors.Is(err, service.ErrVersionConflict):
+			return versionConflict(c, err)
 		case errors.Is(err, repository.ErrNotFound):
 			errResponse.Error.Code = "not_found"
 			errResponse.Error.Message = "PR не найден"
@@ -167,8 +256,11 @@ func (h *PRHandler) PostPullRequestReassign(c echo.Context) error {
 		}
 	}
 
-	// new one allways last
-	replacedBy := pr.Reviewers[len(pr.Reviewers)-1].ID.String()
+	var replacedBy *string
+	if replacement != nil {
+		id := replacement.ID.String()
+		replacedBy = &id
+	}
 
 	prResponse := api.PullRequest{
 		PullRequestId:     pr.ID.String(),
@@ -182,22 +274,124 @@ func (h *PRHandler) PostPullRequestReassign(c echo.Context) error {
 	for _, reviewer := range pr.Reviewers {
 		prResponse.AssignedReviewers = append(prResponse.AssignedReviewers, reviewer.ID.String())
 	}
+	prResponse.ReviewerPools = toAPIReviewerPools(pr.Reviewers)
 
+	setETag(c, pr)
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"pr":          prResponse,
 		"replaced_by": replacedBy,
 	})
 }
 
+func (h *PRHandler) PostPullRequestReviewersSet(c echo.Context, params api.PostPullRequestReviewersSetParams) error {
+	return h.overrideReviewers(c, params.IfMatch, h.prService.PRSetReviewers)
+}
+
+func (h *PRHandler) PostPullRequestReviewersAdd(c echo.Context, params api.PostPullRequestReviewersAddParams) error {
+	return h.overrideReviewers(c, params.IfMatch, h.prService.PRAddReviewers)
+}
+
+func (h *PRHandler) PostPullRequestReviewersRemove(c echo.Context, params api.PostPullRequestReviewersRemoveParams) error {
+	return h.overrideReviewers(c, params.IfMatch, h.prService.PRRemoveReviewers)
+}
+
+// overrideReviewers applies a manual change of the reviewers and responds with the PR.
+func (h *PRHandler) overrideReviewers(
+	c echo.Context,
+	ifMatch *api.IfMatchHeader,
+	change func(ctx context.Context, prID uuid.UUID, ids []uuid.UUID) (*models.PullRequest, error),
+) error {
+	body := api.ReviewerOverride{}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, "bad request")
+	}
+
+	prID, err := uuid.Parse(body.PullRequestId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "invalid pull_request_id")
+	}
+
+	ids := make([]uuid.UUID, len(body.ReviewerIds))
+	for i, id := range body.ReviewerIds {
+		if ids[i], err = uuid.Parse(id); err != nil {
+			return c.JSON(http.StatusBadRequest, "invalid reviewer_ids")
+		}
+	}
+
+	ctx, ok := withIfMatch(c, ifMatch)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, "invalid If-Match")
+	}
+
+	pr, err := change(ctx, prID, ids)
+	if err != nil {
+		errResponse := api.ErrorResponse{}
+		switch {
+		case errors.Is(err, service.ErrInvalidReviewer):
+			return c.JSON(http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrCanNotReassing):
+			errResponse.Error.Code = api.PRMERGED
+			errResponse.Error.Message = "cannot change reviewers on merged PR"
+			return c.JSON(http.StatusConflict, errResponse)
+		case errors.Is(err, service.ErrReviewPolicy):
+			errResponse.Error.Code = api.POLICYUNSATISFIED
+			errResponse.Error.Message = err.Error()
+			return c.JSON(http.StatusConflict, errResponse)
+		case errors.Is(err, service.ErrVersionConflict):
+			return versionConflict(c, err)
+		case errors.Is(err, service.ErrNotAssinged):
+			errResponse.Error.Code = api.NOTASSIGNED
+			errResponse.Error.Message = "reviewer not assigned to PR"
+			return c.JSON(http.StatusNotFound, errResponse)
+		case errors.Is(err, repository.ErrNotFound):
+			errResponse.Error.Code = api.NOTFOUND
+			errResponse.Error.Message = "PR не найден"
+			return c.JSON(http.StatusNotFound, errResponse)
+		default:
+			return c.JSON(http.StatusInternalServerError, "")
+		}
+	}
+
+	prResponse := api.PullRequest{
+		PullRequestId:     pr.ID.String(),
+		AuthorId:          pr.AuthorID.String(),
+		PullRequestName:   pr.Name,
+		Status:            api.PullRequestStatus(pr.Status),
+		CreatedAt:         &pr.CreatedAt,
+		AssignedReviewers: []string{},
+	}
+
+	for _, reviewer := range pr.Reviewers {
+		prResponse.AssignedReviewers = append(prResponse.AssignedReviewers, reviewer.ID.String())
+	}
+	prResponse.ReviewerPools = toAPIReviewerPools(pr.Reviewers)
+
+	setETag(c, pr)
+	return c.JSON(http.StatusOK, echo.Map{
+		"pr": prResponse,
+	})
+}
+
 func (h *PRHandler) PostTeamAdd(c echo.Context) error {
 	body := &api.Team{}
 	if err := c.Bind(body); err != nil {
 		return c.JSON(http.StatusBadRequest, "bad request")
 	}
 
+	digest, err := toModelDigest(body.DigestSchedule)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "invalid digest_schedule")
+	}
+
 	team := &models.Team{
-		Name:    body.TeamName,
-		Members: make([]*models.User, len(body.Members)),
+		Name:      body.TeamName,
+		ReviewSLA: toModelSLA(body.ReviewSla),
+		Policy:    toModelPolicy(body.ReviewPolicy),
+		Digest:    digest,
+		Members:   make([]*models.User, len(body.Members)),
+	}
+	if body.ParentTeam != nil {
+		team.Parent = *body.ParentTeam
 	}
 
 	for i, m := range body.Members {
@@ -210,6 +404,12 @@ func (h *PRHandler) PostTeamAdd(c echo.Context) error {
 			Name:     m.Username,
 			IsActive: m.IsActive,
 		}
+		if m.Email != nil {
+			team.Members[i].Email = *m.Email
+		}
+		if m.Role != nil {
+			team.Members[i].Role = models.Role(*m.Role)
+		}
 	}
 
 	if err := h.prService.TeamAdd(c.Request().Context(), team); err != nil {
@@ -219,29 +419,30 @@ func (h *PRHandler) PostTeamAdd(c echo.Context) error {
 			errResp.Error.Message = "team_name already exists"
 			return c.JSON(http.StatusBadRequest, errResp)
 		}
-		return c.JSON(http.StatusInternalServerError, "")
-	}
-
-	resp := api.Team{
-		TeamName: team.Name,
-		Members:  make([]api.TeamMember, len(team.Members)),
-	}
-
-	for i, u := range team.Members {
-		resp.Members[i] = api.TeamMember{
-			UserId:   u.ID.String(),
-			Username: u.Name,
-			IsActive: u.IsActive,
+		if errors.Is(err, service.ErrInvalidSLA) {
+			return c.JSON(http.StatusBadRequest, "invalid review_sla")
+		}
+		if errors.Is(err, service.ErrInvalidSchedule) {
+			return c.JSON(http.StatusBadRequest, "invalid digest_schedule")
+		}
+		if errors.Is(err, service.ErrInvalidParent) || errors.Is(err, service.ErrInvalidRole) {
+			return c.JSON(http.StatusBadRequest, err.Error())
 		}
+		return c.JSON(http.StatusInternalServerError, "")
 	}
 
 	return c.JSON(http.StatusCreated, map[string]interface{}{
-		"team": resp,
+		"team": toAPITeam(team),
 	})
 }
 
 func (h *PRHandler) GetTeamGet(c echo.Context, params api.GetTeamGetParams) error {
-	team, err := h.prService.TeamGet(c.Request().Context(), params.TeamName)
+	get := h.prService.TeamGet
+	if params.IncludeSubteams != nil && *params.IncludeSubteams {
+		get = h.prService.TeamGetSubtree
+	}
+
+	team, err := get(c.Request().Context(), params.TeamName)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			errResp := api.ErrorResponse{}
@@ -252,20 +453,186 @@ func (h *PRHandler) GetTeamGet(c echo.Context, params api.GetTeamGetParams) erro
 		return c.JSON(http.StatusInternalServerError, "")
 	}
 
-	resp := api.Team{
-		TeamName: team.Name,
-		Members:  make([]api.TeamMember, len(team.Members)),
+	return c.JSON(http.StatusOK, toAPITeam(team))
+}
+
+func (h *PRHandler) PostTeamSetReviewSLA(c echo.Context) error {
+	body := api.PostTeamSetReviewSLAJSONBody{}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, "bad request")
 	}
 
-	for i, u := range team.Members {
-		resp.Members[i] = api.TeamMember{
-			UserId:   u.ID.String(),
-			Username: u.Name,
-			IsActive: u.IsActive,
+	team, err := h.prService.TeamSetReviewSLA(c.Request().Context(), body.TeamName, toModelSLA(body.ReviewSla))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidSLA):
+			return c.JSON(http.StatusBadRequest, "invalid review_sla")
+		case errors.Is(err, repository.ErrNotFound):
+			errResp := api.ErrorResponse{}
+			errResp.Error.Code = api.NOTFOUND
+			errResp.Error.Message = "team not found"
+			return c.JSON(http.StatusNotFound, errResp)
+		default:
+			return c.JSON(http.StatusInternalServerError, "")
 		}
 	}
 
-	return c.JSON(http.StatusOK, resp)
+	return c.JSON(http.StatusOK, echo.Map{
+		"team": toAPITeam(team),
+	})
+}
+
+func (h *PRHandler) PostTeamSetReviewPolicy(c echo.Context) error {
+	body := api.PostTeamSetReviewPolicyJSONBody{}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, "bad request")
+	}
+
+	team, err := h.prService.TeamSetReviewPolicy(c.Request().Context(), body.TeamName, toModelPolicy(body.ReviewPolicy))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidRole):
+			return c.JSON(http.StatusBadRequest, err.Error())
+		case errors.Is(err, repository.ErrNotFound):
+			errResp := api.ErrorResponse{}
+			errResp.Error.Code = api.NOTFOUND
+			errResp.Error.Message = "team not found"
+			return c.JSON(http.StatusNotFound, errResp)
+		default:
+			return c.JSON(http.StatusInternalServerError, "")
+		}
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"team": toAPITeam(team),
+	})
+}
+
+func (h *PRHandler) PostTeamSetDigestSchedule(c echo.Context) error {
+	body := api.PostTeamSetDigestScheduleJSONBody{}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, "bad request")
+	}
+
+	digest, err := toModelDigest(body.DigestSchedule)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "invalid digest_schedule")
+	}
+
+	team, err := h.prService.TeamSetDigestSchedule(c.Request().Context(), body.TeamName, digest)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidSchedule):
+			return c.JSON(http.StatusBadRequest, "invalid digest_schedule")
+		case errors.Is(err, repository.ErrNotFound):
+			errResp := api.ErrorResponse{}
+			errResp.Error.Code = api.NOTFOUND
+			errResp.Error.Message = "team not found"
+			return c.JSON(http.StatusNotFound, errResp)
+		default:
+			return c.JSON(http.StatusInternalServerError, "")
+		}
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"team": toAPITeam(team),
+	})
+}
+
+func (h *PRHandler) PostTeamSetFallbackPools(c echo.Context) error {
+	body := api.PostTeamSetFallbackPoolsJSONBody{}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, "bad request")
+	}
+
+	team, err := h.prService.TeamSetFallbackPools(c.Request().Context(), body.TeamName, body.FallbackTeams)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidFallback):
+			return c.JSON(http.StatusBadRequest, err.Error())
+		case errors.Is(err, repository.ErrNotFound):
+			errResp := api.ErrorResponse{}
+			errResp.Error.Code = api.NOTFOUND
+			errResp.Error.Message = "team not found"
+			return c.JSON(http.StatusNotFound, errResp)
+		default:
+			return c.JSON(http.StatusInternalServerError, "")
+		}
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"team": toAPITeam(team),
+	})
+}
+
+func (h *PRHandler) PostTeamSetParent(c echo.Context) error {
+	body := api.PostTeamSetParentJSONBody{}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, "bad request")
+	}
+
+	var parentName string
+	if body.ParentTeam != nil {
+		parentName = *body.ParentTeam
+	}
+
+	team, err := h.prService.TeamSetParent(c.Request().Context(), body.TeamName, parentName)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidParent):
+			return c.JSON(http.StatusBadRequest, err.Error())
+		case errors.Is(err, repository.ErrNotFound):
+			errResp := api.ErrorResponse{}
+			errResp.Error.Code = api.NOTFOUND
+			errResp.Error.Message = "team not found"
+			return c.JSON(http.StatusNotFound, errResp)
+		default:
+			return c.JSON(http.StatusInternalServerError, "")
+		}
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"team": toAPITeam(team),
+	})
+}
+
+func (h *PRHandler) PostTeamSetCodeOwners(c echo.Context) error {
+	body := api.PostTeamSetCodeOwnersJSONBody{}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, "bad request")
+	}
+
+	rules, err := h.prService.TeamSetCodeOwners(c.Request().Context(), body.TeamName, body.Codeowners)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidCodeOwners):
+			return c.JSON(http.StatusBadRequest, err.Error())
+		case errors.Is(err, repository.ErrNotFound):
+			errResp := api.ErrorResponse{}
+			errResp.Error.Code = api.NOTFOUND
+			errResp.Error.Message = "team not found"
+			return c.JSON(http.StatusNotFound, errResp)
+		default:
+			return c.JSON(http.StatusInternalServerError, "")
+		}
+	}
+
+	return c.JSON(http.StatusOK, toAPICodeOwners(body.TeamName, rules))
+}
+
+func (h *PRHandler) GetTeamCodeOwners(c echo.Context, params api.GetTeamCodeOwnersParams) error {
+	rules, err := h.prService.TeamGetCodeOwners(c.Request().Context(), params.TeamName)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			errResp := api.ErrorResponse{}
+			errResp.Error.Code = api.NOTFOUND
+			errResp.Error.Message = "team not found"
+			return c.JSON(http.StatusNotFound, errResp)
+		}
+		return c.JSON(http.StatusInternalServerError, "")
+	}
+
+	return c.JSON(http.StatusOK, toAPICodeOwners(params.TeamName, rules))
 }
 
 func (h *PRHandler) GetUsersGetReview(c echo.Context, params api.GetUsersGetReviewParams) error {
@@ -298,6 +665,7 @@ func (h *PRHandler) GetUsersGetReview(c echo.Context, params api.GetUsersGetRevi
 			PullRequestName: pr.Name,
 			AuthorId:        pr.AuthorID.String(),
 			Status:          api.PullRequestShortStatus(pr.Status),
+			Version:         pr.Version,
 		}
 	}
 
@@ -338,11 +706,206 @@ func (h *PRHandler) PostUsersSetIsActive(c echo.Context) error {
 	}
 
 	return c.JSON(http.StatusOK, echo.Map{
-		"user": api.User{
-			UserId:   user.ID.String(),
-			Username: user.Name,
-			IsActive: user.IsActive,
-			TeamName: team.Name,
-		},
+		"user": toAPIUser(user, team),
 	})
 }
+
+func (h *PRHandler) PostUsersSetRole(c echo.Context) error {
+	req := api.PostUsersSetRoleJSONBody{}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, "bad request")
+	}
+
+	id, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "invalid id")
+	}
+
+	user, err := h.prService.UsersSetRole(c.Request().Context(), id, models.Role(req.Role))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidRole):
+			return c.JSON(http.StatusBadRequest, err.Error())
+		case errors.Is(err, repository.ErrNotFound):
+			errResp := api.ErrorResponse{}
+			errResp.Error.Code = api.NOTFOUND
+			errResp.Error.Message = "user not found"
+			return c.JSON(http.StatusNotFound, errResp)
+		default:
+			return c.JSON(http.StatusInternalServerError, "")
+		}
+	}
+
+	var team *models.Team
+	if user.TeamID != nil {
+		team, err = h.prService.TeamGetByID(c.Request().Context(), *user.TeamID)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, "")
+		}
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"user": toAPIUser(user, team),
+	})
+}
+
+// toAPIUser converts the user of the team; team is nil for a user without one.
+func toAPIUser(user *models.User, team *models.Team) api.User {
+	resp := api.User{
+		UserId:   user.ID.String(),
+		Username: user.Name,
+		IsActive: user.IsActive,
+	}
+	if team != nil {
+		resp.TeamName = team.Name
+	}
+	if user.Role != "" {
+		role := string(user.Role)
+		resp.Role = &role
+	}
+	return resp
+}
+
+func toModelSLA(sla *api.ReviewSLA) *models.ReviewSLA {
+	if sla == nil {
+		return nil
+	}
+
+	return &models.ReviewSLA{
+		Timeout: time.Duration(sla.TimeoutSeconds) * time.Second,
+		Action:  models.SLAAction(sla.Action),
+	}
+}
+
+func toModelPolicy(p *api.ReviewPolicy) *models.ReviewPolicy {
+	if p == nil {
+		return nil
+	}
+
+	return &models.ReviewPolicy{MinRole: models.Role(p.MinRole)}
+}
+
+func toAPIPolicy(p *models.ReviewPolicy) *api.ReviewPolicy {
+	if p == nil {
+		return nil
+	}
+
+	return &api.ReviewPolicy{MinRole: string(p.MinRole)}
+}
+
+func toAPISLA(sla *models.ReviewSLA) *api.ReviewSLA {
+	if sla == nil {
+		return nil
+	}
+
+	return &api.ReviewSLA{
+		TimeoutSeconds: int(sla.Timeout / time.Second),
+		Action:         api.ReviewSLAAction(sla.Action),
+	}
+}
+
+func toAPITeam(team *models.Team) api.Team {
+	resp := api.Team{
+		TeamName:       team.Name,
+		ReviewSla:      toAPISLA(team.ReviewSLA),
+		ReviewPolicy:   toAPIPolicy(team.Policy),
+		DigestSchedule: toAPIDigest(team.Digest),
+		Members:        make([]api.TeamMember, len(team.Members)),
+	}
+	if len(team.Fallback) > 0 {
+		resp.FallbackTeams = &team.Fallback
+	}
+	if team.Parent != "" {
+		resp.ParentTeam = &team.Parent
+	}
+	if team.SubTeams != nil {
+		subTeams := make([]api.Team, len(team.SubTeams))
+		for i, t := range team.SubTeams {
+			subTeams[i] = toAPITeam(t)
+		}
+		resp.SubTeams = &subTeams
+	}
+
+	for i, u := range team.Members {
+		resp.Members[i] = api.TeamMember{
+			UserId:   u.ID.String(),
+			Username: u.Name,
+			IsActive: u.IsActive,
+		}
+		if u.Email != "" {
+			resp.Members[i].Email = &u.Email
+		}
+		if u.Role != "" {
+			role := string(u.Role)
+			resp.Members[i].Role = &role
+		}
+	}
+
+	return resp
+}
+
+func toAPIReviewerPools(reviewers []*models.PRReviewer) *[]api.ReviewerPool {
+	pools := make([]api.ReviewerPool, len(reviewers))
+	for i, r := range reviewers {
+		pools[i] = api.ReviewerPool{
+			UserId: r.ID.String(),
+			Pool:   string(r.Pool),
+		}
+		if r.PoolTeam != "" {
+			pools[i].TeamName = &r.PoolTeam
+		}
+	}
+
+	return &pools
+}
+
+func toAPICodeOwners(teamName string, rules []models.CodeOwnerRule) api.TeamCodeOwners {
+	resp := api.TeamCodeOwners{
+		TeamName: teamName,
+		Rules:    make([]api.CodeOwnerRule, len(rules)),
+	}
+
+	for i, rule := range rules {
+		resp.Rules[i] = api.CodeOwnerRule{
+			Pattern: rule.Pattern,
+			Owners:  make([]string, len(rule.Owners)),
+		}
+		for j, o := range rule.Owners {
+			resp.Rules[i].Owners[j] = o.Ref
+		}
+	}
+
+	return resp
+}
+
+// toModelDigest parses the HH:MM local time and the IANA time zone of a schedule.
+func toModelDigest(d *api.DigestSchedule) (*models.DigestSchedule, error) {
+	if d == nil {
+		return nil, nil
+	}
+
+	at, err := time.Parse("15:04", d.Time)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(d.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DigestSchedule{
+		At:       time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute,
+		Location: loc,
+	}, nil
+}
+
+func toAPIDigest(d *models.DigestSchedule) *api.DigestSchedule {
+	if d == nil {
+		return nil
+	}
+
+	return &api.DigestSchedule{
+		Time:     fmt.Sprintf("%02d:%02d", int(d.At/time.Hour), int(d.At%time.Hour/time.Minute)),
+		Timezone: d.Location.String(),
+	}
+}
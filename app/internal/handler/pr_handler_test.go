@@ -0,0 +1,131 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pr-service/internal/api"
+	"pr-service/internal/clock/clocktest"
+	"pr-service/internal/handler"
+	"pr-service/internal/models"
+	"pr-service/internal/repository/memory"
+	"pr-service/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newPRServer serves the PR handler over memory repositories, with a PR by
+// Alice that Bob, her only teammate, reviews.
+func newPRServer(t *testing.T) (*echo.Echo, *models.PullRequest, *models.User) {
+	t.Helper()
+
+	store := memory.NewStore()
+	clk := clocktest.NewFake(time.Date(2025, 10, 24, 12, 0, 0, 0, time.UTC))
+	prService := service.NewPRService(
+		memory.NewTeamRepository(store),
+		memory.NewUserRepository(store),
+		memory.NewPRRepository(store, clk),
+		nil,
+		memory.NewTxManager(store),
+		clk,
+		zap.NewNop(),
+	)
+
+	e := echo.New()
+	api.RegisterHandlers(e, handler.NewServer(handler.NewPRHandler(prService, zap.NewNop()), nil, nil, nil))
+
+	team := &models.Team{
+		Name: "payments",
+		Members: []*models.User{
+			{Name: "Alice", IsActive: true},
+			{Name: "Bob", IsActive: true},
+		},
+	}
+	require.NoError(t, prService.TeamAdd(t.Context(), team))
+	alice, bob := team.Members[0], team.Members[1]
+
+	pr := &models.PullRequest{ID: uuid.New(), Name: "Add search", AuthorID: alice.ID, Status: string(models.PRStatusOpen)}
+	require.NoError(t, prService.CreatePR(t.Context(), pr))
+	require.Len(t, pr.Reviewers, 1)
+	require.Equal(t, bob.ID, pr.Reviewers[0].ID)
+
+	return e, pr, bob
+}
+
+func serve(t *testing.T, e *echo.Echo, req *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func postJSON(t *testing.T, e *echo.Echo, path string, body any, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+
+	data, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(data))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	return serve(t, e, req)
+}
+
+func TestPRHandler_DeclineOnlyReviewer(t *testing.T) {
+	e, pr, bob := newPRServer(t)
+
+	rec := postJSON(t, e, "/pullRequest/decline", api.PostPullRequestDeclineJSONBody{
+		PullRequestId: pr.ID.String(),
+		UserId:        bob.ID.String(),
+		Reason:        string(models.DeclineBusy),
+	}, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp struct {
+		PR         api.PullRequest `json:"pr"`
+		ReplacedBy *string         `json:"replaced_by"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Nil(t, resp.ReplacedBy)
+	require.Empty(t, resp.PR.AssignedReviewers)
+	require.Contains(t, rec.Body.String(), `"replaced_by":null`)
+}
+
+func TestPRHandler_ReviewListVersion(t *testing.T) {
+	e, pr, bob := newPRServer(t)
+
+	getReview := func(t *testing.T) api.PullRequestShort {
+		t.Helper()
+
+		rec := serve(t, e, httptest.NewRequest(http.MethodGet, "/users/getReview?user_id="+bob.ID.String(), nil))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var resp struct {
+			PullRequests []api.PullRequestShort `json:"pull_requests"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		require.Len(t, resp.PullRequests, 1)
+		return resp.PullRequests[0]
+	}
+
+	listed := getReview(t)
+	require.Equal(t, int64(1), listed.Version)
+
+	// the listed version is what a change is conditioned on
+	merge := api.PostPullRequestMergeJSONBody{PullRequestId: pr.ID.String()}
+	rec := postJSON(t, e, "/pullRequest/merge", merge, http.Header{"If-Match": {`"1"`}})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.Equal(t, `"2"`, rec.Header().Get("ETag"))
+
+	require.Equal(t, int64(2), getReview(t).Version)
+}
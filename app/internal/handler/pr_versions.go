@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"pr-service/internal/api"
+	"pr-service/internal/models"
+	"pr-service/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+// withIfMatch returns the request context that expects the PR version sent
+// in If-Match; without the header, or with "*", any version is accepted.
+func withIfMatch(c echo.Context, ifMatch *api.IfMatchHeader) (context.Context, bool) {
+	ctx := c.Request().Context()
+	if ifMatch == nil || *ifMatch == "*" {
+		return ctx, true
+	}
+
+	version, err := strconv.ParseInt(strings.Trim(*ifMatch, `"`), 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	return service.WithExpectedVersion(ctx, version), true
+}
+
+// setETag sends the PR version as a strong ETag.
+func setETag(c echo.Context, pr *models.PullRequest) {
+	c.Response().Header().Set("ETag", strconv.Quote(strconv.FormatInt(pr.Version, 10)))
+}
+
+// versionConflict writes the response for service.ErrVersionConflict.
+func versionConflict(c echo.Context, err error) error {
+	errResponse := api.ErrorResponse{}
+	errResponse.Error.Code = api.VERSIONCONFLICT
+	errResponse.Error.Message = err.Error()
+	return c.JSON(http.StatusPreconditionFailed, errResponse)
+}
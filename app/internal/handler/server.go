@@ -0,0 +1,27 @@
+package handler
+
+import "pr-service/internal/api"
+
+// Server combines the handlers into the generated api.ServerInterface.
+type Server struct {
+	*PRHandler
+	*StatsHandler
+	*AvailabilityHandler
+	*IntegrationHandler
+}
+
+var _ api.ServerInterface = (*Server)(nil)
+
+func NewServer(
+	prHandler *PRHandler,
+	statsHandler *StatsHandler,
+	availabilityHandler *AvailabilityHandler,
+	integrationHandler *IntegrationHandler,
+) *Server {
+	return &Server{
+		PRHandler:           prHandler,
+		StatsHandler:        statsHandler,
+		AvailabilityHandler: availabilityHandler,
+		IntegrationHandler:  integrationHandler,
+	}
+}
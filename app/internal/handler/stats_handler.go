@@ -0,0 +1,338 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"pr-service/internal/api"
+	"pr-service/internal/models"
+	"pr-service/internal/service"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type StatsHandler struct {
+	statsService *service.StatsService
+	log          *zap.Logger
+}
+
+func NewStatsHandler(statsService *service.StatsService, log *zap.Logger) *StatsHandler {
+	return &StatsHandler{
+		statsService: statsService,
+		log:          log,
+	}
+}
+
+func (h *StatsHandler) GetStatsReviewers(c echo.Context, params api.GetStatsReviewersParams) error {
+	var from, to time.Time
+	if params.From != nil {
+		from = *params.From
+	}
+	if params.To != nil {
+		to = *params.To
+	}
+	var teamName string
+	if params.TeamName != nil {
+		teamName = *params.TeamName
+	}
+
+	report, err := h.statsService.ReviewerStats(c.Request().Context(), from, to, teamName)
+	if err != nil {
+		return statsError(c, err)
+	}
+
+	resp := struct {
+		From  time.Time             `json:"from"`
+		To    time.Time             `json:"to"`
+		Users []api.UserReviewStats `json:"users"`
+		Teams []api.TeamReviewStats `json:"teams"`
+	}{
+		From:  report.From,
+		To:    report.To,
+		Users: make([]api.UserReviewStats, len(report.Users)),
+		Teams: make([]api.TeamReviewStats, len(report.Teams)),
+	}
+
+	for i, u := range report.Users {
+		resp.Users[i] = api.UserReviewStats{
+			UserId:                   u.UserID.String(),
+			Username:                 u.Username,
+			TeamName:                 u.TeamName,
+			OpenAssigned:             u.OpenAssigned,
+			AssignedTotal:            u.AssignedTotal,
+			ReassignedAway:           u.ReassignedAway,
+			Declined:                 u.Declined,
+			DeclineRate:              u.DeclineRate(),
+			MedianTimeToMergeSeconds: durationSeconds(u.MedianTimeToMerge),
+		}
+	}
+
+	for i, t := range report.Teams {
+		resp.Teams[i] = api.TeamReviewStats{
+			TeamName:                 t.TeamName,
+			OpenAssigned:             t.OpenAssigned,
+			AssignedTotal:            t.AssignedTotal,
+			ReassignedAway:           t.ReassignedAway,
+			Declined:                 t.Declined,
+			DeclineRate:              t.DeclineRate(),
+			MedianTimeToMergeSeconds: durationSeconds(t.MedianTimeToMerge),
+			Subtree: api.ReviewLoad{
+				OpenAssigned:             t.Subtree.OpenAssigned,
+				AssignedTotal:            t.Subtree.AssignedTotal,
+				ReassignedAway:           t.Subtree.ReassignedAway,
+				Declined:                 t.Subtree.Declined,
+				DeclineRate:              t.Subtree.DeclineRate(),
+				MedianTimeToMergeSeconds: durationSeconds(t.Subtree.MedianTimeToMerge),
+			},
+		}
+		if t.ParentName != "" {
+			resp.Teams[i].ParentTeamName = &t.ParentName
+		}
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// statsError writes the response for an error of the stats service.
+func statsError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidWindow):
+		return c.JSON(http.StatusBadRequest, "invalid window")
+	case errors.Is(err, service.ErrNotFound):
+		errResp := api.ErrorResponse{}
+		errResp.Error.Code = api.NOTFOUND
+		errResp.Error.Message = "team not found"
+		return c.JSON(http.StatusNotFound, errResp)
+	default:
+		return c.JSON(http.StatusInternalServerError, "")
+	}
+}
+
+func durationSeconds(d *time.Duration) *float64 {
+	if d == nil {
+		return nil
+	}
+
+	s := d.Seconds()
+	return &s
+}
+
+func (h *StatsHandler) GetStatsPrs(c echo.Context, params api.GetStatsPrsParams) error {
+	var from, to time.Time
+	if params.From != nil {
+		from = *params.From
+	}
+	if params.To != nil {
+		to = *params.To
+	}
+	var teamName string
+	if params.TeamName != nil {
+		teamName = *params.TeamName
+	}
+
+	if params.Export != nil {
+		return h.exportPRs(c, *params.Export, from, to, teamName)
+	}
+
+	report, err := h.statsService.PRStats(c.Request().Context(), from, to, teamName)
+	if err != nil {
+		return statsError(c, err)
+	}
+
+	resp := struct {
+		From   time.Time          `json:"from"`
+		To     time.Time          `json:"to"`
+		AsOf   time.Time          `json:"as_of"`
+		Groups []api.PRCycleGroup `json:"groups"`
+	}{
+		From:   report.From,
+		To:     report.To,
+		AsOf:   report.AsOf,
+		Groups: make([]api.PRCycleGroup, len(report.Groups)),
+	}
+
+	for i, g := range report.Groups {
+		resp.Groups[i] = api.PRCycleGroup{
+			TeamName:              g.TeamName,
+			WeekStart:             g.WeekStart,
+			Created:               g.Created,
+			Merged:                g.Merged,
+			Open:                  g.Open,
+			TimeToFirstAssignment: toAPIPercentiles(g.TimeToFirstAssignment),
+			TimeToMerge:           toAPIPercentiles(g.TimeToMerge),
+			OpenAge:               toAPIPercentiles(g.OpenAge),
+		}
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// exportFlushEvery is the number of exported rows between flushes to the client.
+const exportFlushEvery = 100
+
+// csvHeader is the column order of the CSV export, matching api.PRCycleRow.
+var csvHeader = []string{
+	"pull_request_id",
+	"pull_request_name",
+	"author_id",
+	"team_name",
+	"status",
+	"created_at",
+	"first_assigned_at",
+	"merged_at",
+}
+
+// rowEncoder writes export rows in one format.
+type rowEncoder struct {
+	contentType string
+	header      func() error
+	write       func(*models.PRCycle) error
+	flush       func() error
+}
+
+func newRowEncoder(format api.GetStatsPrsParamsExport, w io.Writer) (*rowEncoder, bool) {
+	switch format {
+	case api.GetStatsPrsParamsExportCsv:
+		cw := csv.NewWriter(w)
+		return &rowEncoder{
+			contentType: "text/csv; charset=utf-8",
+			header:      func() error { return cw.Write(csvHeader) },
+			write:       func(pr *models.PRCycle) error { return cw.Write(csvRow(pr)) },
+			flush: func() error {
+				cw.Flush()
+				return cw.Error()
+			},
+		}, true
+
+	case api.GetStatsPrsParamsExportNdjson:
+		enc := json.NewEncoder(w)
+		return &rowEncoder{
+			contentType: "application/x-ndjson",
+			header:      func() error { return nil },
+			write:       func(pr *models.PRCycle) error { return enc.Encode(toAPICycleRow(pr)) },
+			flush:       func() error { return nil },
+		}, true
+
+	default:
+		return nil, false
+	}
+}
+
+// exportPRs streams the raw rows. The status and headers are sent with the
+// first row, so errors before it still get a proper status code; a failure
+// mid-stream can only cut the body short.
+func (h *StatsHandler) exportPRs(
+	c echo.Context,
+	format api.GetStatsPrsParamsExport,
+	from, to time.Time,
+	teamName string,
+) error {
+	res := c.Response()
+
+	enc, ok := newRowEncoder(format, res)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, "invalid export format")
+	}
+
+	start := func() error {
+		res.Header().Set(echo.HeaderContentType, enc.contentType)
+		res.WriteHeader(http.StatusOK)
+		return enc.header()
+	}
+
+	rows := 0
+	err := h.statsService.ExportPRs(c.Request().Context(), from, to, teamName, func(pr *models.PRCycle) error {
+		if !res.Committed {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+
+		if err := enc.write(pr); err != nil {
+			return err
+		}
+
+		if rows++; rows%exportFlushEvery == 0 {
+			if err := enc.flush(); err != nil {
+				return err
+			}
+			res.Flush()
+		}
+		return nil
+	})
+
+	if err != nil && !res.Committed {
+		return statsError(c, err)
+	}
+	if err != nil {
+		h.log.Error("PR export aborted", zap.Error(err), zap.Int("rows", rows))
+		return nil
+	}
+
+	if !res.Committed {
+		if err := start(); err != nil {
+			return err
+		}
+	}
+	return enc.flush()
+}
+
+func csvRow(pr *models.PRCycle) []string {
+	return []string{
+		pr.PRID.String(),
+		pr.Name,
+		pr.AuthorID.String(),
+		pr.TeamName,
+		pr.Status,
+		pr.CreatedAt.UTC().Format(time.RFC3339),
+		formatOptionalTime(pr.FirstAssignedAt),
+		formatOptionalTime(pr.MergedAt),
+	}
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func toAPICycleRow(pr *models.PRCycle) api.PRCycleRow {
+	return api.PRCycleRow{
+		PullRequestId:   pr.PRID.String(),
+		PullRequestName: pr.Name,
+		AuthorId:        pr.AuthorID.String(),
+		TeamName:        pr.TeamName,
+		Status:          pr.Status,
+		CreatedAt:       pr.CreatedAt.UTC(),
+		FirstAssignedAt: utcPtr(pr.FirstAssignedAt),
+		MergedAt:        utcPtr(pr.MergedAt),
+	}
+}
+
+func utcPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	u := t.UTC()
+	return &u
+}
+
+func toAPIPercentiles(p *models.DurationPercentiles) *api.DurationPercentiles {
+	if p == nil {
+		return nil
+	}
+
+	return &api.DurationPercentiles{
+		Count:      p.Count,
+		P50Seconds: p.P50.Seconds(),
+		P75Seconds: p.P75.Seconds(),
+		P90Seconds: p.P90.Seconds(),
+	}
+}
@@ -13,6 +13,7 @@ import (
 	context "context"
 	models "pr-service/internal/models"
 	reflect "reflect"
+	time "time"
 
 	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
@@ -86,6 +87,149 @@ func (mr *MockTeamRepositoryMockRecorder) GetByName(ctx, name any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByName", reflect.TypeOf((*MockTeamRepository)(nil).GetByName), ctx, name)
 }
 
+// GetCodeOwners mocks base method.
+func (m *MockTeamRepository) GetCodeOwners(ctx context.Context, id uuid.UUID) ([]models.CodeOwnerRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCodeOwners", ctx, id)
+	ret0, _ := ret[0].([]models.CodeOwnerRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCodeOwners indicates an expected call of GetCodeOwners.
+func (mr *MockTeamRepositoryMockRecorder) GetCodeOwners(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCodeOwners", reflect.TypeOf((*MockTeamRepository)(nil).GetCodeOwners), ctx, id)
+}
+
+// GetFallbackPools mocks base method.
+func (m *MockTeamRepository) GetFallbackPools(ctx context.Context, id uuid.UUID) ([]*models.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFallbackPools", ctx, id)
+	ret0, _ := ret[0].([]*models.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFallbackPools indicates an expected call of GetFallbackPools.
+func (mr *MockTeamRepositoryMockRecorder) GetFallbackPools(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFallbackPools", reflect.TypeOf((*MockTeamRepository)(nil).GetFallbackPools), ctx, id)
+}
+
+// GetSubtree mocks base method.
+func (m *MockTeamRepository) GetSubtree(ctx context.Context, id uuid.UUID) ([]*models.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubtree", ctx, id)
+	ret0, _ := ret[0].([]*models.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubtree indicates an expected call of GetSubtree.
+func (mr *MockTeamRepositoryMockRecorder) GetSubtree(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubtree", reflect.TypeOf((*MockTeamRepository)(nil).GetSubtree), ctx, id)
+}
+
+// LockHierarchy mocks base method.
+func (m *MockTeamRepository) LockHierarchy(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockHierarchy", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockHierarchy indicates an expected call of LockHierarchy.
+func (mr *MockTeamRepositoryMockRecorder) LockHierarchy(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockHierarchy", reflect.TypeOf((*MockTeamRepository)(nil).LockHierarchy), ctx)
+}
+
+// SetCodeOwners mocks base method.
+func (m *MockTeamRepository) SetCodeOwners(ctx context.Context, id uuid.UUID, rules []models.CodeOwnerRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCodeOwners", ctx, id, rules)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCodeOwners indicates an expected call of SetCodeOwners.
+func (mr *MockTeamRepositoryMockRecorder) SetCodeOwners(ctx, id, rules any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCodeOwners", reflect.TypeOf((*MockTeamRepository)(nil).SetCodeOwners), ctx, id, rules)
+}
+
+// SetDigestSchedule mocks base method.
+func (m *MockTeamRepository) SetDigestSchedule(ctx context.Context, id uuid.UUID, d *models.DigestSchedule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDigestSchedule", ctx, id, d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDigestSchedule indicates an expected call of SetDigestSchedule.
+func (mr *MockTeamRepositoryMockRecorder) SetDigestSchedule(ctx, id, d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDigestSchedule", reflect.TypeOf((*MockTeamRepository)(nil).SetDigestSchedule), ctx, id, d)
+}
+
+// SetFallbackPools mocks base method.
+func (m *MockTeamRepository) SetFallbackPools(ctx context.Context, id uuid.UUID, poolIDs []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFallbackPools", ctx, id, poolIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetFallbackPools indicates an expected call of SetFallbackPools.
+func (mr *MockTeamRepositoryMockRecorder) SetFallbackPools(ctx, id, poolIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFallbackPools", reflect.TypeOf((*MockTeamRepository)(nil).SetFallbackPools), ctx, id, poolIDs)
+}
+
+// SetParent mocks base method.
+func (m *MockTeamRepository) SetParent(ctx context.Context, id uuid.UUID, parentID *uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetParent", ctx, id, parentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetParent indicates an expected call of SetParent.
+func (mr *MockTeamRepositoryMockRecorder) SetParent(ctx, id, parentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetParent", reflect.TypeOf((*MockTeamRepository)(nil).SetParent), ctx, id, parentID)
+}
+
+// SetReviewPolicy mocks base method.
+func (m *MockTeamRepository) SetReviewPolicy(ctx context.Context, id uuid.UUID, p *models.ReviewPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReviewPolicy", ctx, id, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetReviewPolicy indicates an expected call of SetReviewPolicy.
+func (mr *MockTeamRepositoryMockRecorder) SetReviewPolicy(ctx, id, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReviewPolicy", reflect.TypeOf((*MockTeamRepository)(nil).SetReviewPolicy), ctx, id, p)
+}
+
+// SetReviewSLA mocks base method.
+func (m *MockTeamRepository) SetReviewSLA(ctx context.Context, id uuid.UUID, sla *models.ReviewSLA) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReviewSLA", ctx, id, sla)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetReviewSLA indicates an expected call of SetReviewSLA.
+func (mr *MockTeamRepositoryMockRecorder) SetReviewSLA(ctx, id, sla any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReviewSLA", reflect.TypeOf((*MockTeamRepository)(nil).SetReviewSLA), ctx, id, sla)
+}
+
 // MockUserRepository is a mock of UserRepository interface.
 type MockUserRepository struct {
 	ctrl     *gomock.Controller
@@ -139,6 +283,36 @@ func (mr *MockUserRepositoryMockRecorder) GetActiveByTeam(ctx, teamID any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveByTeam", reflect.TypeOf((*MockUserRepository)(nil).GetActiveByTeam), ctx, teamID)
 }
 
+// GetAvailableByTeam mocks base method.
+func (m *MockUserRepository) GetAvailableByTeam(ctx context.Context, teamID uuid.UUID, at time.Time) ([]*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAvailableByTeam", ctx, teamID, at)
+	ret0, _ := ret[0].([]*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAvailableByTeam indicates an expected call of GetAvailableByTeam.
+func (mr *MockUserRepositoryMockRecorder) GetAvailableByTeam(ctx, teamID, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailableByTeam", reflect.TypeOf((*MockUserRepository)(nil).GetAvailableByTeam), ctx, teamID, at)
+}
+
+// GetBySubtree mocks base method.
+func (m *MockUserRepository) GetBySubtree(ctx context.Context, teamID uuid.UUID) ([]*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBySubtree", ctx, teamID)
+	ret0, _ := ret[0].([]*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBySubtree indicates an expected call of GetBySubtree.
+func (mr *MockUserRepositoryMockRecorder) GetBySubtree(ctx, teamID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySubtree", reflect.TypeOf((*MockUserRepository)(nil).GetBySubtree), ctx, teamID)
+}
+
 // GetByTeam mocks base method.
 func (m *MockUserRepository) GetByTeam(ctx context.Context, teamID uuid.UUID) ([]*models.User, error) {
 	m.ctrl.T.Helper()
@@ -169,6 +343,21 @@ func (mr *MockUserRepositoryMockRecorder) GetUserByID(ctx, id any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockUserRepository)(nil).GetUserByID), ctx, id)
 }
 
+// ListByEmail mocks base method.
+func (m *MockUserRepository) ListByEmail(ctx context.Context, email string) ([]*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByEmail", ctx, email)
+	ret0, _ := ret[0].([]*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByEmail indicates an expected call of ListByEmail.
+func (mr *MockUserRepositoryMockRecorder) ListByEmail(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByEmail", reflect.TypeOf((*MockUserRepository)(nil).ListByEmail), ctx, email)
+}
+
 // UpdateActive mocks base method.
 func (m *MockUserRepository) UpdateActive(ctx context.Context, id uuid.UUID, active bool) error {
 	m.ctrl.T.Helper()
@@ -183,6 +372,20 @@ func (mr *MockUserRepositoryMockRecorder) UpdateActive(ctx, id, active any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateActive", reflect.TypeOf((*MockUserRepository)(nil).UpdateActive), ctx, id, active)
 }
 
+// UpdateRole mocks base method.
+func (m *MockUserRepository) UpdateRole(ctx context.Context, id uuid.UUID, role models.Role) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", ctx, id, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockUserRepositoryMockRecorder) UpdateRole(ctx, id, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockUserRepository)(nil).UpdateRole), ctx, id, role)
+}
+
 // MockPRRepository is a mock of PRRepository interface.
 type MockPRRepository struct {
 	ctrl     *gomock.Controller
@@ -207,8 +410,22 @@ func (m *MockPRRepository) EXPECT() *MockPRRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddReviewer mocks base method.
+func (m *MockPRRepository) AddReviewer(ctx context.Context, prID uuid.UUID, reviewer *models.PRReviewer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddReviewer", ctx, prID, reviewer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddReviewer indicates an expected call of AddReviewer.
+func (mr *MockPRRepositoryMockRecorder) AddReviewer(ctx, prID, reviewer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReviewer", reflect.TypeOf((*MockPRRepository)(nil).AddReviewer), ctx, prID, reviewer)
+}
+
 // AssignReviewers mocks base method.
-func (m *MockPRRepository) AssignReviewers(ctx context.Context, prID uuid.UUID, reviewers []uuid.UUID) error {
+func (m *MockPRRepository) AssignReviewers(ctx context.Context, prID uuid.UUID, reviewers []*models.PRReviewer) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AssignReviewers", ctx, prID, reviewers)
 	ret0, _ := ret[0].(error)
@@ -221,6 +438,20 @@ func (mr *MockPRRepositoryMockRecorder) AssignReviewers(ctx, prID, reviewers any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignReviewers", reflect.TypeOf((*MockPRRepository)(nil).AssignReviewers), ctx, prID, reviewers)
 }
 
+// BumpVersion mocks base method.
+func (m *MockPRRepository) BumpVersion(ctx context.Context, id uuid.UUID, version int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BumpVersion", ctx, id, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BumpVersion indicates an expected call of BumpVersion.
+func (mr *MockPRRepositoryMockRecorder) BumpVersion(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BumpVersion", reflect.TypeOf((*MockPRRepository)(nil).BumpVersion), ctx, id, version)
+}
+
 // Create mocks base method.
 func (m *MockPRRepository) Create(ctx context.Context, pr *models.PullRequest) error {
 	m.ctrl.T.Helper()
@@ -235,6 +466,20 @@ func (mr *MockPRRepositoryMockRecorder) Create(ctx, pr any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPRRepository)(nil).Create), ctx, pr)
 }
 
+// DeclineReviewer mocks base method.
+func (m *MockPRRepository) DeclineReviewer(ctx context.Context, prID, oldID uuid.UUID, reviewer *models.PRReviewer, reason models.DeclineReason) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeclineReviewer", ctx, prID, oldID, reviewer, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeclineReviewer indicates an expected call of DeclineReviewer.
+func (mr *MockPRRepositoryMockRecorder) DeclineReviewer(ctx, prID, oldID, reviewer, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclineReviewer", reflect.TypeOf((*MockPRRepository)(nil).DeclineReviewer), ctx, prID, oldID, reviewer, reason)
+}
+
 // GetByID mocks base method.
 func (m *MockPRRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.PullRequest, error) {
 	m.ctrl.T.Helper()
@@ -250,6 +495,21 @@ func (mr *MockPRRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockPRRepository)(nil).GetByID), ctx, id)
 }
 
+// GetByIDForUpdate mocks base method.
+func (m *MockPRRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*models.PullRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDForUpdate", ctx, id)
+	ret0, _ := ret[0].(*models.PullRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDForUpdate indicates an expected call of GetByIDForUpdate.
+func (mr *MockPRRepositoryMockRecorder) GetByIDForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDForUpdate", reflect.TypeOf((*MockPRRepository)(nil).GetByIDForUpdate), ctx, id)
+}
+
 // ListByReviewer mocks base method.
 func (m *MockPRRepository) ListByReviewer(ctx context.Context, id uuid.UUID) ([]*models.PullRequest, error) {
 	m.ctrl.T.Helper()
@@ -265,6 +525,21 @@ func (mr *MockPRRepositoryMockRecorder) ListByReviewer(ctx, id any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByReviewer", reflect.TypeOf((*MockPRRepository)(nil).ListByReviewer), ctx, id)
 }
 
+// ListDeclined mocks base method.
+func (m *MockPRRepository) ListDeclined(ctx context.Context, prID uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeclined", ctx, prID)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeclined indicates an expected call of ListDeclined.
+func (mr *MockPRRepositoryMockRecorder) ListDeclined(ctx, prID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeclined", reflect.TypeOf((*MockPRRepository)(nil).ListDeclined), ctx, prID)
+}
+
 // Merge mocks base method.
 func (m *MockPRRepository) Merge(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -279,18 +554,32 @@ func (mr *MockPRRepositoryMockRecorder) Merge(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockPRRepository)(nil).Merge), ctx, id)
 }
 
+// RemoveReviewer mocks base method.
+func (m *MockPRRepository) RemoveReviewer(ctx context.Context, prID, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveReviewer", ctx, prID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveReviewer indicates an expected call of RemoveReviewer.
+func (mr *MockPRRepositoryMockRecorder) RemoveReviewer(ctx, prID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveReviewer", reflect.TypeOf((*MockPRRepository)(nil).RemoveReviewer), ctx, prID, id)
+}
+
 // ReplaceReviewer mocks base method.
-func (m *MockPRRepository) ReplaceReviewer(ctx context.Context, prID, oldID, newID uuid.UUID) error {
+func (m *MockPRRepository) ReplaceReviewer(ctx context.Context, prID, oldID uuid.UUID, reviewer *models.PRReviewer) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ReplaceReviewer", ctx, prID, oldID, newID)
+	ret := m.ctrl.Call(m, "ReplaceReviewer", ctx, prID, oldID, reviewer)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ReplaceReviewer indicates an expected call of ReplaceReviewer.
-func (mr *MockPRRepositoryMockRecorder) ReplaceReviewer(ctx, prID, oldID, newID any) *gomock.Call {
+func (mr *MockPRRepositoryMockRecorder) ReplaceReviewer(ctx, prID, oldID, reviewer any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceReviewer", reflect.TypeOf((*MockPRRepository)(nil).ReplaceReviewer), ctx, prID, oldID, newID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceReviewer", reflect.TypeOf((*MockPRRepository)(nil).ReplaceReviewer), ctx, prID, oldID, reviewer)
 }
 
 // MockTxManager is a mock of TxManager interface.
@@ -330,3 +619,39 @@ func (mr *MockTxManagerMockRecorder) Do(ctx, fn any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockTxManager)(nil).Do), ctx, fn)
 }
+
+// MockReviewerPublisher is a mock of ReviewerPublisher interface.
+type MockReviewerPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockReviewerPublisherMockRecorder
+	isgomock struct{}
+}
+
+// MockReviewerPublisherMockRecorder is the mock recorder for MockReviewerPublisher.
+type MockReviewerPublisherMockRecorder struct {
+	mock *MockReviewerPublisher
+}
+
+// NewMockReviewerPublisher creates a new mock instance.
+func NewMockReviewerPublisher(ctrl *gomock.Controller) *MockReviewerPublisher {
+	mock := &MockReviewerPublisher{ctrl: ctrl}
+	mock.recorder = &MockReviewerPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReviewerPublisher) EXPECT() *MockReviewerPublisherMockRecorder {
+	return m.recorder
+}
+
+// PublishReviewersChanged mocks base method.
+func (m *MockReviewerPublisher) PublishReviewersChanged(ctx context.Context, change *models.ReviewersChange) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PublishReviewersChanged", ctx, change)
+}
+
+// PublishReviewersChanged indicates an expected call of PublishReviewersChanged.
+func (mr *MockReviewerPublisherMockRecorder) PublishReviewersChanged(ctx, change any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishReviewersChanged", reflect.TypeOf((*MockReviewerPublisher)(nil).PublishReviewersChanged), ctx, change)
+}
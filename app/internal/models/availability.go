@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Availability is an out-of-office window [StartsAt, EndsAt) of a user.
+// While it lasts the user is not picked as a reviewer.
+type Availability struct {
+	ID            uuid.UUID
+	UserID        uuid.UUID
+	StartsAt      time.Time
+	EndsAt        time.Time
+	Reason        string
+	CreatedAt     time.Time
+	DeactivatedAt *time.Time // set once the window has switched the user's is_active off
+	RestoredAt    *time.Time // set once the restore job has processed the ended window
+}
+
+// Covers reports whether the window is in effect at t.
+func (a *Availability) Covers(t time.Time) bool {
+	return !t.Before(a.StartsAt) && t.Before(a.EndsAt)
+}
@@ -0,0 +1,17 @@
+package models
+
+import "github.com/google/uuid"
+
+// CodeOwner is a user or a team owning paths; exactly one ID is set.
+type CodeOwner struct {
+	Ref    string // as written in CODEOWNERS, e.g. "@payments" or "alice@example.com"
+	UserID uuid.UUID
+	TeamID uuid.UUID
+}
+
+// CodeOwnerRule assigns the paths matching Pattern to Owners. Rules are kept
+// in CODEOWNERS order, the last matching rule wins.
+type CodeOwnerRule struct {
+	Pattern string
+	Owners  []CodeOwner
+}
@@ -0,0 +1,19 @@
+package models
+
+import "slices"
+
+// DeclineReason is why a reviewer declined to review a pull request.
+type DeclineReason string
+
+const (
+	DeclineBusy           DeclineReason = "busy"
+	DeclineWrongExpertise DeclineReason = "wrong_expertise"
+	DeclineConflict       DeclineReason = "conflict"
+)
+
+var declineReasons = []DeclineReason{DeclineBusy, DeclineWrongExpertise, DeclineConflict}
+
+// Valid reports whether r is a known reason.
+func (r DeclineReason) Valid() bool {
+	return slices.Contains(declineReasons, r)
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// DigestSchedule is the local time of day when reviewers of a team get
+// their digest of pending reviews.
+type DigestSchedule struct {
+	At       time.Duration // since local midnight, whole minutes
+	Location *time.Location
+}
+
+// Due reports whether the digest of the local day of now is due, and
+// returns that day as YYYY-MM-DD. The wall clock is compared, so the digest
+// keeps its local time across DST changes.
+func (d *DigestSchedule) Due(now time.Time) (string, bool) {
+	local := now.In(d.Location)
+	sinceMidnight := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+
+	return local.Format(time.DateOnly), sinceMidnight >= d.At
+}
+
+// Digest lists the pending reviews of one user.
+type Digest struct {
+	User        *User
+	TeamName    string
+	Day         string         // local day of the team schedule, YYYY-MM-DD
+	Reviews     []*PullRequest // OPEN PRs, oldest first
+	GeneratedAt time.Time
+}
@@ -0,0 +1,49 @@
+package models
+
+import "github.com/google/uuid"
+
+// Code hosts pull request events are received from.
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+// ExternalPR identifies a pull request on a code host.
+type ExternalPR struct {
+	Provider   string
+	Repository string // e.g. "octo-org/api"; the project ID on GitLab, which survives renames
+	Number     int64
+}
+
+// PREventKind is what a code host event means for the PR.
+type PREventKind string
+
+const (
+	// PREventOpened asks for the PR to exist: it was opened or marked
+	// ready for review.
+	PREventOpened PREventKind = "opened"
+
+	// PREventReopened reports a PR closed without a merge was opened again.
+	PREventReopened PREventKind = "reopened"
+
+	// PREventClosed reports the PR was closed without a merge.
+	PREventClosed PREventKind = "closed"
+
+	// PREventMerged reports the PR was merged.
+	PREventMerged PREventKind = "merged"
+)
+
+// PREvent is a pull request event of a code host in provider-neutral form.
+type PREvent struct {
+	Kind   PREventKind
+	PR     ExternalPR
+	Title  string
+	Author string // login on GitHub, user ID on GitLab
+}
+
+// ReviewersChange lists the reviewers a PR gained and lost in one operation.
+type ReviewersChange struct {
+	PRID    uuid.UUID
+	Added   []uuid.UUID
+	Removed []uuid.UUID
+}
@@ -11,13 +11,22 @@ type User struct {
 	ID       uuid.UUID
 	TeamID   *uuid.UUID
 	Name     string
+	Email    string // empty when unknown
+	Role     Role
 	IsActive bool
 }
 
 type Team struct {
-	ID      uuid.UUID
-	Name    string
-	Members []*User
+	ID        uuid.UUID
+	Name      string
+	ReviewSLA *ReviewSLA      // nil when reviews of the team have no deadline
+	Policy    *ReviewPolicy   // nil when any reviewer will do
+	Digest    *DigestSchedule // nil when the team gets no review digest
+	Fallback  []string        // names of the teams asked for reviewers, in order, when the team has none left
+	ParentID  *uuid.UUID      // nil for a top-level team
+	Parent    string          // name of the parent team, filled by the service
+	SubTeams  []*Team         // child teams, only filled when the subtree is requested
+	Members   []*User
 }
 
 type PullRequest struct {
@@ -27,15 +36,33 @@ type PullRequest struct {
 	Status    string
 	CreatedAt time.Time
 	MergedAt  *time.Time
+	Version   int64 // starts at 1 and grows with every change of the PR
 	Reviewers []*PRReviewer
+	Files     []string // changed paths, only used to pick owners as reviewers
 }
 
 type PRReviewer struct {
 	ID         uuid.UUID
 	PRID       uuid.UUID
 	AssignedAt time.Time
+	Pool       ReviewerPool
+	PoolTeam   string // name of the fallback team, empty for other pools
 }
 
+// ReviewerPool is where a reviewer was picked from.
+type ReviewerPool string
+
+const (
+	// ReviewerPoolTeam is the author's team.
+	ReviewerPoolTeam ReviewerPool = "team"
+
+	// ReviewerPoolCodeOwners are the owners of the changed files.
+	ReviewerPoolCodeOwners ReviewerPool = "codeowners"
+
+	// ReviewerPoolFallback is a fallback team of the author's team.
+	ReviewerPoolFallback ReviewerPool = "fallback"
+)
+
 type PRStatus api.PullRequestStatus
 
 const (
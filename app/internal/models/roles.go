@@ -0,0 +1,39 @@
+package models
+
+import "slices"
+
+// Role is the seniority of a reviewer.
+type Role string
+
+const (
+	RoleJunior     Role = "junior"
+	RoleSenior     Role = "senior"
+	RoleMaintainer Role = "maintainer"
+)
+
+// DefaultRole is the role of a user created without one.
+const DefaultRole = RoleSenior
+
+// roles lists the roles from the lowest to the highest.
+var roles = []Role{RoleJunior, RoleSenior, RoleMaintainer}
+
+// Valid reports whether r is a known role.
+func (r Role) Valid() bool {
+	return slices.Contains(roles, r)
+}
+
+// AtLeast reports whether r is lowest or higher.
+func (r Role) AtLeast(lowest Role) bool {
+	return slices.Index(roles, r) >= slices.Index(roles, lowest)
+}
+
+// ReviewPolicy is what the reviewers of every PR of a team must satisfy.
+type ReviewPolicy struct {
+	// MinRole is the role at least one reviewer must have or exceed.
+	MinRole Role
+}
+
+// SatisfiedBy reports whether at least one of the roles satisfies the policy.
+func (p *ReviewPolicy) SatisfiedBy(roles ...Role) bool {
+	return slices.ContainsFunc(roles, func(r Role) bool { return r.AtLeast(p.MinRole) })
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SLAAction is what happens to a review that outlives the team SLA.
+type SLAAction string
+
+const (
+	// SLAActionReassign hands the review to another team member,
+	// escalating only when nobody is available.
+	SLAActionReassign SLAAction = "reassign"
+
+	// SLAActionEscalate keeps the reviewer and emits an escalation.
+	SLAActionEscalate SLAAction = "escalate"
+)
+
+// ReviewSLA is the time a reviewer of the team has to review a PR,
+// counted from the assignment.
+type ReviewSLA struct {
+	Timeout time.Duration
+	Action  SLAAction
+}
+
+// OverdueReview is an assignment on an OPEN PR that outlived the SLA
+// of the author's team.
+type OverdueReview struct {
+	PRID       uuid.UUID
+	PRName     string
+	ReviewerID uuid.UUID
+	AssignedAt time.Time
+	TeamID     uuid.UUID
+	TeamName   string
+	SLA        ReviewSLA
+}
+
+// Deadline returns when the review was due.
+func (o *OverdueReview) Deadline() time.Time {
+	return o.AssignedAt.Add(o.SLA.Timeout)
+}
+
+// ReviewEscalation is emitted once per overdue assignment that was not reassigned.
+type ReviewEscalation struct {
+	PRID        uuid.UUID
+	PRName      string
+	ReviewerID  uuid.UUID
+	TeamName    string
+	AssignedAt  time.Time
+	Deadline    time.Time // AssignedAt plus the team SLA
+	EscalatedAt time.Time
+	Reason      string // why the review was escalated rather than reassigned
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReviewLoad aggregates the review activity of a user or a team.
+type ReviewLoad struct {
+	OpenAssigned      int            // open PRs assigned right now, regardless of the window
+	AssignedTotal     int            // assignments made within the window, including reassigned ones
+	ReassignedAway    int            // reassignments away from the reviewer within the window
+	Declined          int            // reassignments away the reviewer asked for by declining, within the window
+	MedianTimeToMerge *time.Duration // assignment to merge, for assignments within the window; nil if none merged
+}
+
+// DeclineRate is the share of the window's assignments the reviewer
+// declined, nil when there were none.
+func (l ReviewLoad) DeclineRate() *float64 {
+	if l.AssignedTotal == 0 {
+		return nil
+	}
+
+	rate := float64(l.Declined) / float64(l.AssignedTotal)
+	return &rate
+}
+
+type ReviewerStats struct {
+	UserID   uuid.UUID
+	Username string
+	TeamName string
+	ReviewLoad
+}
+
+type TeamReviewStats struct {
+	TeamID     uuid.UUID
+	TeamName   string
+	ParentName string // empty for a top-level team
+	ReviewLoad
+	Subtree ReviewLoad // the team together with all its sub-teams
+}
+
+// ReviewStatsReport is the review load of every user and team within [From, To).
+type ReviewStatsReport struct {
+	From  time.Time
+	To    time.Time
+	Users []*ReviewerStats
+	Teams []*TeamReviewStats
+}
+
+// PRCycle is the lifecycle of a single pull request.
+type PRCycle struct {
+	PRID            uuid.UUID
+	Name            string
+	AuthorID        uuid.UUID
+	TeamName        string // team of the author
+	Status          string
+	CreatedAt       time.Time
+	FirstAssignedAt *time.Time // earliest reviewer assignment, including reassigned ones
+	MergedAt        *time.Time
+}
+
+// DurationPercentiles summarizes a sample of durations.
+type DurationPercentiles struct {
+	Count int
+	P50   time.Duration
+	P75   time.Duration
+	P90   time.Duration
+}
+
+// PRCycleGroup aggregates the pull requests of a team created in one week.
+type PRCycleGroup struct {
+	TeamName              string
+	WeekStart             time.Time // Monday 00:00 UTC
+	Created               int
+	Merged                int
+	Open                  int
+	TimeToFirstAssignment *DurationPercentiles // creation to first reviewer assignment
+	TimeToMerge           *DurationPercentiles
+	OpenAge               *DurationPercentiles // age of still open PRs at AsOf
+}
+
+// PRStatsReport is the cycle time of pull requests created within [From, To).
+type PRStatsReport struct {
+	From   time.Time
+	To     time.Time
+	AsOf   time.Time
+	Groups []*PRCycleGroup
+}
@@ -0,0 +1,45 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"pr-service/internal/models"
+	"pr-service/internal/service"
+)
+
+var _ service.Notifier = (*FileNotifier)(nil)
+
+// FileNotifier appends rendered digests to a file; meant for development.
+type FileNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{path: path}
+}
+
+func (n *FileNotifier) SendDigest(_ context.Context, d *models.Digest) error {
+	subject, body := render(d)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open digest file: %w", err)
+	}
+
+	_, err = fmt.Fprintf(f, "To: %s <%s>\nSubject: %s\n\n%s\n", d.User.Name, d.User.Email, subject, body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("write digest file: %w", err)
+	}
+
+	return nil
+}
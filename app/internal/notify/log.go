@@ -0,0 +1,36 @@
+package notify
+
+import (
+	"context"
+
+	"pr-service/internal/models"
+	"pr-service/internal/service"
+
+	"go.uber.org/zap"
+)
+
+var _ service.Notifier = (*LogNotifier)(nil)
+
+// LogNotifier writes digests to the application log; meant for development.
+type LogNotifier struct {
+	log *zap.Logger
+}
+
+func NewLogNotifier(log *zap.Logger) *LogNotifier {
+	return &LogNotifier{log: log.Named("notify")}
+}
+
+func (n *LogNotifier) SendDigest(_ context.Context, d *models.Digest) error {
+	prIDs := make([]string, len(d.Reviews))
+	for i, pr := range d.Reviews {
+		prIDs[i] = pr.ID.String()
+	}
+
+	n.log.Info("review digest",
+		zap.String("user_id", d.User.ID.String()),
+		zap.String("team_name", d.TeamName),
+		zap.String("day", d.Day),
+		zap.Strings("pr_ids", prIDs),
+	)
+	return nil
+}
@@ -0,0 +1,36 @@
+// Package notify delivers review digests to reviewers.
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"pr-service/internal/models"
+)
+
+// render formats a digest as a plain-text message.
+func render(d *models.Digest) (subject, body string) {
+	subject = fmt.Sprintf("Pending reviews for %s: %d", d.Day, len(d.Reviews))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Hi %s,\n\n", d.User.Name)
+	fmt.Fprintf(&b, "you have %d open pull requests to review in team %s, oldest first:\n\n", len(d.Reviews), d.TeamName)
+	for _, pr := range d.Reviews {
+		fmt.Fprintf(&b, "- %s (%s), open for %s\n", pr.Name, pr.ID, age(d.GeneratedAt.Sub(pr.CreatedAt)))
+	}
+
+	return subject, b.String()
+}
+
+// age rounds a PR age for people: days, then hours, then minutes.
+func age(d time.Duration) string {
+	switch {
+	case d >= 48*time.Hour:
+		return fmt.Sprintf("%d days", d/(24*time.Hour))
+	case d >= 2*time.Hour:
+		return fmt.Sprintf("%d hours", d/time.Hour)
+	default:
+		return fmt.Sprintf("%d minutes", max(d/time.Minute, 0))
+	}
+}
@@ -0,0 +1,180 @@
+package notify_test
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"pr-service/internal/models"
+	"pr-service/internal/notify"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeSMTP is a plain-text SMTP server that accepts every message.
+type fakeSMTP struct {
+	ln       net.Listener
+	messages chan smtpMessage
+}
+
+type smtpMessage struct {
+	from, to, data string
+}
+
+func startFakeSMTP(t *testing.T) *fakeSMTP {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	s := &fakeSMTP{ln: ln, messages: make(chan smtpMessage, 10)}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+
+	return s
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+	reply("220 fake ESMTP")
+
+	var msg smtpMessage
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 fake")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			msg.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			msg.to = strings.Trim(line[len("RCPT TO:"):], "<>")
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			msg.data = data.String()
+			s.messages <- msg
+			msg = smtpMessage{}
+			reply("250 queued")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func testDigest(email string) *models.Digest {
+	now := time.Date(2025, 10, 24, 9, 0, 0, 0, time.UTC)
+
+	return &models.Digest{
+		User:     &models.User{ID: uuid.New(), Name: "Bob", Email: email},
+		TeamName: "backend",
+		Day:      "2025-10-24",
+		Reviews: []*models.PullRequest{
+			{ID: uuid.New(), Name: "Add search", CreatedAt: now.Add(-72 * time.Hour)},
+			{ID: uuid.New(), Name: "Fix login", CreatedAt: now.Add(-3 * time.Hour)},
+		},
+		GeneratedAt: now,
+	}
+}
+
+func TestSMTPNotifier_SendDigest(t *testing.T) {
+	srv := startFakeSMTP(t)
+
+	n, err := notify.NewSMTPNotifier(notify.SMTPConfig{
+		Addr:    srv.ln.Addr().String(),
+		From:    "PR Service <pr-service@example.com>",
+		Timeout: 5 * time.Second,
+	}, zap.NewNop())
+	require.NoError(t, err)
+
+	require.NoError(t, n.SendDigest(t.Context(), testDigest("bob@example.com")))
+
+	select {
+	case msg := <-srv.messages:
+		require.Equal(t, "pr-service@example.com", msg.from)
+		require.Equal(t, "bob@example.com", msg.to)
+		require.Contains(t, msg.data, "To: \"Bob\" <bob@example.com>\r\n")
+		require.Contains(t, msg.data, "Subject: Pending reviews for 2025-10-24: 2\r\n")
+		require.Contains(t, msg.data, "- Add search")
+		require.Contains(t, msg.data, "open for 3 days")
+		require.Less(t, strings.Index(msg.data, "Add search"), strings.Index(msg.data, "Fix login"))
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+	}
+
+	t.Run("no email", func(t *testing.T) {
+		require.NoError(t, n.SendDigest(t.Context(), testDigest("")))
+		select {
+		case <-srv.messages:
+			t.Fatal("digest sent to a user without email")
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("server down", func(t *testing.T) {
+		down, err := notify.NewSMTPNotifier(notify.SMTPConfig{
+			Addr: "127.0.0.1:1",
+			From: "pr-service@example.com",
+		}, zap.NewNop())
+		require.NoError(t, err)
+		require.Error(t, down.SendDigest(t.Context(), testDigest("bob@example.com")))
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := notify.NewSMTPNotifier(notify.SMTPConfig{Addr: "localhost", From: "x@example.com"}, zap.NewNop())
+		require.Error(t, err)
+		_, err = notify.NewSMTPNotifier(notify.SMTPConfig{Addr: "localhost:25", From: "not an address"}, zap.NewNop())
+		require.Error(t, err)
+	})
+}
+
+func TestFileNotifier_SendDigest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "digests.log")
+	n := notify.NewFileNotifier(path)
+
+	require.NoError(t, n.SendDigest(t.Context(), testDigest("bob@example.com")))
+	require.NoError(t, n.SendDigest(t.Context(), testDigest("")))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, 2, strings.Count(string(data), "Subject: Pending reviews for 2025-10-24: 2"))
+	require.Contains(t, string(data), "- Fix login")
+	require.Contains(t, string(data), "open for 3 hours")
+}
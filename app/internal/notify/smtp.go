@@ -0,0 +1,134 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+
+	"pr-service/internal/models"
+	"pr-service/internal/service"
+
+	"go.uber.org/zap"
+)
+
+var _ service.Notifier = (*SMTPNotifier)(nil)
+
+// SMTPConfig holds the mail server settings of SMTPNotifier.
+type SMTPConfig struct {
+	Addr     string // host:port
+	Username string // no authentication when empty
+	Password string
+	From     string
+	Timeout  time.Duration // per message when ctx has no deadline, 0 = none
+}
+
+// SMTPNotifier mails digests to users. Users without an email are skipped.
+// STARTTLS is used when the server offers it.
+type SMTPNotifier struct {
+	cfg  SMTPConfig
+	host string
+	log  *zap.Logger
+}
+
+func NewSMTPNotifier(cfg SMTPConfig, log *zap.Logger) (*SMTPNotifier, error) {
+	host, _, err := net.SplitHostPort(cfg.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid smtp address: %w", err)
+	}
+	if _, err := mail.ParseAddress(cfg.From); err != nil {
+		return nil, fmt.Errorf("invalid smtp sender: %w", err)
+	}
+
+	return &SMTPNotifier{cfg: cfg, host: host, log: log.Named("notify")}, nil
+}
+
+func (n *SMTPNotifier) SendDigest(ctx context.Context, d *models.Digest) error {
+	if d.User.Email == "" {
+		n.log.Debug("digest skipped, user has no email",
+			zap.String("user_id", d.User.ID.String()),
+		)
+		return nil
+	}
+
+	to := (&mail.Address{Name: d.User.Name, Address: d.User.Email}).String()
+	subject, body := render(d)
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", n.cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", d.GeneratedAt.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(body)
+
+	if err := n.send(ctx, d.User.Email, msg.Bytes()); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	return nil
+}
+
+func (n *SMTPNotifier) send(ctx context.Context, to string, msg []byte) error {
+	if _, ok := ctx.Deadline(); !ok && n.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, n.cfg.Timeout)
+		defer cancel()
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", n.cfg.Addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, n.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: n.host}); err != nil {
+			return err
+		}
+	}
+	if n.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.host)); err != nil {
+			return err
+		}
+	}
+
+	from, err := mail.ParseAddress(n.cfg.From)
+	if err != nil {
+		return err
+	}
+	if err := c.Mail(from.Address); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"pr-service/internal/models"
+	"pr-service/internal/retry"
+
+	sq "github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var availabilityColumns = []string{
+	"id", "user_id", "starts_at", "ends_at", "reason", "created_at", "deactivated_at", "restored_at",
+}
+
+type AvailabilityRepository struct {
+	db      *pgxpool.Pool
+	getter  *trmpgx.CtxGetter
+	psql    sq.StatementBuilderType
+	retrier retry.Retrier
+}
+
+func NewAvailabilityRepository(db *pgxpool.Pool, c *trmpgx.CtxGetter, r retry.Retrier) *AvailabilityRepository {
+	return &AvailabilityRepository{
+		db:      db,
+		getter:  c,
+		psql:    sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+		retrier: r,
+	}
+}
+
+func (r *AvailabilityRepository) Create(ctx context.Context, a *models.Availability) error {
+	query := r.psql.Insert("user_availability").
+		Columns("user_id", "starts_at", "ends_at", "reason", "created_at").
+		Values(a.UserID, a.StartsAt, a.EndsAt, a.Reason, a.CreatedAt).
+		Suffix("RETURNING id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		return conn.QueryRow(ctx, sql, args...).Scan(&a.ID)
+	})
+
+	return wrapDBError(err)
+}
+
+func (r *AvailabilityRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Availability, error) {
+	windows, err := r.listBy(ctx, sq.Eq{"id": id}, "id")
+	if err != nil {
+		return nil, err
+	}
+	if len(windows) == 0 {
+		return nil, ErrNotFound
+	}
+	return windows[0], nil
+}
+
+func (r *AvailabilityRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Availability, error) {
+	return r.listBy(ctx, sq.Eq{"user_id": userID}, "starts_at", "id")
+}
+
+func (r *AvailabilityRepository) ListEnded(ctx context.Context, at time.Time) ([]*models.Availability, error) {
+	return r.listBy(ctx, sq.And{
+		sq.LtOrEq{"ends_at": at},
+		sq.Eq{"restored_at": nil},
+	}, "ends_at", "id")
+}
+
+func (r *AvailabilityRepository) ListStarted(ctx context.Context, at time.Time) ([]*models.Availability, error) {
+	return r.listBy(ctx, sq.And{
+		sq.LtOrEq{"starts_at": at},
+		sq.Gt{"ends_at": at},
+		sq.Eq{"deactivated_at": nil},
+	}, "starts_at", "id")
+}
+
+func (r *AvailabilityRepository) listBy(ctx context.Context, where sq.Sqlizer, orderBy ...string) ([]*models.Availability, error) {
+	query := r.psql.Select(availabilityColumns...).
+		From("user_availability").
+		Where(where).
+		OrderBy(orderBy...)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	var windows []*models.Availability
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		rows, err := conn.Query(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		windows = make([]*models.Availability, 0)
+		for rows.Next() {
+			a := &models.Availability{}
+			if err := rows.Scan(
+				&a.ID, &a.UserID, &a.StartsAt, &a.EndsAt, &a.Reason, &a.CreatedAt, &a.DeactivatedAt, &a.RestoredAt,
+			); err != nil {
+				return err
+			}
+
+			windows = append(windows, a)
+		}
+
+		return rows.Err()
+	})
+
+	return windows, wrapDBError(err)
+}
+
+func (r *AvailabilityRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := r.psql.Delete("user_availability").
+		Where(sq.Eq{"id": id})
+
+	return r.execOne(ctx, query)
+}
+
+func (r *AvailabilityRepository) MarkRestored(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := r.psql.Update("user_availability").
+		Set("restored_at", at).
+		Where(sq.Eq{"id": id})
+
+	return r.execOne(ctx, query)
+}
+
+func (r *AvailabilityRepository) MarkDeactivated(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := r.psql.Update("user_availability").
+		Set("deactivated_at", at).
+		Where(sq.Eq{"id": id})
+
+	return r.execOne(ctx, query)
+}
+
+// execOne runs a statement that must change a row, ErrNotFound otherwise.
+func (r *AvailabilityRepository) execOne(ctx context.Context, query sq.Sqlizer) error {
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		tag, retryErr := conn.Exec(ctx, sql, args...)
+		if retryErr != nil {
+			return retryErr
+		}
+
+		if tag.RowsAffected() == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+
+	return wrapDBError(err)
+}
@@ -0,0 +1,35 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"testing"
+
+	"pr-service/internal/clock"
+	"pr-service/internal/repository"
+	"pr-service/internal/repository/repotest"
+
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T, clk clock.Clock) repotest.Repos {
+		_, err := db.Exec(t.Context(), "TRUNCATE team_fallback_pools, code_owner_rules, external_pull_requests, external_users, review_escalations, user_availability, pr_reassignments, pr_reviewers, pull_requests, users, teams CASCADE")
+		require.NoError(t, err)
+
+		return repotest.Repos{
+			Teams:        repository.NewTeamRepository(db, trmpgx.DefaultCtxGetter, retrier),
+			Users:        repository.NewUserRepository(db, trmpgx.DefaultCtxGetter, retrier),
+			PRs:          repository.NewPRRepository(db, trmpgx.DefaultCtxGetter, retrier, clk),
+			Stats:        repository.NewStatsRepository(db, trmpgx.DefaultCtxGetter, retrier),
+			Availability: repository.NewAvailabilityRepository(db, trmpgx.DefaultCtxGetter, retrier),
+			SLA:          repository.NewSLARepository(db, trmpgx.DefaultCtxGetter, retrier),
+			Digest:       repository.NewDigestRepository(db, trmpgx.DefaultCtxGetter, retrier),
+			Integration:  repository.NewIntegrationRepository(db, trmpgx.DefaultCtxGetter, retrier),
+			Tx:           manager.Must(trmpgx.NewDefaultFactory(db)),
+		}
+	})
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"pr-service/internal/models"
+	"pr-service/internal/retry"
+
+	sq "github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type DigestRepository struct {
+	db      *pgxpool.Pool
+	getter  *trmpgx.CtxGetter
+	psql    sq.StatementBuilderType
+	retrier retry.Retrier
+}
+
+func NewDigestRepository(db *pgxpool.Pool, c *trmpgx.CtxGetter, r retry.Retrier) *DigestRepository {
+	return &DigestRepository{
+		db:      db,
+		getter:  c,
+		psql:    sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+		retrier: r,
+	}
+}
+
+// ListScheduled returns the teams with a digest schedule; only ID, Name and
+// Digest are set.
+func (r *DigestRepository) ListScheduled(ctx context.Context) ([]*models.Team, error) {
+	query := r.psql.Select("id", "name", "digest_minute", "digest_timezone").
+		From("teams").
+		Where(sq.NotEq{"digest_minute": nil}).
+		OrderBy("name")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	var teams []*models.Team
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		rows, err := conn.Query(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		teams = make([]*models.Team, 0)
+		for rows.Next() {
+			var (
+				t              = &models.Team{}
+				digestMinute   *int64
+				digestTimezone string
+			)
+			if err := rows.Scan(&t.ID, &t.Name, &digestMinute, &digestTimezone); err != nil {
+				return err
+			}
+
+			if t.Digest, err = DigestFromColumns(digestMinute, digestTimezone); err != nil {
+				return err
+			}
+			teams = append(teams, t)
+		}
+
+		return rows.Err()
+	})
+
+	return teams, wrapDBError(err)
+}
+
+// SentOn returns the last day (YYYY-MM-DD) the team digest went out to
+// every reviewer, or "" if it never did.
+func (r *DigestRepository) SentOn(ctx context.Context, teamID uuid.UUID) (string, error) {
+	sql, args, err := r.psql.Select("digest_sent_on").
+		From("teams").
+		Where(sq.Eq{"id": teamID}).
+		ToSql()
+	if err != nil {
+		return "", err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	var sentOn *time.Time
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		return conn.QueryRow(ctx, sql, args...).Scan(&sentOn)
+	})
+	if err != nil {
+		return "", wrapDBError(err)
+	}
+
+	if sentOn == nil {
+		return "", nil
+	}
+	return sentOn.Format(time.DateOnly), nil
+}
+
+// MarkDelivered records that the user got the team digest of day.
+// Reports false if it was already recorded.
+func (r *DigestRepository) MarkDelivered(ctx context.Context, teamID, userID uuid.UUID, day string) (bool, error) {
+	date, err := time.Parse(time.DateOnly, day)
+	if err != nil {
+		return false, err
+	}
+
+	sql, args, err := r.psql.Insert("digest_deliveries").
+		Columns("team_id", "user_id", "day").
+		Values(teamID, userID, date).
+		Suffix("ON CONFLICT DO NOTHING").
+		ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	var marked bool
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		tag, retryErr := conn.Exec(ctx, sql, args...)
+		if retryErr != nil {
+			return retryErr
+		}
+
+		marked = tag.RowsAffected() > 0
+		return nil
+	})
+
+	return marked, wrapDBError(err)
+}
+
+// MarkSent records that the team digest of day (YYYY-MM-DD) went out to
+// every reviewer. Reports false if it was already recorded for that or a
+// later day.
+func (r *DigestRepository) MarkSent(ctx context.Context, teamID uuid.UUID, day string) (bool, error) {
+	date, err := time.Parse(time.DateOnly, day)
+	if err != nil {
+		return false, err
+	}
+
+	query := r.psql.Update("teams").
+		Set("digest_sent_on", date).
+		Where(sq.Eq{"id": teamID}).
+		Where(sq.Or{
+			sq.Eq{"digest_sent_on": nil},
+			sq.Lt{"digest_sent_on": date},
+		})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	var marked bool
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		tag, retryErr := conn.Exec(ctx, sql, args...)
+		if retryErr != nil {
+			return retryErr
+		}
+
+		marked = tag.RowsAffected() > 0
+		return nil
+	})
+
+	return marked, wrapDBError(err)
+}
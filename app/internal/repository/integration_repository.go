@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+
+	"pr-service/internal/models"
+	"pr-service/internal/retry"
+
+	sq "github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// IntegrationRepository maps code host users and pull requests to ours.
+type IntegrationRepository struct {
+	db      *pgxpool.Pool
+	getter  *trmpgx.CtxGetter
+	psql    sq.StatementBuilderType
+	retrier retry.Retrier
+}
+
+func NewIntegrationRepository(db *pgxpool.Pool, c *trmpgx.CtxGetter, r retry.Retrier) *IntegrationRepository {
+	return &IntegrationRepository{
+		db:      db,
+		getter:  c,
+		psql:    sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+		retrier: r,
+	}
+}
+
+func (r *IntegrationRepository) LinkUser(ctx context.Context, provider, login string, userID uuid.UUID) error {
+	query := r.psql.Insert("external_users").
+		Columns("provider", "login", "user_id").
+		Values(provider, login, userID).
+		Suffix("ON CONFLICT (provider, login) DO UPDATE SET user_id = EXCLUDED.user_id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		_, retryErr := conn.Exec(ctx, sql, args...)
+		return retryErr
+	})
+
+	return wrapDBError(err)
+}
+
+func (r *IntegrationRepository) GetUserID(ctx context.Context, provider, login string) (uuid.UUID, error) {
+	query := r.psql.Select("user_id").
+		From("external_users").
+		Where(sq.Eq{"provider": provider, "login": login})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	var id uuid.UUID
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		return conn.QueryRow(ctx, sql, args...).Scan(&id)
+	})
+
+	return id, wrapDBError(err)
+}
+
+func (r *IntegrationRepository) GetLogin(ctx context.Context, provider string, userID uuid.UUID) (string, error) {
+	query := r.psql.Select("login").
+		From("external_users").
+		Where(sq.Eq{"provider": provider, "user_id": userID}).
+		OrderBy("login").
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return "", err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	var login string
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		return conn.QueryRow(ctx, sql, args...).Scan(&login)
+	})
+
+	return login, wrapDBError(err)
+}
+
+func (r *IntegrationRepository) LinkPR(ctx context.Context, ref models.ExternalPR, prID uuid.UUID) error {
+	query := r.psql.Insert("external_pull_requests").
+		Columns("provider", "repository", "number", "pull_request_id").
+		Values(ref.Provider, ref.Repository, ref.Number, prID)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		_, retryErr := conn.Exec(ctx, sql, args...)
+		return retryErr
+	})
+
+	return wrapDBError(err)
+}
+
+func (r *IntegrationRepository) GetPRID(ctx context.Context, ref models.ExternalPR) (uuid.UUID, error) {
+	query := r.psql.Select("pull_request_id").
+		From("external_pull_requests").
+		Where(sq.Eq{"provider": ref.Provider, "repository": ref.Repository, "number": ref.Number})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	var id uuid.UUID
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		return conn.QueryRow(ctx, sql, args...).Scan(&id)
+	})
+
+	return id, wrapDBError(err)
+}
+
+func (r *IntegrationRepository) GetExternalPR(ctx context.Context, provider string, prID uuid.UUID) (models.ExternalPR, error) {
+	query := r.psql.Select("repository", "number").
+		From("external_pull_requests").
+		Where(sq.Eq{"provider": provider, "pull_request_id": prID})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return models.ExternalPR{}, err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	ref := models.ExternalPR{Provider: provider}
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		return conn.QueryRow(ctx, sql, args...).Scan(&ref.Repository, &ref.Number)
+	})
+
+	return ref, wrapDBError(err)
+}
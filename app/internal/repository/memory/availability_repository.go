@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/service"
+
+	"github.com/google/uuid"
+)
+
+var _ service.AvailabilityRepository = (*AvailabilityRepository)(nil)
+
+type AvailabilityRepository struct {
+	store *Store
+}
+
+func NewAvailabilityRepository(store *Store) *AvailabilityRepository {
+	return &AvailabilityRepository{store: store}
+}
+
+func (r *AvailabilityRepository) Create(ctx context.Context, a *models.Availability) error {
+	return r.store.do(ctx, func(st *state) error {
+		if _, ok := st.users[a.UserID]; !ok {
+			return repository.ErrForeignKeyViolation
+		}
+
+		a.ID = uuid.New()
+		st.availability[a.ID] = copyAvailability(a)
+
+		return nil
+	})
+}
+
+func (r *AvailabilityRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Availability, error) {
+	var window *models.Availability
+
+	err := r.store.do(ctx, func(st *state) error {
+		a, ok := st.availability[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		window = copyAvailability(a)
+		return nil
+	})
+
+	return window, err
+}
+
+func (r *AvailabilityRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Availability, error) {
+	windows := make([]*models.Availability, 0)
+
+	err := r.store.do(ctx, func(st *state) error {
+		for _, a := range st.availability {
+			if a.UserID == userID {
+				windows = append(windows, copyAvailability(a))
+			}
+		}
+		sortAvailability(windows, func(a *models.Availability) time.Time { return a.StartsAt })
+		return nil
+	})
+
+	return windows, err
+}
+
+func (r *AvailabilityRepository) ListEnded(ctx context.Context, at time.Time) ([]*models.Availability, error) {
+	windows := make([]*models.Availability, 0)
+
+	err := r.store.do(ctx, func(st *state) error {
+		for _, a := range st.availability {
+			if !a.EndsAt.After(at) && a.RestoredAt == nil {
+				windows = append(windows, copyAvailability(a))
+			}
+		}
+		sortAvailability(windows, func(a *models.Availability) time.Time { return a.EndsAt })
+		return nil
+	})
+
+	return windows, err
+}
+
+func (r *AvailabilityRepository) ListStarted(ctx context.Context, at time.Time) ([]*models.Availability, error) {
+	windows := make([]*models.Availability, 0)
+
+	err := r.store.do(ctx, func(st *state) error {
+		for _, a := range st.availability {
+			if a.Covers(at) && a.DeactivatedAt == nil {
+				windows = append(windows, copyAvailability(a))
+			}
+		}
+		sortAvailability(windows, func(a *models.Availability) time.Time { return a.StartsAt })
+		return nil
+	})
+
+	return windows, err
+}
+
+func (r *AvailabilityRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.store.do(ctx, func(st *state) error {
+		if _, ok := st.availability[id]; !ok {
+			return repository.ErrNotFound
+		}
+		delete(st.availability, id)
+		return nil
+	})
+}
+
+func (r *AvailabilityRepository) MarkRestored(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return r.store.do(ctx, func(st *state) error {
+		a, ok := st.availability[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		a.RestoredAt = &at
+		return nil
+	})
+}
+
+func (r *AvailabilityRepository) MarkDeactivated(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return r.store.do(ctx, func(st *state) error {
+		a, ok := st.availability[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		a.DeactivatedAt = &at
+		return nil
+	})
+}
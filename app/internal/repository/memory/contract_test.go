@@ -0,0 +1,27 @@
+package memory_test
+
+import (
+	"testing"
+
+	"pr-service/internal/clock"
+	"pr-service/internal/repository/memory"
+	"pr-service/internal/repository/repotest"
+)
+
+func TestContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T, clk clock.Clock) repotest.Repos {
+		store := memory.NewStore()
+
+		return repotest.Repos{
+			Teams:        memory.NewTeamRepository(store),
+			Users:        memory.NewUserRepository(store),
+			PRs:          memory.NewPRRepository(store, clk),
+			Stats:        memory.NewStatsRepository(store),
+			Availability: memory.NewAvailabilityRepository(store),
+			SLA:          memory.NewSLARepository(store),
+			Digest:       memory.NewDigestRepository(store),
+			Integration:  memory.NewIntegrationRepository(store),
+			Tx:           memory.NewTxManager(store),
+		}
+	})
+}
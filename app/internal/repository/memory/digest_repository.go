@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/service"
+
+	"github.com/google/uuid"
+)
+
+var _ service.DigestRepository = (*DigestRepository)(nil)
+
+type DigestRepository struct {
+	store *Store
+}
+
+func NewDigestRepository(store *Store) *DigestRepository {
+	return &DigestRepository{store: store}
+}
+
+func (r *DigestRepository) ListScheduled(ctx context.Context) ([]*models.Team, error) {
+	teams := make([]*models.Team, 0)
+
+	err := r.store.do(ctx, func(st *state) error {
+		for _, id := range st.teamOrder {
+			t := st.teams[id]
+			if t.Digest == nil {
+				continue
+			}
+			teams = append(teams, &models.Team{ID: t.ID, Name: t.Name, Digest: copyDigest(t.Digest)})
+		}
+		return nil
+	})
+
+	slices.SortFunc(teams, func(a, b *models.Team) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return teams, err
+}
+
+func (r *DigestRepository) SentOn(ctx context.Context, teamID uuid.UUID) (string, error) {
+	var sentOn string
+
+	err := r.store.do(ctx, func(st *state) error {
+		if _, ok := st.teams[teamID]; !ok {
+			return repository.ErrNotFound
+		}
+		sentOn = st.digestSentOn[teamID]
+		return nil
+	})
+
+	return sentOn, err
+}
+
+func (r *DigestRepository) MarkDelivered(ctx context.Context, teamID, userID uuid.UUID, day string) (bool, error) {
+	var marked bool
+
+	err := r.store.do(ctx, func(st *state) error {
+		if _, ok := st.teams[teamID]; !ok {
+			return repository.ErrForeignKeyViolation
+		}
+		if _, ok := st.users[userID]; !ok {
+			return repository.ErrForeignKeyViolation
+		}
+
+		key := digestDelivery{teamID: teamID, userID: userID, day: day}
+		if st.digestDeliveries[key] {
+			return nil
+		}
+
+		st.digestDeliveries[key] = true
+		marked = true
+		return nil
+	})
+
+	return marked, err
+}
+
+func (r *DigestRepository) MarkSent(ctx context.Context, teamID uuid.UUID, day string) (bool, error) {
+	var marked bool
+
+	err := r.store.do(ctx, func(st *state) error {
+		if _, ok := st.teams[teamID]; !ok {
+			return nil
+		}
+		if sent, ok := st.digestSentOn[teamID]; ok && sent >= day {
+			return nil
+		}
+
+		st.digestSentOn[teamID] = day
+		marked = true
+		return nil
+	})
+
+	return marked, err
+}
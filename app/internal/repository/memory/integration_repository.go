@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/service"
+
+	"github.com/google/uuid"
+)
+
+var _ service.IntegrationRepository = (*IntegrationRepository)(nil)
+
+type IntegrationRepository struct {
+	store *Store
+}
+
+func NewIntegrationRepository(store *Store) *IntegrationRepository {
+	return &IntegrationRepository{store: store}
+}
+
+func (r *IntegrationRepository) LinkUser(ctx context.Context, provider, login string, userID uuid.UUID) error {
+	return r.store.do(ctx, func(st *state) error {
+		if _, ok := st.users[userID]; !ok {
+			return repository.ErrForeignKeyViolation
+		}
+		st.externalUsers[externalUser{provider: provider, login: login}] = userID
+		return nil
+	})
+}
+
+func (r *IntegrationRepository) GetUserID(ctx context.Context, provider, login string) (uuid.UUID, error) {
+	var id uuid.UUID
+
+	err := r.store.do(ctx, func(st *state) error {
+		userID, ok := st.externalUsers[externalUser{provider: provider, login: login}]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		id = userID
+		return nil
+	})
+
+	return id, err
+}
+
+func (r *IntegrationRepository) GetLogin(ctx context.Context, provider string, userID uuid.UUID) (string, error) {
+	var login string
+
+	err := r.store.do(ctx, func(st *state) error {
+		for u, id := range st.externalUsers {
+			if u.provider == provider && id == userID && (login == "" || u.login < login) {
+				login = u.login
+			}
+		}
+		if login == "" {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+
+	return login, err
+}
+
+func (r *IntegrationRepository) LinkPR(ctx context.Context, ref models.ExternalPR, prID uuid.UUID) error {
+	return r.store.do(ctx, func(st *state) error {
+		if _, ok := st.prs[prID]; !ok {
+			return repository.ErrForeignKeyViolation
+		}
+		if _, ok := st.externalPRs[ref]; ok {
+			return repository.ErrDuplicate
+		}
+		for _, id := range st.externalPRs {
+			if id == prID {
+				return repository.ErrDuplicate
+			}
+		}
+
+		st.externalPRs[ref] = prID
+		return nil
+	})
+}
+
+func (r *IntegrationRepository) GetPRID(ctx context.Context, ref models.ExternalPR) (uuid.UUID, error) {
+	var id uuid.UUID
+
+	err := r.store.do(ctx, func(st *state) error {
+		prID, ok := st.externalPRs[ref]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		id = prID
+		return nil
+	})
+
+	return id, err
+}
+
+func (r *IntegrationRepository) GetExternalPR(ctx context.Context, provider string, prID uuid.UUID) (models.ExternalPR, error) {
+	var ref models.ExternalPR
+
+	err := r.store.do(ctx, func(st *state) error {
+		for ext, id := range st.externalPRs {
+			if ext.Provider == provider && id == prID {
+				ref = ext
+				return nil
+			}
+		}
+		return repository.ErrNotFound
+	})
+
+	return ref, err
+}
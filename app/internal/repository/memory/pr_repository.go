@@ -0,0 +1,257 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"pr-service/internal/clock"
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/service"
+
+	"github.com/google/uuid"
+)
+
+var _ service.PRRepository = (*PRRepository)(nil)
+
+type PRRepository struct {
+	store *Store
+	clock clock.Clock
+}
+
+func NewPRRepository(store *Store, clk clock.Clock) *PRRepository {
+	return &PRRepository{store: store, clock: clk}
+}
+
+func (r *PRRepository) Create(ctx context.Context, pr *models.PullRequest) error {
+	return r.store.do(ctx, func(st *state) error {
+		if _, ok := st.prs[pr.ID]; ok {
+			return repository.ErrDuplicate
+		}
+		if _, ok := st.users[pr.AuthorID]; !ok {
+			return repository.ErrForeignKeyViolation
+		}
+
+		pr.Version = 1
+		st.prs[pr.ID] = copyPR(pr)
+		st.prOrder = append(st.prOrder, pr.ID)
+
+		return nil
+	})
+}
+
+func (r *PRRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.PullRequest, error) {
+	var pr *models.PullRequest
+
+	err := r.store.do(ctx, func(st *state) error {
+		p, ok := st.prs[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		pr = copyPR(p)
+		pr.Reviewers = copyReviewers(st.reviewers[id])
+		sortReviewers(pr.Reviewers)
+		return nil
+	})
+
+	return pr, err
+}
+
+// GetByIDForUpdate is GetByID: transactions are serialized, so nothing
+// changes the PR until the caller's transaction ends.
+func (r *PRRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*models.PullRequest, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *PRRepository) AssignReviewers(ctx context.Context, prID uuid.UUID, reviewers []*models.PRReviewer) error {
+	now := r.clock.Now()
+
+	return r.store.do(ctx, func(st *state) error {
+		if _, ok := st.prs[prID]; !ok {
+			return repository.ErrForeignKeyViolation
+		}
+
+		assigned := make([]*models.PRReviewer, 0, len(reviewers))
+		for i, rv := range reviewers {
+			if _, ok := st.users[rv.ID]; !ok {
+				return repository.ErrForeignKeyViolation
+			}
+			if slices.ContainsFunc(reviewers[:i], func(o *models.PRReviewer) bool { return o.ID == rv.ID }) {
+				return repository.ErrDuplicate
+			}
+			assigned = append(assigned, newReviewer(rv, prID, now))
+		}
+
+		st.reviewers[prID] = assigned
+		return nil
+	})
+}
+
+func (r *PRRepository) AddReviewer(ctx context.Context, prID uuid.UUID, reviewer *models.PRReviewer) error {
+	now := r.clock.Now()
+
+	return r.store.do(ctx, func(st *state) error {
+		if _, ok := st.prs[prID]; !ok {
+			return repository.ErrForeignKeyViolation
+		}
+		if _, ok := st.users[reviewer.ID]; !ok {
+			return repository.ErrForeignKeyViolation
+		}
+		if slices.ContainsFunc(st.reviewers[prID], func(rv *models.PRReviewer) bool { return rv.ID == reviewer.ID }) {
+			return repository.ErrDuplicate
+		}
+
+		st.reviewers[prID] = append(slices.Clone(st.reviewers[prID]), newReviewer(reviewer, prID, now))
+		return nil
+	})
+}
+
+func (r *PRRepository) RemoveReviewer(ctx context.Context, prID, id uuid.UUID) error {
+	return r.store.do(ctx, func(st *state) error {
+		current := st.reviewers[prID]
+
+		idx := slices.IndexFunc(current, func(rv *models.PRReviewer) bool { return rv.ID == id })
+		if idx < 0 {
+			return repository.ErrNotFound
+		}
+
+		st.reviewers[prID] = slices.Delete(slices.Clone(current), idx, idx+1)
+		return nil
+	})
+}
+
+func (r *PRRepository) ReplaceReviewer(ctx context.Context, prID, oldID uuid.UUID, reviewer *models.PRReviewer) error {
+	return r.replaceReviewer(ctx, prID, oldID, reviewer, "")
+}
+
+func (r *PRRepository) DeclineReviewer(
+	ctx context.Context,
+	prID, oldID uuid.UUID,
+	reviewer *models.PRReviewer,
+	reason models.DeclineReason,
+) error {
+	return r.replaceReviewer(ctx, prID, oldID, reviewer, reason)
+}
+
+// replaceReviewer records the reassignment with declineReason, empty when
+// the reviewer did not decline. A nil reviewer leaves nobody in place.
+func (r *PRRepository) replaceReviewer(
+	ctx context.Context,
+	prID, oldID uuid.UUID,
+	reviewer *models.PRReviewer,
+	declineReason models.DeclineReason,
+) error {
+	var newID uuid.UUID
+	if reviewer != nil {
+		newID = reviewer.ID
+	}
+	now := r.clock.Now()
+
+	return r.store.do(ctx, func(st *state) error {
+		current := st.reviewers[prID]
+
+		idx := slices.IndexFunc(current, func(rv *models.PRReviewer) bool { return rv.ID == oldID })
+		if idx < 0 {
+			return repository.ErrNotFound
+		}
+		if _, ok := st.users[newID]; reviewer != nil && !ok {
+			return repository.ErrForeignKeyViolation
+		}
+
+		st.reassignments = append(st.reassignments, reassignment{
+			prID:           prID,
+			fromUserID:     oldID,
+			toUserID:       newID,
+			fromAssignedAt: current[idx].AssignedAt,
+			reassignedAt:   now,
+			declineReason:  declineReason,
+		})
+
+		replaced := slices.Delete(slices.Clone(current), idx, idx+1)
+		if reviewer != nil && !slices.ContainsFunc(replaced, func(rv *models.PRReviewer) bool { return rv.ID == newID }) {
+			replaced = append(replaced, newReviewer(reviewer, prID, now))
+		}
+
+		st.reviewers[prID] = replaced
+		return nil
+	})
+}
+
+// newReviewer is the stored copy of an assignment, in the author's team pool
+// unless told otherwise.
+func newReviewer(rv *models.PRReviewer, prID uuid.UUID, at time.Time) *models.PRReviewer {
+	pool := rv.Pool
+	if pool == "" {
+		pool = models.ReviewerPoolTeam
+	}
+
+	return &models.PRReviewer{
+		ID:         rv.ID,
+		PRID:       prID,
+		AssignedAt: at,
+		Pool:       pool,
+		PoolTeam:   rv.PoolTeam,
+	}
+}
+
+func (r *PRRepository) ListDeclined(ctx context.Context, prID uuid.UUID) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0)
+
+	err := r.store.do(ctx, func(st *state) error {
+		for _, ra := range st.reassignments {
+			if ra.prID == prID && ra.declineReason != "" && !slices.Contains(ids, ra.fromUserID) {
+				ids = append(ids, ra.fromUserID)
+			}
+		}
+		return nil
+	})
+
+	return ids, err
+}
+
+func (r *PRRepository) BumpVersion(ctx context.Context, id uuid.UUID, version int64) error {
+	return r.store.do(ctx, func(st *state) error {
+		pr, ok := st.prs[id]
+		if !ok || pr.Version != version {
+			return repository.ErrNotFound
+		}
+
+		pr.Version++
+		return nil
+	})
+}
+
+func (r *PRRepository) Merge(ctx context.Context, id uuid.UUID) error {
+	now := r.clock.Now()
+
+	return r.store.do(ctx, func(st *state) error {
+		pr, ok := st.prs[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+
+		pr.Status = string(models.PRStatusMerged)
+		pr.MergedAt = &now
+		return nil
+	})
+}
+
+func (r *PRRepository) ListByReviewer(ctx context.Context, id uuid.UUID) ([]*models.PullRequest, error) {
+	prs := make([]*models.PullRequest, 0)
+
+	err := r.store.do(ctx, func(st *state) error {
+		for _, prID := range st.prOrder {
+			isReviewer := slices.ContainsFunc(st.reviewers[prID], func(rv *models.PRReviewer) bool {
+				return rv.ID == id
+			})
+			if isReviewer {
+				prs = append(prs, copyPR(st.prs[prID]))
+			}
+		}
+		sortPRs(prs)
+		return nil
+	})
+
+	return prs, err
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/service"
+)
+
+var _ service.SLARepository = (*SLARepository)(nil)
+
+type SLARepository struct {
+	store *Store
+}
+
+func NewSLARepository(store *Store) *SLARepository {
+	return &SLARepository{store: store}
+}
+
+func (r *SLARepository) ListOverdue(ctx context.Context, at time.Time) ([]*models.OverdueReview, error) {
+	overdue := make([]*models.OverdueReview, 0)
+
+	err := r.store.do(ctx, func(st *state) error {
+		for _, prID := range st.prOrder {
+			pr := st.prs[prID]
+			if pr.Status != string(models.PRStatusOpen) {
+				continue
+			}
+
+			author := st.users[pr.AuthorID]
+			if author == nil || author.TeamID == nil {
+				continue
+			}
+			team := st.teams[*author.TeamID]
+			if team == nil || team.ReviewSLA == nil {
+				continue
+			}
+
+			for _, rv := range st.reviewers[prID] {
+				if rv.AssignedAt.Add(team.ReviewSLA.Timeout).After(at) || st.escalated(prID, rv) {
+					continue
+				}
+
+				overdue = append(overdue, &models.OverdueReview{
+					PRID:       prID,
+					PRName:     pr.Name,
+					ReviewerID: rv.ID,
+					AssignedAt: rv.AssignedAt,
+					TeamID:     team.ID,
+					TeamName:   team.Name,
+					SLA:        *team.ReviewSLA,
+				})
+			}
+		}
+
+		slices.SortFunc(overdue, func(a, b *models.OverdueReview) int {
+			return cmp.Or(
+				a.AssignedAt.Compare(b.AssignedAt),
+				bytes.Compare(a.PRID[:], b.PRID[:]),
+				bytes.Compare(a.ReviewerID[:], b.ReviewerID[:]),
+			)
+		})
+		return nil
+	})
+
+	return overdue, err
+}
+
+func (r *SLARepository) RecordEscalation(ctx context.Context, e *models.ReviewEscalation) error {
+	return r.store.do(ctx, func(st *state) error {
+		if _, ok := st.prs[e.PRID]; !ok {
+			return repository.ErrForeignKeyViolation
+		}
+		if _, ok := st.users[e.ReviewerID]; !ok {
+			return repository.ErrForeignKeyViolation
+		}
+		if st.escalated(e.PRID, &models.PRReviewer{ID: e.ReviewerID, AssignedAt: e.AssignedAt}) {
+			return repository.ErrDuplicate
+		}
+
+		c := *e
+		st.escalations = append(st.escalations, &c)
+		return nil
+	})
+}
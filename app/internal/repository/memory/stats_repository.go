@@ -0,0 +1,258 @@
+package memory
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/service"
+
+	"github.com/google/uuid"
+)
+
+var _ service.StatsRepository = (*StatsRepository)(nil)
+
+type StatsRepository struct {
+	store *Store
+}
+
+func NewStatsRepository(store *Store) *StatsRepository {
+	return &StatsRepository{store: store}
+}
+
+func (r *StatsRepository) ReviewerStats(
+	ctx context.Context,
+	from, to time.Time,
+	scope uuid.UUID,
+) ([]*models.ReviewerStats, error) {
+	stats := make([]*models.ReviewerStats, 0)
+
+	err := r.store.do(ctx, func(st *state) error {
+		inScope := st.inScope(scope)
+		for _, id := range st.userOrder {
+			u := st.users[id]
+			if !inScope(u.TeamID) {
+				continue
+			}
+
+			s := &models.ReviewerStats{
+				UserID:     u.ID,
+				Username:   u.Name,
+				ReviewLoad: st.reviewLoad(from, to, func(userID uuid.UUID) bool { return userID == u.ID }),
+			}
+			if u.TeamID != nil {
+				s.TeamName = st.teams[*u.TeamID].Name
+			}
+			stats = append(stats, s)
+		}
+		return nil
+	})
+
+	slices.SortFunc(stats, func(a, b *models.ReviewerStats) int {
+		return cmp.Or(
+			cmp.Compare(a.TeamName, b.TeamName),
+			cmp.Compare(a.Username, b.Username),
+			bytes.Compare(a.UserID[:], b.UserID[:]),
+		)
+	})
+
+	return stats, err
+}
+
+func (r *StatsRepository) TeamReviewStats(
+	ctx context.Context,
+	from, to time.Time,
+	scope uuid.UUID,
+) ([]*models.TeamReviewStats, error) {
+	stats := make([]*models.TeamReviewStats, 0)
+
+	err := r.store.do(ctx, func(st *state) error {
+		inScope := st.inScope(scope)
+		for _, id := range st.teamOrder {
+			t := st.teams[id]
+			if !inScope(&t.ID) {
+				continue
+			}
+
+			inSubtree := st.inSubtree(t.ID)
+			s := &models.TeamReviewStats{
+				TeamID:   t.ID,
+				TeamName: t.Name,
+				ReviewLoad: st.reviewLoad(from, to, func(userID uuid.UUID) bool {
+					u := st.users[userID]
+					return u != nil && u.TeamID != nil && *u.TeamID == t.ID
+				}),
+				Subtree: st.reviewLoad(from, to, func(userID uuid.UUID) bool {
+					u := st.users[userID]
+					return u != nil && u.TeamID != nil && inSubtree[*u.TeamID]
+				}),
+			}
+			if t.ParentID != nil {
+				s.ParentName = st.teams[*t.ParentID].Name
+			}
+			stats = append(stats, s)
+		}
+		return nil
+	})
+
+	slices.SortFunc(stats, func(a, b *models.TeamReviewStats) int {
+		return cmp.Compare(a.TeamName, b.TeamName)
+	})
+
+	return stats, err
+}
+
+// inScope reports whether a team is in the subtree of scope. With uuid.Nil
+// every team, and no team at all, is in scope.
+func (st *state) inScope(scope uuid.UUID) func(teamID *uuid.UUID) bool {
+	if scope == uuid.Nil {
+		return func(*uuid.UUID) bool { return true }
+	}
+
+	inSubtree := st.inSubtree(scope)
+	return func(teamID *uuid.UUID) bool {
+		return teamID != nil && inSubtree[*teamID]
+	}
+}
+
+// reviewLoad aggregates the reviews of users matched by isReviewer within [from, to).
+func (st *state) reviewLoad(from, to time.Time, isReviewer func(userID uuid.UUID) bool) models.ReviewLoad {
+	inWindow := func(t time.Time) bool {
+		return !t.Before(from) && t.Before(to)
+	}
+
+	var (
+		load      models.ReviewLoad
+		durations []time.Duration
+	)
+
+	for prID, reviewers := range st.reviewers {
+		pr := st.prs[prID]
+
+		for _, rv := range reviewers {
+			if !isReviewer(rv.ID) {
+				continue
+			}
+
+			if pr.Status == string(models.PRStatusOpen) {
+				load.OpenAssigned++
+			}
+			if inWindow(rv.AssignedAt) {
+				load.AssignedTotal++
+				if pr.MergedAt != nil {
+					durations = append(durations, pr.MergedAt.Sub(rv.AssignedAt))
+				}
+			}
+		}
+	}
+
+	for _, ra := range st.reassignments {
+		if !isReviewer(ra.fromUserID) {
+			continue
+		}
+
+		if inWindow(ra.fromAssignedAt) {
+			load.AssignedTotal++
+		}
+		if inWindow(ra.reassignedAt) {
+			load.ReassignedAway++
+			if ra.declineReason != "" {
+				load.Declined++
+			}
+		}
+	}
+
+	load.MedianTimeToMerge = repository.MedianDuration(durations)
+	return load
+}
+
+func (r *StatsRepository) ListPRCycles(
+	ctx context.Context,
+	from, to time.Time,
+	scope uuid.UUID,
+) ([]*models.PRCycle, error) {
+	cycles := make([]*models.PRCycle, 0)
+
+	err := r.StreamPRCycles(ctx, from, to, scope, func(c *models.PRCycle) error {
+		cycles = append(cycles, c)
+		return nil
+	})
+
+	return cycles, err
+}
+
+// StreamPRCycles collects the rows under the store lock and calls fn after
+// releasing it, so a slow consumer does not block writers.
+func (r *StatsRepository) StreamPRCycles(
+	ctx context.Context,
+	from, to time.Time,
+	scope uuid.UUID,
+	fn func(*models.PRCycle) error,
+) error {
+	var cycles []*models.PRCycle
+
+	err := r.store.do(ctx, func(st *state) error {
+		inScope := st.inScope(scope)
+		for _, id := range st.prOrder {
+			pr := st.prs[id]
+			if pr.CreatedAt.Before(from) || !pr.CreatedAt.Before(to) || !inScope(st.users[pr.AuthorID].TeamID) {
+				continue
+			}
+
+			c := &models.PRCycle{
+				PRID:      pr.ID,
+				Name:      pr.Name,
+				AuthorID:  pr.AuthorID,
+				Status:    pr.Status,
+				CreatedAt: pr.CreatedAt,
+				MergedAt:  copyPR(pr).MergedAt,
+			}
+			if author := st.users[pr.AuthorID]; author.TeamID != nil {
+				c.TeamName = st.teams[*author.TeamID].Name
+			}
+			c.FirstAssignedAt = st.firstAssignedAt(pr.ID)
+
+			cycles = append(cycles, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	slices.SortFunc(cycles, func(a, b *models.PRCycle) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), bytes.Compare(a.PRID[:], b.PRID[:]))
+	})
+
+	for _, c := range cycles {
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// firstAssignedAt returns the earliest assignment to the PR, including
+// reviewers that were reassigned away, or nil if it never had reviewers.
+func (st *state) firstAssignedAt(prID uuid.UUID) *time.Time {
+	var first *time.Time
+	earlier := func(t time.Time) {
+		if first == nil || t.Before(*first) {
+			first = &t
+		}
+	}
+
+	for _, rv := range st.reviewers[prID] {
+		earlier(rv.AssignedAt)
+	}
+	for _, ra := range st.reassignments {
+		if ra.prID == prID {
+			earlier(ra.fromAssignedAt)
+		}
+	}
+	return first
+}
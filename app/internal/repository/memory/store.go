@@ -0,0 +1,315 @@
+// Package memory implements the service repositories on top of in-process
+// maps. It is meant for tests and demo mode: data lives only as long as the
+// Store, and transactions are serialized.
+package memory
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"pr-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Store holds the data shared by the memory repositories.
+type Store struct {
+	// txMu serializes transactions and standalone repository calls,
+	// so a rollback never discards another caller's writes.
+	txMu sync.Mutex
+	mu   sync.Mutex
+	st   *state
+}
+
+// state is the snapshot-able content of a Store.
+type state struct {
+	teams     map[uuid.UUID]*models.Team
+	teamOrder []uuid.UUID
+
+	users     map[uuid.UUID]*models.User
+	userOrder []uuid.UUID
+
+	prs     map[uuid.UUID]*models.PullRequest
+	prOrder []uuid.UUID
+
+	// reviewers by pull request ID, in assignment order
+	reviewers map[uuid.UUID][]*models.PRReviewer
+
+	reassignments []reassignment
+
+	// out-of-office windows by ID; readers sort them
+	availability map[uuid.UUID]*models.Availability
+
+	escalations []*models.ReviewEscalation
+
+	// last day a team digest went out to every reviewer, YYYY-MM-DD by team ID
+	digestSentOn map[uuid.UUID]string
+	// digests delivered to a reviewer
+	digestDeliveries map[digestDelivery]bool
+
+	// code host users and pull requests linked to ours
+	externalUsers map[externalUser]uuid.UUID
+	externalPRs   map[models.ExternalPR]uuid.UUID
+
+	// CODEOWNERS rules by team ID; a rule set is replaced, never modified
+	codeOwners map[uuid.UUID][]models.CodeOwnerRule
+
+	// fallback team IDs by team ID, in order; a list is replaced, never modified
+	fallbackPools map[uuid.UUID][]uuid.UUID
+}
+
+// externalUser is a user login on a code host.
+type externalUser struct {
+	provider string
+	login    string
+}
+
+// digestDelivery is a team digest of a day delivered to a user.
+type digestDelivery struct {
+	teamID uuid.UUID
+	userID uuid.UUID
+	day    string
+}
+
+// reassignment is a row of the reviewer reassignment history.
+type reassignment struct {
+	prID           uuid.UUID
+	fromUserID     uuid.UUID
+	toUserID       uuid.UUID // uuid.Nil when nobody took over
+	fromAssignedAt time.Time
+	reassignedAt   time.Time
+	declineReason  models.DeclineReason // empty unless the reviewer declined
+}
+
+// NewStore returns an empty Store.
+func NewStore() *Store {
+	return &Store{
+		st: &state{
+			teams:     make(map[uuid.UUID]*models.Team),
+			users:     make(map[uuid.UUID]*models.User),
+			prs:       make(map[uuid.UUID]*models.PullRequest),
+			reviewers: make(map[uuid.UUID][]*models.PRReviewer),
+
+			availability:     make(map[uuid.UUID]*models.Availability),
+			digestSentOn:     make(map[uuid.UUID]string),
+			digestDeliveries: make(map[digestDelivery]bool),
+
+			externalUsers: make(map[externalUser]uuid.UUID),
+			externalPRs:   make(map[models.ExternalPR]uuid.UUID),
+
+			codeOwners:    make(map[uuid.UUID][]models.CodeOwnerRule),
+			fallbackPools: make(map[uuid.UUID][]uuid.UUID),
+		},
+	}
+}
+
+type txKey struct{}
+
+// inTx reports whether ctx belongs to a transaction opened by TxManager.
+func inTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*Store)
+	return ok
+}
+
+// do runs fn against the store state. Outside a transaction the call is
+// serialized with transactions, like an autocommit statement.
+func (s *Store) do(ctx context.Context, fn func(st *state) error) error {
+	if !inTx(ctx) {
+		s.txMu.Lock()
+		defer s.txMu.Unlock()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return fn(s.st)
+}
+
+// clone returns a deep copy of the state.
+func (st *state) clone() *state {
+	c := &state{
+		teams:     make(map[uuid.UUID]*models.Team, len(st.teams)),
+		teamOrder: slices.Clone(st.teamOrder),
+		users:     make(map[uuid.UUID]*models.User, len(st.users)),
+		userOrder: slices.Clone(st.userOrder),
+		prs:       make(map[uuid.UUID]*models.PullRequest, len(st.prs)),
+		prOrder:   slices.Clone(st.prOrder),
+		reviewers: make(map[uuid.UUID][]*models.PRReviewer, len(st.reviewers)),
+
+		reassignments: slices.Clone(st.reassignments),
+
+		availability: make(map[uuid.UUID]*models.Availability, len(st.availability)),
+
+		escalations:      slices.Clone(st.escalations),
+		digestSentOn:     maps.Clone(st.digestSentOn),
+		digestDeliveries: maps.Clone(st.digestDeliveries),
+
+		externalUsers: maps.Clone(st.externalUsers),
+		externalPRs:   maps.Clone(st.externalPRs),
+
+		codeOwners:    maps.Clone(st.codeOwners),
+		fallbackPools: maps.Clone(st.fallbackPools),
+	}
+
+	for id, t := range st.teams {
+		c.teams[id] = copyTeam(t)
+	}
+	for id, u := range st.users {
+		c.users[id] = copyUser(u)
+	}
+	for id, pr := range st.prs {
+		c.prs[id] = copyPR(pr)
+	}
+	for id, rs := range st.reviewers {
+		c.reviewers[id] = copyReviewers(rs)
+	}
+	for id, a := range st.availability {
+		c.availability[id] = copyAvailability(a)
+	}
+
+	return c
+}
+
+func copyTeam(t *models.Team) *models.Team {
+	c := &models.Team{
+		ID:        t.ID,
+		Name:      t.Name,
+		ReviewSLA: copySLA(t.ReviewSLA),
+		Digest:    copyDigest(t.Digest),
+		Policy:    copyPolicy(t.Policy),
+	}
+	if t.ParentID != nil {
+		parentID := *t.ParentID
+		c.ParentID = &parentID
+	}
+	return c
+}
+
+func copySLA(sla *models.ReviewSLA) *models.ReviewSLA {
+	if sla == nil {
+		return nil
+	}
+	c := *sla
+	return &c
+}
+
+func copyDigest(d *models.DigestSchedule) *models.DigestSchedule {
+	if d == nil {
+		return nil
+	}
+	c := *d
+	return &c
+}
+
+func copyPolicy(p *models.ReviewPolicy) *models.ReviewPolicy {
+	if p == nil {
+		return nil
+	}
+	c := *p
+	return &c
+}
+
+func copyUser(u *models.User) *models.User {
+	c := *u
+	if u.TeamID != nil {
+		teamID := *u.TeamID
+		c.TeamID = &teamID
+	}
+	return &c
+}
+
+func copyPR(pr *models.PullRequest) *models.PullRequest {
+	c := *pr
+	if pr.MergedAt != nil {
+		mergedAt := *pr.MergedAt
+		c.MergedAt = &mergedAt
+	}
+	c.Reviewers = nil
+	c.Files = nil
+	return &c
+}
+
+func copyReviewers(rs []*models.PRReviewer) []*models.PRReviewer {
+	c := make([]*models.PRReviewer, len(rs))
+	for i, r := range rs {
+		rc := *r
+		c[i] = &rc
+	}
+	return c
+}
+
+func copyCodeOwners(rules []models.CodeOwnerRule) []models.CodeOwnerRule {
+	c := make([]models.CodeOwnerRule, len(rules))
+	for i, rule := range rules {
+		c[i] = models.CodeOwnerRule{Pattern: rule.Pattern, Owners: slices.Clone(rule.Owners)}
+		if c[i].Owners == nil {
+			c[i].Owners = []models.CodeOwner{}
+		}
+	}
+	return c
+}
+
+func copyAvailability(a *models.Availability) *models.Availability {
+	c := *a
+	if a.DeactivatedAt != nil {
+		deactivatedAt := *a.DeactivatedAt
+		c.DeactivatedAt = &deactivatedAt
+	}
+	if a.RestoredAt != nil {
+		restoredAt := *a.RestoredAt
+		c.RestoredAt = &restoredAt
+	}
+	return &c
+}
+
+// away reports whether an availability window of the user covers at.
+func (st *state) away(userID uuid.UUID, at time.Time) bool {
+	for _, a := range st.availability {
+		if a.UserID == userID && a.Covers(at) {
+			return true
+		}
+	}
+	return false
+}
+
+// escalated reports whether the assignment has an escalation recorded.
+func (st *state) escalated(prID uuid.UUID, rv *models.PRReviewer) bool {
+	for _, e := range st.escalations {
+		if e.PRID == prID && e.ReviewerID == rv.ID && e.AssignedAt.Equal(rv.AssignedAt) {
+			return true
+		}
+	}
+	return false
+}
+
+// The sort helpers mirror the ORDER BY clauses of the Postgres repositories.
+// UUIDs compare bytewise, as in Postgres.
+
+func sortUsers(users []*models.User) {
+	slices.SortFunc(users, func(a, b *models.User) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), bytes.Compare(a.ID[:], b.ID[:]))
+	})
+}
+
+func sortReviewers(rs []*models.PRReviewer) {
+	slices.SortFunc(rs, func(a, b *models.PRReviewer) int {
+		return cmp.Or(a.AssignedAt.Compare(b.AssignedAt), bytes.Compare(a.ID[:], b.ID[:]))
+	})
+}
+
+func sortAvailability(windows []*models.Availability, key func(a *models.Availability) time.Time) {
+	slices.SortFunc(windows, func(a, b *models.Availability) int {
+		return cmp.Or(key(a).Compare(key(b)), bytes.Compare(a.ID[:], b.ID[:]))
+	})
+}
+
+func sortPRs(prs []*models.PullRequest) {
+	slices.SortFunc(prs, func(a, b *models.PullRequest) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), bytes.Compare(a.ID[:], b.ID[:]))
+	})
+}
@@ -0,0 +1,258 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/service"
+
+	"github.com/google/uuid"
+)
+
+var _ service.TeamRepository = (*TeamRepository)(nil)
+
+type TeamRepository struct {
+	store *Store
+}
+
+func NewTeamRepository(store *Store) *TeamRepository {
+	return &TeamRepository{store: store}
+}
+
+func (r *TeamRepository) Create(ctx context.Context, t *models.Team) error {
+	return r.store.do(ctx, func(st *state) error {
+		for _, existing := range st.teams {
+			if existing.Name == t.Name {
+				return repository.ErrDuplicate
+			}
+		}
+		if t.ParentID != nil {
+			if _, ok := st.teams[*t.ParentID]; !ok {
+				return repository.ErrForeignKeyViolation
+			}
+		}
+
+		t.ID = uuid.New()
+		st.teams[t.ID] = copyTeam(t)
+		st.teamOrder = append(st.teamOrder, t.ID)
+
+		return nil
+	})
+}
+
+func (r *TeamRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Team, error) {
+	var team *models.Team
+
+	err := r.store.do(ctx, func(st *state) error {
+		t, ok := st.teams[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		team = copyTeam(t)
+		return nil
+	})
+
+	return team, err
+}
+
+func (r *TeamRepository) GetByName(ctx context.Context, name string) (*models.Team, error) {
+	var team *models.Team
+
+	err := r.store.do(ctx, func(st *state) error {
+		for _, t := range st.teams {
+			if t.Name == name {
+				team = copyTeam(t)
+				return nil
+			}
+		}
+		return repository.ErrNotFound
+	})
+
+	return team, err
+}
+
+func (r *TeamRepository) SetReviewSLA(ctx context.Context, id uuid.UUID, sla *models.ReviewSLA) error {
+	return r.store.do(ctx, func(st *state) error {
+		t, ok := st.teams[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		t.ReviewSLA = copySLA(sla)
+		return nil
+	})
+}
+
+func (r *TeamRepository) SetDigestSchedule(ctx context.Context, id uuid.UUID, d *models.DigestSchedule) error {
+	return r.store.do(ctx, func(st *state) error {
+		t, ok := st.teams[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		t.Digest = copyDigest(d)
+		return nil
+	})
+}
+
+func (r *TeamRepository) SetReviewPolicy(ctx context.Context, id uuid.UUID, p *models.ReviewPolicy) error {
+	return r.store.do(ctx, func(st *state) error {
+		t, ok := st.teams[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		t.Policy = copyPolicy(p)
+		return nil
+	})
+}
+
+func (r *TeamRepository) SetParent(ctx context.Context, id uuid.UUID, parentID *uuid.UUID) error {
+	return r.store.do(ctx, func(st *state) error {
+		t, ok := st.teams[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		if parentID == nil {
+			t.ParentID = nil
+			return nil
+		}
+		if _, ok := st.teams[*parentID]; !ok {
+			return repository.ErrForeignKeyViolation
+		}
+
+		p := *parentID
+		t.ParentID = &p
+		return nil
+	})
+}
+
+// LockHierarchy does nothing: transactions are serialized.
+func (r *TeamRepository) LockHierarchy(ctx context.Context) error {
+	return nil
+}
+
+func (r *TeamRepository) GetSubtree(ctx context.Context, id uuid.UUID) ([]*models.Team, error) {
+	teams := make([]*models.Team, 0)
+
+	err := r.store.do(ctx, func(st *state) error {
+		for _, t := range st.subtree(id) {
+			c := copyTeam(t)
+			teams = append(teams, &models.Team{ID: c.ID, Name: c.Name, ParentID: c.ParentID})
+		}
+		return nil
+	})
+
+	return teams, err
+}
+
+// subtree returns the team and all teams below it, level by level and by
+// name within a level, like the recursive query of the SQL backends.
+func (st *state) subtree(id uuid.UUID) []*models.Team {
+	root, ok := st.teams[id]
+	if !ok {
+		return nil
+	}
+
+	all := []*models.Team{root}
+	seen := map[uuid.UUID]bool{id: true}
+	for level := all; len(level) > 0; {
+		var next []*models.Team
+		for _, parent := range level {
+			for _, teamID := range st.teamOrder {
+				if t := st.teams[teamID]; t.ParentID != nil && *t.ParentID == parent.ID && !seen[t.ID] {
+					seen[t.ID] = true
+					next = append(next, t)
+				}
+			}
+		}
+		slices.SortFunc(next, func(a, b *models.Team) int { return strings.Compare(a.Name, b.Name) })
+
+		all = append(all, next...)
+		level = next
+	}
+
+	return all
+}
+
+// inSubtree returns the IDs of the team and all teams below it.
+func (st *state) inSubtree(id uuid.UUID) map[uuid.UUID]bool {
+	ids := make(map[uuid.UUID]bool)
+	for _, t := range st.subtree(id) {
+		ids[t.ID] = true
+	}
+	return ids
+}
+
+func (r *TeamRepository) SetCodeOwners(ctx context.Context, id uuid.UUID, rules []models.CodeOwnerRule) error {
+	return r.store.do(ctx, func(st *state) error {
+		if _, ok := st.teams[id]; !ok {
+			return repository.ErrForeignKeyViolation
+		}
+		for _, rule := range rules {
+			for _, o := range rule.Owners {
+				if _, ok := st.users[o.UserID]; o.UserID != uuid.Nil && !ok {
+					return repository.ErrForeignKeyViolation
+				}
+				if _, ok := st.teams[o.TeamID]; o.TeamID != uuid.Nil && !ok {
+					return repository.ErrForeignKeyViolation
+				}
+			}
+		}
+
+		if len(rules) == 0 {
+			delete(st.codeOwners, id)
+			return nil
+		}
+		st.codeOwners[id] = copyCodeOwners(rules)
+		return nil
+	})
+}
+
+func (r *TeamRepository) GetCodeOwners(ctx context.Context, id uuid.UUID) ([]models.CodeOwnerRule, error) {
+	var rules []models.CodeOwnerRule
+
+	err := r.store.do(ctx, func(st *state) error {
+		rules = copyCodeOwners(st.codeOwners[id])
+		return nil
+	})
+
+	return rules, err
+}
+
+func (r *TeamRepository) SetFallbackPools(ctx context.Context, id uuid.UUID, poolIDs []uuid.UUID) error {
+	return r.store.do(ctx, func(st *state) error {
+		if _, ok := st.teams[id]; !ok {
+			return repository.ErrForeignKeyViolation
+		}
+		for i, poolID := range poolIDs {
+			if _, ok := st.teams[poolID]; !ok {
+				return repository.ErrForeignKeyViolation
+			}
+			if slices.Contains(poolIDs[:i], poolID) {
+				return repository.ErrDuplicate
+			}
+		}
+
+		if len(poolIDs) == 0 {
+			delete(st.fallbackPools, id)
+			return nil
+		}
+		st.fallbackPools[id] = slices.Clone(poolIDs)
+		return nil
+	})
+}
+
+func (r *TeamRepository) GetFallbackPools(ctx context.Context, id uuid.UUID) ([]*models.Team, error) {
+	teams := make([]*models.Team, 0)
+
+	err := r.store.do(ctx, func(st *state) error {
+		for _, poolID := range st.fallbackPools[id] {
+			t := st.teams[poolID]
+			teams = append(teams, &models.Team{ID: t.ID, Name: t.Name})
+		}
+		return nil
+	})
+
+	return teams, err
+}
@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"context"
+
+	"pr-service/internal/service"
+)
+
+var _ service.TxManager = (*TxManager)(nil)
+
+// TxManager runs functions in serialized transactions over a Store.
+// On error or panic every change made inside the transaction is rolled back.
+type TxManager struct {
+	store *Store
+}
+
+func NewTxManager(store *Store) *TxManager {
+	return &TxManager{store: store}
+}
+
+// Do runs fn inside a transaction. Nested calls join the outer transaction.
+func (m *TxManager) Do(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if inTx(ctx) {
+		return fn(ctx)
+	}
+
+	s := m.store
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+
+	s.mu.Lock()
+	snapshot := s.st.clone()
+	s.mu.Unlock()
+
+	rollback := func() {
+		s.mu.Lock()
+		s.st = snapshot
+		s.mu.Unlock()
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			rollback()
+			panic(p)
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, s)); err != nil {
+		rollback()
+	}
+
+	return err
+}
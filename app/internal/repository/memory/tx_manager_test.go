@@ -0,0 +1,85 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/repository/memory"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxManager(t *testing.T) {
+	ctx := t.Context()
+	store := memory.NewStore()
+	trManager := memory.NewTxManager(store)
+	teamRepo := memory.NewTeamRepository(store)
+	userRepo := memory.NewUserRepository(store)
+
+	t.Run("commit", func(t *testing.T) {
+		team := &models.Team{Name: "committed"}
+		err := trManager.Do(ctx, func(ctx context.Context) error {
+			return teamRepo.Create(ctx, team)
+		})
+		require.NoError(t, err)
+
+		_, err = teamRepo.GetByID(ctx, team.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("rollback on error", func(t *testing.T) {
+		errRollback := errors.New("rollback")
+		team := &models.Team{Name: "rolled-back"}
+		user := &models.User{Name: "user", IsActive: true}
+
+		err := trManager.Do(ctx, func(ctx context.Context) error {
+			require.NoError(t, teamRepo.Create(ctx, team))
+			user.TeamID = &team.ID
+			require.NoError(t, userRepo.Create(ctx, user))
+
+			// visible inside the transaction
+			_, err := userRepo.GetUserByID(ctx, user.ID)
+			require.NoError(t, err)
+
+			return errRollback
+		})
+		require.ErrorIs(t, err, errRollback)
+
+		_, err = teamRepo.GetByName(ctx, team.Name)
+		require.ErrorIs(t, err, repository.ErrNotFound)
+		_, err = userRepo.GetUserByID(ctx, user.ID)
+		require.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run("rollback on panic", func(t *testing.T) {
+		team := &models.Team{Name: "panicked"}
+
+		require.Panics(t, func() {
+			_ = trManager.Do(ctx, func(ctx context.Context) error {
+				require.NoError(t, teamRepo.Create(ctx, team))
+				panic("boom")
+			})
+		})
+
+		_, err := teamRepo.GetByName(ctx, team.Name)
+		require.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run("nested joins outer", func(t *testing.T) {
+		team := &models.Team{Name: "nested"}
+
+		err := trManager.Do(ctx, func(ctx context.Context) error {
+			require.NoError(t, trManager.Do(ctx, func(ctx context.Context) error {
+				return teamRepo.Create(ctx, team)
+			}))
+			return errors.New("outer fails")
+		})
+		require.Error(t, err)
+
+		_, err = teamRepo.GetByName(ctx, team.Name)
+		require.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
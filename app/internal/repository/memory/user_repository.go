@@ -0,0 +1,150 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/service"
+
+	"github.com/google/uuid"
+)
+
+var _ service.UserRepository = (*UserRepository)(nil)
+
+type UserRepository struct {
+	store *Store
+}
+
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{store: store}
+}
+
+func (r *UserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	var user *models.User
+
+	err := r.store.do(ctx, func(st *state) error {
+		u, ok := st.users[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		user = copyUser(u)
+		return nil
+	})
+
+	return user, err
+}
+
+func (r *UserRepository) GetActiveByTeam(ctx context.Context, teamID uuid.UUID) ([]*models.User, error) {
+	return r.getUsersBy(ctx, func(u *models.User) bool {
+		return u.TeamID != nil && *u.TeamID == teamID && u.IsActive
+	})
+}
+
+// GetAvailableByTeam returns active team members without an
+// out-of-office window covering at.
+func (r *UserRepository) GetAvailableByTeam(ctx context.Context, teamID uuid.UUID, at time.Time) ([]*models.User, error) {
+	users := make([]*models.User, 0)
+
+	err := r.store.do(ctx, func(st *state) error {
+		for _, id := range st.userOrder {
+			u := st.users[id]
+			if u.TeamID != nil && *u.TeamID == teamID && u.IsActive && !st.away(id, at) {
+				users = append(users, copyUser(u))
+			}
+		}
+		sortUsers(users)
+		return nil
+	})
+
+	return users, err
+}
+
+func (r *UserRepository) GetByTeam(ctx context.Context, teamID uuid.UUID) ([]*models.User, error) {
+	return r.getUsersBy(ctx, func(u *models.User) bool {
+		return u.TeamID != nil && *u.TeamID == teamID
+	})
+}
+
+// GetBySubtree returns members of the team and of all its sub-teams.
+func (r *UserRepository) GetBySubtree(ctx context.Context, teamID uuid.UUID) ([]*models.User, error) {
+	users := make([]*models.User, 0)
+
+	err := r.store.do(ctx, func(st *state) error {
+		inSubtree := st.inSubtree(teamID)
+		for _, id := range st.userOrder {
+			if u := st.users[id]; u.TeamID != nil && inSubtree[*u.TeamID] {
+				users = append(users, copyUser(u))
+			}
+		}
+		sortUsers(users)
+		return nil
+	})
+
+	return users, err
+}
+
+func (r *UserRepository) ListByEmail(ctx context.Context, email string) ([]*models.User, error) {
+	return r.getUsersBy(ctx, func(u *models.User) bool {
+		return u.Email != "" && strings.EqualFold(u.Email, email)
+	})
+}
+
+func (r *UserRepository) getUsersBy(ctx context.Context, match func(u *models.User) bool) ([]*models.User, error) {
+	users := make([]*models.User, 0)
+
+	err := r.store.do(ctx, func(st *state) error {
+		for _, id := range st.userOrder {
+			if u := st.users[id]; match(u) {
+				users = append(users, copyUser(u))
+			}
+		}
+		sortUsers(users)
+		return nil
+	})
+
+	return users, err
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	return r.store.do(ctx, func(st *state) error {
+		if user.TeamID != nil {
+			if _, ok := st.teams[*user.TeamID]; !ok {
+				return repository.ErrForeignKeyViolation
+			}
+		}
+
+		if user.Role == "" {
+			user.Role = models.DefaultRole
+		}
+		user.ID = uuid.New()
+		st.users[user.ID] = copyUser(user)
+		st.userOrder = append(st.userOrder, user.ID)
+
+		return nil
+	})
+}
+
+func (r *UserRepository) UpdateActive(ctx context.Context, id uuid.UUID, active bool) error {
+	return r.store.do(ctx, func(st *state) error {
+		u, ok := st.users[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		u.IsActive = active
+		return nil
+	})
+}
+
+func (r *UserRepository) UpdateRole(ctx context.Context, id uuid.UUID, role models.Role) error {
+	return r.store.do(ctx, func(st *state) error {
+		u, ok := st.users[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		u.Role = role
+		return nil
+	})
+}
@@ -0,0 +1,121 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"errors"
+	"fmt"
+	"pr-service/internal/clock"
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/service"
+	"sync"
+	"testing"
+
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPRRepository_ConcurrentReassignAndMerge(t *testing.T) {
+	ctx := t.Context()
+
+	prRepo := repository.NewPRRepository(db, trmpgx.DefaultCtxGetter, retrier, clock.Real())
+	svc := service.NewPRService(
+		repository.NewTeamRepository(db, trmpgx.DefaultCtxGetter, retrier),
+		repository.NewUserRepository(db, trmpgx.DefaultCtxGetter, retrier),
+		prRepo,
+		nil,
+		service.NewCommitHooks(manager.Must(trmpgx.NewDefaultFactory(db))),
+		clock.Real(),
+		zap.NewNop(),
+	)
+
+	team := &models.Team{Name: "locking-" + uuid.NewString()}
+	for i := range 9 {
+		team.Members = append(team.Members, &models.User{Name: fmt.Sprintf("u%d", i), IsActive: true})
+	}
+	require.NoError(t, svc.TeamAdd(ctx, team))
+
+	pr := &models.PullRequest{ID: uuid.New(), Name: "locking", AuthorID: team.Members[0].ID, Status: string(models.PRStatusOpen)}
+	require.NoError(t, svc.CreatePR(ctx, pr))
+	reviewers := len(pr.Reviewers)
+	require.Equal(t, 2, reviewers)
+
+	const workers, attempts = 8, 20
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		errs     []error
+		counts   []int
+		merged   *models.PullRequest
+		mergeErr error
+		start    = make(chan struct{})
+	)
+
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			for range attempts {
+				current, err := prRepo.GetByID(ctx, pr.ID)
+				if err == nil && len(current.Reviewers) > 0 {
+					var got *models.PullRequest
+					got, _, err = svc.PRReassign(ctx, pr.ID, current.Reviewers[0].ID, service.ReassignOptions{})
+					if err == nil {
+						mu.Lock()
+						counts = append(counts, len(got.Reviewers))
+						mu.Unlock()
+					}
+				}
+
+				// a reviewer reassigned by another worker since the read is
+				// not assigned anymore, and nobody is after the merge
+				if err != nil &&
+					!errors.Is(err, service.ErrNotAssinged) &&
+					!errors.Is(err, service.ErrCanNotReassing) &&
+					!errors.Is(err, service.ErrNoAvailableReviewer) {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-start
+		merged, mergeErr = svc.PRMerge(ctx, pr.ID)
+	}()
+
+	close(start)
+	wg.Wait()
+
+	require.NoError(t, mergeErr)
+	require.Empty(t, errs)
+	for _, n := range counts {
+		require.Equal(t, reviewers, n)
+	}
+
+	actual, err := prRepo.GetByID(ctx, pr.ID)
+	require.NoError(t, err)
+	require.Equal(t, string(models.PRStatusMerged), actual.Status)
+	require.Len(t, actual.Reviewers, reviewers)
+	require.Equal(t, reviewerSet(merged.Reviewers), reviewerSet(actual.Reviewers), "reviewers changed after the merge")
+}
+
+func reviewerSet(reviewers []*models.PRReviewer) map[uuid.UUID]bool {
+	set := make(map[uuid.UUID]bool, len(reviewers))
+	for _, r := range reviewers {
+		set[r.ID] = true
+	}
+	return set
+}
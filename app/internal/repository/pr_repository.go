@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"pr-service/internal/clock"
 	"pr-service/internal/models"
 	"pr-service/internal/retry"
 	"time"
@@ -18,21 +19,24 @@ type PRRepository struct {
 	getter  *trmpgx.CtxGetter
 	psql    sq.StatementBuilderType
 	retrier retry.Retrier
+	clock   clock.Clock
 }
 
-func NewPRRepository(db *pgxpool.Pool, c *trmpgx.CtxGetter, r retry.Retrier) *PRRepository {
+func NewPRRepository(db *pgxpool.Pool, c *trmpgx.CtxGetter, r retry.Retrier, clk clock.Clock) *PRRepository {
 	return &PRRepository{
 		db:      db,
 		getter:  c,
 		psql:    sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
 		retrier: r,
+		clock:   clk,
 	}
 }
 
 func (r *PRRepository) Create(ctx context.Context, pr *models.PullRequest) error {
+	pr.Version = 1
 	query := r.psql.Insert("pull_requests").
-		Columns("id", "name", "author_id", "status", "created_at").
-		Values(pr.ID, pr.Name, pr.AuthorID, pr.Status, pr.CreatedAt)
+		Columns("id", "name", "author_id", "status", "created_at", "version").
+		Values(pr.ID, pr.Name, pr.AuthorID, pr.Status, pr.CreatedAt, pr.Version)
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return err
@@ -40,7 +44,7 @@ func (r *PRRepository) Create(ctx context.Context, pr *models.PullRequest) error
 
 	conn := r.getter.DefaultTrOrDB(ctx, r.db)
 
-	err = r.retrier.Do(ctx, func() error {
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
 		_, retryErr := conn.Exec(ctx, sql, args...)
 		return retryErr
 	})
@@ -56,11 +60,15 @@ func (r *PRRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.PullR
 		"pr.status",
 		"pr.created_at",
 		"pr.merged_at",
+		"pr.version",
 		"r.id",
 		"r.assigned_at",
+		"r.pool",
+		"r.pool_team",
 	).From("pull_requests pr").
 		LeftJoin("pr_reviewers r ON r.pull_request_id = pr.id").
-		Where(sq.Eq{"pr.id": id})
+		Where(sq.Eq{"pr.id": id}).
+		OrderBy("r.assigned_at", "r.id")
 
 	sql, args, err := query.ToSql()
 	if err != nil {
@@ -68,20 +76,30 @@ func (r *PRRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.PullR
 	}
 
 	conn := r.getter.DefaultTrOrDB(ctx, r.db)
-	pr := &models.PullRequest{
-		Reviewers: make([]*models.PRReviewer, 0),
-	}
+	var pr *models.PullRequest
 
-	err = r.retrier.Do(ctx, func() error {
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
 		rows, err := conn.Query(ctx, sql, args...)
 		if err != nil {
 			return err
 		}
 		defer rows.Close()
 
+		// start over on every attempt so a retried read does not duplicate reviewers
+		pr = &models.PullRequest{
+			Reviewers: make([]*models.PRReviewer, 0),
+		}
+		found := false
+
 		for rows.Next() {
-			var reviewerID *uuid.UUID
-			var assignedAt *time.Time
+			found = true
+
+			var (
+				reviewerID *uuid.UUID
+				assignedAt *time.Time
+				pool       *string
+				poolTeam   *string
+			)
 			err := rows.Scan(
 				&pr.ID,
 				&pr.Name,
@@ -89,32 +107,80 @@ func (r *PRRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.PullR
 				&pr.Status,
 				&pr.CreatedAt,
 				&pr.MergedAt,
+				&pr.Version,
 				&reviewerID,
 				&assignedAt,
+				&pool,
+				&poolTeam,
 			)
 			if err != nil {
 				return err
 			}
 
 			if reviewerID != nil && assignedAt != nil {
-				pr.Reviewers = append(pr.Reviewers, &models.PRReviewer{
+				rv := &models.PRReviewer{
 					ID:         *reviewerID,
 					PRID:       pr.ID,
 					AssignedAt: *assignedAt,
-				})
+				}
+				rv.Pool, rv.PoolTeam = ReviewerPoolFromColumns(*pool, poolTeam)
+				pr.Reviewers = append(pr.Reviewers, rv)
 			}
 		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if !found {
+			return ErrNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, wrapDBError(err)
+	}
+
+	return pr, nil
+}
+
+// GetByIDForUpdate locks the pull_requests row before reading the PR in a
+// separate statement: a read that waited for the lock in the same statement
+// would see the reviewers as they were before the wait.
+func (r *PRRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*models.PullRequest, error) {
+	sql, args, err := r.psql.Select("1").
+		From("pull_requests").
+		Where(sq.Eq{"id": id}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		tag, retryErr := conn.Exec(ctx, sql, args...)
+		if retryErr != nil {
+			return retryErr
+		}
+
+		if tag.RowsAffected() == 0 {
+			return ErrNotFound
+		}
 		return nil
 	})
+	if err != nil {
+		return nil, wrapDBError(err)
+	}
 
-	return pr, wrapDBError(err)
+	return r.GetByID(ctx, id)
 }
 
-func (r *PRRepository) AssignReviewers(ctx context.Context, prID uuid.UUID, reviewers []uuid.UUID) error {
+func (r *PRRepository) AssignReviewers(ctx context.Context, prID uuid.UUID, reviewers []*models.PRReviewer) error {
 	conn := r.getter.DefaultTrOrDB(ctx, r.db)
-	now := time.Now()
+	now := r.clock.Now()
 
-	err := r.retrier.Do(ctx, func() error {
+	err := r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
 		delSQL, delArgs, err := r.psql.
 			Delete("pr_reviewers").
 			Where(sq.Eq{"pull_request_id": prID}).
@@ -123,16 +189,16 @@ func (r *PRRepository) AssignReviewers(ctx context.Context, prID uuid.UUID, revi
 			return err
 		}
 
-		if _, err := conn.Exec(ctx, delSQL, delArgs...); err != nil {
-			return err
-		}
-
+		// The batch runs in a single implicit transaction, so a failed insert
+		// keeps the previous reviewers.
 		batch := &pgx.Batch{}
-		for _, reviewerID := range reviewers {
+		batch.Queue(delSQL, delArgs...)
+		for _, rv := range reviewers {
+			pool, poolTeam := ReviewerPoolColumns(rv)
 			sql, args, err := r.psql.
 				Insert("pr_reviewers").
-				Columns("id", "pull_request_id", "assigned_at").
-				Values(reviewerID, prID, now).
+				Columns("id", "pull_request_id", "assigned_at", "pool", "pool_team").
+				Values(rv.ID, prID, now, pool, poolTeam).
 				ToSql()
 			if err != nil {
 				return err
@@ -149,43 +215,176 @@ func (r *PRRepository) AssignReviewers(ctx context.Context, prID uuid.UUID, revi
 	return wrapDBError(err)
 }
 
-func (r *PRRepository) ReplaceReviewer(ctx context.Context, prID, oldID, newID uuid.UUID) error {
+func (r *PRRepository) AddReviewer(ctx context.Context, prID uuid.UUID, reviewer *models.PRReviewer) error {
+	pool, poolTeam := ReviewerPoolColumns(reviewer)
+	query := r.psql.Insert("pr_reviewers").
+		Columns("id", "pull_request_id", "assigned_at", "pool", "pool_team").
+		Values(reviewer.ID, prID, r.clock.Now(), pool, poolTeam)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		_, retryErr := conn.Exec(ctx, sql, args...)
+		return retryErr
+	})
+
+	return wrapDBError(err)
+}
+
+func (r *PRRepository) RemoveReviewer(ctx context.Context, prID, id uuid.UUID) error {
+	query := r.psql.Delete("pr_reviewers").
+		Where(sq.Eq{
+			"id":              id,
+			"pull_request_id": prID,
+		})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
 	conn := r.getter.DefaultTrOrDB(ctx, r.db)
-	now := time.Now()
 
-	err := r.retrier.Do(ctx, func() error {
-		delSQL, delArgs, err := r.psql.
-			Delete("pr_reviewers").
-			Where(sq.Eq{
-				"id":              oldID,
-				"pull_request_id": prID,
-			}).
-			ToSql()
-		if err != nil {
-			return err
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		tag, retryErr := conn.Exec(ctx, sql, args...)
+		if retryErr != nil {
+			return retryErr
 		}
 
-		tag, err := conn.Exec(ctx, delSQL, delArgs...)
+		if tag.RowsAffected() == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+
+	return wrapDBError(err)
+}
+
+// replaceReviewerSQL swaps reviewers and records the reassignment, with the
+// decline reason $7 if any, in one statement, so a failed insert keeps the
+// old reviewer. A NULL $3 removes the reviewer without a replacement. It
+// returns the number of removed rows.
+const replaceReviewerSQL = `
+WITH deleted AS (
+	DELETE FROM pr_reviewers
+	WHERE id = $1 AND pull_request_id = $2
+	RETURNING pull_request_id, assigned_at
+), inserted AS (
+	INSERT INTO pr_reviewers (id, pull_request_id, assigned_at, pool, pool_team)
+	SELECT $3::uuid, pull_request_id, $4, $5, $6 FROM deleted
+	WHERE $3::uuid IS NOT NULL
+	ON CONFLICT DO NOTHING
+	RETURNING id
+), history AS (
+	INSERT INTO pr_reassignments (pull_request_id, from_user_id, to_user_id, from_assigned_at, reassigned_at, decline_reason)
+	SELECT pull_request_id, $1, $3, assigned_at, $4, $7 FROM deleted
+)
+SELECT count(*) FROM deleted`
+
+func (r *PRRepository) ReplaceReviewer(ctx context.Context, prID, oldID uuid.UUID, reviewer *models.PRReviewer) error {
+	return r.replaceReviewer(ctx, prID, oldID, reviewer, nil)
+}
+
+func (r *PRRepository) DeclineReviewer(
+	ctx context.Context,
+	prID, oldID uuid.UUID,
+	reviewer *models.PRReviewer,
+	reason models.DeclineReason,
+) error {
+	return r.replaceReviewer(ctx, prID, oldID, reviewer, string(reason))
+}
+
+// replaceReviewer records the reassignment with declineReason, nil when
+// the reviewer did not decline. A nil reviewer leaves nobody in place.
+func (r *PRRepository) replaceReviewer(ctx context.Context, prID, oldID uuid.UUID, reviewer *models.PRReviewer, declineReason any) error {
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	now := r.clock.Now()
+
+	var newID, pool, poolTeam any
+	if reviewer != nil {
+		newID = reviewer.ID
+		pool, poolTeam = ReviewerPoolColumns(reviewer)
+	}
+
+	err := r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		var deleted int
+		err := conn.QueryRow(ctx, replaceReviewerSQL, oldID, prID, newID, now, pool, poolTeam, declineReason).Scan(&deleted)
 		if err != nil {
 			return err
 		}
 
-		if tag.RowsAffected() == 0 {
+		if deleted == 0 {
 			return ErrNotFound
 		}
+		return nil
+	})
 
-		insertSQL, insertArgs, err := r.psql.
-			Insert("pr_reviewers").
-			Columns("id", "pull_request_id", "assigned_at").
-			Values(newID, prID, now).
-			Suffix("ON CONFLICT DO NOTHING").
-			ToSql()
+	return wrapDBError(err)
+}
+
+func (r *PRRepository) ListDeclined(ctx context.Context, prID uuid.UUID) ([]uuid.UUID, error) {
+	query := r.psql.Select("DISTINCT from_user_id").
+		From("pr_reassignments").
+		Where(sq.Eq{"pull_request_id": prID}).
+		Where(sq.NotEq{"decline_reason": nil})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	var ids []uuid.UUID
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		rows, err := conn.Query(ctx, sql, args...)
 		if err != nil {
 			return err
 		}
+		defer rows.Close()
+
+		ids = make([]uuid.UUID, 0)
+		for rows.Next() {
+			var id uuid.UUID
+			if err := rows.Scan(&id); err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+
+		return rows.Err()
+	})
+
+	return ids, wrapDBError(err)
+}
+
+func (r *PRRepository) BumpVersion(ctx context.Context, id uuid.UUID, version int64) error {
+	query := r.psql.Update("pull_requests").
+		Set("version", sq.Expr("version + 1")).
+		Where(sq.Eq{"id": id, "version": version})
 
-		_, err = conn.Exec(ctx, insertSQL, insertArgs...)
+	sql, args, err := query.ToSql()
+	if err != nil {
 		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		tag, retryErr := conn.Exec(ctx, sql, args...)
+		if retryErr != nil {
+			return retryErr
+		}
+
+		if tag.RowsAffected() == 0 {
+			return ErrNotFound
+		}
+		return nil
 	})
 
 	return wrapDBError(err)
@@ -194,7 +393,7 @@ func (r *PRRepository) ReplaceReviewer(ctx context.Context, prID, oldID, newID u
 func (r *PRRepository) Merge(ctx context.Context, id uuid.UUID) error {
 	query := r.psql.Update("pull_requests").
 		Set("status", string(models.PRStatusMerged)).
-		Set("merged_at", time.Now()).
+		Set("merged_at", r.clock.Now()).
 		Where(sq.Eq{"id": id})
 
 	sql, args, err := query.ToSql()
@@ -204,9 +403,16 @@ func (r *PRRepository) Merge(ctx context.Context, id uuid.UUID) error {
 
 	conn := r.getter.DefaultTrOrDB(ctx, r.db)
 
-	err = r.retrier.Do(ctx, func() error {
-		_, retryErr := conn.Exec(ctx, sql, args...)
-		return retryErr
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		tag, retryErr := conn.Exec(ctx, sql, args...)
+		if retryErr != nil {
+			return retryErr
+		}
+
+		if tag.RowsAffected() == 0 {
+			return ErrNotFound
+		}
+		return nil
 	})
 
 	return wrapDBError(err)
@@ -216,9 +422,11 @@ func (r *PRRepository) ListByReviewer(ctx context.Context, id uuid.UUID) ([]*mod
 	query := r.psql.Select(
 		"pr.id", "pr.name", "pr.author_id",
 		"pr.status", "pr.created_at", "pr.merged_at",
+		"pr.version",
 	).From("pull_requests pr").
 		Join("pr_reviewers r ON r.pull_request_id = pr.id").
-		Where(sq.Eq{"r.id": id})
+		Where(sq.Eq{"r.id": id}).
+		OrderBy("pr.created_at", "pr.id")
 
 	sql, args, err := query.ToSql()
 	if err != nil {
@@ -226,15 +434,16 @@ func (r *PRRepository) ListByReviewer(ctx context.Context, id uuid.UUID) ([]*mod
 	}
 
 	conn := r.getter.DefaultTrOrDB(ctx, r.db)
-	prs := make([]*models.PullRequest, 0)
+	var prs []*models.PullRequest
 
-	err = r.retrier.Do(ctx, func() error {
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
 		rows, err := conn.Query(ctx, sql, args...)
 		if err != nil {
 			return err
 		}
 		defer rows.Close()
 
+		prs = make([]*models.PullRequest, 0)
 		pr := &models.PullRequest{}
 		for rows.Next() {
 			if err := rows.Scan(
@@ -244,6 +453,7 @@ func (r *PRRepository) ListByReviewer(ctx context.Context, id uuid.UUID) ([]*mod
 				&pr.Status,
 				&pr.CreatedAt,
 				&pr.MergedAt,
+				&pr.Version,
 			); err != nil {
 				return err
 			}
@@ -256,3 +466,26 @@ func (r *PRRepository) ListByReviewer(ctx context.Context, id uuid.UUID) ([]*mod
 
 	return prs, wrapDBError(err)
 }
+
+// ReviewerPoolColumns converts the pool of a reviewer to the stored columns:
+// the pool, the author's team when unset, and the fallback team name or NULL.
+func ReviewerPoolColumns(rv *models.PRReviewer) (string, *string) {
+	pool := rv.Pool
+	if pool == "" {
+		pool = models.ReviewerPoolTeam
+	}
+	if rv.PoolTeam == "" {
+		return string(pool), nil
+	}
+
+	return string(pool), &rv.PoolTeam
+}
+
+// ReviewerPoolFromColumns is the inverse of ReviewerPoolColumns.
+func ReviewerPoolFromColumns(pool string, team *string) (models.ReviewerPool, string) {
+	if team == nil {
+		return models.ReviewerPool(pool), ""
+	}
+
+	return models.ReviewerPool(pool), *team
+}
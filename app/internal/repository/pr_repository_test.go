@@ -6,6 +6,7 @@ package repository_test
 import (
 	"context"
 	"fmt"
+	"pr-service/internal/clock"
 	"pr-service/internal/models"
 	"pr-service/internal/repository"
 	"testing"
@@ -25,6 +26,7 @@ func TestPRRepository(t *testing.T) {
 		db,
 		trmpgx.DefaultCtxGetter,
 		retrier,
+		clock.Real(),
 	)
 
 	userRepo := repository.NewUserRepository(db, trmpgx.DefaultCtxGetter, retrier)
@@ -72,7 +74,7 @@ func TestPRRepository(t *testing.T) {
 			require.NoError(t, userRepo.Create(ctx, r1))
 			require.NoError(t, userRepo.Create(ctx, r2))
 
-			err := prRepo.AssignReviewers(ctx, pr.ID, []uuid.UUID{r1.ID, r2.ID})
+			err := prRepo.AssignReviewers(ctx, pr.ID, []*models.PRReviewer{{ID: r1.ID}, {ID: r2.ID}})
 			require.NoError(t, err)
 
 			fetched, err := prRepo.GetByID(ctx, pr.ID)
@@ -90,7 +92,7 @@ func TestPRRepository(t *testing.T) {
 
 			oldID := fetchedPR.Reviewers[0].ID
 
-			err = prRepo.ReplaceReviewer(ctx, pr.ID, oldID, r3.ID)
+			err = prRepo.ReplaceReviewer(ctx, pr.ID, oldID, &models.PRReviewer{ID: r3.ID})
 			require.NoError(t, err)
 
 			updated, err := prRepo.GetByID(ctx, pr.ID)
@@ -0,0 +1,1512 @@
+// Package repotest holds the behavioral contract shared by every
+// implementation of the service repositories. Each backend runs the same
+// suite from its own tests, so Postgres and alternative stores cannot drift.
+package repotest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"pr-service/internal/clock"
+	"pr-service/internal/clock/clocktest"
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// Repos is a set of repositories backed by the same empty store.
+type Repos struct {
+	Teams        service.TeamRepository
+	Users        service.UserRepository
+	PRs          service.PRRepository
+	Stats        service.StatsRepository
+	Availability service.AvailabilityRepository
+	SLA          service.SLARepository
+	Digest       service.DigestRepository
+	Integration  service.IntegrationRepository
+	Tx           service.TxManager
+}
+
+// Factory returns repositories over an empty store that use clk for timestamps.
+type Factory func(t *testing.T, clk clock.Clock) Repos
+
+// epoch is the start time of the fake clock; second precision survives every backend.
+var epoch = time.Date(2025, 10, 24, 12, 0, 0, 0, time.UTC)
+
+// Run executes the whole contract suite against the repositories built by newRepos.
+func Run(t *testing.T, newRepos Factory) {
+	t.Run("TeamRepository", func(t *testing.T) { testTeams(t, newRepos) })
+	t.Run("UserRepository", func(t *testing.T) { testUsers(t, newRepos) })
+	t.Run("PRRepository", func(t *testing.T) { testPRs(t, newRepos) })
+	t.Run("StatsRepository", func(t *testing.T) { testStats(t, newRepos) })
+	t.Run("AvailabilityRepository", func(t *testing.T) { testAvailability(t, newRepos) })
+	t.Run("SLARepository", func(t *testing.T) { testSLA(t, newRepos) })
+	t.Run("DigestRepository", func(t *testing.T) { testDigest(t, newRepos) })
+	t.Run("IntegrationRepository", func(t *testing.T) { testIntegration(t, newRepos) })
+	t.Run("TxManager", func(t *testing.T) { testTx(t, newRepos) })
+}
+
+// fixture is a populated store: one team with members named in creation order.
+type fixture struct {
+	Repos
+	clk   *clocktest.Fake
+	team  *models.Team
+	users []*models.User
+}
+
+func newFixture(t *testing.T, newRepos Factory, names ...string) *fixture {
+	t.Helper()
+
+	clk := clocktest.NewFake(epoch)
+	f := &fixture{Repos: newRepos(t, clk), clk: clk}
+
+	f.team = &models.Team{Name: "team-" + uuid.NewString()}
+	require.NoError(t, f.Teams.Create(t.Context(), f.team))
+
+	for _, name := range names {
+		u := &models.User{Name: name, TeamID: &f.team.ID, IsActive: true}
+		require.NoError(t, f.Users.Create(t.Context(), u))
+		f.users = append(f.users, u)
+	}
+
+	return f
+}
+
+func (f *fixture) newAway(t *testing.T, user *models.User, startsAt, endsAt time.Time) *models.Availability {
+	t.Helper()
+
+	a := &models.Availability{
+		UserID:    user.ID,
+		StartsAt:  startsAt,
+		EndsAt:    endsAt,
+		Reason:    "vacation",
+		CreatedAt: epoch,
+	}
+	require.NoError(t, f.Availability.Create(t.Context(), a))
+	return a
+}
+
+func (f *fixture) newPR(t *testing.T, author *models.User, createdAt time.Time) *models.PullRequest {
+	t.Helper()
+
+	pr := &models.PullRequest{
+		ID:        uuid.New(),
+		Name:      "pr",
+		AuthorID:  author.ID,
+		Status:    string(models.PRStatusOpen),
+		CreatedAt: createdAt,
+	}
+	require.NoError(t, f.PRs.Create(t.Context(), pr))
+	return pr
+}
+
+func userIDs(users []*models.User) []uuid.UUID {
+	ids := make([]uuid.UUID, len(users))
+	for i, u := range users {
+		ids[i] = u.ID
+	}
+	return ids
+}
+
+func teamNames(teams []*models.Team) []string {
+	names := make([]string, len(teams))
+	for i, t := range teams {
+		names[i] = t.Name
+	}
+	return names
+}
+
+func asReviewers(ids ...uuid.UUID) []*models.PRReviewer {
+	reviewers := make([]*models.PRReviewer, len(ids))
+	for i, id := range ids {
+		reviewers[i] = &models.PRReviewer{ID: id}
+	}
+	return reviewers
+}
+
+func reviewerIDs(reviewers []*models.PRReviewer) []uuid.UUID {
+	ids := make([]uuid.UUID, len(reviewers))
+	for i, r := range reviewers {
+		ids[i] = r.ID
+	}
+	return ids
+}
+
+func prIDs(prs []*models.PullRequest) []uuid.UUID {
+	ids := make([]uuid.UUID, len(prs))
+	for i, pr := range prs {
+		ids[i] = pr.ID
+	}
+	return ids
+}
+
+func requireSameTime(t *testing.T, want, got time.Time) {
+	t.Helper()
+	require.True(t, want.Equal(got), "want %s, got %s", want, got)
+}
+
+func testTeams(t *testing.T, newRepos Factory) {
+	t.Run("create and get", func(t *testing.T) {
+		ctx := t.Context()
+		repos := newRepos(t, clocktest.NewFake(epoch))
+
+		team := &models.Team{Name: "backend"}
+		require.NoError(t, repos.Teams.Create(ctx, team))
+		require.NotEqual(t, uuid.Nil, team.ID)
+
+		byID, err := repos.Teams.GetByID(ctx, team.ID)
+		require.NoError(t, err)
+		require.Equal(t, team.ID, byID.ID)
+		require.Equal(t, team.Name, byID.Name)
+
+		byName, err := repos.Teams.GetByName(ctx, team.Name)
+		require.NoError(t, err)
+		require.Equal(t, team.ID, byName.ID)
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		ctx := t.Context()
+		repos := newRepos(t, clocktest.NewFake(epoch))
+
+		require.NoError(t, repos.Teams.Create(ctx, &models.Team{Name: "backend"}))
+		err := repos.Teams.Create(ctx, &models.Team{Name: "backend"})
+		require.ErrorIs(t, err, repository.ErrDuplicate)
+	})
+
+	t.Run("review sla", func(t *testing.T) {
+		ctx := t.Context()
+		repos := newRepos(t, clocktest.NewFake(epoch))
+
+		sla := &models.ReviewSLA{Timeout: 24 * time.Hour, Action: models.SLAActionReassign}
+		team := &models.Team{Name: "backend", ReviewSLA: sla}
+		require.NoError(t, repos.Teams.Create(ctx, team))
+
+		got, err := repos.Teams.GetByID(ctx, team.ID)
+		require.NoError(t, err)
+		require.Equal(t, sla, got.ReviewSLA)
+
+		sla = &models.ReviewSLA{Timeout: time.Hour, Action: models.SLAActionEscalate}
+		require.NoError(t, repos.Teams.SetReviewSLA(ctx, team.ID, sla))
+
+		got, err = repos.Teams.GetByName(ctx, team.Name)
+		require.NoError(t, err)
+		require.Equal(t, sla, got.ReviewSLA)
+
+		require.NoError(t, repos.Teams.SetReviewSLA(ctx, team.ID, nil))
+
+		got, err = repos.Teams.GetByID(ctx, team.ID)
+		require.NoError(t, err)
+		require.Nil(t, got.ReviewSLA)
+
+		require.ErrorIs(t, repos.Teams.SetReviewSLA(ctx, uuid.New(), nil), repository.ErrNotFound)
+	})
+
+	t.Run("review policy", func(t *testing.T) {
+		ctx := t.Context()
+		repos := newRepos(t, clocktest.NewFake(epoch))
+
+		policy := &models.ReviewPolicy{MinRole: models.RoleMaintainer}
+		team := &models.Team{Name: "backend", Policy: policy}
+		require.NoError(t, repos.Teams.Create(ctx, team))
+
+		got, err := repos.Teams.GetByID(ctx, team.ID)
+		require.NoError(t, err)
+		require.Equal(t, policy, got.Policy)
+
+		policy = &models.ReviewPolicy{MinRole: models.RoleSenior}
+		require.NoError(t, repos.Teams.SetReviewPolicy(ctx, team.ID, policy))
+
+		got, err = repos.Teams.GetByName(ctx, team.Name)
+		require.NoError(t, err)
+		require.Equal(t, policy, got.Policy)
+
+		require.NoError(t, repos.Teams.SetReviewPolicy(ctx, team.ID, nil))
+
+		got, err = repos.Teams.GetByID(ctx, team.ID)
+		require.NoError(t, err)
+		require.Nil(t, got.Policy)
+
+		require.ErrorIs(t, repos.Teams.SetReviewPolicy(ctx, uuid.New(), nil), repository.ErrNotFound)
+	})
+
+	t.Run("digest schedule", func(t *testing.T) {
+		ctx := t.Context()
+		repos := newRepos(t, clocktest.NewFake(epoch))
+
+		moscow, err := time.LoadLocation("Europe/Moscow")
+		require.NoError(t, err)
+
+		d := &models.DigestSchedule{At: 9*time.Hour + 30*time.Minute, Location: moscow}
+		team := &models.Team{Name: "backend", Digest: d}
+		require.NoError(t, repos.Teams.Create(ctx, team))
+
+		got, err := repos.Teams.GetByID(ctx, team.ID)
+		require.NoError(t, err)
+		require.Equal(t, d.At, got.Digest.At)
+		require.Equal(t, "Europe/Moscow", got.Digest.Location.String())
+
+		d = &models.DigestSchedule{At: 0, Location: time.UTC}
+		require.NoError(t, repos.Teams.SetDigestSchedule(ctx, team.ID, d))
+
+		got, err = repos.Teams.GetByName(ctx, team.Name)
+		require.NoError(t, err)
+		require.Equal(t, time.Duration(0), got.Digest.At)
+		require.Equal(t, "UTC", got.Digest.Location.String())
+
+		require.NoError(t, repos.Teams.SetDigestSchedule(ctx, team.ID, nil))
+
+		got, err = repos.Teams.GetByID(ctx, team.ID)
+		require.NoError(t, err)
+		require.Nil(t, got.Digest)
+
+		require.ErrorIs(t, repos.Teams.SetDigestSchedule(ctx, uuid.New(), nil), repository.ErrNotFound)
+	})
+
+	t.Run("code owners", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos, "alice")
+		alice := f.users[0]
+
+		other := &models.Team{Name: "team-" + uuid.NewString()}
+		require.NoError(t, f.Teams.Create(ctx, other))
+
+		rules, err := f.Teams.GetCodeOwners(ctx, f.team.ID)
+		require.NoError(t, err)
+		require.Empty(t, rules)
+
+		rules = []models.CodeOwnerRule{
+			{Pattern: "*", Owners: []models.CodeOwner{{Ref: "@" + f.team.Name, TeamID: f.team.ID}}},
+			{Pattern: "/billing/", Owners: []models.CodeOwner{
+				{Ref: "@" + other.Name, TeamID: other.ID},
+				{Ref: "alice@example.com", UserID: alice.ID},
+			}},
+			{Pattern: "/vendor/", Owners: []models.CodeOwner{}},
+		}
+		require.NoError(t, f.Teams.SetCodeOwners(ctx, f.team.ID, rules))
+
+		got, err := f.Teams.GetCodeOwners(ctx, f.team.ID)
+		require.NoError(t, err)
+		require.Equal(t, rules, got)
+
+		// other teams keep their own rules
+		got, err = f.Teams.GetCodeOwners(ctx, other.ID)
+		require.NoError(t, err)
+		require.Empty(t, got)
+
+		// upload replaces the whole set
+		rules = rules[2:]
+		require.NoError(t, f.Teams.SetCodeOwners(ctx, f.team.ID, rules))
+		got, err = f.Teams.GetCodeOwners(ctx, f.team.ID)
+		require.NoError(t, err)
+		require.Equal(t, rules, got)
+
+		require.NoError(t, f.Teams.SetCodeOwners(ctx, f.team.ID, nil))
+		got, err = f.Teams.GetCodeOwners(ctx, f.team.ID)
+		require.NoError(t, err)
+		require.Empty(t, got)
+
+		err = f.Teams.SetCodeOwners(ctx, f.team.ID, []models.CodeOwnerRule{
+			{Pattern: "*", Owners: []models.CodeOwner{{Ref: "ghost@example.com", UserID: uuid.New()}}},
+		})
+		require.ErrorIs(t, err, repository.ErrForeignKeyViolation)
+	})
+
+	t.Run("fallback pools", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos)
+
+		sibling := &models.Team{Name: "team-" + uuid.NewString()}
+		require.NoError(t, f.Teams.Create(ctx, sibling))
+		guild := &models.Team{Name: "team-" + uuid.NewString()}
+		require.NoError(t, f.Teams.Create(ctx, guild))
+
+		got, err := f.Teams.GetFallbackPools(ctx, f.team.ID)
+		require.NoError(t, err)
+		require.Empty(t, got)
+
+		require.NoError(t, f.Teams.SetFallbackPools(ctx, f.team.ID, []uuid.UUID{guild.ID, sibling.ID}))
+
+		got, err = f.Teams.GetFallbackPools(ctx, f.team.ID)
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		require.Equal(t, guild.ID, got[0].ID)
+		require.Equal(t, guild.Name, got[0].Name)
+		require.Equal(t, sibling.ID, got[1].ID)
+
+		// other teams keep their own pools
+		got, err = f.Teams.GetFallbackPools(ctx, guild.ID)
+		require.NoError(t, err)
+		require.Empty(t, got)
+
+		// setting replaces the whole list
+		require.NoError(t, f.Teams.SetFallbackPools(ctx, f.team.ID, []uuid.UUID{sibling.ID}))
+		got, err = f.Teams.GetFallbackPools(ctx, f.team.ID)
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		require.Equal(t, sibling.ID, got[0].ID)
+
+		err = f.Teams.SetFallbackPools(ctx, f.team.ID, []uuid.UUID{guild.ID, guild.ID})
+		require.ErrorIs(t, err, repository.ErrDuplicate)
+
+		err = f.Teams.SetFallbackPools(ctx, f.team.ID, []uuid.UUID{uuid.New()})
+		require.ErrorIs(t, err, repository.ErrForeignKeyViolation)
+
+		require.NoError(t, f.Teams.SetFallbackPools(ctx, f.team.ID, nil))
+		got, err = f.Teams.GetFallbackPools(ctx, f.team.ID)
+		require.NoError(t, err)
+		require.Empty(t, got)
+	})
+
+	t.Run("hierarchy", func(t *testing.T) {
+		ctx := t.Context()
+		repos := newRepos(t, clocktest.NewFake(epoch))
+
+		dept := &models.Team{Name: "engineering"}
+		require.NoError(t, repos.Teams.Create(ctx, dept))
+		web := &models.Team{Name: "web", ParentID: &dept.ID}
+		require.NoError(t, repos.Teams.Create(ctx, web))
+		api := &models.Team{Name: "api", ParentID: &dept.ID}
+		require.NoError(t, repos.Teams.Create(ctx, api))
+		payments := &models.Team{Name: "payments"}
+		require.NoError(t, repos.Teams.Create(ctx, payments))
+
+		got, err := repos.Teams.GetByID(ctx, web.ID)
+		require.NoError(t, err)
+		require.Equal(t, &dept.ID, got.ParentID)
+
+		got, err = repos.Teams.GetByName(ctx, dept.Name)
+		require.NoError(t, err)
+		require.Nil(t, got.ParentID)
+
+		require.NoError(t, repos.Teams.SetParent(ctx, payments.ID, &api.ID))
+
+		// parents before children, by name within a level
+		subtree, err := repos.Teams.GetSubtree(ctx, dept.ID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"engineering", "api", "web", "payments"}, teamNames(subtree))
+		require.Nil(t, subtree[0].ParentID)
+		require.Equal(t, &api.ID, subtree[3].ParentID)
+
+		subtree, err = repos.Teams.GetSubtree(ctx, api.ID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"api", "payments"}, teamNames(subtree))
+
+		require.NoError(t, repos.Teams.SetParent(ctx, payments.ID, nil))
+		subtree, err = repos.Teams.GetSubtree(ctx, api.ID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"api"}, teamNames(subtree))
+
+		subtree, err = repos.Teams.GetSubtree(ctx, uuid.New())
+		require.NoError(t, err)
+		require.Empty(t, subtree)
+
+		missing := uuid.New()
+		err = repos.Teams.SetParent(ctx, payments.ID, &missing)
+		require.ErrorIs(t, err, repository.ErrForeignKeyViolation)
+
+		err = repos.Teams.Create(ctx, &models.Team{Name: "orphan", ParentID: &missing})
+		require.ErrorIs(t, err, repository.ErrForeignKeyViolation)
+
+		err = repos.Teams.SetParent(ctx, uuid.New(), nil)
+		require.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctx := t.Context()
+		repos := newRepos(t, clocktest.NewFake(epoch))
+
+		_, err := repos.Teams.GetByID(ctx, uuid.New())
+		require.ErrorIs(t, err, repository.ErrNotFound)
+
+		_, err = repos.Teams.GetByName(ctx, "missing")
+		require.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
+
+func testUsers(t *testing.T, newRepos Factory) {
+	t.Run("create and get", func(t *testing.T) {
+		f := newFixture(t, newRepos, "alice")
+		u := f.users[0]
+		require.NotEqual(t, uuid.Nil, u.ID)
+
+		got, err := f.Users.GetUserByID(t.Context(), u.ID)
+		require.NoError(t, err)
+		require.Equal(t, u, got)
+	})
+
+	t.Run("email", func(t *testing.T) {
+		f := newFixture(t, newRepos)
+
+		u := &models.User{Name: "bob", Email: "bob@example.com", TeamID: &f.team.ID, IsActive: true}
+		require.NoError(t, f.Users.Create(t.Context(), u))
+
+		got, err := f.Users.GetUserByID(t.Context(), u.ID)
+		require.NoError(t, err)
+		require.Equal(t, "bob@example.com", got.Email)
+
+		members, err := f.Users.GetByTeam(t.Context(), f.team.ID)
+		require.NoError(t, err)
+		require.Equal(t, []*models.User{u}, members)
+
+		found, err := f.Users.ListByEmail(t.Context(), "Bob@Example.com")
+		require.NoError(t, err)
+		require.Equal(t, []*models.User{u}, found)
+
+		found, err = f.Users.ListByEmail(t.Context(), "carol@example.com")
+		require.NoError(t, err)
+		require.Empty(t, found)
+	})
+
+	t.Run("role", func(t *testing.T) {
+		f := newFixture(t, newRepos, "alice")
+
+		u := &models.User{Name: "bob", Role: models.RoleJunior, TeamID: &f.team.ID, IsActive: true}
+		require.NoError(t, f.Users.Create(t.Context(), u))
+
+		got, err := f.Users.GetUserByID(t.Context(), u.ID)
+		require.NoError(t, err)
+		require.Equal(t, models.RoleJunior, got.Role)
+
+		require.NoError(t, f.Users.UpdateRole(t.Context(), u.ID, models.RoleMaintainer))
+
+		members, err := f.Users.GetAvailableByTeam(t.Context(), f.team.ID, epoch)
+		require.NoError(t, err)
+		require.Len(t, members, 2)
+		require.Equal(t, models.DefaultRole, members[0].Role)
+		require.Equal(t, models.RoleMaintainer, members[1].Role)
+
+		require.ErrorIs(t, f.Users.UpdateRole(t.Context(), uuid.New(), models.RoleSenior), repository.ErrNotFound)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		repos := newRepos(t, clocktest.NewFake(epoch))
+
+		_, err := repos.Users.GetUserByID(t.Context(), uuid.New())
+		require.ErrorIs(t, err, repository.ErrNotFound)
+
+		err = repos.Users.UpdateActive(t.Context(), uuid.New(), false)
+		require.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run("unknown team", func(t *testing.T) {
+		repos := newRepos(t, clocktest.NewFake(epoch))
+
+		teamID := uuid.New()
+		err := repos.Users.Create(t.Context(), &models.User{Name: "bob", TeamID: &teamID})
+		require.ErrorIs(t, err, repository.ErrForeignKeyViolation)
+	})
+
+	t.Run("team members ordered by name", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos, "carol", "alice", "bob")
+		carol, alice, bob := f.users[0], f.users[1], f.users[2]
+
+		members, err := f.Users.GetByTeam(ctx, f.team.ID)
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{alice.ID, bob.ID, carol.ID}, userIDs(members))
+
+		// updates must not reorder rows
+		require.NoError(t, f.Users.UpdateActive(ctx, alice.ID, false))
+		require.NoError(t, f.Users.UpdateActive(ctx, alice.ID, true))
+
+		active, err := f.Users.GetActiveByTeam(ctx, f.team.ID)
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{alice.ID, bob.ID, carol.ID}, userIDs(active))
+	})
+
+	t.Run("active filter", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos, "alice", "bob")
+
+		require.NoError(t, f.Users.UpdateActive(ctx, f.users[0].ID, false))
+
+		got, err := f.Users.GetUserByID(ctx, f.users[0].ID)
+		require.NoError(t, err)
+		require.False(t, got.IsActive)
+
+		active, err := f.Users.GetActiveByTeam(ctx, f.team.ID)
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{f.users[1].ID}, userIDs(active))
+
+		all, err := f.Users.GetByTeam(ctx, f.team.ID)
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+	})
+
+	t.Run("available filter", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos, "alice", "bob", "carol", "dave")
+		alice, bob, carol, dave := f.users[0], f.users[1], f.users[2], f.users[3]
+
+		require.NoError(t, f.Users.UpdateActive(ctx, dave.ID, false))
+		f.newAway(t, alice, epoch, epoch.Add(time.Hour))
+		f.newAway(t, bob, epoch.Add(-time.Hour), epoch)
+
+		available, err := f.Users.GetAvailableByTeam(ctx, f.team.ID, epoch)
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{bob.ID, carol.ID}, userIDs(available), "window end is exclusive")
+
+		available, err = f.Users.GetAvailableByTeam(ctx, f.team.ID, epoch.Add(-time.Second))
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{alice.ID, carol.ID}, userIDs(available))
+	})
+
+	t.Run("subtree members", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos, "lead")
+		lead := f.users[0]
+
+		child := &models.Team{Name: "team-" + uuid.NewString(), ParentID: &f.team.ID}
+		require.NoError(t, f.Teams.Create(ctx, child))
+		grandchild := &models.Team{Name: "team-" + uuid.NewString(), ParentID: &child.ID}
+		require.NoError(t, f.Teams.Create(ctx, grandchild))
+		other := &models.Team{Name: "team-" + uuid.NewString()}
+		require.NoError(t, f.Teams.Create(ctx, other))
+
+		newMember := func(name string, team *models.Team) *models.User {
+			u := &models.User{Name: name, TeamID: &team.ID, IsActive: true}
+			require.NoError(t, f.Users.Create(ctx, u))
+			return u
+		}
+		dev := newMember("dev", child)
+		intern := newMember("intern", grandchild)
+		newMember("outsider", other)
+
+		members, err := f.Users.GetBySubtree(ctx, f.team.ID)
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{dev.ID, intern.ID, lead.ID}, userIDs(members))
+
+		members, err = f.Users.GetBySubtree(ctx, child.ID)
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{dev.ID, intern.ID}, userIDs(members))
+	})
+
+	t.Run("unknown team has no members", func(t *testing.T) {
+		repos := newRepos(t, clocktest.NewFake(epoch))
+
+		members, err := repos.Users.GetByTeam(t.Context(), uuid.New())
+		require.NoError(t, err)
+		require.Empty(t, members)
+	})
+}
+
+func testPRs(t *testing.T, newRepos Factory) {
+	t.Run("create and get", func(t *testing.T) {
+		f := newFixture(t, newRepos, "author")
+		pr := f.newPR(t, f.users[0], epoch)
+
+		got, err := f.PRs.GetByID(t.Context(), pr.ID)
+		require.NoError(t, err)
+		require.Equal(t, pr.ID, got.ID)
+		require.Equal(t, pr.Name, got.Name)
+		require.Equal(t, pr.AuthorID, got.AuthorID)
+		require.Equal(t, string(models.PRStatusOpen), got.Status)
+		requireSameTime(t, epoch, got.CreatedAt)
+		require.Nil(t, got.MergedAt)
+		require.Empty(t, got.Reviewers)
+	})
+
+	t.Run("duplicate id", func(t *testing.T) {
+		f := newFixture(t, newRepos, "author")
+		pr := f.newPR(t, f.users[0], epoch)
+
+		dup := *pr
+		err := f.PRs.Create(t.Context(), &dup)
+		require.ErrorIs(t, err, repository.ErrDuplicate)
+	})
+
+	t.Run("unknown author", func(t *testing.T) {
+		repos := newRepos(t, clocktest.NewFake(epoch))
+
+		err := repos.PRs.Create(t.Context(), &models.PullRequest{
+			ID:        uuid.New(),
+			Name:      "pr",
+			AuthorID:  uuid.New(),
+			Status:    string(models.PRStatusOpen),
+			CreatedAt: epoch,
+		})
+		require.ErrorIs(t, err, repository.ErrForeignKeyViolation)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		repos := newRepos(t, clocktest.NewFake(epoch))
+
+		_, err := repos.PRs.GetByID(t.Context(), uuid.New())
+		require.ErrorIs(t, err, repository.ErrNotFound)
+
+		err = repos.PRs.Merge(t.Context(), uuid.New())
+		require.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run("assign reviewers", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos, "author", "r1", "r2", "r3")
+		pr := f.newPR(t, f.users[0], epoch)
+
+		f.clk.Advance(time.Minute)
+		require.NoError(t, f.PRs.AssignReviewers(ctx, pr.ID, asReviewers(userIDs(f.users[1:3])...)))
+
+		got, err := f.PRs.GetByID(ctx, pr.ID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, userIDs(f.users[1:3]), reviewerIDs(got.Reviewers))
+		for _, r := range got.Reviewers {
+			require.Equal(t, pr.ID, r.PRID)
+			requireSameTime(t, epoch.Add(time.Minute), r.AssignedAt)
+			require.Equal(t, models.ReviewerPoolTeam, r.Pool)
+			require.Empty(t, r.PoolTeam)
+		}
+
+		// assigning again replaces the whole set
+		require.NoError(t, f.PRs.AssignReviewers(ctx, pr.ID, asReviewers(userIDs(f.users[3:])...)))
+
+		got, err = f.PRs.GetByID(ctx, pr.ID)
+		require.NoError(t, err)
+		require.Equal(t, userIDs(f.users[3:]), reviewerIDs(got.Reviewers))
+
+		require.NoError(t, f.PRs.AssignReviewers(ctx, pr.ID, nil))
+
+		got, err = f.PRs.GetByID(ctx, pr.ID)
+		require.NoError(t, err)
+		require.Empty(t, got.Reviewers)
+	})
+
+	t.Run("assign reviewers violations keep previous set", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos, "author", "r1", "r2")
+		pr := f.newPR(t, f.users[0], epoch)
+		require.NoError(t, f.PRs.AssignReviewers(ctx, pr.ID, asReviewers(f.users[1].ID)))
+
+		err := f.PRs.AssignReviewers(ctx, pr.ID, asReviewers(f.users[2].ID, uuid.New()))
+		require.ErrorIs(t, err, repository.ErrForeignKeyViolation)
+
+		err = f.PRs.AssignReviewers(ctx, pr.ID, asReviewers(f.users[2].ID, f.users[2].ID))
+		require.ErrorIs(t, err, repository.ErrDuplicate)
+
+		err = f.PRs.AssignReviewers(ctx, uuid.New(), asReviewers(f.users[2].ID))
+		require.ErrorIs(t, err, repository.ErrForeignKeyViolation)
+
+		got, err := f.PRs.GetByID(ctx, pr.ID)
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{f.users[1].ID}, reviewerIDs(got.Reviewers))
+	})
+
+	t.Run("reviewers ordered by assignment time", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos, "author", "r1", "r2", "r3")
+		pr := f.newPR(t, f.users[0], epoch)
+		r1, r2, r3 := f.users[1], f.users[2], f.users[3]
+
+		require.NoError(t, f.PRs.AssignReviewers(ctx, pr.ID, asReviewers(r1.ID, r2.ID)))
+
+		f.clk.Advance(time.Minute)
+		require.NoError(t, f.PRs.ReplaceReviewer(ctx, pr.ID, r1.ID, &models.PRReviewer{ID: r3.ID}))
+
+		got, err := f.PRs.GetByID(ctx, pr.ID)
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{r2.ID, r3.ID}, reviewerIDs(got.Reviewers))
+		requireSameTime(t, epoch.Add(time.Minute), got.Reviewers[1].AssignedAt)
+	})
+
+	t.Run("replace reviewer", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos, "author", "r1", "r2", "r3")
+		pr := f.newPR(t, f.users[0], epoch)
+		r1, r2, r3 := f.users[1], f.users[2], f.users[3]
+		require.NoError(t, f.PRs.AssignReviewers(ctx, pr.ID, asReviewers(r1.ID, r2.ID)))
+
+		err := f.PRs.ReplaceReviewer(ctx, pr.ID, r3.ID, &models.PRReviewer{ID: r1.ID})
+		require.ErrorIs(t, err, repository.ErrNotFound, "old reviewer is not assigned")
+
+		err = f.PRs.ReplaceReviewer(ctx, pr.ID, r1.ID, &models.PRReviewer{ID: uuid.New()})
+		require.ErrorIs(t, err, repository.ErrForeignKeyViolation)
+
+		got, err := f.PRs.GetByID(ctx, pr.ID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uuid.UUID{r1.ID, r2.ID}, reviewerIDs(got.Reviewers), "failed replace keeps reviewers")
+
+		f.clk.Advance(time.Minute)
+		require.NoError(t, f.PRs.ReplaceReviewer(ctx, pr.ID, r1.ID, &models.PRReviewer{
+			ID:       r3.ID,
+			Pool:     models.ReviewerPoolFallback,
+			PoolTeam: "platform",
+		}))
+
+		got, err = f.PRs.GetByID(ctx, pr.ID)
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{r2.ID, r3.ID}, reviewerIDs(got.Reviewers))
+		require.Equal(t, models.ReviewerPoolFallback, got.Reviewers[1].Pool)
+		require.Equal(t, "platform", got.Reviewers[1].PoolTeam)
+
+		// replacing with someone already assigned just drops the old reviewer
+		require.NoError(t, f.PRs.ReplaceReviewer(ctx, pr.ID, r3.ID, &models.PRReviewer{ID: r2.ID}))
+
+		got, err = f.PRs.GetByID(ctx, pr.ID)
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{r2.ID}, reviewerIDs(got.Reviewers))
+	})
+
+	t.Run("decline reviewer", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos, "author", "r1", "r2", "r3")
+		pr := f.newPR(t, f.users[0], epoch)
+		r1, r2, r3 := f.users[1], f.users[2], f.users[3]
+		require.NoError(t, f.PRs.AssignReviewers(ctx, pr.ID, asReviewers(r1.ID)))
+
+		err := f.PRs.DeclineReviewer(ctx, pr.ID, r2.ID, &models.PRReviewer{ID: r3.ID}, models.DeclineBusy)
+		require.ErrorIs(t, err, repository.ErrNotFound, "reviewer is not assigned")
+
+		declined, err := f.PRs.ListDeclined(ctx, pr.ID)
+		require.NoError(t, err)
+		require.Empty(t, declined)
+
+		require.NoError(t, f.PRs.DeclineReviewer(ctx, pr.ID, r1.ID, &models.PRReviewer{ID: r2.ID}, models.DeclineBusy))
+		// a plain reassignment is not a decline
+		require.NoError(t, f.PRs.ReplaceReviewer(ctx, pr.ID, r2.ID, &models.PRReviewer{ID: r1.ID}))
+		require.NoError(t, f.PRs.DeclineReviewer(ctx, pr.ID, r1.ID, &models.PRReviewer{ID: r3.ID}, models.DeclineConflict))
+
+		got, err := f.PRs.GetByID(ctx, pr.ID)
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{r3.ID}, reviewerIDs(got.Reviewers))
+
+		declined, err = f.PRs.ListDeclined(ctx, pr.ID)
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{r1.ID}, declined)
+
+		// nobody to take over, the decline is recorded all the same
+		require.NoError(t, f.PRs.DeclineReviewer(ctx, pr.ID, r3.ID, nil, models.DeclineBusy))
+
+		got, err = f.PRs.GetByID(ctx, pr.ID)
+		require.NoError(t, err)
+		require.Empty(t, got.Reviewers)
+
+		declined, err = f.PRs.ListDeclined(ctx, pr.ID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uuid.UUID{r1.ID, r3.ID}, declined)
+
+		other := f.newPR(t, f.users[0], epoch)
+		declined, err = f.PRs.ListDeclined(ctx, other.ID)
+		require.NoError(t, err)
+		require.Empty(t, declined)
+	})
+
+	t.Run("add and remove reviewer", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos, "author", "r1", "r2")
+		pr := f.newPR(t, f.users[0], epoch)
+		r1, r2 := f.users[1], f.users[2]
+		require.NoError(t, f.PRs.AssignReviewers(ctx, pr.ID, asReviewers(r1.ID)))
+
+		err := f.PRs.AddReviewer(ctx, pr.ID, &models.PRReviewer{ID: r1.ID})
+		require.ErrorIs(t, err, repository.ErrDuplicate)
+
+		err = f.PRs.AddReviewer(ctx, pr.ID, &models.PRReviewer{ID: uuid.New()})
+		require.ErrorIs(t, err, repository.ErrForeignKeyViolation)
+
+		f.clk.Advance(time.Minute)
+		require.NoError(t, f.PRs.AddReviewer(ctx, pr.ID, &models.PRReviewer{
+			ID:       r2.ID,
+			Pool:     models.ReviewerPoolFallback,
+			PoolTeam: "platform",
+		}))
+
+		got, err := f.PRs.GetByID(ctx, pr.ID)
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{r1.ID, r2.ID}, reviewerIDs(got.Reviewers))
+		requireSameTime(t, epoch, got.Reviewers[0].AssignedAt)
+		requireSameTime(t, epoch.Add(time.Minute), got.Reviewers[1].AssignedAt)
+		require.Equal(t, models.ReviewerPoolFallback, got.Reviewers[1].Pool)
+		require.Equal(t, "platform", got.Reviewers[1].PoolTeam)
+
+		require.NoError(t, f.PRs.RemoveReviewer(ctx, pr.ID, r1.ID))
+		require.ErrorIs(t, f.PRs.RemoveReviewer(ctx, pr.ID, r1.ID), repository.ErrNotFound)
+
+		got, err = f.PRs.GetByID(ctx, pr.ID)
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{r2.ID}, reviewerIDs(got.Reviewers))
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos, "author")
+		pr := f.newPR(t, f.users[0], epoch)
+
+		f.clk.Advance(time.Hour)
+		require.NoError(t, f.PRs.Merge(ctx, pr.ID))
+
+		got, err := f.PRs.GetByID(ctx, pr.ID)
+		require.NoError(t, err)
+		require.Equal(t, string(models.PRStatusMerged), got.Status)
+		require.NotNil(t, got.MergedAt)
+		requireSameTime(t, epoch.Add(time.Hour), *got.MergedAt)
+	})
+
+	t.Run("version", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos, "author")
+		pr := f.newPR(t, f.users[0], epoch)
+		require.Equal(t, int64(1), pr.Version)
+
+		require.ErrorIs(t, f.PRs.BumpVersion(ctx, pr.ID, 2), repository.ErrNotFound, "stale version")
+		require.ErrorIs(t, f.PRs.BumpVersion(ctx, uuid.New(), 1), repository.ErrNotFound)
+
+		require.NoError(t, f.PRs.BumpVersion(ctx, pr.ID, 1))
+		require.ErrorIs(t, f.PRs.BumpVersion(ctx, pr.ID, 1), repository.ErrNotFound, "bumped once per version")
+
+		got, err := f.PRs.GetByID(ctx, pr.ID)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), got.Version)
+	})
+
+	t.Run("list by reviewer", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos, "author", "r1", "r2")
+		author, r1, r2 := f.users[0], f.users[1], f.users[2]
+
+		newer := f.newPR(t, author, epoch.Add(time.Hour))
+		older := f.newPR(t, author, epoch)
+		other := f.newPR(t, author, epoch.Add(2*time.Hour))
+
+		require.NoError(t, f.PRs.AssignReviewers(ctx, newer.ID, asReviewers(r1.ID)))
+		require.NoError(t, f.PRs.AssignReviewers(ctx, older.ID, asReviewers(r1.ID, r2.ID)))
+		require.NoError(t, f.PRs.AssignReviewers(ctx, other.ID, asReviewers(r2.ID)))
+		require.NoError(t, f.PRs.Merge(ctx, older.ID))
+
+		prs, err := f.PRs.ListByReviewer(ctx, r1.ID)
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{older.ID, newer.ID}, prIDs(prs), "oldest first")
+		require.Equal(t, string(models.PRStatusMerged), prs[0].Status)
+		require.Equal(t, author.ID, prs[1].AuthorID)
+		require.Equal(t, int64(1), prs[1].Version)
+
+		require.NoError(t, f.PRs.BumpVersion(ctx, newer.ID, 1))
+		prs, err = f.PRs.ListByReviewer(ctx, r1.ID)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), prs[1].Version)
+
+		prs, err = f.PRs.ListByReviewer(ctx, author.ID)
+		require.NoError(t, err)
+		require.Empty(t, prs)
+	})
+}
+
+func testStats(t *testing.T, newRepos Factory) {
+	ctx := t.Context()
+	clk := clocktest.NewFake(epoch)
+	repos := newRepos(t, clk)
+
+	newTeam := func(name string) *models.Team {
+		team := &models.Team{Name: name}
+		require.NoError(t, repos.Teams.Create(ctx, team))
+		return team
+	}
+	newUser := func(name string, team *models.Team) *models.User {
+		u := &models.User{Name: name, IsActive: true}
+		if team != nil {
+			u.TeamID = &team.ID
+		}
+		require.NoError(t, repos.Users.Create(ctx, u))
+		return u
+	}
+	newPR := func(author *models.User) *models.PullRequest {
+		pr := &models.PullRequest{
+			ID:        uuid.New(),
+			Name:      "pr",
+			AuthorID:  author.ID,
+			Status:    string(models.PRStatusOpen),
+			CreatedAt: clk.Now(),
+		}
+		require.NoError(t, repos.PRs.Create(ctx, pr))
+		return pr
+	}
+
+	frontend := newTeam("frontend")
+	backend := newTeam("backend")
+	solo := newUser("solo", frontend)
+	r3 := newUser("r3", backend)
+	r2 := newUser("r2", backend)
+	r1 := newUser("r1", backend)
+	author := newUser("author", backend)
+	loner := newUser("loner", nil)
+
+	// pr1: r1 and r2 at T, r1 declines for r3 at T+1h, merged at T+2h
+	pr1 := newPR(author)
+	require.NoError(t, repos.PRs.AssignReviewers(ctx, pr1.ID, asReviewers(r1.ID, r2.ID)))
+	clk.Advance(time.Hour)
+	require.NoError(t, repos.PRs.DeclineReviewer(ctx, pr1.ID, r1.ID, &models.PRReviewer{ID: r3.ID}, models.DeclineBusy))
+	clk.Advance(time.Hour)
+	require.NoError(t, repos.PRs.Merge(ctx, pr1.ID))
+
+	// pr2: r2 at T+2h, still open
+	pr2 := newPR(author)
+	require.NoError(t, repos.PRs.AssignReviewers(ctx, pr2.ID, asReviewers(r2.ID)))
+
+	// pr3: no team, no reviewers
+	clk.Advance(time.Minute)
+	pr3 := newPR(loner)
+
+	hours := func(h float64) *time.Duration {
+		d := time.Duration(h * float64(time.Hour))
+		return &d
+	}
+
+	t.Run("reviewers", func(t *testing.T) {
+		stats, err := repos.Stats.ReviewerStats(ctx, epoch, epoch.Add(3*time.Hour), uuid.Nil)
+		require.NoError(t, err)
+
+		// no team first, then by team and user name
+		require.Equal(t, []*models.ReviewerStats{
+			{UserID: loner.ID, Username: "loner"},
+			{UserID: author.ID, Username: "author", TeamName: "backend"},
+			{UserID: r1.ID, Username: "r1", TeamName: "backend", ReviewLoad: models.ReviewLoad{
+				AssignedTotal: 1, ReassignedAway: 1, Declined: 1,
+			}},
+			{UserID: r2.ID, Username: "r2", TeamName: "backend", ReviewLoad: models.ReviewLoad{
+				OpenAssigned: 1, AssignedTotal: 2, MedianTimeToMerge: hours(2),
+			}},
+			{UserID: r3.ID, Username: "r3", TeamName: "backend", ReviewLoad: models.ReviewLoad{
+				AssignedTotal: 1, MedianTimeToMerge: hours(1),
+			}},
+			{UserID: solo.ID, Username: "solo", TeamName: "frontend"},
+		}, stats)
+	})
+
+	t.Run("teams", func(t *testing.T) {
+		stats, err := repos.Stats.TeamReviewStats(ctx, epoch, epoch.Add(3*time.Hour), uuid.Nil)
+		require.NoError(t, err)
+
+		backendLoad := models.ReviewLoad{
+			OpenAssigned: 1, AssignedTotal: 4, ReassignedAway: 1, Declined: 1, MedianTimeToMerge: hours(1.5),
+		}
+		require.Equal(t, []*models.TeamReviewStats{
+			{TeamID: backend.ID, TeamName: "backend", ReviewLoad: backendLoad, Subtree: backendLoad},
+			{TeamID: frontend.ID, TeamName: "frontend"},
+		}, stats)
+	})
+
+	t.Run("pr cycles", func(t *testing.T) {
+		cycles, err := repos.Stats.ListPRCycles(ctx, epoch, epoch.Add(3*time.Hour), uuid.Nil)
+		require.NoError(t, err)
+		require.Len(t, cycles, 3)
+
+		// created order
+		c1, c2, c3 := cycles[0], cycles[1], cycles[2]
+		require.Equal(t, []uuid.UUID{pr1.ID, pr2.ID, pr3.ID}, []uuid.UUID{c1.PRID, c2.PRID, c3.PRID})
+
+		require.Equal(t, "pr", c1.Name)
+		require.Equal(t, author.ID, c1.AuthorID)
+		require.Equal(t, "backend", c1.TeamName)
+		require.Equal(t, string(models.PRStatusMerged), c1.Status)
+		requireSameTime(t, epoch, c1.CreatedAt)
+		require.NotNil(t, c1.FirstAssignedAt, "reassigned reviewer still counts")
+		requireSameTime(t, epoch, *c1.FirstAssignedAt)
+		require.NotNil(t, c1.MergedAt)
+		requireSameTime(t, epoch.Add(2*time.Hour), *c1.MergedAt)
+
+		require.Equal(t, string(models.PRStatusOpen), c2.Status)
+		require.NotNil(t, c2.FirstAssignedAt)
+		requireSameTime(t, epoch.Add(2*time.Hour), *c2.FirstAssignedAt)
+		require.Nil(t, c2.MergedAt)
+
+		require.Empty(t, c3.TeamName)
+		require.Nil(t, c3.FirstAssignedAt)
+
+		cycles, err = repos.Stats.ListPRCycles(ctx, epoch.Add(time.Hour), epoch.Add(2*time.Hour+time.Minute), uuid.Nil)
+		require.NoError(t, err)
+		require.Len(t, cycles, 1, "window is half-open")
+		require.Equal(t, pr2.ID, cycles[0].PRID)
+	})
+
+	t.Run("stream pr cycles", func(t *testing.T) {
+		var ids []uuid.UUID
+		err := repos.Stats.StreamPRCycles(ctx, epoch, epoch.Add(3*time.Hour), uuid.Nil, func(c *models.PRCycle) error {
+			ids = append(ids, c.PRID)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{pr1.ID, pr2.ID, pr3.ID}, ids)
+
+		errStop := errors.New("stop")
+		calls := 0
+		err = repos.Stats.StreamPRCycles(ctx, epoch, epoch.Add(3*time.Hour), uuid.Nil, func(*models.PRCycle) error {
+			calls++
+			return errStop
+		})
+		require.ErrorIs(t, err, errStop)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("window", func(t *testing.T) {
+		from := epoch.Add(90 * time.Minute)
+
+		stats, err := repos.Stats.ReviewerStats(ctx, from, epoch.Add(3*time.Hour), uuid.Nil)
+		require.NoError(t, err)
+		require.Len(t, stats, 6)
+
+		// open assignments ignore the window
+		require.Equal(t, models.ReviewLoad{}, stats[2].ReviewLoad, "r1")
+		require.Equal(t, models.ReviewLoad{OpenAssigned: 1, AssignedTotal: 1}, stats[3].ReviewLoad, "r2")
+		require.Equal(t, models.ReviewLoad{}, stats[4].ReviewLoad, "r3")
+
+		teams, err := repos.Stats.TeamReviewStats(ctx, from, epoch.Add(3*time.Hour), uuid.Nil)
+		require.NoError(t, err)
+		require.Equal(t, models.ReviewLoad{OpenAssigned: 1, AssignedTotal: 1}, teams[0].ReviewLoad)
+	})
+
+	// keep last: moves backend under a department
+	t.Run("subtree", func(t *testing.T) {
+		engineering := &models.Team{Name: "engineering"}
+		require.NoError(t, repos.Teams.Create(ctx, engineering))
+		require.NoError(t, repos.Teams.SetParent(ctx, backend.ID, &engineering.ID))
+		manager := newUser("manager", engineering)
+
+		teams, err := repos.Stats.TeamReviewStats(ctx, epoch, epoch.Add(3*time.Hour), engineering.ID)
+		require.NoError(t, err)
+
+		backendLoad := models.ReviewLoad{
+			OpenAssigned: 1, AssignedTotal: 4, ReassignedAway: 1, Declined: 1, MedianTimeToMerge: hours(1.5),
+		}
+		require.Equal(t, []*models.TeamReviewStats{
+			{TeamID: backend.ID, TeamName: "backend", ParentName: "engineering", ReviewLoad: backendLoad, Subtree: backendLoad},
+			{TeamID: engineering.ID, TeamName: "engineering", Subtree: backendLoad},
+		}, teams)
+
+		users, err := repos.Stats.ReviewerStats(ctx, epoch, epoch.Add(3*time.Hour), engineering.ID)
+		require.NoError(t, err)
+		got := make([]uuid.UUID, len(users))
+		for i, u := range users {
+			got[i] = u.UserID
+		}
+		require.Equal(t, []uuid.UUID{author.ID, r1.ID, r2.ID, r3.ID, manager.ID}, got)
+
+		cycles, err := repos.Stats.ListPRCycles(ctx, epoch, epoch.Add(3*time.Hour), engineering.ID)
+		require.NoError(t, err)
+		require.Len(t, cycles, 2, "only authors within the subtree")
+
+		cycles, err = repos.Stats.ListPRCycles(ctx, epoch, epoch.Add(3*time.Hour), frontend.ID)
+		require.NoError(t, err)
+		require.Empty(t, cycles)
+
+		var streamed int
+		err = repos.Stats.StreamPRCycles(ctx, epoch, epoch.Add(3*time.Hour), backend.ID, func(*models.PRCycle) error {
+			streamed++
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, streamed)
+	})
+}
+
+func testAvailability(t *testing.T, newRepos Factory) {
+	t.Run("create and list by user", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos, "alice", "bob")
+		alice := f.users[0]
+
+		later := f.newAway(t, alice, epoch.Add(48*time.Hour), epoch.Add(72*time.Hour))
+		sooner := f.newAway(t, alice, epoch, epoch.Add(24*time.Hour))
+		f.newAway(t, f.users[1], epoch, epoch.Add(time.Hour))
+		require.NotEqual(t, uuid.Nil, sooner.ID)
+
+		windows, err := f.Availability.ListByUser(ctx, alice.ID)
+		require.NoError(t, err)
+		require.Len(t, windows, 2)
+		require.Equal(t, sooner.ID, windows[0].ID)
+		require.Equal(t, later.ID, windows[1].ID)
+
+		got := windows[0]
+		require.Equal(t, alice.ID, got.UserID)
+		require.Equal(t, "vacation", got.Reason)
+		requireSameTime(t, sooner.StartsAt, got.StartsAt)
+		requireSameTime(t, sooner.EndsAt, got.EndsAt)
+		requireSameTime(t, epoch, got.CreatedAt)
+		require.Nil(t, got.RestoredAt)
+
+		windows, err = f.Availability.ListByUser(ctx, uuid.New())
+		require.NoError(t, err)
+		require.Empty(t, windows)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		repos := newRepos(t, clocktest.NewFake(epoch))
+
+		err := repos.Availability.Create(t.Context(), &models.Availability{
+			UserID:   uuid.New(),
+			StartsAt: epoch,
+			EndsAt:   epoch.Add(time.Hour),
+		})
+		require.ErrorIs(t, err, repository.ErrForeignKeyViolation)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos, "alice")
+		a := f.newAway(t, f.users[0], epoch, epoch.Add(time.Hour))
+
+		require.NoError(t, f.Availability.Delete(ctx, a.ID))
+		require.ErrorIs(t, f.Availability.Delete(ctx, a.ID), repository.ErrNotFound)
+
+		windows, err := f.Availability.ListByUser(ctx, f.users[0].ID)
+		require.NoError(t, err)
+		require.Empty(t, windows)
+	})
+
+	t.Run("ended until restored", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos, "alice", "bob")
+
+		second := f.newAway(t, f.users[0], epoch.Add(-2*time.Hour), epoch)
+		first := f.newAway(t, f.users[1], epoch.Add(-3*time.Hour), epoch.Add(-time.Hour))
+		f.newAway(t, f.users[0], epoch.Add(-time.Hour), epoch.Add(time.Second))
+
+		ended, err := f.Availability.ListEnded(ctx, epoch)
+		require.NoError(t, err)
+		require.Len(t, ended, 2)
+		require.Equal(t, first.ID, ended[0].ID)
+		require.Equal(t, second.ID, ended[1].ID)
+
+		restoredAt := epoch.Add(time.Minute)
+		require.NoError(t, f.Availability.MarkRestored(ctx, first.ID, restoredAt))
+		require.ErrorIs(t, f.Availability.MarkRestored(ctx, uuid.New(), restoredAt), repository.ErrNotFound)
+
+		ended, err = f.Availability.ListEnded(ctx, epoch)
+		require.NoError(t, err)
+		require.Len(t, ended, 1)
+		require.Equal(t, second.ID, ended[0].ID)
+
+		windows, err := f.Availability.ListByUser(ctx, f.users[1].ID)
+		require.NoError(t, err)
+		require.NotNil(t, windows[0].RestoredAt)
+		requireSameTime(t, restoredAt, *windows[0].RestoredAt)
+	})
+
+	t.Run("started until deactivated", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos, "alice", "bob")
+
+		later := f.newAway(t, f.users[0], epoch, epoch.Add(time.Hour))
+		sooner := f.newAway(t, f.users[1], epoch.Add(-time.Hour), epoch.Add(time.Second))
+		f.newAway(t, f.users[0], epoch.Add(-2*time.Hour), epoch)
+		f.newAway(t, f.users[1], epoch.Add(time.Second), epoch.Add(time.Hour))
+
+		started, err := f.Availability.ListStarted(ctx, epoch)
+		require.NoError(t, err)
+		require.Len(t, started, 2)
+		require.Equal(t, sooner.ID, started[0].ID)
+		require.Equal(t, later.ID, started[1].ID)
+
+		deactivatedAt := epoch.Add(time.Minute)
+		require.NoError(t, f.Availability.MarkDeactivated(ctx, sooner.ID, deactivatedAt))
+		require.ErrorIs(t, f.Availability.MarkDeactivated(ctx, uuid.New(), deactivatedAt), repository.ErrNotFound)
+
+		started, err = f.Availability.ListStarted(ctx, epoch)
+		require.NoError(t, err)
+		require.Len(t, started, 1)
+		require.Equal(t, later.ID, started[0].ID)
+
+		got, err := f.Availability.GetByID(ctx, sooner.ID)
+		require.NoError(t, err)
+		require.Equal(t, f.users[1].ID, got.UserID)
+		require.NotNil(t, got.DeactivatedAt)
+		requireSameTime(t, deactivatedAt, *got.DeactivatedAt)
+		require.Nil(t, got.RestoredAt)
+
+		_, err = f.Availability.GetByID(ctx, uuid.New())
+		require.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
+
+func testSLA(t *testing.T, newRepos Factory) {
+	t.Run("overdue until escalated", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos, "author", "r1", "r2")
+		author, r1, r2 := f.users[0], f.users[1], f.users[2]
+
+		sla := models.ReviewSLA{Timeout: time.Hour, Action: models.SLAActionEscalate}
+		require.NoError(t, f.Teams.SetReviewSLA(ctx, f.team.ID, &sla))
+
+		// reassigning the set re-stamps every assignment a minute later
+		pr := f.newPR(t, author, epoch)
+		require.NoError(t, f.PRs.AssignReviewers(ctx, pr.ID, asReviewers(r1.ID)))
+		f.clk.Advance(time.Minute)
+		require.NoError(t, f.PRs.AssignReviewers(ctx, pr.ID, asReviewers(r1.ID, r2.ID)))
+
+		merged := f.newPR(t, author, epoch)
+		require.NoError(t, f.PRs.AssignReviewers(ctx, merged.ID, asReviewers(r1.ID)))
+		require.NoError(t, f.PRs.Merge(ctx, merged.ID))
+
+		overdue, err := f.SLA.ListOverdue(ctx, epoch.Add(time.Hour))
+		require.NoError(t, err)
+		require.Empty(t, overdue)
+
+		// the deadline is inclusive and merged PRs are skipped
+		overdue, err = f.SLA.ListOverdue(ctx, epoch.Add(time.Hour+time.Minute))
+		require.NoError(t, err)
+		require.Len(t, overdue, 2)
+		require.ElementsMatch(t, []uuid.UUID{r1.ID, r2.ID}, []uuid.UUID{overdue[0].ReviewerID, overdue[1].ReviewerID})
+
+		first, second := overdue[0], overdue[1]
+		require.Negative(t, bytes.Compare(first.ReviewerID[:], second.ReviewerID[:]), "ordered by reviewer id")
+		require.Equal(t, pr.ID, first.PRID)
+		require.Equal(t, pr.Name, first.PRName)
+		require.Equal(t, f.team.ID, first.TeamID)
+		require.Equal(t, f.team.Name, first.TeamName)
+		require.Equal(t, sla, first.SLA)
+		requireSameTime(t, epoch.Add(time.Minute), first.AssignedAt)
+		requireSameTime(t, epoch.Add(time.Hour+time.Minute), first.Deadline())
+
+		e := &models.ReviewEscalation{
+			PRID:        first.PRID,
+			ReviewerID:  first.ReviewerID,
+			AssignedAt:  first.AssignedAt,
+			EscalatedAt: epoch.Add(2 * time.Hour),
+			Reason:      "team policy",
+		}
+		require.NoError(t, f.SLA.RecordEscalation(ctx, e))
+		require.ErrorIs(t, f.SLA.RecordEscalation(ctx, e), repository.ErrDuplicate)
+
+		overdue, err = f.SLA.ListOverdue(ctx, epoch.Add(2*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, overdue, 1)
+		require.Equal(t, second.ReviewerID, overdue[0].ReviewerID)
+	})
+
+	t.Run("teams without sla are skipped", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos, "author", "r1")
+
+		pr := f.newPR(t, f.users[0], epoch)
+		require.NoError(t, f.PRs.AssignReviewers(ctx, pr.ID, asReviewers(f.users[1].ID)))
+
+		overdue, err := f.SLA.ListOverdue(ctx, epoch.Add(365*24*time.Hour))
+		require.NoError(t, err)
+		require.Empty(t, overdue)
+	})
+}
+
+func testDigest(t *testing.T, newRepos Factory) {
+	t.Run("list scheduled", func(t *testing.T) {
+		ctx := t.Context()
+		repos := newRepos(t, clocktest.NewFake(epoch))
+
+		d := &models.DigestSchedule{At: 9 * time.Hour, Location: time.UTC}
+		frontend := &models.Team{Name: "frontend", Digest: d}
+		backend := &models.Team{Name: "backend", Digest: d}
+		require.NoError(t, repos.Teams.Create(ctx, frontend))
+		require.NoError(t, repos.Teams.Create(ctx, &models.Team{Name: "ops"}))
+		require.NoError(t, repos.Teams.Create(ctx, backend))
+
+		teams, err := repos.Digest.ListScheduled(ctx)
+		require.NoError(t, err)
+		require.Len(t, teams, 2)
+		require.Equal(t, backend.ID, teams[0].ID)
+		require.Equal(t, "backend", teams[0].Name)
+		require.Equal(t, 9*time.Hour, teams[0].Digest.At)
+		require.Equal(t, frontend.ID, teams[1].ID)
+	})
+
+	t.Run("mark sent once per day", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos)
+
+		marked, err := f.Digest.MarkSent(ctx, f.team.ID, "2025-10-24")
+		require.NoError(t, err)
+		require.True(t, marked)
+
+		marked, err = f.Digest.MarkSent(ctx, f.team.ID, "2025-10-24")
+		require.NoError(t, err)
+		require.False(t, marked)
+
+		// a time zone change must not send an earlier day again
+		marked, err = f.Digest.MarkSent(ctx, f.team.ID, "2025-10-23")
+		require.NoError(t, err)
+		require.False(t, marked)
+
+		marked, err = f.Digest.MarkSent(ctx, f.team.ID, "2025-10-25")
+		require.NoError(t, err)
+		require.True(t, marked)
+
+		marked, err = f.Digest.MarkSent(ctx, uuid.New(), "2025-10-25")
+		require.NoError(t, err)
+		require.False(t, marked)
+
+		sentOn, err := f.Digest.SentOn(ctx, f.team.ID)
+		require.NoError(t, err)
+		require.Equal(t, "2025-10-25", sentOn)
+
+		_, err = f.Digest.SentOn(ctx, uuid.New())
+		require.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run("mark delivered once per user and day", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos, "alice", "bob")
+		alice, bob := f.users[0], f.users[1]
+
+		sentOn, err := f.Digest.SentOn(ctx, f.team.ID)
+		require.NoError(t, err)
+		require.Empty(t, sentOn)
+
+		marked, err := f.Digest.MarkDelivered(ctx, f.team.ID, alice.ID, "2025-10-24")
+		require.NoError(t, err)
+		require.True(t, marked)
+
+		marked, err = f.Digest.MarkDelivered(ctx, f.team.ID, alice.ID, "2025-10-24")
+		require.NoError(t, err)
+		require.False(t, marked)
+
+		marked, err = f.Digest.MarkDelivered(ctx, f.team.ID, alice.ID, "2025-10-25")
+		require.NoError(t, err)
+		require.True(t, marked)
+
+		// a failed send rolls the delivery back
+		boom := errors.New("smtp down")
+		err = f.Tx.Do(ctx, func(ctx context.Context) error {
+			if _, err := f.Digest.MarkDelivered(ctx, f.team.ID, bob.ID, "2025-10-24"); err != nil {
+				return err
+			}
+			return boom
+		})
+		require.ErrorIs(t, err, boom)
+
+		marked, err = f.Digest.MarkDelivered(ctx, f.team.ID, bob.ID, "2025-10-24")
+		require.NoError(t, err)
+		require.True(t, marked)
+
+		_, err = f.Digest.MarkDelivered(ctx, f.team.ID, uuid.New(), "2025-10-24")
+		require.ErrorIs(t, err, repository.ErrForeignKeyViolation)
+	})
+}
+
+func testIntegration(t *testing.T, newRepos Factory) {
+	t.Run("users", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos, "alice", "bob")
+		alice, bob := f.users[0], f.users[1]
+
+		_, err := f.Integration.GetUserID(ctx, models.ProviderGitHub, "octocat")
+		require.ErrorIs(t, err, repository.ErrNotFound)
+
+		require.NoError(t, f.Integration.LinkUser(ctx, models.ProviderGitHub, "octocat", alice.ID))
+		id, err := f.Integration.GetUserID(ctx, models.ProviderGitHub, "octocat")
+		require.NoError(t, err)
+		require.Equal(t, alice.ID, id)
+
+		// relinking moves the login, other providers are separate
+		require.NoError(t, f.Integration.LinkUser(ctx, models.ProviderGitHub, "octocat", bob.ID))
+		id, err = f.Integration.GetUserID(ctx, models.ProviderGitHub, "octocat")
+		require.NoError(t, err)
+		require.Equal(t, bob.ID, id)
+
+		_, err = f.Integration.GetUserID(ctx, "gitlab", "octocat")
+		require.ErrorIs(t, err, repository.ErrNotFound)
+
+		err = f.Integration.LinkUser(ctx, models.ProviderGitHub, "ghost", uuid.New())
+		require.ErrorIs(t, err, repository.ErrForeignKeyViolation)
+	})
+
+	t.Run("logins", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos, "alice", "bob")
+		alice, bob := f.users[0], f.users[1]
+
+		_, err := f.Integration.GetLogin(ctx, models.ProviderGitHub, alice.ID)
+		require.ErrorIs(t, err, repository.ErrNotFound)
+
+		// several logins resolve to the first one
+		require.NoError(t, f.Integration.LinkUser(ctx, models.ProviderGitHub, "alice-work", alice.ID))
+		require.NoError(t, f.Integration.LinkUser(ctx, models.ProviderGitHub, "alice", alice.ID))
+		require.NoError(t, f.Integration.LinkUser(ctx, models.ProviderGitLab, "1042", bob.ID))
+
+		login, err := f.Integration.GetLogin(ctx, models.ProviderGitHub, alice.ID)
+		require.NoError(t, err)
+		require.Equal(t, "alice", login)
+
+		_, err = f.Integration.GetLogin(ctx, models.ProviderGitHub, bob.ID)
+		require.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run("pull requests", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos, "author")
+		pr := f.newPR(t, f.users[0], epoch)
+		other := f.newPR(t, f.users[0], epoch)
+
+		ref := models.ExternalPR{Provider: models.ProviderGitHub, Repository: "octo-org/api", Number: 42}
+
+		_, err := f.Integration.GetPRID(ctx, ref)
+		require.ErrorIs(t, err, repository.ErrNotFound)
+
+		require.NoError(t, f.Integration.LinkPR(ctx, ref, pr.ID))
+		id, err := f.Integration.GetPRID(ctx, ref)
+		require.NoError(t, err)
+		require.Equal(t, pr.ID, id)
+
+		require.ErrorIs(t, f.Integration.LinkPR(ctx, ref, other.ID), repository.ErrDuplicate)
+
+		// one PR of ours maps to a single code host PR
+		ref43 := models.ExternalPR{Provider: models.ProviderGitHub, Repository: "octo-org/api", Number: 43}
+		require.ErrorIs(t, f.Integration.LinkPR(ctx, ref43, pr.ID), repository.ErrDuplicate)
+
+		require.ErrorIs(t, f.Integration.LinkPR(ctx, ref43, uuid.New()), repository.ErrForeignKeyViolation)
+
+		_, err = f.Integration.GetPRID(ctx, models.ExternalPR{Provider: models.ProviderGitHub, Repository: "octo-org/web", Number: 42})
+		require.ErrorIs(t, err, repository.ErrNotFound)
+
+		got, err := f.Integration.GetExternalPR(ctx, models.ProviderGitHub, pr.ID)
+		require.NoError(t, err)
+		require.Equal(t, ref, got)
+
+		_, err = f.Integration.GetExternalPR(ctx, models.ProviderGitLab, pr.ID)
+		require.ErrorIs(t, err, repository.ErrNotFound)
+		_, err = f.Integration.GetExternalPR(ctx, models.ProviderGitHub, other.ID)
+		require.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
+
+func testTx(t *testing.T, newRepos Factory) {
+	t.Run("rollback", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos, "author", "r1")
+		pr := f.newPR(t, f.users[0], epoch)
+
+		err := f.Tx.Do(ctx, func(ctx context.Context) error {
+			if err := f.PRs.AssignReviewers(ctx, pr.ID, asReviewers(f.users[1].ID)); err != nil {
+				return err
+			}
+			if err := f.Users.UpdateActive(ctx, f.users[1].ID, false); err != nil {
+				return err
+			}
+			return f.Teams.Create(ctx, &models.Team{Name: f.team.Name})
+		})
+		require.ErrorIs(t, err, repository.ErrDuplicate)
+
+		got, err := f.PRs.GetByID(ctx, pr.ID)
+		require.NoError(t, err)
+		require.Empty(t, got.Reviewers)
+
+		u, err := f.Users.GetUserByID(ctx, f.users[1].ID)
+		require.NoError(t, err)
+		require.True(t, u.IsActive)
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		ctx := t.Context()
+		f := newFixture(t, newRepos, "author", "r1")
+		pr := f.newPR(t, f.users[0], epoch)
+
+		err := f.Tx.Do(ctx, func(ctx context.Context) error {
+			return f.PRs.AssignReviewers(ctx, pr.ID, asReviewers(f.users[1].ID))
+		})
+		require.NoError(t, err)
+
+		got, err := f.PRs.GetByID(ctx, pr.ID)
+		require.NoError(t, err)
+		require.Len(t, got.Reviewers, 1)
+	})
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"pr-service/internal/models"
+	"pr-service/internal/retry"
+
+	sq "github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// overdueReviewsSQL lists assignments on OPEN PRs that outlived the SLA of
+// the author's team at $1 and have not been escalated yet.
+const overdueReviewsSQL = `
+SELECT pr.id, pr.name, r.id, r.assigned_at, t.id, t.name, t.review_sla_seconds, t.review_sla_action
+FROM pr_reviewers r
+JOIN pull_requests pr ON pr.id = r.pull_request_id
+JOIN users au ON au.id = pr.author_id
+JOIN teams t ON t.id = au.team_id
+WHERE pr.status = 'OPEN'
+	AND t.review_sla_seconds IS NOT NULL
+	AND r.assigned_at + t.review_sla_seconds * interval '1 second' <= $1
+	AND NOT EXISTS (
+		SELECT 1 FROM review_escalations e
+		WHERE e.pull_request_id = r.pull_request_id AND e.reviewer_id = r.id AND e.assigned_at = r.assigned_at
+	)
+ORDER BY r.assigned_at, pr.id, r.id`
+
+type SLARepository struct {
+	db      *pgxpool.Pool
+	getter  *trmpgx.CtxGetter
+	psql    sq.StatementBuilderType
+	retrier retry.Retrier
+}
+
+func NewSLARepository(db *pgxpool.Pool, c *trmpgx.CtxGetter, r retry.Retrier) *SLARepository {
+	return &SLARepository{
+		db:      db,
+		getter:  c,
+		psql:    sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+		retrier: r,
+	}
+}
+
+func (r *SLARepository) ListOverdue(ctx context.Context, at time.Time) ([]*models.OverdueReview, error) {
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	var overdue []*models.OverdueReview
+
+	err := r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		rows, err := conn.Query(ctx, overdueReviewsSQL, at)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		overdue = make([]*models.OverdueReview, 0)
+		for rows.Next() {
+			var (
+				o          = &models.OverdueReview{}
+				slaSeconds *int64
+				slaAction  string
+			)
+			if err := rows.Scan(
+				&o.PRID, &o.PRName, &o.ReviewerID, &o.AssignedAt, &o.TeamID, &o.TeamName, &slaSeconds, &slaAction,
+			); err != nil {
+				return err
+			}
+
+			o.SLA = *SLAFromColumns(slaSeconds, slaAction)
+			overdue = append(overdue, o)
+		}
+
+		return rows.Err()
+	})
+
+	return overdue, wrapDBError(err)
+}
+
+func (r *SLARepository) RecordEscalation(ctx context.Context, e *models.ReviewEscalation) error {
+	query := r.psql.Insert("review_escalations").
+		Columns("pull_request_id", "reviewer_id", "assigned_at", "escalated_at", "reason").
+		Values(e.PRID, e.ReviewerID, e.AssignedAt, e.EscalatedAt, e.Reason)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		_, retryErr := conn.Exec(ctx, sql, args...)
+		return retryErr
+	})
+
+	return wrapDBError(err)
+}
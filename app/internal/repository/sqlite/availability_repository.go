@@ -0,0 +1,185 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/retry"
+	"pr-service/internal/service"
+
+	sq "github.com/Masterminds/squirrel"
+	trmsql "github.com/avito-tech/go-transaction-manager/drivers/sql/v2"
+	"github.com/google/uuid"
+)
+
+var _ service.AvailabilityRepository = (*AvailabilityRepository)(nil)
+
+var availabilityColumns = []string{
+	"id", "user_id", "starts_at", "ends_at", "reason", "created_at", "deactivated_at", "restored_at",
+}
+
+type AvailabilityRepository struct {
+	db      *sql.DB
+	getter  *trmsql.CtxGetter
+	psql    sq.StatementBuilderType
+	retrier retry.Retrier
+}
+
+func NewAvailabilityRepository(db *sql.DB, c *trmsql.CtxGetter, r retry.Retrier) *AvailabilityRepository {
+	return &AvailabilityRepository{
+		db:      db,
+		getter:  c,
+		psql:    sq.StatementBuilder.PlaceholderFormat(sq.Question),
+		retrier: r,
+	}
+}
+
+func (r *AvailabilityRepository) Create(ctx context.Context, a *models.Availability) error {
+	id := uuid.New()
+	query := r.psql.Insert("user_availability").
+		Columns("id", "user_id", "starts_at", "ends_at", "reason", "created_at").
+		Values(id, a.UserID, toMicro(a.StartsAt), toMicro(a.EndsAt), a.Reason, toMicro(a.CreatedAt))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		_, retryErr := conn.ExecContext(ctx, sql, args...)
+		return retryErr
+	})
+	if err != nil {
+		return wrapDBError(err)
+	}
+
+	a.ID = id
+	return nil
+}
+
+func (r *AvailabilityRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Availability, error) {
+	windows, err := r.listBy(ctx, sq.Eq{"id": id}, "id")
+	if err != nil {
+		return nil, err
+	}
+	if len(windows) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return windows[0], nil
+}
+
+func (r *AvailabilityRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Availability, error) {
+	return r.listBy(ctx, sq.Eq{"user_id": userID}, "starts_at", "id")
+}
+
+func (r *AvailabilityRepository) ListEnded(ctx context.Context, at time.Time) ([]*models.Availability, error) {
+	return r.listBy(ctx, sq.And{
+		sq.LtOrEq{"ends_at": toMicro(at)},
+		sq.Eq{"restored_at": nil},
+	}, "ends_at", "id")
+}
+
+func (r *AvailabilityRepository) ListStarted(ctx context.Context, at time.Time) ([]*models.Availability, error) {
+	return r.listBy(ctx, sq.And{
+		sq.LtOrEq{"starts_at": toMicro(at)},
+		sq.Gt{"ends_at": toMicro(at)},
+		sq.Eq{"deactivated_at": nil},
+	}, "starts_at", "id")
+}
+
+func (r *AvailabilityRepository) listBy(ctx context.Context, where sq.Sqlizer, orderBy ...string) ([]*models.Availability, error) {
+	query := r.psql.Select(availabilityColumns...).
+		From("user_availability").
+		Where(where).
+		OrderBy(orderBy...)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	var windows []*models.Availability
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		rows, err := conn.QueryContext(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		windows = make([]*models.Availability, 0)
+		for rows.Next() {
+			var (
+				a                           = &models.Availability{}
+				startsAt, endsAt, createdAt int64
+				deactivatedAt, restoredAt   *int64
+			)
+			if err := rows.Scan(
+				&a.ID, &a.UserID, &startsAt, &endsAt, &a.Reason, &createdAt, &deactivatedAt, &restoredAt,
+			); err != nil {
+				return err
+			}
+
+			a.StartsAt = fromMicro(startsAt)
+			a.EndsAt = fromMicro(endsAt)
+			a.CreatedAt = fromMicro(createdAt)
+			a.DeactivatedAt = fromMicroPtr(deactivatedAt)
+			a.RestoredAt = fromMicroPtr(restoredAt)
+			windows = append(windows, a)
+		}
+
+		return rows.Err()
+	})
+
+	return windows, wrapDBError(err)
+}
+
+func (r *AvailabilityRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := r.psql.Delete("user_availability").
+		Where(sq.Eq{"id": id})
+
+	return r.execOne(ctx, query)
+}
+
+func (r *AvailabilityRepository) MarkRestored(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := r.psql.Update("user_availability").
+		Set("restored_at", toMicro(at)).
+		Where(sq.Eq{"id": id})
+
+	return r.execOne(ctx, query)
+}
+
+func (r *AvailabilityRepository) MarkDeactivated(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := r.psql.Update("user_availability").
+		Set("deactivated_at", toMicro(at)).
+		Where(sq.Eq{"id": id})
+
+	return r.execOne(ctx, query)
+}
+
+// execOne runs a statement that must change a row, ErrNotFound otherwise.
+func (r *AvailabilityRepository) execOne(ctx context.Context, query sq.Sqlizer) error {
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		res, retryErr := conn.ExecContext(ctx, sql, args...)
+		if retryErr != nil {
+			return retryErr
+		}
+
+		return requireAffected(res)
+	})
+
+	return wrapDBError(err)
+}
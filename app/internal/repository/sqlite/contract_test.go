@@ -0,0 +1,48 @@
+package sqlite_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"pr-service/internal/clock"
+	"pr-service/internal/repository/repotest"
+	"pr-service/internal/repository/sqlite"
+	"pr-service/internal/retry"
+
+	trmsql "github.com/avito-tech/go-transaction-manager/drivers/sql/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContract(t *testing.T) {
+	retrier := retry.NoRetry()
+
+	repotest.Run(t, func(t *testing.T, clk clock.Clock) repotest.Repos {
+		db, err := sqlite.Open(t.Context(), filepath.Join(t.TempDir(), "pr.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+
+		require.NoError(t, sqlite.Migrate(db))
+
+		return repotest.Repos{
+			Teams:        sqlite.NewTeamRepository(db, trmsql.DefaultCtxGetter, retrier),
+			Users:        sqlite.NewUserRepository(db, trmsql.DefaultCtxGetter, retrier),
+			PRs:          sqlite.NewPRRepository(db, trmsql.DefaultCtxGetter, retrier, clk),
+			Stats:        sqlite.NewStatsRepository(db, trmsql.DefaultCtxGetter, retrier),
+			Availability: sqlite.NewAvailabilityRepository(db, trmsql.DefaultCtxGetter, retrier),
+			SLA:          sqlite.NewSLARepository(db, trmsql.DefaultCtxGetter, retrier),
+			Digest:       sqlite.NewDigestRepository(db, trmsql.DefaultCtxGetter, retrier),
+			Integration:  sqlite.NewIntegrationRepository(db, trmsql.DefaultCtxGetter, retrier),
+			Tx:           manager.Must(trmsql.NewDefaultFactory(db)),
+		}
+	})
+}
+
+func TestMigrateIsIdempotent(t *testing.T) {
+	db, err := sqlite.Open(t.Context(), filepath.Join(t.TempDir(), "pr.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, sqlite.Migrate(db))
+	require.NoError(t, sqlite.Migrate(db))
+}
@@ -0,0 +1,87 @@
+// Package sqlite implements the service repositories on top of an embedded
+// SQLite database, for tools that run the review logic without Postgres.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	migratesqlite "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	_ "modernc.org/sqlite"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Open opens the database file at path with foreign keys enforced and
+// verifies it with a ping. SQLite allows a single writer, so the pool
+// keeps one connection and callers queue on it instead of failing busy.
+func Open(ctx context.Context, path string) (*sql.DB, error) {
+	dsn := "file:" + path + "?" + url.Values{
+		"_pragma": {"foreign_keys(1)", "busy_timeout(5000)"},
+		"_txlock": {"immediate"},
+	}.Encode()
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite open error: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("sqlite ping error: %w", err)
+	}
+	return db, nil
+}
+
+// Migrate applies the embedded migrations to db.
+func Migrate(db *sql.DB) error {
+	src, err := iofs.New(migrations, "migrations")
+	if err != nil {
+		return err
+	}
+
+	driver, err := migratesqlite.WithInstance(db, &migratesqlite.Config{})
+	if err != nil {
+		return fmt.Errorf("migrate driver error: %w", err)
+	}
+
+	// Closing the migrate instance would close db as well, so it is left open.
+	m, err := migrate.NewWithInstance("iofs", src, "sqlite", driver)
+	if err != nil {
+		return err
+	}
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// toMicro converts t to the stored representation.
+func toMicro(t time.Time) int64 {
+	return t.UnixMicro()
+}
+
+// fromMicro converts a stored timestamp back to UTC time.
+func fromMicro(v int64) time.Time {
+	return time.UnixMicro(v).UTC()
+}
+
+// fromMicroPtr converts a nullable stored timestamp.
+func fromMicroPtr(v *int64) *time.Time {
+	if v == nil {
+		return nil
+	}
+
+	t := fromMicro(*v)
+	return &t
+}
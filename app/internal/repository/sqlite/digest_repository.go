@@ -0,0 +1,169 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/retry"
+	"pr-service/internal/service"
+
+	sq "github.com/Masterminds/squirrel"
+	trmsql "github.com/avito-tech/go-transaction-manager/drivers/sql/v2"
+	"github.com/google/uuid"
+)
+
+var _ service.DigestRepository = (*DigestRepository)(nil)
+
+type DigestRepository struct {
+	db      *sql.DB
+	getter  *trmsql.CtxGetter
+	psql    sq.StatementBuilderType
+	retrier retry.Retrier
+}
+
+func NewDigestRepository(db *sql.DB, c *trmsql.CtxGetter, r retry.Retrier) *DigestRepository {
+	return &DigestRepository{
+		db:      db,
+		getter:  c,
+		psql:    sq.StatementBuilder.PlaceholderFormat(sq.Question),
+		retrier: r,
+	}
+}
+
+func (r *DigestRepository) ListScheduled(ctx context.Context) ([]*models.Team, error) {
+	query := r.psql.Select("id", "name", "digest_minute", "digest_timezone").
+		From("teams").
+		Where(sq.NotEq{"digest_minute": nil}).
+		OrderBy("name")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	var teams []*models.Team
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		rows, err := conn.QueryContext(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		teams = make([]*models.Team, 0)
+		for rows.Next() {
+			var (
+				t              = &models.Team{}
+				digestMinute   *int64
+				digestTimezone string
+			)
+			if err := rows.Scan(&t.ID, &t.Name, &digestMinute, &digestTimezone); err != nil {
+				return err
+			}
+
+			if t.Digest, err = repository.DigestFromColumns(digestMinute, digestTimezone); err != nil {
+				return err
+			}
+			teams = append(teams, t)
+		}
+
+		return rows.Err()
+	})
+
+	return teams, wrapDBError(err)
+}
+
+func (r *DigestRepository) SentOn(ctx context.Context, teamID uuid.UUID) (string, error) {
+	sql, args, err := r.psql.Select("digest_sent_on").
+		From("teams").
+		Where(sq.Eq{"id": teamID}).
+		ToSql()
+	if err != nil {
+		return "", err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	var sentOn *string
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		return conn.QueryRowContext(ctx, sql, args...).Scan(&sentOn)
+	})
+	if err != nil {
+		return "", wrapDBError(err)
+	}
+
+	if sentOn == nil {
+		return "", nil
+	}
+	return *sentOn, nil
+}
+
+func (r *DigestRepository) MarkDelivered(ctx context.Context, teamID, userID uuid.UUID, day string) (bool, error) {
+	sql, args, err := r.psql.Insert("digest_deliveries").
+		Columns("team_id", "user_id", "day").
+		Values(teamID, userID, day).
+		Suffix("ON CONFLICT DO NOTHING").
+		ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	var marked bool
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		res, retryErr := conn.ExecContext(ctx, sql, args...)
+		if retryErr != nil {
+			return retryErr
+		}
+
+		n, retryErr := res.RowsAffected()
+		if retryErr != nil {
+			return retryErr
+		}
+
+		marked = n > 0
+		return nil
+	})
+
+	return marked, wrapDBError(err)
+}
+
+// MarkSent stores the day as YYYY-MM-DD text, which orders like a date.
+func (r *DigestRepository) MarkSent(ctx context.Context, teamID uuid.UUID, day string) (bool, error) {
+	query := r.psql.Update("teams").
+		Set("digest_sent_on", day).
+		Where(sq.Eq{"id": teamID}).
+		Where(sq.Or{
+			sq.Eq{"digest_sent_on": nil},
+			sq.Lt{"digest_sent_on": day},
+		})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	var marked bool
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		res, retryErr := conn.ExecContext(ctx, sql, args...)
+		if retryErr != nil {
+			return retryErr
+		}
+
+		n, retryErr := res.RowsAffected()
+		if retryErr != nil {
+			return retryErr
+		}
+
+		marked = n > 0
+		return nil
+	})
+
+	return marked, wrapDBError(err)
+}
@@ -0,0 +1,41 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"pr-service/internal/repository"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// wrapDBError converts SQLite errors into the repository errors returned by
+// the Postgres implementation.
+func wrapDBError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, repository.ErrNotFound) {
+		return repository.ErrNotFound
+	}
+	if errors.Is(err, sql.ErrTxDone) {
+		return repository.ErrTxAborted
+	}
+
+	var liteErr *sqlite.Error
+	if errors.As(err, &liteErr) {
+		switch liteErr.Code() {
+		case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+			return repository.ErrDuplicate
+		case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+			return repository.ErrForeignKeyViolation
+		default:
+			return fmt.Errorf("sqlite error [%d]: %w", liteErr.Code(), err)
+		}
+	}
+
+	return err
+}
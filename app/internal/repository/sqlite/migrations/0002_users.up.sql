@@ -0,0 +1,8 @@
+CREATE TABLE users (
+    id TEXT PRIMARY KEY,
+    team_id TEXT REFERENCES teams(id) ON DELETE SET NULL,
+    name TEXT NOT NULL,
+    is_active INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE INDEX users_team_id_idx ON users(team_id);
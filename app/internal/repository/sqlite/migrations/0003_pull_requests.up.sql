@@ -0,0 +1,9 @@
+-- Timestamps are stored as Unix microseconds, the precision Postgres keeps.
+CREATE TABLE pull_requests (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    author_id TEXT NOT NULL REFERENCES users(id),
+    status TEXT NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'MERGED')),
+    created_at INTEGER NOT NULL,
+    merged_at INTEGER NULL
+);
@@ -0,0 +1,8 @@
+CREATE TABLE pr_reviewers (
+    id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    assigned_at INTEGER NOT NULL,
+    PRIMARY KEY(pull_request_id, id)
+);
+
+CREATE INDEX pr_reviewers_id_idx ON pr_reviewers(id);
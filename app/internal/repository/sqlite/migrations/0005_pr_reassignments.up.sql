@@ -0,0 +1,10 @@
+CREATE TABLE pr_reassignments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    from_user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    to_user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    from_assigned_at INTEGER NOT NULL,
+    reassigned_at INTEGER NOT NULL
+);
+
+CREATE INDEX pr_reassignments_from_user_id_idx ON pr_reassignments(from_user_id);
@@ -0,0 +1,13 @@
+CREATE TABLE user_availability (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    starts_at INTEGER NOT NULL,
+    ends_at INTEGER NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at INTEGER NOT NULL,
+    restored_at INTEGER,
+    CHECK (starts_at < ends_at)
+);
+
+CREATE INDEX user_availability_user_id_idx ON user_availability(user_id, starts_at);
+CREATE INDEX user_availability_pending_idx ON user_availability(ends_at) WHERE restored_at IS NULL;
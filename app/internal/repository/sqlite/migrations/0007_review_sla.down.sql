@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS review_escalations;
+
+ALTER TABLE teams DROP COLUMN review_sla_action;
+ALTER TABLE teams DROP COLUMN review_sla_seconds;
\ No newline at end of file
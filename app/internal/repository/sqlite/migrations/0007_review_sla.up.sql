@@ -0,0 +1,13 @@
+ALTER TABLE teams ADD COLUMN review_sla_seconds INTEGER CHECK (review_sla_seconds > 0);
+ALTER TABLE teams ADD COLUMN review_sla_action TEXT NOT NULL DEFAULT 'escalate'
+    CHECK (review_sla_action IN ('reassign', 'escalate'));
+
+CREATE TABLE review_escalations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    reviewer_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    assigned_at INTEGER NOT NULL,
+    escalated_at INTEGER NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    UNIQUE (pull_request_id, reviewer_id, assigned_at)
+);
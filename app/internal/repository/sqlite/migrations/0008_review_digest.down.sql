@@ -0,0 +1,5 @@
+ALTER TABLE teams DROP COLUMN digest_sent_on;
+ALTER TABLE teams DROP COLUMN digest_timezone;
+ALTER TABLE teams DROP COLUMN digest_minute;
+
+ALTER TABLE users DROP COLUMN email;
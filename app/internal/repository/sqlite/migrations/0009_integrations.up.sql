@@ -0,0 +1,14 @@
+CREATE TABLE external_users (
+    provider TEXT NOT NULL,
+    login TEXT NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (provider, login)
+);
+
+CREATE TABLE external_pull_requests (
+    provider TEXT NOT NULL,
+    repository TEXT NOT NULL,
+    number INTEGER NOT NULL,
+    pull_request_id TEXT NOT NULL UNIQUE REFERENCES pull_requests(id) ON DELETE CASCADE,
+    PRIMARY KEY (provider, repository, number)
+);
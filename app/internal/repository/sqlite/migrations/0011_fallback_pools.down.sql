@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS team_fallback_pools;
+
+ALTER TABLE pr_reviewers DROP COLUMN pool_team;
+ALTER TABLE pr_reviewers DROP COLUMN pool;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS teams_parent_id_idx;
+
+ALTER TABLE teams DROP COLUMN parent_id;
@@ -0,0 +1,2 @@
+-- incremented on every change of the PR, served as its ETag
+ALTER TABLE pull_requests ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,2 @@
+DROP INDEX user_availability_started_idx;
+ALTER TABLE user_availability DROP COLUMN deactivated_at;
@@ -0,0 +1,7 @@
+-- digests delivered to a reviewer, recorded in the same transaction as the send
+CREATE TABLE digest_deliveries (
+    team_id TEXT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    day TEXT NOT NULL,
+    PRIMARY KEY (team_id, user_id, day)
+);
@@ -0,0 +1,24 @@
+CREATE TABLE pr_reassignments_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    from_user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    to_user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    from_assigned_at INTEGER NOT NULL,
+    reassigned_at INTEGER NOT NULL,
+    decline_reason TEXT
+        CHECK (decline_reason IN ('busy', 'wrong_expertise', 'conflict'))
+);
+
+INSERT INTO pr_reassignments_old
+SELECT id, pull_request_id, from_user_id, to_user_id, from_assigned_at, reassigned_at, decline_reason
+FROM pr_reassignments
+WHERE to_user_id IS NOT NULL;
+
+DROP TABLE pr_reassignments;
+
+ALTER TABLE pr_reassignments_old RENAME TO pr_reassignments;
+
+CREATE INDEX pr_reassignments_from_user_id_idx ON pr_reassignments(from_user_id);
+
+CREATE INDEX pr_reassignments_declined_idx ON pr_reassignments (pull_request_id)
+    WHERE decline_reason IS NOT NULL;
@@ -0,0 +1,25 @@
+-- a reviewer may decline with nobody to take over; to_user_id is NULL then.
+-- SQLite can not drop NOT NULL in place, so the table is rebuilt.
+CREATE TABLE pr_reassignments_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    from_user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    to_user_id TEXT REFERENCES users(id) ON DELETE CASCADE,
+    from_assigned_at INTEGER NOT NULL,
+    reassigned_at INTEGER NOT NULL,
+    decline_reason TEXT
+        CHECK (decline_reason IN ('busy', 'wrong_expertise', 'conflict'))
+);
+
+INSERT INTO pr_reassignments_new
+SELECT id, pull_request_id, from_user_id, to_user_id, from_assigned_at, reassigned_at, decline_reason
+FROM pr_reassignments;
+
+DROP TABLE pr_reassignments;
+
+ALTER TABLE pr_reassignments_new RENAME TO pr_reassignments;
+
+CREATE INDEX pr_reassignments_from_user_id_idx ON pr_reassignments(from_user_id);
+
+CREATE INDEX pr_reassignments_declined_idx ON pr_reassignments (pull_request_id)
+    WHERE decline_reason IS NOT NULL;
@@ -0,0 +1,498 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"pr-service/internal/clock"
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/retry"
+	"pr-service/internal/service"
+
+	sq "github.com/Masterminds/squirrel"
+	trmsql "github.com/avito-tech/go-transaction-manager/drivers/sql/v2"
+	"github.com/google/uuid"
+)
+
+var _ service.PRRepository = (*PRRepository)(nil)
+
+type PRRepository struct {
+	db      *sql.DB
+	getter  *trmsql.CtxGetter
+	psql    sq.StatementBuilderType
+	retrier retry.Retrier
+	clock   clock.Clock
+}
+
+func NewPRRepository(db *sql.DB, c *trmsql.CtxGetter, r retry.Retrier, clk clock.Clock) *PRRepository {
+	return &PRRepository{
+		db:      db,
+		getter:  c,
+		psql:    sq.StatementBuilder.PlaceholderFormat(sq.Question),
+		retrier: r,
+		clock:   clk,
+	}
+}
+
+func (r *PRRepository) Create(ctx context.Context, pr *models.PullRequest) error {
+	pr.Version = 1
+	query := r.psql.Insert("pull_requests").
+		Columns("id", "name", "author_id", "status", "created_at", "version").
+		Values(pr.ID, pr.Name, pr.AuthorID, pr.Status, toMicro(pr.CreatedAt), pr.Version)
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		_, retryErr := conn.ExecContext(ctx, sql, args...)
+		return retryErr
+	})
+
+	return wrapDBError(err)
+}
+
+func (r *PRRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.PullRequest, error) {
+	query := r.psql.Select(
+		"pr.id",
+		"pr.name",
+		"pr.author_id",
+		"pr.status",
+		"pr.created_at",
+		"pr.merged_at",
+		"pr.version",
+		"r.id",
+		"r.assigned_at",
+		"r.pool",
+		"r.pool_team",
+	).From("pull_requests pr").
+		LeftJoin("pr_reviewers r ON r.pull_request_id = pr.id").
+		Where(sq.Eq{"pr.id": id}).
+		OrderBy("r.assigned_at", "r.id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	var pr *models.PullRequest
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		rows, err := conn.QueryContext(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		pr = &models.PullRequest{
+			Reviewers: make([]*models.PRReviewer, 0),
+		}
+		found := false
+
+		for rows.Next() {
+			found = true
+
+			var (
+				createdAt  int64
+				mergedAt   *int64
+				reviewerID *uuid.UUID
+				assignedAt *int64
+				pool       *string
+				poolTeam   *string
+			)
+			err := rows.Scan(
+				&pr.ID,
+				&pr.Name,
+				&pr.AuthorID,
+				&pr.Status,
+				&createdAt,
+				&mergedAt,
+				&pr.Version,
+				&reviewerID,
+				&assignedAt,
+				&pool,
+				&poolTeam,
+			)
+			if err != nil {
+				return err
+			}
+
+			pr.CreatedAt = fromMicro(createdAt)
+			if mergedAt != nil {
+				t := fromMicro(*mergedAt)
+				pr.MergedAt = &t
+			}
+
+			if reviewerID != nil && assignedAt != nil {
+				rv := &models.PRReviewer{
+					ID:         *reviewerID,
+					PRID:       pr.ID,
+					AssignedAt: fromMicro(*assignedAt),
+				}
+				rv.Pool, rv.PoolTeam = repository.ReviewerPoolFromColumns(*pool, poolTeam)
+				pr.Reviewers = append(pr.Reviewers, rv)
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if !found {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, wrapDBError(err)
+	}
+
+	return pr, nil
+}
+
+// GetByIDForUpdate is GetByID: transactions are opened IMMEDIATE and take
+// the database write lock up front, so nothing else changes the PR meanwhile.
+func (r *PRRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*models.PullRequest, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *PRRepository) AssignReviewers(ctx context.Context, prID uuid.UUID, reviewers []*models.PRReviewer) error {
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	now := toMicro(r.clock.Now())
+
+	delSQL, delArgs, err := r.psql.
+		Delete("pr_reviewers").
+		Where(sq.Eq{"pull_request_id": prID}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	var (
+		insertSQL  string
+		insertArgs []any
+	)
+	if len(reviewers) > 0 {
+		insert := r.psql.
+			Insert("pr_reviewers").
+			Columns("id", "pull_request_id", "assigned_at", "pool", "pool_team")
+		for _, rv := range reviewers {
+			pool, poolTeam := repository.ReviewerPoolColumns(rv)
+			insert = insert.Values(rv.ID, prID, now, pool, poolTeam)
+		}
+
+		insertSQL, insertArgs, err = insert.ToSql()
+		if err != nil {
+			return err
+		}
+	}
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		return atomic(ctx, r.db, conn, func(tr trmsql.Tr) error {
+			if _, err := tr.ExecContext(ctx, delSQL, delArgs...); err != nil {
+				return err
+			}
+
+			if insertSQL == "" {
+				return nil
+			}
+
+			_, err := tr.ExecContext(ctx, insertSQL, insertArgs...)
+			return err
+		})
+	})
+
+	return wrapDBError(err)
+}
+
+func (r *PRRepository) AddReviewer(ctx context.Context, prID uuid.UUID, reviewer *models.PRReviewer) error {
+	pool, poolTeam := repository.ReviewerPoolColumns(reviewer)
+	query := r.psql.Insert("pr_reviewers").
+		Columns("id", "pull_request_id", "assigned_at", "pool", "pool_team").
+		Values(reviewer.ID, prID, toMicro(r.clock.Now()), pool, poolTeam)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		_, retryErr := conn.ExecContext(ctx, sql, args...)
+		return retryErr
+	})
+
+	return wrapDBError(err)
+}
+
+func (r *PRRepository) RemoveReviewer(ctx context.Context, prID, id uuid.UUID) error {
+	query := r.psql.Delete("pr_reviewers").
+		Where(sq.Eq{
+			"id":              id,
+			"pull_request_id": prID,
+		})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		res, retryErr := conn.ExecContext(ctx, sql, args...)
+		if retryErr != nil {
+			return retryErr
+		}
+
+		return requireAffected(res)
+	})
+
+	return wrapDBError(err)
+}
+
+func (r *PRRepository) ReplaceReviewer(ctx context.Context, prID, oldID uuid.UUID, reviewer *models.PRReviewer) error {
+	return r.replaceReviewer(ctx, prID, oldID, reviewer, nil)
+}
+
+func (r *PRRepository) DeclineReviewer(
+	ctx context.Context,
+	prID, oldID uuid.UUID,
+	reviewer *models.PRReviewer,
+	reason models.DeclineReason,
+) error {
+	return r.replaceReviewer(ctx, prID, oldID, reviewer, string(reason))
+}
+
+// replaceReviewer records the reassignment with declineReason, nil when
+// the reviewer did not decline. A nil reviewer leaves nobody in place.
+func (r *PRRepository) replaceReviewer(ctx context.Context, prID, oldID uuid.UUID, reviewer *models.PRReviewer, declineReason any) error {
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	now := toMicro(r.clock.Now())
+
+	delSQL, delArgs, err := r.psql.
+		Delete("pr_reviewers").
+		Where(sq.Eq{
+			"id":              oldID,
+			"pull_request_id": prID,
+		}).
+		Suffix("RETURNING assigned_at").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	var (
+		newID      any
+		insertSQL  string
+		insertArgs []any
+	)
+	if reviewer != nil {
+		newID = reviewer.ID
+		pool, poolTeam := repository.ReviewerPoolColumns(reviewer)
+		insertSQL, insertArgs, err = r.psql.
+			Insert("pr_reviewers").
+			Columns("id", "pull_request_id", "assigned_at", "pool", "pool_team").
+			Values(reviewer.ID, prID, now, pool, poolTeam).
+			Suffix("ON CONFLICT DO NOTHING").
+			ToSql()
+		if err != nil {
+			return err
+		}
+	}
+
+	history := r.psql.
+		Insert("pr_reassignments").
+		Columns("pull_request_id", "from_user_id", "to_user_id", "from_assigned_at", "reassigned_at", "decline_reason")
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		return atomic(ctx, r.db, conn, func(tr trmsql.Tr) error {
+			var assignedAt int64
+			if err := tr.QueryRowContext(ctx, delSQL, delArgs...).Scan(&assignedAt); err != nil {
+				return err
+			}
+
+			if insertSQL != "" {
+				if _, err := tr.ExecContext(ctx, insertSQL, insertArgs...); err != nil {
+					return err
+				}
+			}
+
+			historySQL, historyArgs, err := history.Values(prID, oldID, newID, assignedAt, now, declineReason).ToSql()
+			if err != nil {
+				return err
+			}
+
+			_, err = tr.ExecContext(ctx, historySQL, historyArgs...)
+			return err
+		})
+	})
+
+	return wrapDBError(err)
+}
+
+func (r *PRRepository) ListDeclined(ctx context.Context, prID uuid.UUID) ([]uuid.UUID, error) {
+	query := r.psql.Select("DISTINCT from_user_id").
+		From("pr_reassignments").
+		Where(sq.Eq{"pull_request_id": prID}).
+		Where(sq.NotEq{"decline_reason": nil})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	var ids []uuid.UUID
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		rows, err := conn.QueryContext(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		ids = make([]uuid.UUID, 0)
+		for rows.Next() {
+			var id uuid.UUID
+			if err := rows.Scan(&id); err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+
+		return rows.Err()
+	})
+
+	return ids, wrapDBError(err)
+}
+
+func (r *PRRepository) BumpVersion(ctx context.Context, id uuid.UUID, version int64) error {
+	query := r.psql.Update("pull_requests").
+		Set("version", sq.Expr("version + 1")).
+		Where(sq.Eq{"id": id, "version": version})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		res, retryErr := conn.ExecContext(ctx, sql, args...)
+		if retryErr != nil {
+			return retryErr
+		}
+
+		return requireAffected(res)
+	})
+
+	return wrapDBError(err)
+}
+
+func (r *PRRepository) Merge(ctx context.Context, id uuid.UUID) error {
+	query := r.psql.Update("pull_requests").
+		Set("status", string(models.PRStatusMerged)).
+		Set("merged_at", toMicro(r.clock.Now())).
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		res, retryErr := conn.ExecContext(ctx, sql, args...)
+		if retryErr != nil {
+			return retryErr
+		}
+
+		return requireAffected(res)
+	})
+
+	return wrapDBError(err)
+}
+
+func (r *PRRepository) ListByReviewer(ctx context.Context, id uuid.UUID) ([]*models.PullRequest, error) {
+	query := r.psql.Select(
+		"pr.id", "pr.name", "pr.author_id",
+		"pr.status", "pr.created_at", "pr.merged_at",
+		"pr.version",
+	).From("pull_requests pr").
+		Join("pr_reviewers r ON r.pull_request_id = pr.id").
+		Where(sq.Eq{"r.id": id}).
+		OrderBy("pr.created_at", "pr.id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	var prs []*models.PullRequest
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		rows, err := conn.QueryContext(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		prs = make([]*models.PullRequest, 0)
+		for rows.Next() {
+			var (
+				pr        = &models.PullRequest{}
+				createdAt int64
+				mergedAt  *int64
+			)
+			if err := rows.Scan(
+				&pr.ID,
+				&pr.Name,
+				&pr.AuthorID,
+				&pr.Status,
+				&createdAt,
+				&mergedAt,
+				&pr.Version,
+			); err != nil {
+				return err
+			}
+
+			pr.CreatedAt = fromMicro(createdAt)
+			if mergedAt != nil {
+				t := fromMicro(*mergedAt)
+				pr.MergedAt = &t
+			}
+			prs = append(prs, pr)
+		}
+
+		return rows.Err()
+	})
+
+	return prs, wrapDBError(err)
+}
+
+// atomic runs fn in the caller's transaction, or in a new one when conn is
+// the bare database, so multi-statement writes never leave partial results.
+func atomic(ctx context.Context, db *sql.DB, conn trmsql.Tr, fn func(tr trmsql.Tr) error) error {
+	if conn != trmsql.Tr(db) {
+		return fn(conn)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,357 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/retry"
+	"pr-service/internal/service"
+
+	trmsql "github.com/avito-tech/go-transaction-manager/drivers/sql/v2"
+	"github.com/google/uuid"
+)
+
+var _ service.StatsRepository = (*StatsRepository)(nil)
+
+// The counters mirror the Postgres aggregates. SQLite has no percentile
+// aggregate, so medians are computed from reviewDurationsSQL in Go.
+// ?1 and ?2 bound the window [from, to), ?3 limits the rows to a subtree of teams.
+const (
+	openAssignedSQL = `(
+	SELECT count(*) FROM pr_reviewers r
+	JOIN pull_requests pr ON pr.id = r.pull_request_id
+	WHERE %[1]s AND pr.status = 'OPEN')`
+
+	assignedTotalSQL = `(
+	SELECT count(*) FROM pr_reviewers r
+	WHERE %[1]s AND r.assigned_at >= ?1 AND r.assigned_at < ?2) + (
+	SELECT count(*) FROM pr_reassignments a
+	WHERE %[2]s AND a.from_assigned_at >= ?1 AND a.from_assigned_at < ?2)`
+
+	reassignedAwaySQL = `(
+	SELECT count(*) FROM pr_reassignments a
+	WHERE %[2]s AND a.reassigned_at >= ?1 AND a.reassigned_at < ?2)`
+
+	declinedSQL = `(
+	SELECT count(*) FROM pr_reassignments a
+	WHERE %[2]s AND a.decline_reason IS NOT NULL AND a.reassigned_at >= ?1 AND a.reassigned_at < ?2)`
+
+	// reviewDurationsSQL repeats an assignment for every subtree its
+	// reviewer's team belongs to; the row with root_id = team_id is the
+	// team's own.
+	reviewDurationsSQL = teamTreeSQL + `
+SELECT r.id, coalesce(u.team_id, ''), coalesce(s.root_id, ''), pr.merged_at - r.assigned_at
+FROM pr_reviewers r
+JOIN pull_requests pr ON pr.id = r.pull_request_id
+JOIN users u ON u.id = r.id
+LEFT JOIN tree s ON s.id = u.team_id
+WHERE pr.merged_at IS NOT NULL AND r.assigned_at >= ?1 AND r.assigned_at < ?2`
+)
+
+// teamTreeSQL pairs every team (root_id) with itself and with each team
+// below it (id), so a subtree is a plain filter on root_id.
+const teamTreeSQL = `
+WITH RECURSIVE tree(root_id, id) AS (
+	SELECT id, id FROM teams
+	UNION
+	SELECT tree.root_id, c.id FROM teams c JOIN tree ON c.parent_id = tree.id
+)`
+
+// inScopeSQL keeps rows whose team is in the subtree of ?3, or every row
+// when ?3 is NULL.
+const inScopeSQL = `(?3 IS NULL OR %s IN (SELECT id FROM tree WHERE root_id = ?3))`
+
+// prCyclesSQL lists pull requests created within the window. The first
+// assignment also counts reviewers that were reassigned away later.
+var prCyclesSQL = teamTreeSQL + `
+SELECT pr.id, pr.name, pr.author_id, coalesce(t.name, ''), pr.status, pr.created_at, pr.merged_at, (
+	SELECT min(x.assigned_at) FROM (
+		SELECT r.assigned_at FROM pr_reviewers r WHERE r.pull_request_id = pr.id
+		UNION ALL
+		SELECT a.from_assigned_at FROM pr_reassignments a WHERE a.pull_request_id = pr.id
+	) x)
+FROM pull_requests pr
+JOIN users u ON u.id = pr.author_id
+LEFT JOIN teams t ON t.id = u.team_id
+WHERE pr.created_at >= ?1 AND pr.created_at < ?2 AND ` + fmt.Sprintf(inScopeSQL, "u.team_id") + `
+ORDER BY pr.created_at, pr.id`
+
+var reviewerStatsSQL = teamTreeSQL + `
+SELECT u.id, u.name, coalesce(t.name, ''),
+	` + loadColumns("r.id = u.id", "a.from_user_id = u.id") + `
+FROM users u
+LEFT JOIN teams t ON t.id = u.team_id
+WHERE ` + fmt.Sprintf(inScopeSQL, "u.team_id") + `
+ORDER BY coalesce(t.name, ''), u.name, u.id`
+
+var teamReviewStatsSQL = teamTreeSQL + `
+SELECT t.id, t.name, coalesce(p.name, ''),
+	` + loadColumns(
+	"r.id IN (SELECT m.id FROM users m WHERE m.team_id = t.id)",
+	"a.from_user_id IN (SELECT m.id FROM users m WHERE m.team_id = t.id)",
+) + `,
+	` + loadColumns(
+	"r.id IN (SELECT m.id FROM users m JOIN tree s ON s.id = m.team_id WHERE s.root_id = t.id)",
+	"a.from_user_id IN (SELECT m.id FROM users m JOIN tree s ON s.id = m.team_id WHERE s.root_id = t.id)",
+) + `
+FROM teams t
+LEFT JOIN teams p ON p.id = t.parent_id
+WHERE ` + fmt.Sprintf(inScopeSQL, "t.id") + `
+ORDER BY t.name`
+
+func loadColumns(reviewerFilter, reassignmentFilter string) string {
+	cols := make([]string, 0, 4)
+	for _, tmpl := range []string{openAssignedSQL, assignedTotalSQL, reassignedAwaySQL, declinedSQL} {
+		cols = append(cols, fmt.Sprintf(tmpl, reviewerFilter, reassignmentFilter))
+	}
+	return strings.Join(cols, ",\n\t")
+}
+
+type StatsRepository struct {
+	db      *sql.DB
+	getter  *trmsql.CtxGetter
+	retrier retry.Retrier
+}
+
+func NewStatsRepository(db *sql.DB, c *trmsql.CtxGetter, r retry.Retrier) *StatsRepository {
+	return &StatsRepository{
+		db:      db,
+		getter:  c,
+		retrier: r,
+	}
+}
+
+func (r *StatsRepository) ReviewerStats(
+	ctx context.Context,
+	from, to time.Time,
+	scope uuid.UUID,
+) ([]*models.ReviewerStats, error) {
+	var stats []*models.ReviewerStats
+
+	err := r.query(ctx, reviewerStatsSQL, scopedArgs(from, to, scope), func(rows *sql.Rows) error {
+		s := &models.ReviewerStats{}
+		if err := rows.Scan(
+			&s.UserID, &s.Username, &s.TeamName,
+			&s.OpenAssigned, &s.AssignedTotal, &s.ReassignedAway, &s.Declined,
+		); err != nil {
+			return err
+		}
+
+		stats = append(stats, s)
+		return nil
+	}, func() { stats = make([]*models.ReviewerStats, 0) })
+	if err != nil {
+		return nil, wrapDBError(err)
+	}
+
+	byUser, _, _, err := r.reviewDurations(ctx, from, to)
+	if err != nil {
+		return nil, wrapDBError(err)
+	}
+
+	for _, s := range stats {
+		s.MedianTimeToMerge = repository.MedianDuration(byUser[s.UserID])
+	}
+	return stats, nil
+}
+
+func (r *StatsRepository) TeamReviewStats(
+	ctx context.Context,
+	from, to time.Time,
+	scope uuid.UUID,
+) ([]*models.TeamReviewStats, error) {
+	var stats []*models.TeamReviewStats
+
+	err := r.query(ctx, teamReviewStatsSQL, scopedArgs(from, to, scope), func(rows *sql.Rows) error {
+		s := &models.TeamReviewStats{}
+		if err := rows.Scan(
+			&s.TeamID, &s.TeamName, &s.ParentName,
+			&s.OpenAssigned, &s.AssignedTotal, &s.ReassignedAway, &s.Declined,
+			&s.Subtree.OpenAssigned, &s.Subtree.AssignedTotal, &s.Subtree.ReassignedAway, &s.Subtree.Declined,
+		); err != nil {
+			return err
+		}
+
+		stats = append(stats, s)
+		return nil
+	}, func() { stats = make([]*models.TeamReviewStats, 0) })
+	if err != nil {
+		return nil, wrapDBError(err)
+	}
+
+	_, byTeam, bySubtree, err := r.reviewDurations(ctx, from, to)
+	if err != nil {
+		return nil, wrapDBError(err)
+	}
+
+	for _, s := range stats {
+		s.MedianTimeToMerge = repository.MedianDuration(byTeam[s.TeamID.String()])
+		s.Subtree.MedianTimeToMerge = repository.MedianDuration(bySubtree[s.TeamID.String()])
+	}
+	return stats, nil
+}
+
+func (r *StatsRepository) ListPRCycles(
+	ctx context.Context,
+	from, to time.Time,
+	scope uuid.UUID,
+) ([]*models.PRCycle, error) {
+	var cycles []*models.PRCycle
+
+	err := r.query(ctx, prCyclesSQL, scopedArgs(from, to, scope), func(rows *sql.Rows) error {
+		c, err := scanPRCycle(rows)
+		if err != nil {
+			return err
+		}
+
+		cycles = append(cycles, c)
+		return nil
+	}, func() { cycles = make([]*models.PRCycle, 0) })
+
+	return cycles, wrapDBError(err)
+}
+
+// StreamPRCycles is not retried: rows handed to fn cannot be taken back, and
+// a per-attempt timeout would cut long exports short.
+func (r *StatsRepository) StreamPRCycles(
+	ctx context.Context,
+	from, to time.Time,
+	scope uuid.UUID,
+	fn func(*models.PRCycle) error,
+) error {
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	rows, err := conn.QueryContext(ctx, prCyclesSQL, scopedArgs(from, to, scope)...)
+	if err != nil {
+		return wrapDBError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		c, err := scanPRCycle(rows)
+		if err != nil {
+			return wrapDBError(err)
+		}
+
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+
+	return wrapDBError(rows.Err())
+}
+
+func scanPRCycle(rows *sql.Rows) (*models.PRCycle, error) {
+	var (
+		c               = &models.PRCycle{}
+		createdAt       int64
+		mergedAt        *int64
+		firstAssignedAt *int64
+	)
+	if err := rows.Scan(
+		&c.PRID,
+		&c.Name,
+		&c.AuthorID,
+		&c.TeamName,
+		&c.Status,
+		&createdAt,
+		&mergedAt,
+		&firstAssignedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	c.CreatedAt = fromMicro(createdAt)
+	c.MergedAt = fromMicroPtr(mergedAt)
+	c.FirstAssignedAt = fromMicroPtr(firstAssignedAt)
+	return c, nil
+}
+
+// reviewDurations returns the assignment-to-merge durations of assignments
+// within the window, grouped by reviewer, by the reviewer's team and by
+// every team whose subtree includes the reviewer's team.
+func (r *StatsRepository) reviewDurations(
+	ctx context.Context,
+	from, to time.Time,
+) (map[uuid.UUID][]time.Duration, map[string][]time.Duration, map[string][]time.Duration, error) {
+	var (
+		byUser    map[uuid.UUID][]time.Duration
+		byTeam    map[string][]time.Duration
+		bySubtree map[string][]time.Duration
+	)
+
+	err := r.query(ctx, reviewDurationsSQL, []any{toMicro(from), toMicro(to)}, func(rows *sql.Rows) error {
+		var (
+			userID         uuid.UUID
+			teamID, rootID string
+			micros         int64
+		)
+		if err := rows.Scan(&userID, &teamID, &rootID, &micros); err != nil {
+			return err
+		}
+
+		d := time.Duration(micros) * time.Microsecond
+		if rootID != "" {
+			bySubtree[rootID] = append(bySubtree[rootID], d)
+		}
+		if rootID != teamID {
+			return nil
+		}
+
+		byUser[userID] = append(byUser[userID], d)
+		if teamID != "" {
+			byTeam[teamID] = append(byTeam[teamID], d)
+		}
+		return nil
+	}, func() {
+		byUser = make(map[uuid.UUID][]time.Duration)
+		byTeam = make(map[string][]time.Duration)
+		bySubtree = make(map[string][]time.Duration)
+	})
+
+	return byUser, byTeam, bySubtree, err
+}
+
+// query runs stmt within the retrier, calling reset before every attempt
+// and scan for every row.
+func (r *StatsRepository) query(
+	ctx context.Context,
+	stmt string,
+	args []any,
+	scan func(rows *sql.Rows) error,
+	reset func(),
+) error {
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	return r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		rows, err := conn.QueryContext(ctx, stmt, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		reset()
+		for rows.Next() {
+			if err := scan(rows); err != nil {
+				return err
+			}
+		}
+
+		return rows.Err()
+	})
+}
+
+// scopedArgs are the ?1, ?2 and ?3 arguments of the scoped stats queries;
+// ?3 is NULL for every team.
+func scopedArgs(from, to time.Time, scope uuid.UUID) []any {
+	var scopeArg any
+	if scope != uuid.Nil {
+		scopeArg = scope
+	}
+	return []any{toMicro(from), toMicro(to), scopeArg}
+}
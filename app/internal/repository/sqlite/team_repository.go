@@ -0,0 +1,428 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/retry"
+	"pr-service/internal/service"
+
+	sq "github.com/Masterminds/squirrel"
+	trmsql "github.com/avito-tech/go-transaction-manager/drivers/sql/v2"
+	"github.com/google/uuid"
+)
+
+var _ service.TeamRepository = (*TeamRepository)(nil)
+
+type TeamRepository struct {
+	db      *sql.DB
+	getter  *trmsql.CtxGetter
+	psql    sq.StatementBuilderType
+	retrier retry.Retrier
+}
+
+func NewTeamRepository(db *sql.DB, c *trmsql.CtxGetter, r retry.Retrier) *TeamRepository {
+	return &TeamRepository{
+		db:      db,
+		getter:  c,
+		psql:    sq.StatementBuilder.PlaceholderFormat(sq.Question),
+		retrier: r,
+	}
+}
+
+func (r *TeamRepository) Create(ctx context.Context, t *models.Team) error {
+	id := uuid.New()
+	slaSeconds, slaAction := repository.SLAColumns(t.ReviewSLA)
+	digestMinute, digestTimezone := repository.DigestColumns(t.Digest)
+	query := r.psql.Insert("teams").
+		Columns(
+			"id", "name", "parent_id", "review_sla_seconds", "review_sla_action", "digest_minute", "digest_timezone",
+			"review_policy_role",
+		).
+		Values(id, t.Name, t.ParentID, slaSeconds, slaAction, digestMinute, digestTimezone, repository.PolicyColumn(t.Policy))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		_, retryErr := conn.ExecContext(ctx, sql, args...)
+		return retryErr
+	})
+	if err != nil {
+		return wrapDBError(err)
+	}
+
+	t.ID = id
+	return nil
+}
+
+func (r *TeamRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Team, error) {
+	return r.getBy(ctx, sq.Eq{"id": id})
+}
+
+func (r *TeamRepository) GetByName(ctx context.Context, name string) (*models.Team, error) {
+	return r.getBy(ctx, sq.Eq{"name": name})
+}
+
+func (r *TeamRepository) getBy(ctx context.Context, where sq.Eq) (*models.Team, error) {
+	query := r.psql.Select(
+		"id", "name", "parent_id", "review_sla_seconds", "review_sla_action", "digest_minute", "digest_timezone",
+		"review_policy_role",
+	).
+		From("teams").
+		Where(where)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	t := &models.Team{}
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		var (
+			slaSeconds     *int64
+			slaAction      string
+			digestMinute   *int64
+			digestTimezone string
+			policyRole     *string
+		)
+		if err := conn.QueryRowContext(ctx, sql, args...).Scan(
+			&t.ID, &t.Name, &t.ParentID, &slaSeconds, &slaAction, &digestMinute, &digestTimezone, &policyRole,
+		); err != nil {
+			return err
+		}
+
+		digest, err := repository.DigestFromColumns(digestMinute, digestTimezone)
+		if err != nil {
+			return err
+		}
+
+		t.ReviewSLA = repository.SLAFromColumns(slaSeconds, slaAction)
+		t.Digest = digest
+		t.Policy = repository.PolicyFromColumn(policyRole)
+		return nil
+	})
+
+	return t, wrapDBError(err)
+}
+
+func (r *TeamRepository) SetReviewSLA(ctx context.Context, id uuid.UUID, sla *models.ReviewSLA) error {
+	slaSeconds, slaAction := repository.SLAColumns(sla)
+	query := r.psql.Update("teams").
+		Set("review_sla_seconds", slaSeconds).
+		Set("review_sla_action", slaAction).
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		res, retryErr := conn.ExecContext(ctx, sql, args...)
+		if retryErr != nil {
+			return retryErr
+		}
+
+		return requireAffected(res)
+	})
+
+	return wrapDBError(err)
+}
+
+func (r *TeamRepository) SetDigestSchedule(ctx context.Context, id uuid.UUID, d *models.DigestSchedule) error {
+	digestMinute, digestTimezone := repository.DigestColumns(d)
+	query := r.psql.Update("teams").
+		Set("digest_minute", digestMinute).
+		Set("digest_timezone", digestTimezone).
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		res, retryErr := conn.ExecContext(ctx, sql, args...)
+		if retryErr != nil {
+			return retryErr
+		}
+
+		return requireAffected(res)
+	})
+
+	return wrapDBError(err)
+}
+
+func (r *TeamRepository) SetReviewPolicy(ctx context.Context, id uuid.UUID, p *models.ReviewPolicy) error {
+	query := r.psql.Update("teams").
+		Set("review_policy_role", repository.PolicyColumn(p)).
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		res, retryErr := conn.ExecContext(ctx, sql, args...)
+		if retryErr != nil {
+			return retryErr
+		}
+
+		return requireAffected(res)
+	})
+
+	return wrapDBError(err)
+}
+
+func (r *TeamRepository) SetParent(ctx context.Context, id uuid.UUID, parentID *uuid.UUID) error {
+	query := r.psql.Update("teams").
+		Set("parent_id", parentID).
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		res, retryErr := conn.ExecContext(ctx, sql, args...)
+		if retryErr != nil {
+			return retryErr
+		}
+
+		return requireAffected(res)
+	})
+
+	return wrapDBError(err)
+}
+
+// LockHierarchy does nothing: transactions are opened IMMEDIATE and take
+// the database write lock, so they already run one at a time.
+func (r *TeamRepository) LockHierarchy(ctx context.Context) error {
+	return nil
+}
+
+// subtreeSQL walks down from ?1; depth orders parents before their children.
+// Teams form no cycles, the depth bound only keeps a broken tree from
+// recursing forever.
+const subtreeSQL = `
+WITH RECURSIVE subtree AS (
+	SELECT id, name, parent_id, 0 AS depth FROM teams WHERE id = ?1
+	UNION ALL
+	SELECT c.id, c.name, c.parent_id, s.depth + 1
+	FROM teams c
+	JOIN subtree s ON c.parent_id = s.id
+	WHERE s.depth < (SELECT count(*) FROM teams)
+)
+SELECT id, name, parent_id FROM subtree ORDER BY depth, name`
+
+func (r *TeamRepository) GetSubtree(ctx context.Context, id uuid.UUID) ([]*models.Team, error) {
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	var teams []*models.Team
+
+	err := r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		rows, err := conn.QueryContext(ctx, subtreeSQL, id)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		teams = make([]*models.Team, 0)
+		for rows.Next() {
+			t := &models.Team{}
+			if err := rows.Scan(&t.ID, &t.Name, &t.ParentID); err != nil {
+				return err
+			}
+			teams = append(teams, t)
+		}
+
+		return rows.Err()
+	})
+
+	return teams, wrapDBError(err)
+}
+
+func (r *TeamRepository) SetCodeOwners(ctx context.Context, id uuid.UUID, rules []models.CodeOwnerRule) error {
+	deleteSQL, deleteArgs, err := r.psql.Delete("code_owner_rules").
+		Where(sq.Eq{"team_id": id}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	var insertSQL string
+	var insertArgs []any
+	if rows := repository.CodeOwnerRows(id, rules); len(rows) > 0 {
+		insert := r.psql.Insert("code_owner_rules").Columns(
+			"team_id", "position", "owner_position", "pattern", "owner_ref", "owner_user_id", "owner_team_id",
+		)
+		for _, row := range rows {
+			insert = insert.Values(row...)
+		}
+
+		insertSQL, insertArgs, err = insert.ToSql()
+		if err != nil {
+			return err
+		}
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		if _, retryErr := conn.ExecContext(ctx, deleteSQL, deleteArgs...); retryErr != nil {
+			return retryErr
+		}
+		if insertSQL == "" {
+			return nil
+		}
+
+		_, retryErr := conn.ExecContext(ctx, insertSQL, insertArgs...)
+		return retryErr
+	})
+
+	return wrapDBError(err)
+}
+
+func (r *TeamRepository) GetCodeOwners(ctx context.Context, id uuid.UUID) ([]models.CodeOwnerRule, error) {
+	query := r.psql.Select(
+		"position", "pattern", "owner_ref", "owner_user_id", "owner_team_id",
+	).From("code_owner_rules").
+		Where(sq.Eq{"team_id": id}).
+		OrderBy("position", "owner_position")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	var rules []models.CodeOwnerRule
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		rows, err := conn.QueryContext(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		rules = make([]models.CodeOwnerRule, 0)
+		last := -1
+		for rows.Next() {
+			var (
+				position       int
+				pattern        string
+				ref            *string
+				userID, teamID *uuid.UUID
+			)
+			if err := rows.Scan(&position, &pattern, &ref, &userID, &teamID); err != nil {
+				return err
+			}
+
+			if position != last {
+				rules = append(rules, models.CodeOwnerRule{Pattern: pattern, Owners: []models.CodeOwner{}})
+				last = position
+			}
+			if o := repository.CodeOwnerFromColumns(ref, userID, teamID); o != nil {
+				rules[len(rules)-1].Owners = append(rules[len(rules)-1].Owners, *o)
+			}
+		}
+
+		return rows.Err()
+	})
+
+	return rules, wrapDBError(err)
+}
+
+func (r *TeamRepository) SetFallbackPools(ctx context.Context, id uuid.UUID, poolIDs []uuid.UUID) error {
+	deleteSQL, deleteArgs, err := r.psql.Delete("team_fallback_pools").
+		Where(sq.Eq{"team_id": id}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	var insertSQL string
+	var insertArgs []any
+	if len(poolIDs) > 0 {
+		insert := r.psql.Insert("team_fallback_pools").Columns("team_id", "position", "pool_team_id")
+		for i, poolID := range poolIDs {
+			insert = insert.Values(id, i, poolID)
+		}
+
+		insertSQL, insertArgs, err = insert.ToSql()
+		if err != nil {
+			return err
+		}
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		if _, retryErr := conn.ExecContext(ctx, deleteSQL, deleteArgs...); retryErr != nil {
+			return retryErr
+		}
+		if insertSQL == "" {
+			return nil
+		}
+
+		_, retryErr := conn.ExecContext(ctx, insertSQL, insertArgs...)
+		return retryErr
+	})
+
+	return wrapDBError(err)
+}
+
+func (r *TeamRepository) GetFallbackPools(ctx context.Context, id uuid.UUID) ([]*models.Team, error) {
+	query := r.psql.Select("t.id", "t.name").
+		From("team_fallback_pools p").
+		Join("teams t ON t.id = p.pool_team_id").
+		Where(sq.Eq{"p.team_id": id}).
+		OrderBy("p.position")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	var teams []*models.Team
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		rows, err := conn.QueryContext(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		teams = make([]*models.Team, 0)
+		for rows.Next() {
+			t := &models.Team{}
+			if err := rows.Scan(&t.ID, &t.Name); err != nil {
+				return err
+			}
+			teams = append(teams, t)
+		}
+
+		return rows.Err()
+	})
+
+	return teams, wrapDBError(err)
+}
@@ -0,0 +1,234 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/retry"
+	"pr-service/internal/service"
+
+	sq "github.com/Masterminds/squirrel"
+	trmsql "github.com/avito-tech/go-transaction-manager/drivers/sql/v2"
+	"github.com/google/uuid"
+)
+
+var _ service.UserRepository = (*UserRepository)(nil)
+
+// notAwaySQL keeps users without an availability window covering the given time.
+const notAwaySQL = `NOT EXISTS (
+	SELECT 1 FROM user_availability a
+	WHERE a.user_id = users.id AND a.starts_at <= ? AND a.ends_at > ?
+)`
+
+// inSubtreeSQL keeps users of the given team and of all teams below it.
+const inSubtreeSQL = `team_id IN (
+	WITH RECURSIVE subtree AS (
+		SELECT id FROM teams WHERE id = ?
+		UNION
+		SELECT c.id FROM teams c JOIN subtree s ON c.parent_id = s.id
+	)
+	SELECT id FROM subtree
+)`
+
+type UserRepository struct {
+	db      *sql.DB
+	getter  *trmsql.CtxGetter
+	psql    sq.StatementBuilderType
+	retrier retry.Retrier
+}
+
+func NewUserRepository(db *sql.DB, c *trmsql.CtxGetter, r retry.Retrier) *UserRepository {
+	return &UserRepository{
+		db:      db,
+		getter:  c,
+		psql:    sq.StatementBuilder.PlaceholderFormat(sq.Question),
+		retrier: r,
+	}
+}
+
+func (r *UserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	query := r.psql.Select(
+		"id", "team_id", "name", "email", "role", "is_active",
+	).From("users").
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	u := &models.User{}
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		return conn.QueryRowContext(ctx, sql, args...).
+			Scan(&u.ID, &u.TeamID, &u.Name, &u.Email, &u.Role, &u.IsActive)
+	})
+
+	return u, wrapDBError(err)
+}
+
+func (r *UserRepository) GetActiveByTeam(ctx context.Context, teamID uuid.UUID) ([]*models.User, error) {
+	return r.getUsersBy(ctx, sq.Eq{
+		"team_id":   teamID,
+		"is_active": true,
+	})
+}
+
+// GetAvailableByTeam returns active team members without an
+// out-of-office window covering at.
+func (r *UserRepository) GetAvailableByTeam(ctx context.Context, teamID uuid.UUID, at time.Time) ([]*models.User, error) {
+	return r.getUsersBy(ctx, sq.And{
+		sq.Eq{
+			"team_id":   teamID,
+			"is_active": true,
+		},
+		sq.Expr(notAwaySQL, toMicro(at), toMicro(at)),
+	})
+}
+
+func (r *UserRepository) GetByTeam(ctx context.Context, teamID uuid.UUID) ([]*models.User, error) {
+	return r.getUsersBy(ctx, sq.Eq{"team_id": teamID})
+}
+
+// GetBySubtree returns members of the team and of all its sub-teams.
+func (r *UserRepository) GetBySubtree(ctx context.Context, teamID uuid.UUID) ([]*models.User, error) {
+	return r.getUsersBy(ctx, sq.Expr(inSubtreeSQL, teamID))
+}
+
+func (r *UserRepository) ListByEmail(ctx context.Context, email string) ([]*models.User, error) {
+	return r.getUsersBy(ctx, sq.And{sq.NotEq{"email": ""}, sq.Expr("lower(email) = lower(?)", email)})
+}
+
+func (r *UserRepository) getUsersBy(ctx context.Context, where sq.Sqlizer) ([]*models.User, error) {
+	query := r.psql.Select(
+		"id", "team_id", "name", "email", "role", "is_active",
+	).From("users").
+		Where(where).
+		OrderBy("name", "id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	var users []*models.User
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		rows, err := conn.QueryContext(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		users = make([]*models.User, 0)
+		for rows.Next() {
+			u := &models.User{}
+			if err := rows.Scan(
+				&u.ID, &u.TeamID, &u.Name, &u.Email, &u.Role, &u.IsActive,
+			); err != nil {
+				return err
+			}
+
+			users = append(users, u)
+		}
+
+		return rows.Err()
+	})
+
+	return users, wrapDBError(err)
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	if user.Role == "" {
+		user.Role = models.DefaultRole
+	}
+
+	id := uuid.New()
+	query := r.psql.Insert("users").
+		Columns("id", "team_id", "name", "email", "role", "is_active").
+		Values(id, user.TeamID, user.Name, user.Email, string(user.Role), user.IsActive)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		_, retryErr := conn.ExecContext(ctx, sql, args...)
+		return retryErr
+	})
+	if err != nil {
+		return wrapDBError(err)
+	}
+
+	user.ID = id
+	return nil
+}
+
+func (r *UserRepository) UpdateActive(ctx context.Context, id uuid.UUID, active bool) error {
+	query := r.psql.Update("users").
+		Set("is_active", active).
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		res, retryErr := conn.ExecContext(ctx, sql, args...)
+		if retryErr != nil {
+			return retryErr
+		}
+
+		return requireAffected(res)
+	})
+
+	return wrapDBError(err)
+}
+
+func (r *UserRepository) UpdateRole(ctx context.Context, id uuid.UUID, role models.Role) error {
+	query := r.psql.Update("users").
+		Set("role", string(role)).
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		res, retryErr := conn.ExecContext(ctx, sql, args...)
+		if retryErr != nil {
+			return retryErr
+		}
+
+		return requireAffected(res)
+	})
+
+	return wrapDBError(err)
+}
+
+// requireAffected reports ErrNotFound when a statement changed no rows.
+func requireAffected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
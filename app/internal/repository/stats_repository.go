@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"pr-service/internal/models"
+	"pr-service/internal/retry"
+	"slices"
+	"strings"
+	"time"
+
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Each aggregate is a correlated subquery, so reviewer and reassignment
+// rows do not multiply each other. %[1]s and %[2]s filter pr_reviewers (r)
+// and pr_reassignments (a) down to a single user or every member of a team. $1 and $2 bound the window [from, to),
+// $3 limits the rows to a subtree of teams.
+const (
+	openAssignedSQL = `(
+	SELECT count(*) FROM pr_reviewers r
+	JOIN pull_requests pr ON pr.id = r.pull_request_id
+	WHERE %[1]s AND pr.status = 'OPEN')`
+
+	assignedTotalSQL = `(
+	SELECT count(*) FROM pr_reviewers r
+	WHERE %[1]s AND r.assigned_at >= $1 AND r.assigned_at < $2) + (
+	SELECT count(*) FROM pr_reassignments a
+	WHERE %[2]s AND a.from_assigned_at >= $1 AND a.from_assigned_at < $2)`
+
+	reassignedAwaySQL = `(
+	SELECT count(*) FROM pr_reassignments a
+	WHERE %[2]s AND a.reassigned_at >= $1 AND a.reassigned_at < $2)`
+
+	declinedSQL = `(
+	SELECT count(*) FROM pr_reassignments a
+	WHERE %[2]s AND a.decline_reason IS NOT NULL AND a.reassigned_at >= $1 AND a.reassigned_at < $2)`
+
+	medianTimeToMergeSQL = `(
+	SELECT extract(epoch FROM percentile_cont(0.5) WITHIN GROUP (ORDER BY pr.merged_at - r.assigned_at))
+	FROM pr_reviewers r
+	JOIN pull_requests pr ON pr.id = r.pull_request_id
+	WHERE %[1]s AND pr.merged_at IS NOT NULL AND r.assigned_at >= $1 AND r.assigned_at < $2)`
+)
+
+// teamTreeSQL pairs every team (root_id) with itself and with each team
+// below it (id), so a subtree is a plain filter on root_id.
+const teamTreeSQL = `
+WITH RECURSIVE tree(root_id, id) AS (
+	SELECT id, id FROM teams
+	UNION
+	SELECT tree.root_id, c.id FROM teams c JOIN tree ON c.parent_id = tree.id
+)`
+
+// inScopeSQL keeps rows whose team is in the subtree of $3, or every row
+// when $3 is NULL.
+const inScopeSQL = `($3::uuid IS NULL OR %s IN (SELECT id FROM tree WHERE root_id = $3))`
+
+var reviewerStatsSQL = teamTreeSQL + `
+SELECT u.id, u.name, coalesce(t.name, ''),
+	` + loadColumns("r.id = u.id", "a.from_user_id = u.id") + `
+FROM users u
+LEFT JOIN teams t ON t.id = u.team_id
+WHERE ` + fmt.Sprintf(inScopeSQL, "u.team_id") + `
+ORDER BY coalesce(t.name, ''), u.name, u.id`
+
+var teamReviewStatsSQL = teamTreeSQL + `
+SELECT t.id, t.name, coalesce(p.name, ''),
+	` + loadColumns(
+	"r.id IN (SELECT m.id FROM users m WHERE m.team_id = t.id)",
+	"a.from_user_id IN (SELECT m.id FROM users m WHERE m.team_id = t.id)",
+) + `,
+	` + loadColumns(
+	"r.id IN (SELECT m.id FROM users m JOIN tree s ON s.id = m.team_id WHERE s.root_id = t.id)",
+	"a.from_user_id IN (SELECT m.id FROM users m JOIN tree s ON s.id = m.team_id WHERE s.root_id = t.id)",
+) + `
+FROM teams t
+LEFT JOIN teams p ON p.id = t.parent_id
+WHERE ` + fmt.Sprintf(inScopeSQL, "t.id") + `
+ORDER BY t.name`
+
+// prCyclesSQL lists pull requests created within the window. The first
+// assignment also counts reviewers that were reassigned away later.
+var prCyclesSQL = teamTreeSQL + `
+SELECT pr.id, pr.name, pr.author_id, coalesce(t.name, ''), pr.status, pr.created_at, pr.merged_at, (
+	SELECT min(x.assigned_at) FROM (
+		SELECT r.assigned_at FROM pr_reviewers r WHERE r.pull_request_id = pr.id
+		UNION ALL
+		SELECT a.from_assigned_at FROM pr_reassignments a WHERE a.pull_request_id = pr.id
+	) x)
+FROM pull_requests pr
+JOIN users u ON u.id = pr.author_id
+LEFT JOIN teams t ON t.id = u.team_id
+WHERE pr.created_at >= $1 AND pr.created_at < $2 AND ` + fmt.Sprintf(inScopeSQL, "u.team_id") + `
+ORDER BY pr.created_at, pr.id`
+
+// loadColumns renders the ReviewLoad columns for the given reviewer filters.
+func loadColumns(reviewerFilter, reassignmentFilter string) string {
+	cols := make([]string, 0, 5)
+	for _, tmpl := range []string{openAssignedSQL, assignedTotalSQL, reassignedAwaySQL, declinedSQL, medianTimeToMergeSQL} {
+		cols = append(cols, fmt.Sprintf(tmpl, reviewerFilter, reassignmentFilter))
+	}
+	return strings.Join(cols, ",\n\t")
+}
+
+type StatsRepository struct {
+	db      *pgxpool.Pool
+	getter  *trmpgx.CtxGetter
+	retrier retry.Retrier
+}
+
+func NewStatsRepository(db *pgxpool.Pool, c *trmpgx.CtxGetter, r retry.Retrier) *StatsRepository {
+	return &StatsRepository{
+		db:      db,
+		getter:  c,
+		retrier: r,
+	}
+}
+
+func (r *StatsRepository) ReviewerStats(
+	ctx context.Context,
+	from, to time.Time,
+	scope uuid.UUID,
+) ([]*models.ReviewerStats, error) {
+	var stats []*models.ReviewerStats
+
+	err := r.query(ctx, reviewerStatsSQL, from, to, scope, func(rows pgx.Rows) error {
+		s := &models.ReviewerStats{}
+		var median *float64
+		if err := rows.Scan(
+			&s.UserID, &s.Username, &s.TeamName,
+			&s.OpenAssigned, &s.AssignedTotal, &s.ReassignedAway, &s.Declined, &median,
+		); err != nil {
+			return err
+		}
+
+		s.MedianTimeToMerge = secondsToDuration(median)
+		stats = append(stats, s)
+		return nil
+	}, func() { stats = make([]*models.ReviewerStats, 0) })
+
+	return stats, wrapDBError(err)
+}
+
+func (r *StatsRepository) TeamReviewStats(
+	ctx context.Context,
+	from, to time.Time,
+	scope uuid.UUID,
+) ([]*models.TeamReviewStats, error) {
+	var stats []*models.TeamReviewStats
+
+	err := r.query(ctx, teamReviewStatsSQL, from, to, scope, func(rows pgx.Rows) error {
+		s := &models.TeamReviewStats{}
+		var median, subtreeMedian *float64
+		if err := rows.Scan(
+			&s.TeamID, &s.TeamName, &s.ParentName,
+			&s.OpenAssigned, &s.AssignedTotal, &s.ReassignedAway, &s.Declined, &median,
+			&s.Subtree.OpenAssigned, &s.Subtree.AssignedTotal, &s.Subtree.ReassignedAway, &s.Subtree.Declined, &subtreeMedian,
+		); err != nil {
+			return err
+		}
+
+		s.MedianTimeToMerge = secondsToDuration(median)
+		s.Subtree.MedianTimeToMerge = secondsToDuration(subtreeMedian)
+		stats = append(stats, s)
+		return nil
+	}, func() { stats = make([]*models.TeamReviewStats, 0) })
+
+	return stats, wrapDBError(err)
+}
+
+func (r *StatsRepository) ListPRCycles(
+	ctx context.Context,
+	from, to time.Time,
+	scope uuid.UUID,
+) ([]*models.PRCycle, error) {
+	var cycles []*models.PRCycle
+
+	err := r.query(ctx, prCyclesSQL, from, to, scope, func(rows pgx.Rows) error {
+		c, err := scanPRCycle(rows)
+		if err != nil {
+			return err
+		}
+
+		cycles = append(cycles, c)
+		return nil
+	}, func() { cycles = make([]*models.PRCycle, 0) })
+
+	return cycles, wrapDBError(err)
+}
+
+// StreamPRCycles is not retried: rows handed to fn cannot be taken back, and
+// a per-attempt timeout would cut long exports short.
+func (r *StatsRepository) StreamPRCycles(
+	ctx context.Context,
+	from, to time.Time,
+	scope uuid.UUID,
+	fn func(*models.PRCycle) error,
+) error {
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	rows, err := conn.Query(ctx, prCyclesSQL, from, to, scopeArg(scope))
+	if err != nil {
+		return wrapDBError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		c, err := scanPRCycle(rows)
+		if err != nil {
+			return wrapDBError(err)
+		}
+
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+
+	return wrapDBError(rows.Err())
+}
+
+func scanPRCycle(rows pgx.Rows) (*models.PRCycle, error) {
+	c := &models.PRCycle{}
+	err := rows.Scan(
+		&c.PRID,
+		&c.Name,
+		&c.AuthorID,
+		&c.TeamName,
+		&c.Status,
+		&c.CreatedAt,
+		&c.MergedAt,
+		&c.FirstAssignedAt,
+	)
+	return c, err
+}
+
+// query runs stmt within the retrier, calling reset before every attempt
+// and scan for every row.
+func (r *StatsRepository) query(
+	ctx context.Context,
+	stmt string,
+	from, to time.Time,
+	scope uuid.UUID,
+	scan func(rows pgx.Rows) error,
+	reset func(),
+) error {
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	return r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		rows, err := conn.Query(ctx, stmt, from, to, scopeArg(scope))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		reset()
+		for rows.Next() {
+			if err := scan(rows); err != nil {
+				return err
+			}
+		}
+
+		return rows.Err()
+	})
+}
+
+// scopeArg is the $3 argument of the stats queries: NULL for every team.
+func scopeArg(scope uuid.UUID) *uuid.UUID {
+	if scope == uuid.Nil {
+		return nil
+	}
+	return &scope
+}
+
+// secondsToDuration converts seconds with microsecond precision to a duration.
+func secondsToDuration(seconds *float64) *time.Duration {
+	if seconds == nil {
+		return nil
+	}
+
+	d := time.Duration(math.Round(*seconds*1e6)) * time.Microsecond
+	return &d
+}
+
+// MedianDuration returns the median of ds, interpolating between the two
+// middle values like percentile_cont(0.5), or nil when ds is empty.
+// It is meant for backends without percentile aggregates; ds is sorted in place.
+func MedianDuration(ds []time.Duration) *time.Duration {
+	if len(ds) == 0 {
+		return nil
+	}
+
+	slices.Sort(ds)
+
+	mid := len(ds) / 2
+	m := ds[mid]
+	if len(ds)%2 == 0 {
+		m = ds[mid-1] + (ds[mid]-ds[mid-1])/2
+	}
+	return &m
+}
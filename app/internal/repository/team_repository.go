@@ -2,6 +2,9 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"time"
+
 	"pr-service/internal/models"
 	"pr-service/internal/retry"
 
@@ -28,9 +31,14 @@ func NewTeamRepository(db *pgxpool.Pool, c *trmpgx.CtxGetter, r retry.Retrier) *
 }
 
 func (r *TeamRepository) Create(ctx context.Context, t *models.Team) error {
+	slaSeconds, slaAction := SLAColumns(t.ReviewSLA)
+	digestMinute, digestTimezone := DigestColumns(t.Digest)
 	query := r.psql.Insert("teams").
-		Columns("name").
-		Values(t.Name).
+		Columns(
+			"name", "parent_id", "review_sla_seconds", "review_sla_action", "digest_minute", "digest_timezone",
+			"review_policy_role",
+		).
+		Values(t.Name, t.ParentID, slaSeconds, slaAction, digestMinute, digestTimezone, PolicyColumn(t.Policy)).
 		Suffix("RETURNING id")
 
 	sql, args, err := query.ToSql()
@@ -40,7 +48,7 @@ func (r *TeamRepository) Create(ctx context.Context, t *models.Team) error {
 
 	conn := r.getter.DefaultTrOrDB(ctx, r.db)
 
-	err = r.retrier.Do(ctx, func() error {
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
 		return conn.QueryRow(ctx, sql, args...).Scan(&t.ID)
 	})
 
@@ -56,7 +64,10 @@ func (r *TeamRepository) GetByName(ctx context.Context, name string) (*models.Te
 }
 
 func (r *TeamRepository) getBy(ctx context.Context, where sq.Eq) (*models.Team, error) {
-	query := r.psql.Select("id", "name").
+	query := r.psql.Select(
+		"id", "name", "parent_id", "review_sla_seconds", "review_sla_action", "digest_minute", "digest_timezone",
+		"review_policy_role",
+	).
 		From("teams").
 		Where(where)
 
@@ -68,9 +79,480 @@ func (r *TeamRepository) getBy(ctx context.Context, where sq.Eq) (*models.Team,
 	conn := r.getter.DefaultTrOrDB(ctx, r.db)
 	t := &models.Team{}
 
-	err = r.retrier.Do(ctx, func() error {
-		return conn.QueryRow(ctx, sql, args...).Scan(&t.ID, &t.Name)
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		var (
+			slaSeconds     *int64
+			slaAction      string
+			digestMinute   *int64
+			digestTimezone string
+			policyRole     *string
+		)
+		if err := conn.QueryRow(ctx, sql, args...).Scan(
+			&t.ID, &t.Name, &t.ParentID, &slaSeconds, &slaAction, &digestMinute, &digestTimezone, &policyRole,
+		); err != nil {
+			return err
+		}
+
+		digest, err := DigestFromColumns(digestMinute, digestTimezone)
+		if err != nil {
+			return err
+		}
+
+		t.ReviewSLA = SLAFromColumns(slaSeconds, slaAction)
+		t.Digest = digest
+		t.Policy = PolicyFromColumn(policyRole)
+		return nil
 	})
 
 	return t, wrapDBError(err)
 }
+
+func (r *TeamRepository) SetReviewSLA(ctx context.Context, id uuid.UUID, sla *models.ReviewSLA) error {
+	slaSeconds, slaAction := SLAColumns(sla)
+	query := r.psql.Update("teams").
+		Set("review_sla_seconds", slaSeconds).
+		Set("review_sla_action", slaAction).
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		tag, retryErr := conn.Exec(ctx, sql, args...)
+		if retryErr != nil {
+			return retryErr
+		}
+
+		if tag.RowsAffected() == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+
+	return wrapDBError(err)
+}
+
+func (r *TeamRepository) SetDigestSchedule(ctx context.Context, id uuid.UUID, d *models.DigestSchedule) error {
+	digestMinute, digestTimezone := DigestColumns(d)
+	query := r.psql.Update("teams").
+		Set("digest_minute", digestMinute).
+		Set("digest_timezone", digestTimezone).
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		tag, retryErr := conn.Exec(ctx, sql, args...)
+		if retryErr != nil {
+			return retryErr
+		}
+
+		if tag.RowsAffected() == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+
+	return wrapDBError(err)
+}
+
+func (r *TeamRepository) SetReviewPolicy(ctx context.Context, id uuid.UUID, p *models.ReviewPolicy) error {
+	query := r.psql.Update("teams").
+		Set("review_policy_role", PolicyColumn(p)).
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		tag, retryErr := conn.Exec(ctx, sql, args...)
+		if retryErr != nil {
+			return retryErr
+		}
+
+		if tag.RowsAffected() == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+
+	return wrapDBError(err)
+}
+
+func (r *TeamRepository) SetParent(ctx context.Context, id uuid.UUID, parentID *uuid.UUID) error {
+	query := r.psql.Update("teams").
+		Set("parent_id", parentID).
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		tag, retryErr := conn.Exec(ctx, sql, args...)
+		if retryErr != nil {
+			return retryErr
+		}
+
+		if tag.RowsAffected() == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+
+	return wrapDBError(err)
+}
+
+// hierarchyLockKey is the pg_advisory_xact_lock key held while a team is
+// moved, so concurrent moves can not close a cycle between them.
+const hierarchyLockKey int64 = 0x70725f7465616d // "pr_team"
+
+// LockHierarchy serializes the transactions that move teams: row locks on
+// the moved teams would not stop two moves of unrelated teams from closing a
+// cycle through their existing parents.
+func (r *TeamRepository) LockHierarchy(ctx context.Context) error {
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err := r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		_, retryErr := conn.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", hierarchyLockKey)
+		return retryErr
+	})
+
+	return wrapDBError(err)
+}
+
+// subtreeSQL walks down from $1; depth orders parents before their children.
+// Teams form no cycles, the depth bound only keeps a broken tree from
+// recursing forever.
+const subtreeSQL = `
+WITH RECURSIVE subtree AS (
+	SELECT id, name, parent_id, 0 AS depth FROM teams WHERE id = $1
+	UNION ALL
+	SELECT c.id, c.name, c.parent_id, s.depth + 1
+	FROM teams c
+	JOIN subtree s ON c.parent_id = s.id
+	WHERE s.depth < (SELECT count(*) FROM teams)
+)
+SELECT id, name, parent_id FROM subtree ORDER BY depth, name`
+
+func (r *TeamRepository) GetSubtree(ctx context.Context, id uuid.UUID) ([]*models.Team, error) {
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	var teams []*models.Team
+
+	err := r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		rows, err := conn.Query(ctx, subtreeSQL, id)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		teams = make([]*models.Team, 0)
+		for rows.Next() {
+			t := &models.Team{}
+			if err := rows.Scan(&t.ID, &t.Name, &t.ParentID); err != nil {
+				return err
+			}
+			teams = append(teams, t)
+		}
+
+		return rows.Err()
+	})
+
+	return teams, wrapDBError(err)
+}
+
+// SLAColumns converts a team review SLA to the stored columns: whole seconds,
+// NULL when there is no SLA, and the action.
+func SLAColumns(sla *models.ReviewSLA) (*int64, string) {
+	if sla == nil {
+		return nil, string(models.SLAActionEscalate)
+	}
+
+	seconds := int64(sla.Timeout / time.Second)
+	return &seconds, string(sla.Action)
+}
+
+// SLAFromColumns is the inverse of SLAColumns.
+func SLAFromColumns(seconds *int64, action string) *models.ReviewSLA {
+	if seconds == nil {
+		return nil
+	}
+
+	return &models.ReviewSLA{
+		Timeout: time.Duration(*seconds) * time.Second,
+		Action:  models.SLAAction(action),
+	}
+}
+
+// PolicyColumn converts a team review policy to the stored column: the
+// lowest role one of the reviewers must have, NULL when there is no policy.
+func PolicyColumn(p *models.ReviewPolicy) *string {
+	if p == nil {
+		return nil
+	}
+
+	role := string(p.MinRole)
+	return &role
+}
+
+// PolicyFromColumn is the inverse of PolicyColumn.
+func PolicyFromColumn(role *string) *models.ReviewPolicy {
+	if role == nil {
+		return nil
+	}
+
+	return &models.ReviewPolicy{MinRole: models.Role(*role)}
+}
+
+func (r *TeamRepository) SetCodeOwners(ctx context.Context, id uuid.UUID, rules []models.CodeOwnerRule) error {
+	deleteSQL, deleteArgs, err := r.psql.Delete("code_owner_rules").
+		Where(sq.Eq{"team_id": id}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	var insertSQL string
+	var insertArgs []any
+	if rows := CodeOwnerRows(id, rules); len(rows) > 0 {
+		insert := r.psql.Insert("code_owner_rules").Columns(
+			"team_id", "position", "owner_position", "pattern", "owner_ref", "owner_user_id", "owner_team_id",
+		)
+		for _, row := range rows {
+			insert = insert.Values(row...)
+		}
+
+		insertSQL, insertArgs, err = insert.ToSql()
+		if err != nil {
+			return err
+		}
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		if _, retryErr := conn.Exec(ctx, deleteSQL, deleteArgs...); retryErr != nil {
+			return retryErr
+		}
+		if insertSQL == "" {
+			return nil
+		}
+
+		_, retryErr := conn.Exec(ctx, insertSQL, insertArgs...)
+		return retryErr
+	})
+
+	return wrapDBError(err)
+}
+
+func (r *TeamRepository) GetCodeOwners(ctx context.Context, id uuid.UUID) ([]models.CodeOwnerRule, error) {
+	query := r.psql.Select(
+		"position", "pattern", "owner_ref", "owner_user_id", "owner_team_id",
+	).From("code_owner_rules").
+		Where(sq.Eq{"team_id": id}).
+		OrderBy("position", "owner_position")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	var rules []models.CodeOwnerRule
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		rows, err := conn.Query(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		rules = make([]models.CodeOwnerRule, 0)
+		last := -1
+		for rows.Next() {
+			var (
+				position       int
+				pattern        string
+				ref            *string
+				userID, teamID *uuid.UUID
+			)
+			if err := rows.Scan(&position, &pattern, &ref, &userID, &teamID); err != nil {
+				return err
+			}
+
+			if position != last {
+				rules = append(rules, models.CodeOwnerRule{Pattern: pattern, Owners: []models.CodeOwner{}})
+				last = position
+			}
+			if o := CodeOwnerFromColumns(ref, userID, teamID); o != nil {
+				rules[len(rules)-1].Owners = append(rules[len(rules)-1].Owners, *o)
+			}
+		}
+
+		return rows.Err()
+	})
+
+	return rules, wrapDBError(err)
+}
+
+func (r *TeamRepository) SetFallbackPools(ctx context.Context, id uuid.UUID, poolIDs []uuid.UUID) error {
+	deleteSQL, deleteArgs, err := r.psql.Delete("team_fallback_pools").
+		Where(sq.Eq{"team_id": id}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	var insertSQL string
+	var insertArgs []any
+	if len(poolIDs) > 0 {
+		insert := r.psql.Insert("team_fallback_pools").Columns("team_id", "position", "pool_team_id")
+		for i, poolID := range poolIDs {
+			insert = insert.Values(id, i, poolID)
+		}
+
+		insertSQL, insertArgs, err = insert.ToSql()
+		if err != nil {
+			return err
+		}
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		if _, retryErr := conn.Exec(ctx, deleteSQL, deleteArgs...); retryErr != nil {
+			return retryErr
+		}
+		if insertSQL == "" {
+			return nil
+		}
+
+		_, retryErr := conn.Exec(ctx, insertSQL, insertArgs...)
+		return retryErr
+	})
+
+	return wrapDBError(err)
+}
+
+func (r *TeamRepository) GetFallbackPools(ctx context.Context, id uuid.UUID) ([]*models.Team, error) {
+	query := r.psql.Select("t.id", "t.name").
+		From("team_fallback_pools p").
+		Join("teams t ON t.id = p.pool_team_id").
+		Where(sq.Eq{"p.team_id": id}).
+		OrderBy("p.position")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+	var teams []*models.Team
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		rows, err := conn.Query(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		teams = make([]*models.Team, 0)
+		for rows.Next() {
+			t := &models.Team{}
+			if err := rows.Scan(&t.ID, &t.Name); err != nil {
+				return err
+			}
+			teams = append(teams, t)
+		}
+
+		return rows.Err()
+	})
+
+	return teams, wrapDBError(err)
+}
+
+// CodeOwnerRows flattens rules into code_owner_rules rows of the team, one
+// per owner and one ownerless row for a rule without owners.
+func CodeOwnerRows(teamID uuid.UUID, rules []models.CodeOwnerRule) [][]any {
+	var rows [][]any
+	for i, rule := range rules {
+		if len(rule.Owners) == 0 {
+			rows = append(rows, []any{teamID, i, 0, rule.Pattern, nil, nil, nil})
+			continue
+		}
+
+		for j, o := range rule.Owners {
+			var userID, ownerTeamID any
+			if o.UserID != uuid.Nil {
+				userID = o.UserID
+			}
+			if o.TeamID != uuid.Nil {
+				ownerTeamID = o.TeamID
+			}
+			rows = append(rows, []any{teamID, i, j, rule.Pattern, o.Ref, userID, ownerTeamID})
+		}
+	}
+
+	return rows
+}
+
+// CodeOwnerFromColumns is the owner of a code_owner_rules row, nil for the
+// row of a rule without owners.
+func CodeOwnerFromColumns(ref *string, userID, teamID *uuid.UUID) *models.CodeOwner {
+	if ref == nil {
+		return nil
+	}
+
+	o := &models.CodeOwner{Ref: *ref}
+	if userID != nil {
+		o.UserID = *userID
+	}
+	if teamID != nil {
+		o.TeamID = *teamID
+	}
+	return o
+}
+
+// DigestColumns converts a team digest schedule to the stored columns:
+// minutes since local midnight, NULL when there is no digest, and the time zone.
+func DigestColumns(d *models.DigestSchedule) (*int64, string) {
+	if d == nil {
+		return nil, time.UTC.String()
+	}
+
+	minute := int64(d.At / time.Minute)
+	return &minute, d.Location.String()
+}
+
+// DigestFromColumns is the inverse of DigestColumns.
+func DigestFromColumns(minute *int64, timezone string) (*models.DigestSchedule, error) {
+	if minute == nil {
+		return nil, nil
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("team digest timezone: %w", err)
+	}
+
+	return &models.DigestSchedule{
+		At:       time.Duration(*minute) * time.Minute,
+		Location: loc,
+	}, nil
+}
@@ -6,14 +6,18 @@ package repository_test
 import (
 	"context"
 	"fmt"
+	"pr-service/internal/clock"
 	"pr-service/internal/models"
 	"pr-service/internal/repository"
+	"pr-service/internal/service"
+	"sync"
 	"testing"
 
 	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv5/v2"
 	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
 func TestTeamRepository(t *testing.T) {
@@ -57,3 +61,47 @@ func TestTeamRepository(t *testing.T) {
 		return fmt.Errorf("error for rollback")
 	})
 }
+
+func TestTeamRepository_ConcurrentReparent(t *testing.T) {
+	ctx := t.Context()
+
+	svc := service.NewPRService(
+		repository.NewTeamRepository(db, trmpgx.DefaultCtxGetter, retrier),
+		repository.NewUserRepository(db, trmpgx.DefaultCtxGetter, retrier),
+		repository.NewPRRepository(db, trmpgx.DefaultCtxGetter, retrier, clock.Real()),
+		nil,
+		service.NewCommitHooks(manager.Must(trmpgx.NewDefaultFactory(db))),
+		clock.Real(),
+		zap.NewNop(),
+	)
+
+	for range 20 {
+		a, b := "reparent-"+uuid.NewString(), "reparent-"+uuid.NewString()
+		require.NoError(t, svc.TeamAdd(ctx, &models.Team{Name: a}))
+		require.NoError(t, svc.TeamAdd(ctx, &models.Team{Name: b}))
+
+		var (
+			wg    sync.WaitGroup
+			errs  [2]error
+			start = make(chan struct{})
+		)
+		for i, move := range [][2]string{{a, b}, {b, a}} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				_, errs[i] = svc.TeamSetParent(ctx, move[0], move[1])
+			}()
+		}
+		close(start)
+		wg.Wait()
+
+		// without the hierarchy lock both moves pass the cycle check
+		if errs[0] == nil {
+			require.ErrorIs(t, errs[1], service.ErrInvalidParent)
+		} else {
+			require.ErrorIs(t, errs[0], service.ErrInvalidParent)
+			require.NoError(t, errs[1])
+		}
+	}
+}
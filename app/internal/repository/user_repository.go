@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"pr-service/internal/models"
 	"pr-service/internal/retry"
@@ -12,6 +13,22 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// notAwaySQL keeps users without an availability window covering the given time.
+const notAwaySQL = `NOT EXISTS (
+	SELECT 1 FROM user_availability a
+	WHERE a.user_id = users.id AND a.starts_at <= ? AND a.ends_at > ?
+)`
+
+// inSubtreeSQL keeps users of the given team and of all teams below it.
+const inSubtreeSQL = `team_id IN (
+	WITH RECURSIVE subtree AS (
+		SELECT id FROM teams WHERE id = ?
+		UNION
+		SELECT c.id FROM teams c JOIN subtree s ON c.parent_id = s.id
+	)
+	SELECT id FROM subtree
+)`
+
 type UserRepository struct {
 	db      *pgxpool.Pool
 	getter  *trmpgx.CtxGetter
@@ -30,7 +47,7 @@ func NewUserRepository(db *pgxpool.Pool, c *trmpgx.CtxGetter, r retry.Retrier) *
 
 func (r *UserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	query := r.psql.Select(
-		"id", "team_id", "name", "is_active",
+		"id", "team_id", "name", "email", "role", "is_active",
 	).From("users").
 		Where(sq.Eq{"id": id})
 
@@ -42,9 +59,9 @@ func (r *UserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*models
 	conn := r.getter.DefaultTrOrDB(ctx, r.db)
 	u := &models.User{}
 
-	err = r.retrier.Do(ctx, func() error {
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
 		return conn.QueryRow(ctx, sql, args...).
-			Scan(&u.ID, &u.TeamID, &u.Name, &u.IsActive)
+			Scan(&u.ID, &u.TeamID, &u.Name, &u.Email, &u.Role, &u.IsActive)
 	})
 
 	return u, wrapDBError(err)
@@ -57,16 +74,37 @@ func (r *UserRepository) GetActiveByTeam(ctx context.Context, teamID uuid.UUID)
 	})
 }
 
+// GetAvailableByTeam returns active team members without an
+// out-of-office window covering at.
+func (r *UserRepository) GetAvailableByTeam(ctx context.Context, teamID uuid.UUID, at time.Time) ([]*models.User, error) {
+	return r.getUsersBy(ctx, sq.And{
+		sq.Eq{
+			"team_id":   teamID,
+			"is_active": true,
+		},
+		sq.Expr(notAwaySQL, at, at),
+	})
+}
+
 func (r *UserRepository) GetByTeam(ctx context.Context, teamID uuid.UUID) ([]*models.User, error) {
 	return r.getUsersBy(ctx, sq.Eq{"team_id": teamID})
+}
 
+// GetBySubtree returns members of the team and of all its sub-teams.
+func (r *UserRepository) GetBySubtree(ctx context.Context, teamID uuid.UUID) ([]*models.User, error) {
+	return r.getUsersBy(ctx, sq.Expr(inSubtreeSQL, teamID))
 }
 
-func (r *UserRepository) getUsersBy(ctx context.Context, where sq.Eq) ([]*models.User, error) {
+func (r *UserRepository) ListByEmail(ctx context.Context, email string) ([]*models.User, error) {
+	return r.getUsersBy(ctx, sq.And{sq.NotEq{"email": ""}, sq.Expr("lower(email) = lower(?)", email)})
+}
+
+func (r *UserRepository) getUsersBy(ctx context.Context, where sq.Sqlizer) ([]*models.User, error) {
 	query := r.psql.Select(
-		"id", "team_id", "name", "is_active",
+		"id", "team_id", "name", "email", "role", "is_active",
 	).From("users").
-		Where(where)
+		Where(where).
+		OrderBy("name", "id")
 
 	sql, args, err := query.ToSql()
 	if err != nil {
@@ -74,20 +112,21 @@ func (r *UserRepository) getUsersBy(ctx context.Context, where sq.Eq) ([]*models
 	}
 
 	conn := r.getter.DefaultTrOrDB(ctx, r.db)
-	users := make([]*models.User, 0)
+	var users []*models.User
 
-	err = r.retrier.Do(ctx, func() error {
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
 		rows, err := conn.Query(ctx, sql, args...)
 		if err != nil {
 			return err
 		}
 		defer rows.Close()
 
+		users = make([]*models.User, 0)
 		u := &models.User{}
 
 		for rows.Next() {
 			if err := rows.Scan(
-				&u.ID, &u.TeamID, &u.Name, &u.IsActive,
+				&u.ID, &u.TeamID, &u.Name, &u.Email, &u.Role, &u.IsActive,
 			); err != nil {
 				return err
 			}
@@ -96,16 +135,20 @@ func (r *UserRepository) getUsersBy(ctx context.Context, where sq.Eq) ([]*models
 			u = &models.User{}
 		}
 
-		return nil
+		return rows.Err()
 	})
 
 	return users, wrapDBError(err)
 }
 
 func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	if user.Role == "" {
+		user.Role = models.DefaultRole
+	}
+
 	query := r.psql.Insert("users").
-		Columns("team_id", "name", "is_active").
-		Values(user.TeamID, user.Name, user.IsActive).
+		Columns("team_id", "name", "email", "role", "is_active").
+		Values(user.TeamID, user.Name, user.Email, string(user.Role), user.IsActive).
 		Suffix("RETURNING id")
 
 	sql, args, err := query.ToSql()
@@ -115,7 +158,7 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 
 	conn := r.getter.DefaultTrOrDB(ctx, r.db)
 
-	err = r.retrier.Do(ctx, func() error {
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
 		return conn.QueryRow(ctx, sql, args...).Scan(&user.ID)
 	})
 
@@ -134,9 +177,43 @@ func (r *UserRepository) UpdateActive(ctx context.Context, id uuid.UUID, active
 
 	conn := r.getter.DefaultTrOrDB(ctx, r.db)
 
-	err = r.retrier.Do(ctx, func() error {
-		_, retryErr := conn.Exec(ctx, sql, args...)
-		return retryErr
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		tag, retryErr := conn.Exec(ctx, sql, args...)
+		if retryErr != nil {
+			return retryErr
+		}
+
+		if tag.RowsAffected() == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+
+	return wrapDBError(err)
+}
+
+func (r *UserRepository) UpdateRole(ctx context.Context, id uuid.UUID, role models.Role) error {
+	query := r.psql.Update("users").
+		Set("role", string(role)).
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	conn := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	err = r.retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+		tag, retryErr := conn.Exec(ctx, sql, args...)
+		if retryErr != nil {
+			return retryErr
+		}
+
+		if tag.RowsAffected() == 0 {
+			return ErrNotFound
+		}
+		return nil
 	})
 
 	return wrapDBError(err)
@@ -17,11 +17,12 @@ type Backoff interface {
 type FixedBackoff struct {
 	Interval time.Duration // fixed interval between retries
 	Jitter   float64       // optional jitter as a fraction [0,1)
+	Rand     *rand.Rand    // optional jitter source (nil = global source)
 }
 
 // Next returns the next wait duration for FixedBackoff.
 func (f FixedBackoff) Next(attempt int) time.Duration {
-	return addJitter(time.Duration(f.Interval), f.Jitter)
+	return addJitter(time.Duration(f.Interval), f.Jitter, f.Rand)
 }
 
 // LinearBackoff increases the retry interval linearly with each attempt.
@@ -30,6 +31,7 @@ type LinearBackoff struct {
 	Step   time.Duration // added interval per attempt
 	Max    time.Duration // maximum interval cap
 	Jitter float64       // optional jitter
+	Rand   *rand.Rand    // optional jitter source (nil = global source)
 }
 
 // Next returns the next wait duration for LinearBackoff.
@@ -38,7 +40,7 @@ func (l LinearBackoff) Next(attempt int) time.Duration {
 	if l.Max > 0 && d > l.Max {
 		return l.Max
 	}
-	return addJitter(time.Duration(d), l.Jitter)
+	return addJitter(time.Duration(d), l.Jitter, l.Rand)
 }
 
 // ExponentialBackoff increases the retry interval exponentially with each attempt.
@@ -47,6 +49,7 @@ type ExponentialBackoff struct {
 	Factor float64       // exponential growth factor
 	Max    time.Duration // maximum interval cap
 	Jitter float64       // optional jitter
+	Rand   *rand.Rand    // optional jitter source (nil = global source)
 }
 
 // Next returns the next wait duration for ExponentialBackoff.
@@ -55,14 +58,19 @@ func (e ExponentialBackoff) Next(attempt int) time.Duration {
 	if e.Max > 0 && d > float64(e.Max) {
 		return e.Max
 	}
-	return addJitter(time.Duration(d), e.Jitter)
+	return addJitter(time.Duration(d), e.Jitter, e.Rand)
 }
 
 // addJitter applies random jitter to a duration. Jitter should be in [0,1).
-func addJitter(d time.Duration, jitter float64) time.Duration {
+// If r is nil the global random source is used.
+func addJitter(d time.Duration, jitter float64, r *rand.Rand) time.Duration {
 	if jitter <= 0 || jitter >= 1 {
 		return d
 	}
-	delta := (rand.Float64()*2 - 1) * jitter
+	f := rand.Float64
+	if r != nil {
+		f = r.Float64
+	}
+	delta := (f()*2 - 1) * jitter
 	return time.Duration(float64(d) * (1 + delta))
 }
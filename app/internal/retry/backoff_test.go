@@ -1,6 +1,7 @@
 package retry
 
 import (
+	"math/rand/v2"
 	"testing"
 	"time"
 )
@@ -88,23 +89,32 @@ func TestExponentialBackoff(t *testing.T) {
 
 func TestAddJitter(t *testing.T) {
 	t.Run("no jitter", func(t *testing.T) {
-		got := addJitter(time.Second, 0)
+		got := addJitter(time.Second, 0, nil)
 		if got != time.Second {
 			t.Errorf("expected 1s, got %v", got)
 		}
 	})
 
 	t.Run("invalid jitter >=1", func(t *testing.T) {
-		got := addJitter(time.Second, 1)
+		got := addJitter(time.Second, 1, nil)
 		if got != time.Second {
 			t.Errorf("expected 1s, got %v", got)
 		}
 	})
 
 	t.Run("valid jitter", func(t *testing.T) {
-		got := addJitter(100*time.Millisecond, 0.5)
+		got := addJitter(100*time.Millisecond, 0.5, nil)
 		if got < 50*time.Millisecond || got > 150*time.Millisecond {
 			t.Errorf("expected within [50ms, 150ms], got %v", got)
 		}
 	})
 }
+
+func TestAddJitter_SeededSource(t *testing.T) {
+	a := addJitter(time.Second, 0.5, rand.New(rand.NewPCG(1, 2)))
+	b := addJitter(time.Second, 0.5, rand.New(rand.NewPCG(1, 2)))
+	if a != b {
+		t.Errorf("expected equal jitter for equal seeds, got %v and %v", a, b)
+	}
+	inRange(t, a, time.Second, 0.5)
+}
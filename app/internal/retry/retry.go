@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"time"
+
+	"pr-service/internal/clock"
 )
 
 // RetryOption configures a Retrier.
@@ -12,13 +14,32 @@ type RetryOption func(*retrier)
 // AttemptFunc is the function executed on each retry attempt.
 type AttemptFunc func() error
 
+// AttemptInfo describes the attempt being executed.
+type AttemptInfo struct {
+	Attempt     int // 0-based attempt number
+	MaxAttempts int // configured maximum (0 = unlimited)
+}
+
+// AttemptFuncWithInfo is an attempt-aware function executed on each retry attempt.
+// ctx is the per-attempt context: it carries the attempt timeout if one is configured.
+type AttemptFuncWithInfo func(ctx context.Context, info AttemptInfo) error
+
+// OnRetryFunc is called after a failed attempt, before waiting delay for the next one.
+type OnRetryFunc func(attempt int, err error, delay time.Duration)
+
 // IsRetryableFunc determines if an error should be retried.
 type IsRetryableFunc func(error) bool
 
+// InTxFunc reports whether ctx carries an open transaction.
+type InTxFunc func(ctx context.Context) bool
+
 // Retrier executes an operation with retry logic.
 type Retrier interface {
 	// Do executes the attempt function with retry according to the retrier configuration.
 	Do(ctx context.Context, f AttemptFunc) error
+
+	// DoWithInfo is like Do, but passes the per-attempt context and attempt info to f.
+	DoWithInfo(ctx context.Context, f AttemptFuncWithInfo) error
 }
 
 // retrier is the default implementation of Retrier.
@@ -26,6 +47,10 @@ type retrier struct {
 	backoff     Backoff         // strategy for calculating delay between attempts
 	maxAttempts int             // maximum number of attempts (0 = unlimited)
 	isRetryable IsRetryableFunc // function to determine if an error is retryable
+	clock       clock.Clock     // source of time for backoff waits
+	onRetry     OnRetryFunc     // optional observer of failed attempts
+	timeout     time.Duration   // per-attempt timeout (0 = none)
+	inTx        InTxFunc        // optional check for an open transaction
 }
 
 // New constructs a new Retrier with optional configurations.
@@ -34,6 +59,7 @@ func New(opts ...RetryOption) Retrier {
 		backoff:     defaultBackoff(),
 		maxAttempts: defaultAttempts(),
 		isRetryable: defaultIsRetryableFunc(),
+		clock:       clock.Real(),
 	}
 
 	for _, opt := range opts {
@@ -54,14 +80,28 @@ func NoRetry() Retrier {
 // Do executes the given AttemptFunc with retries according to the retrier's configuration.
 // Returns nil if the attempt succeeds, or the last error if all retries fail.
 func (r retrier) Do(ctx context.Context, f AttemptFunc) error {
+	return r.DoWithInfo(ctx, func(context.Context, AttemptInfo) error {
+		return f()
+	})
+}
+
+// DoWithInfo executes the given AttemptFuncWithInfo with retries according to the retrier's configuration.
+// Each attempt receives a child context bounded by the per-attempt timeout, if any.
+// Inside an open transaction f runs once: a failed statement aborts the transaction.
+func (r retrier) DoWithInfo(ctx context.Context, f AttemptFuncWithInfo) error {
 	var err error
 
-	for attempt := 0; r.maxAttempts == 0 || attempt < r.maxAttempts; attempt++ {
+	maxAttempts := r.maxAttempts
+	if r.inTx != nil && r.inTx(ctx) {
+		maxAttempts = 1
+	}
+
+	for attempt := 0; maxAttempts == 0 || attempt < maxAttempts; attempt++ {
 		if ctxErr := ctx.Err(); ctxErr != nil {
 			return ctxErr
 		}
 
-		if err = f(); err == nil {
+		if err = r.attempt(ctx, f, attempt, maxAttempts); err == nil {
 			return nil
 		}
 
@@ -69,16 +109,40 @@ func (r retrier) Do(ctx context.Context, f AttemptFunc) error {
 			return fmt.Errorf("unretryable error: %w", err)
 		}
 
+		// no wait after the last attempt
+		if maxAttempts != 0 && attempt == maxAttempts-1 {
+			break
+		}
+
+		delay := r.backoff.Next(attempt)
+		if r.onRetry != nil {
+			r.onRetry(attempt, err, delay)
+		}
+
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case <-time.After(r.backoff.Next(attempt)):
+		case <-r.clock.After(delay):
 		}
 	}
 
 	return fmt.Errorf("all attempts failed: %w", err)
 }
 
+// attempt runs f once, deriving a per-attempt context if a timeout is configured.
+func (r retrier) attempt(ctx context.Context, f AttemptFuncWithInfo, attempt, maxAttempts int) error {
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	return f(ctx, AttemptInfo{
+		Attempt:     attempt,
+		MaxAttempts: maxAttempts,
+	})
+}
+
 // defaultAttempts returns the default maximum number of retry attempts.
 func defaultAttempts() int {
 	return 3
@@ -121,3 +185,34 @@ func WithIsRetryableFunc(isRetryable IsRetryableFunc) RetryOption {
 		r.isRetryable = isRetryable
 	}
 }
+
+// WithClock sets the clock used to wait between attempts.
+func WithClock(c clock.Clock) RetryOption {
+	return func(r *retrier) {
+		r.clock = c
+	}
+}
+
+// WithOnRetry sets a function that is called after every failed attempt that will be retried.
+func WithOnRetry(onRetry OnRetryFunc) RetryOption {
+	return func(r *retrier) {
+		r.onRetry = onRetry
+	}
+}
+
+// WithAttemptTimeout bounds each attempt with its own deadline derived from the caller's context.
+// Only functions passed to DoWithInfo observe the per-attempt context.
+func WithAttemptTimeout(timeout time.Duration) RetryOption {
+	return func(r *retrier) {
+		r.timeout = timeout
+	}
+}
+
+// WithInTx sets the check for an open transaction. Attempts inside one are not
+// retried: a failed or timed out statement aborts the transaction, so every
+// retry would fail too.
+func WithInTx(inTx InTxFunc) RetryOption {
+	return func(r *retrier) {
+		r.inTx = inTx
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"testing"
 	"time"
 
+	"pr-service/internal/clock/clocktest"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -83,15 +85,22 @@ func TestRetrier_Do(t *testing.T) {
 		},
 		{
 			name: "context timeout",
-			opts: []RetryOption{WithMaxAttempts(10), WithBackoff(FixedBackoff{Interval: 20 * time.Millisecond})},
+			// the fake clock never fires, so the caller's deadline ends the wait
+			opts: []RetryOption{
+				WithMaxAttempts(10),
+				WithBackoff(FixedBackoff{Interval: 20 * time.Millisecond}),
+				WithClock(clocktest.NewFake(time.Now())),
+			},
 			fn: func() error {
 				return errAlwaysFail
 			},
 			ctx: func() context.Context {
-				ctx, _ := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				_ = cancel // released by the deadline
 				return ctx
 			},
-			wantErr: context.DeadlineExceeded,
+			wantErr:   context.DeadlineExceeded,
+			wantCalls: 1,
 		},
 		{
 			name: "infinite attempts until success",
@@ -127,7 +136,8 @@ func TestRetrier_Do(t *testing.T) {
 			}
 
 			ctx := tt.ctx()
-			r := New(tt.opts...)
+			opts := append([]RetryOption{WithClock(clocktest.NewAutoFake(time.Now()))}, tt.opts...)
+			r := New(opts...)
 			err := r.Do(ctx, wrappedFn)
 
 			if tt.wantErr != nil {
@@ -145,3 +155,168 @@ func TestRetrier_Do(t *testing.T) {
 		})
 	}
 }
+
+func TestRetrier_Do_BackoffSequence(t *testing.T) {
+	clk := clocktest.NewAutoFake(time.Now())
+	r := New(
+		WithMaxAttempts(5),
+		WithBackoff(ExponentialBackoff{Base: time.Second, Factor: 2, Max: 5 * time.Second}),
+		WithClock(clk),
+	)
+
+	err := r.Do(t.Context(), func() error { return errAlwaysFail })
+	require.ErrorIs(t, err, errAlwaysFail)
+
+	// no wait after the last attempt
+	assert.Equal(t, []time.Duration{
+		1 * time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		5 * time.Second, // capped by Max
+	}, clk.Slept())
+}
+
+func TestRetrier_Do_CanceledWhileWaiting(t *testing.T) {
+	clk := clocktest.NewFake(time.Now())
+	r := New(
+		WithMaxAttempts(10),
+		WithBackoff(FixedBackoff{Interval: time.Minute}),
+		WithClock(clk),
+	)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	calls := 0
+	done := make(chan error, 1)
+	go func() {
+		done <- r.Do(ctx, func() error {
+			calls++
+			return errAlwaysFail
+		})
+	}()
+
+	clk.BlockUntil(1)
+	clk.Advance(time.Minute)
+	clk.BlockUntil(1)
+	cancel()
+
+	require.ErrorIs(t, <-done, context.Canceled)
+	assert.Equal(t, 2, calls)
+}
+
+func TestRetrier_OnRetry(t *testing.T) {
+	type call struct {
+		attempt int
+		err     error
+		delay   time.Duration
+	}
+	var calls []call
+
+	r := New(
+		WithMaxAttempts(3),
+		WithBackoff(LinearBackoff{Base: time.Second, Step: time.Second}),
+		WithClock(clocktest.NewAutoFake(time.Now())),
+		WithOnRetry(func(attempt int, err error, delay time.Duration) {
+			calls = append(calls, call{attempt, err, delay})
+		}),
+	)
+
+	err := r.Do(t.Context(), func() error { return errAlwaysFail })
+	require.ErrorIs(t, err, errAlwaysFail)
+
+	assert.Equal(t, []call{
+		{0, errAlwaysFail, 1 * time.Second},
+		{1, errAlwaysFail, 2 * time.Second},
+	}, calls)
+}
+
+func TestRetrier_DoWithInfo(t *testing.T) {
+	t.Run("attempt info", func(t *testing.T) {
+		r := New(
+			WithMaxAttempts(3),
+			WithClock(clocktest.NewAutoFake(time.Now())),
+		)
+
+		var infos []AttemptInfo
+		err := r.DoWithInfo(t.Context(), func(_ context.Context, info AttemptInfo) error {
+			infos = append(infos, info)
+			if info.Attempt < 2 {
+				return errAlwaysFail
+			}
+			return nil
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []AttemptInfo{
+			{Attempt: 0, MaxAttempts: 3},
+			{Attempt: 1, MaxAttempts: 3},
+			{Attempt: 2, MaxAttempts: 3},
+		}, infos)
+	})
+
+	t.Run("per-attempt timeout", func(t *testing.T) {
+		r := New(
+			WithMaxAttempts(2),
+			WithClock(clocktest.NewAutoFake(time.Now())),
+			WithAttemptTimeout(time.Millisecond),
+		)
+
+		parent := t.Context()
+		var attemptCtxs []context.Context
+		err := r.DoWithInfo(parent, func(ctx context.Context, _ AttemptInfo) error {
+			attemptCtxs = append(attemptCtxs, ctx)
+			_, ok := ctx.Deadline()
+			require.True(t, ok)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.EqualError(t, err, "all attempts failed: context deadline exceeded")
+
+		require.Len(t, attemptCtxs, 2)
+		require.NotSame(t, attemptCtxs[0], attemptCtxs[1])
+		require.NoError(t, parent.Err())
+	})
+
+	t.Run("single attempt in transaction", func(t *testing.T) {
+		type txKey struct{}
+		clk := clocktest.NewAutoFake(time.Now())
+		r := New(
+			WithMaxAttempts(3),
+			WithClock(clk),
+			WithAttemptTimeout(time.Millisecond),
+			WithInTx(func(ctx context.Context) bool { return ctx.Value(txKey{}) != nil }),
+		)
+
+		var infos []AttemptInfo
+		err := r.DoWithInfo(context.WithValue(t.Context(), txKey{}, true), func(ctx context.Context, info AttemptInfo) error {
+			infos = append(infos, info)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, []AttemptInfo{{Attempt: 0, MaxAttempts: 1}}, infos)
+		require.Empty(t, clk.Slept())
+
+		// outside the transaction the attempts are retried
+		infos = nil
+		err = r.DoWithInfo(t.Context(), func(ctx context.Context, info AttemptInfo) error {
+			infos = append(infos, info)
+			return errAlwaysFail
+		})
+		require.ErrorIs(t, err, errAlwaysFail)
+		require.Len(t, infos, 3)
+	})
+
+	t.Run("no timeout keeps caller context", func(t *testing.T) {
+		r := New(WithMaxAttempts(1))
+
+		err := r.DoWithInfo(t.Context(), func(ctx context.Context, _ AttemptInfo) error {
+			_, ok := ctx.Deadline()
+			require.False(t, ok)
+			return nil
+		})
+		require.NoError(t, err)
+	})
+}
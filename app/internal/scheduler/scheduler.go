@@ -0,0 +1,106 @@
+// Package scheduler runs periodic background jobs alongside the HTTP server.
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"pr-service/internal/clock"
+
+	"go.uber.org/zap"
+)
+
+// Job runs a task right away and then every interval until stopped.
+// A failed run is logged and does not stop the job: the next run is
+// expected to pick up whatever the failed one left behind.
+type Job struct {
+	name     string
+	interval time.Duration
+	drain    time.Duration
+	task     func(ctx context.Context) error
+
+	clock clock.Clock
+	log   *zap.Logger
+}
+
+// NewJob creates a job that runs task every interval. A run in progress
+// when the job is stopped gets up to drain more to finish.
+func NewJob(
+	name string,
+	interval time.Duration,
+	drain time.Duration,
+	task func(ctx context.Context) error,
+	clk clock.Clock,
+	log *zap.Logger,
+) *Job {
+	return &Job{
+		name:     name,
+		interval: interval,
+		drain:    drain,
+		task:     task,
+		clock:    clk,
+		log:      log,
+	}
+}
+
+// Name returns the job name.
+func (j *Job) Name() string {
+	return j.name
+}
+
+// Run blocks until ctx is cancelled. A run in progress is finished first:
+// the task's context is cancelled only once the drain timeout passes after
+// ctx, so Run returns nil once the current run is over. No new run starts
+// after ctx is cancelled.
+func (j *Job) Run(ctx context.Context) error {
+	for {
+		start := j.clock.Now()
+		if err := j.runOnce(ctx); err != nil {
+			j.log.Error("job run failed",
+				zap.Error(err),
+				zap.String("job", j.name),
+			)
+		} else {
+			j.log.Debug("job run finished",
+				zap.String("job", j.name),
+				zap.Duration("took", j.clock.Now().Sub(start)),
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-j.clock.After(j.interval):
+		}
+
+		// the tick and the cancellation may have been ready together
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// runOnce runs the task with a context that outlives ctx by the drain timeout.
+func (j *Job) runOnce(ctx context.Context) error {
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+		}
+
+		select {
+		case <-done:
+		case <-j.clock.After(j.drain):
+			cancel()
+		}
+	}()
+
+	return j.task(runCtx)
+}
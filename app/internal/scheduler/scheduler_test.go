@@ -0,0 +1,136 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"pr-service/internal/clock/clocktest"
+	"pr-service/internal/scheduler"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestJob_Run(t *testing.T) {
+	clk := clocktest.NewFake(time.Date(2025, 10, 24, 12, 0, 0, 0, time.UTC))
+
+	var runs atomic.Int32
+	job := scheduler.NewJob("test", time.Minute, time.Second, func(ctx context.Context) error {
+		// a failed run must not stop the schedule
+		if runs.Add(1) == 2 {
+			return errors.New("boom")
+		}
+		return nil
+	}, clk, zap.NewNop())
+	require.Equal(t, "test", job.Name())
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() { done <- job.Run(ctx) }()
+
+	// first run happens right away
+	clk.BlockUntil(1)
+	require.EqualValues(t, 1, runs.Load())
+
+	clk.Advance(time.Minute)
+	clk.BlockUntil(1)
+	require.EqualValues(t, 2, runs.Load())
+
+	clk.Advance(time.Minute)
+	clk.BlockUntil(1)
+	require.EqualValues(t, 3, runs.Load())
+
+	cancel()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("job did not stop")
+	}
+	require.EqualValues(t, 3, runs.Load())
+}
+
+func TestJob_Run_NoRunAfterCancel(t *testing.T) {
+	// the auto clock has the next tick ready as soon as a run is over, so
+	// the tick and the cancellation between runs are ready together
+	for range 50 {
+		clk := clocktest.NewAutoFake(time.Date(2025, 10, 24, 12, 0, 0, 0, time.UTC))
+		ctx, cancel := context.WithCancel(t.Context())
+
+		var runs atomic.Int32
+		job := scheduler.NewJob("test", time.Minute, time.Second, func(context.Context) error {
+			if runs.Add(1) == 2 {
+				cancel()
+			}
+			return nil
+		}, clk, zap.NewNop())
+
+		require.NoError(t, job.Run(ctx))
+		require.EqualValues(t, 2, runs.Load(), "a run started after the job was stopped")
+	}
+}
+
+func TestJob_Run_FinishesRunInProgress(t *testing.T) {
+	t.Run("run finishes after stop", func(t *testing.T) {
+		clk := clocktest.NewFake(time.Date(2025, 10, 24, 12, 0, 0, 0, time.UTC))
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		var runErr error
+		job := scheduler.NewJob("test", time.Minute, time.Second, func(ctx context.Context) error {
+			close(started)
+			<-release
+			runErr = ctx.Err()
+			return nil
+		}, clk, zap.NewNop())
+
+		ctx, cancel := context.WithCancel(t.Context())
+		done := make(chan error, 1)
+		go func() { done <- job.Run(ctx) }()
+
+		<-started
+		cancel()
+
+		// the drain timeout is armed, and Run waits for the run
+		clk.BlockUntil(1)
+		select {
+		case <-done:
+			t.Fatal("job stopped before the run finished")
+		default:
+		}
+
+		close(release)
+		require.NoError(t, <-done)
+		require.NoError(t, runErr, "the run was cancelled")
+	})
+
+	t.Run("run is cancelled after the drain timeout", func(t *testing.T) {
+		clk := clocktest.NewFake(time.Date(2025, 10, 24, 12, 0, 0, 0, time.UTC))
+
+		var runErr error
+		job := scheduler.NewJob("test", time.Minute, time.Second, func(ctx context.Context) error {
+			<-ctx.Done()
+			runErr = ctx.Err()
+			return runErr
+		}, clk, zap.NewNop())
+
+		ctx, cancel := context.WithCancel(t.Context())
+		done := make(chan error, 1)
+		go func() { done <- job.Run(ctx) }()
+
+		cancel()
+		clk.BlockUntil(1)
+		clk.Advance(time.Second)
+
+		select {
+		case err := <-done:
+			require.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("job did not stop")
+		}
+		require.ErrorIs(t, runErr, context.Canceled)
+	})
+}
@@ -0,0 +1,348 @@
+//go:generate mockgen -source=availability_service.go -destination=../mocks/availability_service.go -package=mocks .
+
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"pr-service/internal/clock"
+	"pr-service/internal/models"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type AvailabilityRepository interface {
+	// Создать окно отсутствия
+	Create(ctx context.Context, a *models.Availability) error
+
+	// Получить окно отсутствия по ID
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Availability, error)
+
+	// Получить окна отсутствия пользователя по времени начала
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Availability, error)
+
+	// Удалить окно отсутствия
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// Получить действующие в момент at окна, ещё не выключившие пользователя
+	ListStarted(ctx context.Context, at time.Time) ([]*models.Availability, error)
+
+	// Отметить, что окно выключило пользователя
+	MarkDeactivated(ctx context.Context, id uuid.UUID, at time.Time) error
+
+	// Получить закончившиеся к моменту at и ещё не обработанные окна
+	ListEnded(ctx context.Context, at time.Time) ([]*models.Availability, error)
+
+	// Отметить окно обработанным
+	MarkRestored(ctx context.Context, id uuid.UUID, at time.Time) error
+}
+
+type AvailabilityService struct {
+	availabilityRepo AvailabilityRepository
+	userRepo         UserRepository
+
+	trManager TxManager
+
+	clock clock.Clock
+	log   *zap.Logger
+}
+
+func NewAvailabilityService(
+	availabilityRepo AvailabilityRepository,
+	userRepo UserRepository,
+	trManager TxManager,
+	clk clock.Clock,
+	log *zap.Logger,
+) *AvailabilityService {
+	return &AvailabilityService{
+		availabilityRepo: availabilityRepo,
+		userRepo:         userRepo,
+		trManager:        trManager,
+		clock:            clk,
+		log:              log,
+	}
+}
+
+// Add registers an out-of-office window of a.UserID.
+// Returns ErrInvalidWindow unless StartsAt is before EndsAt.
+func (s *AvailabilityService) Add(ctx context.Context, a *models.Availability) error {
+	if !a.StartsAt.Before(a.EndsAt) {
+		return ErrInvalidWindow
+	}
+	a.CreatedAt = s.clock.Now()
+
+	return s.trManager.Do(ctx, func(ctx context.Context) error {
+		if _, err := s.userRepo.GetUserByID(ctx, a.UserID); err != nil {
+			if !errors.Is(err, ErrNotFound) {
+				s.log.Error("failed to get user",
+					zap.Error(err),
+					zap.String("user_id", a.UserID.String()),
+				)
+			}
+			return err
+		}
+
+		if err := s.availabilityRepo.Create(ctx, a); err != nil {
+			s.log.Error("failed to create availability window",
+				zap.Error(err),
+				zap.String("user_id", a.UserID.String()),
+			)
+			return err
+		}
+
+		s.log.Info("availability window added",
+			zap.String("availability_id", a.ID.String()),
+			zap.String("user_id", a.UserID.String()),
+			zap.Time("starts_at", a.StartsAt),
+			zap.Time("ends_at", a.EndsAt),
+		)
+
+		return nil
+	})
+}
+
+// List returns the out-of-office windows of a user ordered by start.
+func (s *AvailabilityService) List(ctx context.Context, userID uuid.UUID) ([]*models.Availability, error) {
+	if _, err := s.userRepo.GetUserByID(ctx, userID); err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			s.log.Error("failed to get user",
+				zap.Error(err),
+				zap.String("user_id", userID.String()),
+			)
+		}
+		return nil, err
+	}
+
+	windows, err := s.availabilityRepo.ListByUser(ctx, userID)
+	if err != nil {
+		s.log.Error("failed to list availability windows",
+			zap.Error(err),
+			zap.String("user_id", userID.String()),
+		)
+		return nil, err
+	}
+
+	return windows, nil
+}
+
+// Delete removes an out-of-office window. A user switched off by the
+// window is switched back on unless another window still covers them.
+func (s *AvailabilityService) Delete(ctx context.Context, id uuid.UUID) error {
+	now := s.clock.Now()
+
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		a, err := s.availabilityRepo.GetByID(ctx, id)
+		if err != nil {
+			if !errors.Is(err, ErrNotFound) {
+				s.log.Error("failed to get availability window",
+					zap.Error(err),
+					zap.String("availability_id", id.String()),
+				)
+			}
+			return err
+		}
+
+		if err := s.availabilityRepo.Delete(ctx, id); err != nil {
+			s.log.Error("failed to delete availability window",
+				zap.Error(err),
+				zap.String("availability_id", id.String()),
+			)
+			return err
+		}
+
+		if a.DeactivatedAt != nil && a.RestoredAt == nil {
+			_, err = s.release(ctx, a.UserID, now)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.log.Info("availability window deleted",
+		zap.String("availability_id", id.String()),
+	)
+
+	return nil
+}
+
+// DeactivateStarted switches is_active off for active users whose
+// out-of-office windows are in effect, and records it on the window, so that
+// only users switched off by a window are switched back on when it ends.
+// Returns the number of users switched off.
+func (s *AvailabilityService) DeactivateStarted(ctx context.Context) (int, error) {
+	now := s.clock.Now()
+	deactivated := 0
+
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		deactivated = 0
+
+		started, err := s.availabilityRepo.ListStarted(ctx, now)
+		if err != nil {
+			s.log.Error("failed to list started availability windows", zap.Error(err))
+			return err
+		}
+
+		// a user already off, by an admin or another window, is left to them
+		checked := make(map[uuid.UUID]bool)
+		for _, a := range started {
+			if checked[a.UserID] {
+				continue
+			}
+			checked[a.UserID] = true
+
+			user, err := s.userRepo.GetUserByID(ctx, a.UserID)
+			if err != nil {
+				s.log.Error("failed to get user",
+					zap.Error(err),
+					zap.String("user_id", a.UserID.String()),
+				)
+				return err
+			}
+			if !user.IsActive {
+				continue
+			}
+
+			if err := s.userRepo.UpdateActive(ctx, a.UserID, false); err != nil {
+				s.log.Error("failed to deactivate user",
+					zap.Error(err),
+					zap.String("user_id", a.UserID.String()),
+				)
+				return err
+			}
+			if err := s.markDeactivated(ctx, a.ID, now); err != nil {
+				return err
+			}
+			deactivated++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if deactivated > 0 {
+		s.log.Info("users deactivated for out-of-office",
+			zap.Int("users_count", deactivated),
+		)
+	}
+
+	return deactivated, nil
+}
+
+// RestoreEnded marks users active again once the out-of-office windows that
+// switched them off are over. A user still covered by another window stays
+// off, and that window switches them back on when it ends. Users whose
+// is_active was switched off otherwise are left as is.
+// Returns the number of users restored.
+func (s *AvailabilityService) RestoreEnded(ctx context.Context) (int, error) {
+	now := s.clock.Now()
+	restored := 0
+
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		restored = 0
+
+		ended, err := s.availabilityRepo.ListEnded(ctx, now)
+		if err != nil {
+			s.log.Error("failed to list ended availability windows", zap.Error(err))
+			return err
+		}
+
+		released := make(map[uuid.UUID]bool)
+		for _, a := range ended {
+			if a.DeactivatedAt != nil && !released[a.UserID] {
+				released[a.UserID] = true
+
+				ok, err := s.release(ctx, a.UserID, now)
+				if err != nil {
+					return err
+				}
+				if ok {
+					restored++
+				}
+			}
+
+			if err := s.availabilityRepo.MarkRestored(ctx, a.ID, now); err != nil {
+				s.log.Error("failed to mark availability window restored",
+					zap.Error(err),
+					zap.String("availability_id", a.ID.String()),
+				)
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if restored > 0 {
+		s.log.Info("users restored after out-of-office",
+			zap.Int("users_count", restored),
+		)
+	}
+
+	return restored, nil
+}
+
+// release switches the user back on once a window that switched them off is
+// gone. If another window covers at, it takes the user over instead, and
+// release reports false.
+func (s *AvailabilityService) release(ctx context.Context, userID uuid.UUID, at time.Time) (bool, error) {
+	cover, err := s.coveringWindow(ctx, userID, at)
+	if err != nil {
+		return false, err
+	}
+
+	if cover != nil {
+		if cover.DeactivatedAt != nil {
+			return false, nil
+		}
+		return false, s.markDeactivated(ctx, cover.ID, at)
+	}
+
+	if err := s.userRepo.UpdateActive(ctx, userID, true); err != nil {
+		s.log.Error("failed to restore user",
+			zap.Error(err),
+			zap.String("user_id", userID.String()),
+		)
+		return false, err
+	}
+	return true, nil
+}
+
+// coveringWindow returns a window of the user in effect at at, or nil.
+func (s *AvailabilityService) coveringWindow(ctx context.Context, userID uuid.UUID, at time.Time) (*models.Availability, error) {
+	windows, err := s.availabilityRepo.ListByUser(ctx, userID)
+	if err != nil {
+		s.log.Error("failed to list availability windows",
+			zap.Error(err),
+			zap.String("user_id", userID.String()),
+		)
+		return nil, err
+	}
+
+	for _, w := range windows {
+		if w.Covers(at) {
+			return w, nil
+		}
+	}
+	return nil, nil
+}
+
+// markDeactivated records that the window switched its user off.
+func (s *AvailabilityService) markDeactivated(ctx context.Context, id uuid.UUID, at time.Time) error {
+	if err := s.availabilityRepo.MarkDeactivated(ctx, id, at); err != nil {
+		s.log.Error("failed to mark availability window deactivated",
+			zap.Error(err),
+			zap.String("availability_id", id.String()),
+		)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"pr-service/internal/mocks"
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestAvailabilityService_Add(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	availabilityRepo := mocks.NewMockAvailabilityRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	svc := service.NewAvailabilityService(availabilityRepo, userRepo, service.TxManagerStub{}, clk, zap.NewNop())
+
+	ctx := t.Context()
+	now := clk.Now()
+	userID := uuid.New()
+
+	t.Run("empty window", func(t *testing.T) {
+		err := svc.Add(ctx, &models.Availability{UserID: userID, StartsAt: now, EndsAt: now})
+		require.ErrorIs(t, err, service.ErrInvalidWindow)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		userRepo.EXPECT().GetUserByID(ctx, userID).Return(nil, repository.ErrNotFound)
+
+		err := svc.Add(ctx, &models.Availability{UserID: userID, StartsAt: now, EndsAt: now.Add(time.Hour)})
+		require.ErrorIs(t, err, service.ErrNotFound)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		a := &models.Availability{UserID: userID, StartsAt: now, EndsAt: now.Add(time.Hour), Reason: "vacation"}
+
+		userRepo.EXPECT().GetUserByID(ctx, userID).Return(&models.User{ID: userID}, nil)
+		availabilityRepo.EXPECT().Create(ctx, a).Return(nil)
+
+		require.NoError(t, svc.Add(ctx, a))
+		require.Equal(t, now, a.CreatedAt)
+	})
+}
+
+func TestAvailabilityService_DeactivateStarted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	availabilityRepo := mocks.NewMockAvailabilityRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	svc := service.NewAvailabilityService(availabilityRepo, userRepo, service.TxManagerStub{}, clk, zap.NewNop())
+
+	ctx := t.Context()
+	now := clk.Now()
+
+	t.Run("switches off only active users", func(t *testing.T) {
+		active, switchedOff := uuid.New(), uuid.New()
+		started := []*models.Availability{
+			{ID: uuid.New(), UserID: active, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)},
+			{ID: uuid.New(), UserID: active, StartsAt: now, EndsAt: now.Add(2 * time.Hour)},
+			{ID: uuid.New(), UserID: switchedOff, StartsAt: now, EndsAt: now.Add(time.Hour)},
+		}
+
+		availabilityRepo.EXPECT().ListStarted(ctx, now).Return(started, nil)
+		userRepo.EXPECT().GetUserByID(ctx, active).Return(&models.User{ID: active, IsActive: true}, nil)
+		userRepo.EXPECT().GetUserByID(ctx, switchedOff).Return(&models.User{ID: switchedOff}, nil)
+		userRepo.EXPECT().UpdateActive(ctx, active, false).Return(nil)
+		availabilityRepo.EXPECT().MarkDeactivated(ctx, started[0].ID, now).Return(nil)
+
+		deactivated, err := svc.DeactivateStarted(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, deactivated)
+	})
+
+	t.Run("update fails", func(t *testing.T) {
+		userID := uuid.New()
+		started := []*models.Availability{
+			{ID: uuid.New(), UserID: userID, StartsAt: now, EndsAt: now.Add(time.Hour)},
+		}
+
+		availabilityRepo.EXPECT().ListStarted(ctx, now).Return(started, nil)
+		userRepo.EXPECT().GetUserByID(ctx, userID).Return(&models.User{ID: userID, IsActive: true}, nil)
+		userRepo.EXPECT().UpdateActive(ctx, userID, false).Return(errors.New("db error"))
+
+		deactivated, err := svc.DeactivateStarted(ctx)
+		require.Error(t, err)
+		require.Zero(t, deactivated)
+	})
+}
+
+func TestAvailabilityService_RestoreEnded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	availabilityRepo := mocks.NewMockAvailabilityRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	svc := service.NewAvailabilityService(availabilityRepo, userRepo, service.TxManagerStub{}, clk, zap.NewNop())
+
+	ctx := t.Context()
+	now := clk.Now()
+	off := now.Add(-2 * time.Hour)
+
+	t.Run("restores only users switched off by the window", func(t *testing.T) {
+		back, stillAway, byAdmin := uuid.New(), uuid.New(), uuid.New()
+		ended := []*models.Availability{
+			{ID: uuid.New(), UserID: back, StartsAt: now.Add(-48 * time.Hour), EndsAt: now.Add(-24 * time.Hour)},
+			{ID: uuid.New(), UserID: back, StartsAt: now.Add(-2 * time.Hour), EndsAt: now, DeactivatedAt: &off},
+			{ID: uuid.New(), UserID: stillAway, StartsAt: now.Add(-2 * time.Hour), EndsAt: now.Add(-time.Hour), DeactivatedAt: &off},
+			{ID: uuid.New(), UserID: byAdmin, StartsAt: now.Add(-2 * time.Hour), EndsAt: now},
+		}
+		next := &models.Availability{ID: uuid.New(), UserID: stillAway, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)}
+
+		availabilityRepo.EXPECT().ListEnded(ctx, now).Return(ended, nil)
+		availabilityRepo.EXPECT().ListByUser(ctx, back).Return(ended[:2], nil)
+		availabilityRepo.EXPECT().ListByUser(ctx, stillAway).Return([]*models.Availability{ended[2], next}, nil)
+		userRepo.EXPECT().UpdateActive(ctx, back, true).Return(nil)
+		// the window still in effect takes the user over
+		availabilityRepo.EXPECT().MarkDeactivated(ctx, next.ID, now).Return(nil)
+		for _, a := range ended {
+			availabilityRepo.EXPECT().MarkRestored(ctx, a.ID, now).Return(nil)
+		}
+
+		restored, err := svc.RestoreEnded(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, restored)
+	})
+
+	t.Run("nothing ended", func(t *testing.T) {
+		availabilityRepo.EXPECT().ListEnded(ctx, now).Return([]*models.Availability{}, nil)
+
+		restored, err := svc.RestoreEnded(ctx)
+		require.NoError(t, err)
+		require.Zero(t, restored)
+	})
+
+	t.Run("update fails", func(t *testing.T) {
+		userID := uuid.New()
+		ended := []*models.Availability{
+			{ID: uuid.New(), UserID: userID, StartsAt: now.Add(-time.Hour), EndsAt: now, DeactivatedAt: &off},
+		}
+
+		availabilityRepo.EXPECT().ListEnded(ctx, now).Return(ended, nil)
+		availabilityRepo.EXPECT().ListByUser(ctx, userID).Return(ended, nil)
+		userRepo.EXPECT().UpdateActive(ctx, userID, true).Return(errors.New("db error"))
+
+		restored, err := svc.RestoreEnded(ctx)
+		require.Error(t, err)
+		require.Zero(t, restored)
+	})
+}
+
+func TestAvailabilityService_Delete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	availabilityRepo := mocks.NewMockAvailabilityRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	svc := service.NewAvailabilityService(availabilityRepo, userRepo, service.TxManagerStub{}, clk, zap.NewNop())
+
+	ctx := t.Context()
+	now := clk.Now()
+	off := now.Add(-time.Hour)
+
+	t.Run("not found", func(t *testing.T) {
+		id := uuid.New()
+		availabilityRepo.EXPECT().GetByID(ctx, id).Return(nil, repository.ErrNotFound)
+
+		require.ErrorIs(t, svc.Delete(ctx, id), service.ErrNotFound)
+	})
+
+	t.Run("window that did not switch the user off", func(t *testing.T) {
+		a := &models.Availability{ID: uuid.New(), UserID: uuid.New(), StartsAt: now, EndsAt: now.Add(time.Hour)}
+		availabilityRepo.EXPECT().GetByID(ctx, a.ID).Return(a, nil)
+		availabilityRepo.EXPECT().Delete(ctx, a.ID).Return(nil)
+
+		require.NoError(t, svc.Delete(ctx, a.ID))
+	})
+
+	t.Run("switches the user back on", func(t *testing.T) {
+		a := &models.Availability{ID: uuid.New(), UserID: uuid.New(), StartsAt: off, EndsAt: now.Add(time.Hour), DeactivatedAt: &off}
+		availabilityRepo.EXPECT().GetByID(ctx, a.ID).Return(a, nil)
+		availabilityRepo.EXPECT().Delete(ctx, a.ID).Return(nil)
+		availabilityRepo.EXPECT().ListByUser(ctx, a.UserID).Return([]*models.Availability{}, nil)
+		userRepo.EXPECT().UpdateActive(ctx, a.UserID, true).Return(nil)
+
+		require.NoError(t, svc.Delete(ctx, a.ID))
+	})
+}
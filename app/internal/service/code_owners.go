@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"pr-service/internal/codeowners"
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// TeamSetCodeOwners replaces the team's path ownership rules with a file in
+// CODEOWNERS syntax. Owners are teams as "@team-name" (an "@org/" prefix is
+// allowed), and users as an email or "@<user id>". Every owner must resolve,
+// otherwise nothing is stored. An empty file removes the rules.
+func (s *PRService) TeamSetCodeOwners(ctx context.Context, teamName, src string) ([]models.CodeOwnerRule, error) {
+	parsed, err := codeowners.Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCodeOwners, err)
+	}
+
+	rules := make([]models.CodeOwnerRule, 0, len(parsed))
+	err = s.trManager.Do(ctx, func(ctx context.Context) error {
+		team, err := s.teamRepo.GetByName(ctx, teamName)
+		if err != nil {
+			if !errors.Is(err, repository.ErrNotFound) {
+				s.log.Error("failed to get team",
+					zap.Error(err),
+					zap.String("team_name", teamName),
+				)
+			}
+			return err
+		}
+
+		for _, p := range parsed {
+			rule := models.CodeOwnerRule{Pattern: p.Pattern, Owners: make([]models.CodeOwner, 0, len(p.Owners))}
+			for _, ref := range p.Owners {
+				owner, err := s.resolveCodeOwner(ctx, ref)
+				if err != nil {
+					return fmt.Errorf("line %d: %w", p.Line, err)
+				}
+				rule.Owners = append(rule.Owners, owner)
+			}
+			rules = append(rules, rule)
+		}
+
+		if err := s.teamRepo.SetCodeOwners(ctx, team.ID, rules); err != nil {
+			s.log.Error("failed to set team code owners",
+				zap.Error(err),
+				zap.String("team_name", teamName),
+			)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("team code owners updated",
+		zap.String("team_name", teamName),
+		zap.Int("rules", len(rules)),
+	)
+
+	return rules, nil
+}
+
+// TeamGetCodeOwners returns the team's path ownership rules.
+func (s *PRService) TeamGetCodeOwners(ctx context.Context, teamName string) ([]models.CodeOwnerRule, error) {
+	team, err := s.teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			s.log.Error("failed to get team",
+				zap.Error(err),
+				zap.String("team_name", teamName),
+			)
+		}
+		return nil, err
+	}
+
+	rules, err := s.teamRepo.GetCodeOwners(ctx, team.ID)
+	if err != nil {
+		s.log.Error("failed to get team code owners",
+			zap.Error(err),
+			zap.String("team_name", teamName),
+		)
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// resolveCodeOwner finds the user or team an owner reference names.
+func (s *PRService) resolveCodeOwner(ctx context.Context, ref string) (models.CodeOwner, error) {
+	owner := models.CodeOwner{Ref: ref}
+
+	name, isHandle := strings.CutPrefix(ref, "@")
+	switch {
+	case isHandle && name != "":
+		if id, err := uuid.Parse(name); err == nil {
+			if _, err := s.userRepo.GetUserByID(ctx, id); err != nil {
+				if errors.Is(err, repository.ErrNotFound) {
+					return owner, fmt.Errorf("%w: unknown owner %s", ErrInvalidCodeOwners, ref)
+				}
+				return owner, err
+			}
+			owner.UserID = id
+			return owner, nil
+		}
+
+		team, err := s.teamRepo.GetByName(ctx, name)
+		if errors.Is(err, repository.ErrNotFound) {
+			if _, teamName, ok := strings.Cut(name, "/"); ok {
+				team, err = s.teamRepo.GetByName(ctx, teamName)
+			}
+		}
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return owner, fmt.Errorf("%w: unknown owner %s", ErrInvalidCodeOwners, ref)
+			}
+			return owner, err
+		}
+		owner.TeamID = team.ID
+		return owner, nil
+
+	case !isHandle && strings.Contains(ref, "@"):
+		users, err := s.userRepo.ListByEmail(ctx, ref)
+		if err != nil {
+			return owner, err
+		}
+		switch len(users) {
+		case 0:
+			return owner, fmt.Errorf("%w: unknown owner %s", ErrInvalidCodeOwners, ref)
+		case 1:
+			owner.UserID = users[0].ID
+			return owner, nil
+		default:
+			return owner, fmt.Errorf("%w: owner %s matches several users", ErrInvalidCodeOwners, ref)
+		}
+
+	default:
+		return owner, fmt.Errorf("%w: invalid owner %s", ErrInvalidCodeOwners, ref)
+	}
+}
+
+// fileOwners returns the owners of the changed files under the team's
+// rules: the owners of the last matching rule of every file, in file order.
+func (s *PRService) fileOwners(ctx context.Context, teamID uuid.UUID, files []string) ([]models.CodeOwner, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	rules, err := s.teamRepo.GetCodeOwners(ctx, teamID)
+	if err != nil || len(rules) == 0 {
+		return nil, err
+	}
+
+	patterns := make([]*codeowners.Pattern, len(rules))
+	for i, rule := range rules {
+		// stored rules were validated on upload
+		patterns[i], _ = codeowners.Compile(rule.Pattern)
+	}
+
+	var owners []models.CodeOwner
+	for _, file := range files {
+		for i := len(rules) - 1; i >= 0; i-- {
+			if patterns[i] != nil && patterns[i].Match(file) {
+				owners = append(owners, rules[i].Owners...)
+				break
+			}
+		}
+	}
+
+	return owners, nil
+}
+
+// ruleOwners returns the owners of every rule of the team, in rule order.
+func (s *PRService) ruleOwners(ctx context.Context, teamID uuid.UUID) ([]models.CodeOwner, error) {
+	rules, err := s.teamRepo.GetCodeOwners(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	var owners []models.CodeOwner
+	for _, rule := range rules {
+		owners = append(owners, rule.Owners...)
+	}
+	return owners, nil
+}
@@ -0,0 +1,119 @@
+package service_test
+
+import (
+	"testing"
+
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPRService_CodeOwners(t *testing.T) {
+	ctx := t.Context()
+	svc := newMemoryPRService()
+
+	backend := &models.Team{
+		Name: "backend",
+		Members: []*models.User{
+			{Name: "author", IsActive: true},
+			{Name: "mate", IsActive: true},
+			{Name: "dba", Email: "DBA@example.com", IsActive: true},
+		},
+	}
+	require.NoError(t, svc.TeamAdd(ctx, backend))
+	author, mate, dba := backend.Members[0], backend.Members[1], backend.Members[2]
+
+	docs := &models.Team{
+		Name: "docs",
+		Members: []*models.User{
+			{Name: "writer", IsActive: true},
+		},
+	}
+	require.NoError(t, svc.TeamAdd(ctx, docs))
+	writer := docs.Members[0]
+
+	t.Run("invalid files are rejected", func(t *testing.T) {
+		for _, src := range []string{
+			"!docs/ @docs",
+			"docs/ @nobody",
+			"docs/ someone",
+			"docs/ ghost@example.com",
+			"docs/ @" + uuid.NewString(),
+		} {
+			_, err := svc.TeamSetCodeOwners(ctx, "backend", src)
+			require.ErrorIs(t, err, service.ErrInvalidCodeOwners, src)
+		}
+
+		rules, err := svc.TeamGetCodeOwners(ctx, "backend")
+		require.NoError(t, err)
+		require.Empty(t, rules)
+	})
+
+	t.Run("unknown team", func(t *testing.T) {
+		_, err := svc.TeamSetCodeOwners(ctx, "missing", "* @docs")
+		require.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	src := `
+# docs belong to the docs team
+docs/ @acme/docs
+*.sql dba@example.com
+/migrations/**/*.sql @` + dba.ID.String() + `
+`
+	rules, err := svc.TeamSetCodeOwners(ctx, "backend", src)
+	require.NoError(t, err)
+	require.Len(t, rules, 3)
+	require.Equal(t, docs.ID, rules[0].Owners[0].TeamID)
+	require.Equal(t, dba.ID, rules[1].Owners[0].UserID)
+
+	stored, err := svc.TeamGetCodeOwners(ctx, "backend")
+	require.NoError(t, err)
+	require.Equal(t, rules, stored)
+
+	create := func(t *testing.T, files ...string) []uuid.UUID {
+		t.Helper()
+		pr := &models.PullRequest{
+			ID:       uuid.New(),
+			Name:     "change",
+			AuthorID: author.ID,
+			Status:   string(models.PRStatusOpen),
+			Files:    files,
+		}
+		require.NoError(t, svc.CreatePR(ctx, pr))
+
+		ids := make([]uuid.UUID, 0, len(pr.Reviewers))
+		for _, r := range pr.Reviewers {
+			ids = append(ids, r.ID)
+		}
+		return ids
+	}
+
+	t.Run("owners come first, across teams", func(t *testing.T) {
+		ids := create(t, "docs/guide.md", "db/schema.sql")
+		require.ElementsMatch(t, []uuid.UUID{writer.ID, dba.ID}, ids)
+	})
+
+	t.Run("team fills the rest", func(t *testing.T) {
+		ids := create(t, "docs/guide.md")
+		require.Len(t, ids, 2)
+		require.Equal(t, writer.ID, ids[0])
+		require.NotContains(t, ids, author.ID)
+	})
+
+	t.Run("unowned files use the team", func(t *testing.T) {
+		ids := create(t, "cmd/main.go")
+		require.ElementsMatch(t, []uuid.UUID{mate.ID, dba.ID}, ids)
+	})
+
+	t.Run("empty file clears the rules", func(t *testing.T) {
+		rules, err := svc.TeamSetCodeOwners(ctx, "backend", "")
+		require.NoError(t, err)
+		require.Empty(t, rules)
+
+		ids := create(t, "docs/guide.md")
+		require.NotContains(t, ids, writer.ID)
+	})
+}
@@ -0,0 +1,45 @@
+package service
+
+import "context"
+
+type commitHooksKey struct{}
+
+// CommitHooks wraps a TxManager so that work registered with AfterCommit
+// runs once the outermost transaction has committed. Nested Do calls join
+// the outer transaction and share its hooks.
+type CommitHooks struct {
+	trManager TxManager
+}
+
+func NewCommitHooks(trManager TxManager) *CommitHooks {
+	return &CommitHooks{trManager: trManager}
+}
+
+func (h *CommitHooks) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(commitHooksKey{}).(*[]func()); ok {
+		return h.trManager.Do(ctx, fn)
+	}
+
+	var hooks []func()
+	if err := h.trManager.Do(context.WithValue(ctx, commitHooksKey{}, &hooks), fn); err != nil {
+		return err
+	}
+
+	for _, hook := range hooks {
+		hook()
+	}
+
+	return nil
+}
+
+// AfterCommit runs hook once the CommitHooks transaction of ctx commits and
+// drops it on rollback. Outside such a transaction hook runs right away.
+func AfterCommit(ctx context.Context, hook func()) {
+	hooks, ok := ctx.Value(commitHooksKey{}).(*[]func())
+	if !ok {
+		hook()
+		return
+	}
+
+	*hooks = append(*hooks, hook)
+}
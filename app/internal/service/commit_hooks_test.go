@@ -0,0 +1,51 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"pr-service/internal/repository/memory"
+	"pr-service/internal/service"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitHooks(t *testing.T) {
+	tx := service.NewCommitHooks(memory.NewTxManager(memory.NewStore()))
+	ctx := t.Context()
+
+	t.Run("commit", func(t *testing.T) {
+		var ran []string
+		err := tx.Do(ctx, func(ctx context.Context) error {
+			service.AfterCommit(ctx, func() { ran = append(ran, "outer") })
+
+			// nested transactions hand their hooks to the outer one
+			err := tx.Do(ctx, func(ctx context.Context) error {
+				service.AfterCommit(ctx, func() { ran = append(ran, "nested") })
+				return nil
+			})
+			require.Empty(t, ran)
+			return err
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"outer", "nested"}, ran)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		boom := errors.New("boom")
+		ran := false
+		err := tx.Do(ctx, func(ctx context.Context) error {
+			service.AfterCommit(ctx, func() { ran = true })
+			return boom
+		})
+		require.ErrorIs(t, err, boom)
+		require.False(t, ran)
+	})
+
+	t.Run("outside transaction", func(t *testing.T) {
+		ran := false
+		service.AfterCommit(ctx, func() { ran = true })
+		require.True(t, ran)
+	})
+}
@@ -0,0 +1,232 @@
+//go:generate mockgen -source=digest_service.go -destination=../mocks/digest_service.go -package=mocks .
+
+package service
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"pr-service/internal/clock"
+	"pr-service/internal/models"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type DigestRepository interface {
+	// Получить команды с расписанием дайджеста
+	ListScheduled(ctx context.Context) ([]*models.Team, error)
+
+	// Получить последний день, за который дайджест команды получили все, "" если не было
+	SentOn(ctx context.Context, teamID uuid.UUID) (string, error)
+
+	// Отметить доставку дайджеста команды пользователю за день, false если он уже доставлен
+	MarkDelivered(ctx context.Context, teamID, userID uuid.UUID, day string) (bool, error)
+
+	// Отметить отправку дайджеста команды за день, false если он уже отправлен
+	MarkSent(ctx context.Context, teamID uuid.UUID, day string) (bool, error)
+}
+
+type Notifier interface {
+	// Отправить дайджест пользователю
+	SendDigest(ctx context.Context, d *models.Digest) error
+}
+
+type DigestService struct {
+	digestRepo DigestRepository
+	userRepo   UserRepository
+	prRepo     PRRepository
+	notifier   Notifier
+
+	trManager TxManager
+
+	clock clock.Clock
+	log   *zap.Logger
+}
+
+func NewDigestService(
+	digestRepo DigestRepository,
+	userRepo UserRepository,
+	prRepo PRRepository,
+	notifier Notifier,
+	trManager TxManager,
+	clk clock.Clock,
+	log *zap.Logger,
+) *DigestService {
+	return &DigestService{
+		digestRepo: digestRepo,
+		userRepo:   userRepo,
+		prRepo:     prRepo,
+		notifier:   notifier,
+		trManager:  trManager,
+		clock:      clk,
+		log:        log,
+	}
+}
+
+// SendDue sends the digests of every team whose local digest time has come
+// today and returns how many were sent. Each delivery is recorded in the
+// same transaction as the send, so concurrent instances never send it twice
+// and a failed one is retried on the next run; the team day is marked sent
+// once every reviewer got theirs. Failures do not stop other reviewers; the
+// first error is returned after the run.
+func (s *DigestService) SendDue(ctx context.Context) (int, error) {
+	now := s.clock.Now()
+	teams, err := s.digestRepo.ListScheduled(ctx)
+	if err != nil {
+		s.log.Error("failed to list teams with digest", zap.Error(err))
+		return 0, err
+	}
+
+	var (
+		sent     int
+		firstErr error
+	)
+	for _, team := range teams {
+		if ctx.Err() != nil {
+			return sent, ctx.Err()
+		}
+
+		day, due := team.Digest.Due(now)
+		if !due {
+			continue
+		}
+
+		sentOn, err := s.digestRepo.SentOn(ctx, team.ID)
+		if err != nil {
+			s.log.Error("failed to get team digest day",
+				zap.Error(err),
+				zap.String("team_name", team.Name),
+			)
+			firstErr = cmp.Or(firstErr, err)
+			continue
+		}
+		if sentOn >= day {
+			continue
+		}
+
+		n, err := s.sendTeam(ctx, team, day, now)
+		sent += n
+		if err != nil {
+			firstErr = cmp.Or(firstErr, err)
+			continue
+		}
+
+		if _, err := s.digestRepo.MarkSent(ctx, team.ID, day); err != nil {
+			s.log.Error("failed to mark team digest sent",
+				zap.Error(err),
+				zap.String("team_name", team.Name),
+			)
+			firstErr = cmp.Or(firstErr, err)
+			continue
+		}
+
+		s.log.Info("team digest sent",
+			zap.String("team_name", team.Name),
+			zap.String("day", day),
+			zap.Int("digests", n),
+		)
+	}
+
+	return sent, firstErr
+}
+
+// sendTeam sends a digest to every available team member with open reviews
+// who has not got the one of day yet.
+func (s *DigestService) sendTeam(ctx context.Context, team *models.Team, day string, now time.Time) (int, error) {
+	members, err := s.userRepo.GetAvailableByTeam(ctx, team.ID, now)
+	if err != nil {
+		s.log.Error("failed to get team members",
+			zap.Error(err),
+			zap.String("team_name", team.Name),
+		)
+		return 0, err
+	}
+
+	var (
+		sent     int
+		firstErr error
+	)
+	for _, u := range members {
+		delivered, err := s.deliver(ctx, team, u, day, now)
+		if err != nil {
+			firstErr = cmp.Or(firstErr, err)
+			continue
+		}
+		if delivered {
+			sent++
+		}
+	}
+
+	return sent, firstErr
+}
+
+// deliver sends the user their digest of day unless it was already
+// recorded. The record is rolled back when the send fails.
+func (s *DigestService) deliver(ctx context.Context, team *models.Team, u *models.User, day string, now time.Time) (bool, error) {
+	var delivered bool
+
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		marked, err := s.digestRepo.MarkDelivered(ctx, team.ID, u.ID, day)
+		if err != nil {
+			s.log.Error("failed to mark digest delivered",
+				zap.Error(err),
+				zap.String("user_id", u.ID.String()),
+			)
+			return err
+		}
+		if !marked {
+			return nil
+		}
+
+		d, err := s.build(ctx, u, now)
+		if err != nil {
+			return err
+		}
+		if len(d.Reviews) == 0 {
+			return nil
+		}
+
+		d.TeamName = team.Name
+		d.Day = day
+		if err := s.notifier.SendDigest(ctx, d); err != nil {
+			s.log.Error("failed to send digest",
+				zap.Error(err),
+				zap.String("user_id", u.ID.String()),
+			)
+			return err
+		}
+
+		delivered = true
+		return nil
+	})
+
+	return delivered, err
+}
+
+// build collects the OPEN PRs the user reviews, oldest first.
+func (s *DigestService) build(ctx context.Context, u *models.User, now time.Time) (*models.Digest, error) {
+	prs, err := s.prRepo.ListByReviewer(ctx, u.ID)
+	if err != nil {
+		s.log.Error("failed to get PRs for review",
+			zap.Error(err),
+			zap.String("user_id", u.ID.String()),
+		)
+		return nil, err
+	}
+
+	prs = slices.DeleteFunc(prs, func(pr *models.PullRequest) bool {
+		return pr.Status != string(models.PRStatusOpen)
+	})
+	slices.SortStableFunc(prs, func(a, b *models.PullRequest) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return &models.Digest{
+		User:        u,
+		Reviews:     prs,
+		GeneratedAt: now,
+	}, nil
+}
@@ -0,0 +1,112 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"pr-service/internal/mocks"
+	"pr-service/internal/models"
+	"pr-service/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestDigestService_SendDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	digestRepo := mocks.NewMockDigestRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	prRepo := mocks.NewMockPRRepository(ctrl)
+	notifier := mocks.NewMockNotifier(ctrl)
+	svc := service.NewDigestService(digestRepo, userRepo, prRepo, notifier, service.TxManagerStub{}, clk, zap.NewNop())
+
+	ctx := t.Context()
+	now := clk.Now() // 12:00 UTC
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	t.Run("due teams only", func(t *testing.T) {
+		// 12:00 UTC is 21:00 in Tokyo
+		due := &models.Team{ID: uuid.New(), Name: "backend", Digest: &models.DigestSchedule{At: 21 * time.Hour, Location: tokyo}}
+		early := &models.Team{ID: uuid.New(), Name: "frontend", Digest: &models.DigestSchedule{At: 13 * time.Hour, Location: time.UTC}}
+		sent := &models.Team{ID: uuid.New(), Name: "ops", Digest: &models.DigestSchedule{At: 9 * time.Hour, Location: time.UTC}}
+
+		bob := &models.User{ID: uuid.New(), Name: "Bob"}
+		carol := &models.User{ID: uuid.New(), Name: "Carol"}
+
+		newer := &models.PullRequest{ID: uuid.New(), Status: string(models.PRStatusOpen), CreatedAt: now.Add(-time.Hour)}
+		older := &models.PullRequest{ID: uuid.New(), Status: string(models.PRStatusOpen), CreatedAt: now.Add(-48 * time.Hour)}
+		merged := &models.PullRequest{ID: uuid.New(), Status: string(models.PRStatusMerged), CreatedAt: now.Add(-72 * time.Hour)}
+
+		digestRepo.EXPECT().ListScheduled(ctx).Return([]*models.Team{due, early, sent}, nil)
+		digestRepo.EXPECT().SentOn(ctx, due.ID).Return("2025-10-23", nil)
+		digestRepo.EXPECT().SentOn(ctx, sent.ID).Return("2025-10-24", nil)
+		userRepo.EXPECT().GetAvailableByTeam(ctx, due.ID, now).Return([]*models.User{bob, carol}, nil)
+		digestRepo.EXPECT().MarkDelivered(ctx, due.ID, bob.ID, "2025-10-24").Return(true, nil)
+		digestRepo.EXPECT().MarkDelivered(ctx, due.ID, carol.ID, "2025-10-24").Return(true, nil)
+		prRepo.EXPECT().ListByReviewer(ctx, bob.ID).Return([]*models.PullRequest{merged, newer, older}, nil)
+		prRepo.EXPECT().ListByReviewer(ctx, carol.ID).Return([]*models.PullRequest{merged}, nil)
+		notifier.EXPECT().SendDigest(ctx, &models.Digest{
+			User:        bob,
+			TeamName:    "backend",
+			Day:         "2025-10-24",
+			Reviews:     []*models.PullRequest{older, newer},
+			GeneratedAt: now,
+		}).Return(nil)
+		digestRepo.EXPECT().MarkSent(ctx, due.ID, "2025-10-24").Return(true, nil)
+
+		n, err := svc.SendDue(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+	})
+
+	t.Run("delivery failure does not stop the team", func(t *testing.T) {
+		team := &models.Team{ID: uuid.New(), Name: "backend", Digest: &models.DigestSchedule{At: 0, Location: time.UTC}}
+		bob := &models.User{ID: uuid.New(), Name: "Bob"}
+		carol := &models.User{ID: uuid.New(), Name: "Carol"}
+		pr := &models.PullRequest{ID: uuid.New(), Status: string(models.PRStatusOpen), CreatedAt: now}
+		boom := errors.New("smtp down")
+
+		digestRepo.EXPECT().ListScheduled(ctx).Return([]*models.Team{team}, nil)
+		digestRepo.EXPECT().SentOn(ctx, team.ID).Return("", nil)
+		userRepo.EXPECT().GetAvailableByTeam(ctx, team.ID, now).Return([]*models.User{bob, carol}, nil)
+		digestRepo.EXPECT().MarkDelivered(ctx, team.ID, bob.ID, "2025-10-24").Return(true, nil)
+		digestRepo.EXPECT().MarkDelivered(ctx, team.ID, carol.ID, "2025-10-24").Return(true, nil)
+		prRepo.EXPECT().ListByReviewer(ctx, bob.ID).Return([]*models.PullRequest{pr}, nil)
+		prRepo.EXPECT().ListByReviewer(ctx, carol.ID).Return([]*models.PullRequest{pr}, nil)
+		notifier.EXPECT().SendDigest(ctx, gomock.Any()).Return(boom)
+		notifier.EXPECT().SendDigest(ctx, gomock.Any()).Return(nil)
+
+		n, err := svc.SendDue(ctx)
+		require.ErrorIs(t, err, boom)
+		require.Equal(t, 1, n)
+
+		// the team day stays open, so the next run retries Bob only
+		digestRepo.EXPECT().ListScheduled(ctx).Return([]*models.Team{team}, nil)
+		digestRepo.EXPECT().SentOn(ctx, team.ID).Return("", nil)
+		userRepo.EXPECT().GetAvailableByTeam(ctx, team.ID, now).Return([]*models.User{bob, carol}, nil)
+		digestRepo.EXPECT().MarkDelivered(ctx, team.ID, bob.ID, "2025-10-24").Return(true, nil)
+		digestRepo.EXPECT().MarkDelivered(ctx, team.ID, carol.ID, "2025-10-24").Return(false, nil)
+		prRepo.EXPECT().ListByReviewer(ctx, bob.ID).Return([]*models.PullRequest{pr}, nil)
+		notifier.EXPECT().SendDigest(ctx, gomock.Any()).Return(nil)
+		digestRepo.EXPECT().MarkSent(ctx, team.ID, "2025-10-24").Return(true, nil)
+
+		n, err = svc.SendDue(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		boom := errors.New("db down")
+		digestRepo.EXPECT().ListScheduled(ctx).Return(nil, boom)
+
+		_, err := svc.SendDue(ctx)
+		require.ErrorIs(t, err, boom)
+	})
+}
@@ -11,5 +11,17 @@ var (
 	ErrCanNotReassing      = errors.New("can not reassign reviewer, pr is merged")
 	ErrTeamAlreadyExists   = errors.New("team already exists")
 	ErrNotAssinged         = errors.New("not assigned")
+	ErrInvalidWindow       = errors.New("invalid time window")
+	ErrInvalidSLA          = errors.New("invalid review sla")
+	ErrInvalidSchedule     = errors.New("invalid digest schedule")
+	ErrUnknownExternalUser = errors.New("code host user is not linked")
+	ErrInvalidCodeOwners   = errors.New("invalid codeowners")
+	ErrInvalidFallback     = errors.New("invalid fallback teams")
+	ErrInvalidParent       = errors.New("invalid parent team")
+	ErrInvalidRole         = errors.New("invalid reviewer role")
+	ErrReviewPolicy        = errors.New("review policy can not be satisfied")
+	ErrInvalidReviewer     = errors.New("invalid reviewer")
+	ErrInvalidDecline      = errors.New("invalid decline reason")
+	ErrVersionConflict     = errors.New("pull request was changed")
 	ErrNotFound            = repository.ErrNotFound
 )
@@ -0,0 +1,229 @@
+//go:generate mockgen -source=integration_service.go -destination=../mocks/integration_service.go -package=mocks .
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type IntegrationRepository interface {
+	// Связать пользователя кодового хостинга с пользователем сервиса
+	LinkUser(ctx context.Context, provider, login string, userID uuid.UUID) error
+
+	// Получить пользователя сервиса по логину на кодовом хостинге
+	GetUserID(ctx context.Context, provider, login string) (uuid.UUID, error)
+
+	// Получить логин пользователя сервиса на кодовом хостинге
+	GetLogin(ctx context.Context, provider string, userID uuid.UUID) (string, error)
+
+	// Связать PR кодового хостинга с PR сервиса, ErrDuplicate если он уже связан
+	LinkPR(ctx context.Context, ref models.ExternalPR, prID uuid.UUID) error
+
+	// Получить PR сервиса по PR кодового хостинга
+	GetPRID(ctx context.Context, ref models.ExternalPR) (uuid.UUID, error)
+
+	// Получить PR кодового хостинга по PR сервиса
+	GetExternalPR(ctx context.Context, provider string, prID uuid.UUID) (models.ExternalPR, error)
+}
+
+type PRSyncer interface {
+	// Создать пулл-реквест и назначить ревьюеров
+	CreatePR(ctx context.Context, pr *models.PullRequest) error
+
+	// Замерджить пулл-реквест
+	PRMerge(ctx context.Context, id uuid.UUID) (*models.PullRequest, error)
+}
+
+// SyncResult is what a code host event changed.
+type SyncResult string
+
+const (
+	SyncCreated   SyncResult = "created"
+	SyncMerged    SyncResult = "merged"
+	SyncUnchanged SyncResult = "unchanged" // already in the requested state, e.g. a redelivery
+	SyncIgnored   SyncResult = "ignored"   // nothing to do for the event
+)
+
+type IntegrationService struct {
+	integrationRepo IntegrationRepository
+	userRepo        UserRepository
+	prs             PRSyncer
+
+	trManager TxManager
+
+	log *zap.Logger
+}
+
+func NewIntegrationService(
+	integrationRepo IntegrationRepository,
+	userRepo UserRepository,
+	prs PRSyncer,
+	trManager TxManager,
+	log *zap.Logger,
+) *IntegrationService {
+	return &IntegrationService{
+		integrationRepo: integrationRepo,
+		userRepo:        userRepo,
+		prs:             prs,
+		trManager:       trManager,
+		log:             log,
+	}
+}
+
+// LinkUser maps a code host login to a user; logins are case-insensitive.
+func (s *IntegrationService) LinkUser(ctx context.Context, provider, login string, userID uuid.UUID) error {
+	login = strings.ToLower(login)
+
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		if _, err := s.userRepo.GetUserByID(ctx, userID); err != nil {
+			return err
+		}
+		return s.integrationRepo.LinkUser(ctx, provider, login, userID)
+	})
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			s.log.Error("failed to link external user",
+				zap.Error(err),
+				zap.String("provider", provider),
+				zap.String("login", login),
+			)
+		}
+		return err
+	}
+
+	s.log.Info("external user linked",
+		zap.String("provider", provider),
+		zap.String("login", login),
+		zap.String("user_id", userID.String()),
+	)
+
+	return nil
+}
+
+// HandlePREvent applies a code host event to our PRs. Events are
+// idempotent: a redelivered or out-of-order event reports SyncUnchanged or
+// SyncIgnored instead of failing.
+func (s *IntegrationService) HandlePREvent(ctx context.Context, e *models.PREvent) (SyncResult, error) {
+	var (
+		result SyncResult
+		err    error
+	)
+
+	switch e.Kind {
+	case models.PREventOpened:
+		result, err = s.open(ctx, e)
+	case models.PREventReopened:
+		// closing is not tracked, so a linked PR is still open; one closed
+		// before the integration is created like an opened one
+		result, err = s.open(ctx, e)
+	case models.PREventClosed:
+		s.log.Info("external PR closed without merge ignored, PRs have no closed state",
+			zap.String("provider", e.PR.Provider),
+			zap.String("repository", e.PR.Repository),
+			zap.Int64("number", e.PR.Number),
+		)
+		return SyncIgnored, nil
+	case models.PREventMerged:
+		result, err = s.merge(ctx, e)
+	default:
+		return SyncIgnored, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	s.log.Info("external PR event handled",
+		zap.String("provider", e.PR.Provider),
+		zap.String("repository", e.PR.Repository),
+		zap.Int64("number", e.PR.Number),
+		zap.String("kind", string(e.Kind)),
+		zap.String("result", string(result)),
+	)
+
+	return result, nil
+}
+
+// open creates and links the PR unless it is linked already.
+func (s *IntegrationService) open(ctx context.Context, e *models.PREvent) (SyncResult, error) {
+	result := SyncCreated
+
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		_, err := s.integrationRepo.GetPRID(ctx, e.PR)
+		if err == nil {
+			result = SyncUnchanged
+			return nil
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+
+		authorID, err := s.integrationRepo.GetUserID(ctx, e.PR.Provider, strings.ToLower(e.Author))
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return fmt.Errorf("%w: %s", ErrUnknownExternalUser, e.Author)
+			}
+			return err
+		}
+
+		pr := &models.PullRequest{
+			ID:       uuid.New(),
+			Name:     e.Title,
+			AuthorID: authorID,
+			Status:   string(models.PRStatusOpen),
+		}
+		if err := s.prs.CreatePR(ctx, pr); err != nil {
+			return err
+		}
+
+		return s.integrationRepo.LinkPR(ctx, e.PR, pr.ID)
+	})
+	if errors.Is(err, repository.ErrDuplicate) {
+		// a concurrent delivery linked the PR first
+		return SyncUnchanged, nil
+	}
+	if err != nil {
+		if !errors.Is(err, ErrUnknownExternalUser) && !errors.Is(err, ErrReviewPolicy) {
+			s.log.Error("failed to open external PR",
+				zap.Error(err),
+				zap.String("provider", e.PR.Provider),
+				zap.String("repository", e.PR.Repository),
+				zap.Int64("number", e.PR.Number),
+			)
+		}
+		return "", err
+	}
+
+	return result, nil
+}
+
+// merge merges the linked PR; PRs opened before the integration are ignored.
+func (s *IntegrationService) merge(ctx context.Context, e *models.PREvent) (SyncResult, error) {
+	prID, err := s.integrationRepo.GetPRID(ctx, e.PR)
+	if errors.Is(err, repository.ErrNotFound) {
+		return SyncIgnored, nil
+	}
+	if err != nil {
+		s.log.Error("failed to get linked PR",
+			zap.Error(err),
+			zap.String("provider", e.PR.Provider),
+			zap.String("repository", e.PR.Repository),
+			zap.Int64("number", e.PR.Number),
+		)
+		return "", err
+	}
+
+	if _, err := s.prs.PRMerge(ctx, prID); err != nil {
+		return "", err
+	}
+
+	return SyncMerged, nil
+}
@@ -0,0 +1,125 @@
+package service_test
+
+import (
+	"testing"
+
+	"pr-service/internal/mocks"
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestIntegrationService_HandlePREvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	integrationRepo := mocks.NewMockIntegrationRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	prs := mocks.NewMockPRSyncer(ctrl)
+	core, logs := observer.New(zap.InfoLevel)
+	svc := service.NewIntegrationService(integrationRepo, userRepo, prs, service.TxManagerStub{}, zap.New(core))
+
+	ctx := t.Context()
+	ref := models.ExternalPR{Provider: models.ProviderGitHub, Repository: "octo-org/api", Number: 42}
+	authorID := uuid.New()
+
+	opened := &models.PREvent{Kind: models.PREventOpened, PR: ref, Title: "Add search", Author: "OctoCat"}
+	reopened := &models.PREvent{Kind: models.PREventReopened, PR: ref, Title: "Add search", Author: "OctoCat"}
+	closed := &models.PREvent{Kind: models.PREventClosed, PR: ref}
+	merged := &models.PREvent{Kind: models.PREventMerged, PR: ref}
+
+	t.Run("opened", func(t *testing.T) {
+		integrationRepo.EXPECT().GetPRID(ctx, ref).Return(uuid.Nil, repository.ErrNotFound)
+		integrationRepo.EXPECT().GetUserID(ctx, models.ProviderGitHub, "octocat").Return(authorID, nil)
+
+		var created *models.PullRequest
+		prs.EXPECT().CreatePR(ctx, gomock.Any()).DoAndReturn(func(_ any, pr *models.PullRequest) error {
+			created = pr
+			return nil
+		})
+		integrationRepo.EXPECT().LinkPR(ctx, ref, gomock.Any()).DoAndReturn(func(_ any, _ models.ExternalPR, prID uuid.UUID) error {
+			require.Equal(t, created.ID, prID)
+			return nil
+		})
+
+		result, err := svc.HandlePREvent(ctx, opened)
+		require.NoError(t, err)
+		require.Equal(t, service.SyncCreated, result)
+		require.Equal(t, "Add search", created.Name)
+		require.Equal(t, authorID, created.AuthorID)
+	})
+
+	t.Run("concurrent delivery", func(t *testing.T) {
+		integrationRepo.EXPECT().GetPRID(ctx, ref).Return(uuid.Nil, repository.ErrNotFound)
+		integrationRepo.EXPECT().GetUserID(ctx, models.ProviderGitHub, "octocat").Return(authorID, nil)
+		prs.EXPECT().CreatePR(ctx, gomock.Any()).Return(nil)
+		integrationRepo.EXPECT().LinkPR(ctx, ref, gomock.Any()).Return(repository.ErrDuplicate)
+
+		result, err := svc.HandlePREvent(ctx, opened)
+		require.NoError(t, err)
+		require.Equal(t, service.SyncUnchanged, result)
+	})
+
+	t.Run("unknown author", func(t *testing.T) {
+		integrationRepo.EXPECT().GetPRID(ctx, ref).Return(uuid.Nil, repository.ErrNotFound)
+		integrationRepo.EXPECT().GetUserID(ctx, models.ProviderGitHub, "octocat").Return(uuid.Nil, repository.ErrNotFound)
+
+		_, err := svc.HandlePREvent(ctx, opened)
+		require.ErrorIs(t, err, service.ErrUnknownExternalUser)
+	})
+
+	t.Run("reopened", func(t *testing.T) {
+		integrationRepo.EXPECT().GetPRID(ctx, ref).Return(uuid.New(), nil)
+
+		result, err := svc.HandlePREvent(ctx, reopened)
+		require.NoError(t, err)
+		require.Equal(t, service.SyncUnchanged, result, "the PR stayed open")
+	})
+
+	t.Run("reopened before integration", func(t *testing.T) {
+		integrationRepo.EXPECT().GetPRID(ctx, ref).Return(uuid.Nil, repository.ErrNotFound)
+		integrationRepo.EXPECT().GetUserID(ctx, models.ProviderGitHub, "octocat").Return(authorID, nil)
+		prs.EXPECT().CreatePR(ctx, gomock.Any()).Return(nil)
+		integrationRepo.EXPECT().LinkPR(ctx, ref, gomock.Any()).Return(nil)
+
+		result, err := svc.HandlePREvent(ctx, reopened)
+		require.NoError(t, err)
+		require.Equal(t, service.SyncCreated, result)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		logs.TakeAll()
+
+		result, err := svc.HandlePREvent(ctx, closed)
+		require.NoError(t, err)
+		require.Equal(t, service.SyncIgnored, result)
+
+		entries := logs.TakeAll()
+		require.Len(t, entries, 1)
+		require.Contains(t, entries[0].Message, "PRs have no closed state")
+	})
+
+	t.Run("merged", func(t *testing.T) {
+		prID := uuid.New()
+		integrationRepo.EXPECT().GetPRID(ctx, ref).Return(prID, nil)
+		prs.EXPECT().PRMerge(ctx, prID).Return(&models.PullRequest{ID: prID}, nil)
+
+		result, err := svc.HandlePREvent(ctx, merged)
+		require.NoError(t, err)
+		require.Equal(t, service.SyncMerged, result)
+	})
+
+	t.Run("merged before integration", func(t *testing.T) {
+		integrationRepo.EXPECT().GetPRID(ctx, ref).Return(uuid.Nil, repository.ErrNotFound)
+
+		result, err := svc.HandlePREvent(ctx, merged)
+		require.NoError(t, err)
+		require.Equal(t, service.SyncIgnored, result)
+	})
+}
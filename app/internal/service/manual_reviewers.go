@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// PRSetReviewers makes the given users the only reviewers of the PR.
+// Reviewers that stay keep their assignment time.
+func (s *PRService) PRSetReviewers(ctx context.Context, prID uuid.UUID, ids []uuid.UUID) (*models.PullRequest, error) {
+	return s.overrideReviewers(ctx, prID, ids, func(pr *models.PullRequest) (add, remove []uuid.UUID, err error) {
+		for _, r := range pr.Reviewers {
+			if !slices.Contains(ids, r.ID) {
+				remove = append(remove, r.ID)
+			}
+		}
+		for _, id := range ids {
+			if !isReviewer(pr.Reviewers, id) {
+				add = append(add, id)
+			}
+		}
+		return add, remove, nil
+	})
+}
+
+// PRAddReviewers assigns the given users on top of the current reviewers.
+func (s *PRService) PRAddReviewers(ctx context.Context, prID uuid.UUID, ids []uuid.UUID) (*models.PullRequest, error) {
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: no reviewers given", ErrInvalidReviewer)
+	}
+
+	return s.overrideReviewers(ctx, prID, ids, func(pr *models.PullRequest) (add, remove []uuid.UUID, err error) {
+		for _, id := range ids {
+			if isReviewer(pr.Reviewers, id) {
+				return nil, nil, fmt.Errorf("%w: %s is already assigned", ErrInvalidReviewer, id)
+			}
+		}
+		return ids, nil, nil
+	})
+}
+
+// PRRemoveReviewers unassigns the given reviewers without picking replacements.
+func (s *PRService) PRRemoveReviewers(ctx context.Context, prID uuid.UUID, ids []uuid.UUID) (*models.PullRequest, error) {
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: no reviewers given", ErrInvalidReviewer)
+	}
+
+	return s.overrideReviewers(ctx, prID, ids, func(pr *models.PullRequest) (add, remove []uuid.UUID, err error) {
+		for _, id := range ids {
+			if !isReviewer(pr.Reviewers, id) {
+				return nil, nil, ErrNotAssinged
+			}
+		}
+		return nil, ids, nil
+	})
+}
+
+// overrideReviewers applies the change that diff computes from the current
+// reviewers of an open PR. Added users come from the same sources
+// pickReviewers draws from and must be available; the resulting set must
+// satisfy the review policy of the author's team.
+func (s *PRService) overrideReviewers(
+	ctx context.Context,
+	prID uuid.UUID,
+	ids []uuid.UUID,
+	diff func(pr *models.PullRequest) (add, remove []uuid.UUID, err error),
+) (*models.PullRequest, error) {
+	for i, id := range ids {
+		if slices.Contains(ids[:i], id) {
+			return nil, fmt.Errorf("%w: %s is listed twice", ErrInvalidReviewer, id)
+		}
+	}
+
+	var pr *models.PullRequest
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		pr, err = s.prRepo.GetByIDForUpdate(ctx, prID)
+		if err != nil {
+			if !errors.Is(err, repository.ErrNotFound) {
+				s.log.Error("failed to get PR",
+					zap.Error(err),
+					zap.String("pr_id", prID.String()),
+				)
+			}
+			return err
+		}
+
+		if pr.Status == string(models.PRStatusMerged) {
+			return ErrCanNotReassing
+		}
+
+		if err := s.claimVersion(ctx, pr); err != nil {
+			return err
+		}
+
+		add, remove, err := diff(pr)
+		if err != nil {
+			return err
+		}
+
+		author, err := s.userRepo.GetUserByID(ctx, pr.AuthorID)
+		if err != nil {
+			s.log.Error("failed to get author",
+				zap.Error(err),
+				zap.String("pr_id", prID.String()),
+			)
+			return err
+		}
+
+		added, err := s.manualReviewers(ctx, pr, author, add)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range remove {
+			if err := s.prRepo.RemoveReviewer(ctx, prID, id); err != nil {
+				if errors.Is(err, repository.ErrNotFound) {
+					return ErrNotAssinged
+				}
+				s.log.Error("failed to remove reviewer",
+					zap.Error(err),
+					zap.String("pr_id", prID.String()),
+					zap.String("user_id", id.String()),
+				)
+				return err
+			}
+		}
+		for _, r := range added {
+			if err := s.prRepo.AddReviewer(ctx, prID, r); err != nil {
+				if errors.Is(err, repository.ErrDuplicate) {
+					return fmt.Errorf("%w: %s is already assigned", ErrInvalidReviewer, r.ID)
+				}
+				s.log.Error("failed to add reviewer",
+					zap.Error(err),
+					zap.String("pr_id", prID.String()),
+					zap.String("user_id", r.ID.String()),
+				)
+				return err
+			}
+		}
+
+		pr.Reviewers = slices.DeleteFunc(pr.Reviewers, func(r *models.PRReviewer) bool {
+			return slices.Contains(remove, r.ID)
+		})
+		pr.Reviewers = append(pr.Reviewers, added...)
+
+		if err := s.checkPolicy(ctx, author, pr.Reviewers); err != nil {
+			return err
+		}
+
+		s.publishReviewers(ctx, &models.ReviewersChange{PRID: pr.ID, Added: add, Removed: remove})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("reviewers overridden",
+		zap.String("pr_id", prID.String()),
+		zap.Int("reviewers_count", len(pr.Reviewers)),
+	)
+
+	return pr, nil
+}
+
+// manualReviewers checks the users chosen as reviewers of the PR and
+// records which pool each of them belongs to. They are drawn from the same
+// pools as picked reviewers: the owners in the CODEOWNERS rules of the
+// author's team, whichever files they cover since the changed files are not
+// stored, then the author's team and the teams it falls back to. Users who
+// are inactive, out of office or declined the PR can not be chosen.
+func (s *PRService) manualReviewers(ctx context.Context, pr *models.PullRequest, author *models.User, ids []uuid.UUID) ([]*models.PRReviewer, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	declined, err := s.prRepo.ListDeclined(ctx, pr.ID)
+	if err != nil {
+		s.log.Error("failed to get declined reviewers",
+			zap.Error(err),
+			zap.String("pr_id", pr.ID.String()),
+		)
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	available := s.availableCache(ctx, now)
+	allMembers := func(teamID uuid.UUID) ([]*models.User, error) {
+		return s.userRepo.GetByTeam(ctx, teamID)
+	}
+
+	// every user reviewers are drawn from, with the first pool they are found in
+	members := make(map[uuid.UUID]*models.User)
+	pools := make(map[uuid.UUID]*models.PRReviewer)
+	add := func(u *models.User, pool models.ReviewerPool, poolTeam string) bool {
+		if _, ok := pools[u.ID]; !ok {
+			members[u.ID] = u
+			pools[u.ID] = &models.PRReviewer{ID: u.ID, PRID: pr.ID, AssignedAt: now, Pool: pool, PoolTeam: poolTeam}
+		}
+		return true
+	}
+
+	owners, err := s.ruleOwners(ctx, *author.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	ownerCandidates, err := s.ownerCandidates(ctx, owners, allMembers)
+	if err != nil {
+		return nil, err
+	}
+	for _, users := range ownerCandidates {
+		for _, u := range users {
+			add(u, models.ReviewerPoolCodeOwners, "")
+		}
+	}
+
+	if err := s.candidates(ctx, *author.TeamID, allMembers, add); err != nil {
+		return nil, err
+	}
+
+	reviewers := make([]*models.PRReviewer, len(ids))
+	for i, id := range ids {
+		u, ok := members[id]
+		switch {
+		case id == pr.AuthorID:
+			return nil, fmt.Errorf("%w: the author can not review the PR", ErrInvalidReviewer)
+		case !ok:
+			return nil, fmt.Errorf("%w: %s is not a code owner, a member of the author's team or the teams it falls back to", ErrInvalidReviewer, id)
+		case slices.Contains(declined, id):
+			return nil, fmt.Errorf("%w: %s declined the PR", ErrInvalidReviewer, id)
+		}
+
+		users, err := available(*u.TeamID)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.ContainsFunc(users, func(a *models.User) bool { return a.ID == id }) {
+			return nil, fmt.Errorf("%w: %s is inactive or out of office", ErrInvalidReviewer, id)
+		}
+
+		reviewers[i] = pools[id]
+	}
+
+	return reviewers, nil
+}
+
+// chosenReplacement checks newID as the reviewer of the PR instead of oldID.
+func (s *PRService) chosenReplacement(ctx context.Context, pr *models.PullRequest, author *models.User, oldID, newID uuid.UUID) (*models.PRReviewer, error) {
+	if isReviewer(pr.Reviewers, newID) {
+		return nil, fmt.Errorf("%w: %s is already assigned", ErrInvalidReviewer, newID)
+	}
+
+	chosen, err := s.manualReviewers(ctx, pr, author, []uuid.UUID{newID})
+	if err != nil {
+		return nil, err
+	}
+
+	reviewers := slices.DeleteFunc(slices.Clone(pr.Reviewers), func(r *models.PRReviewer) bool { return r.ID == oldID })
+	if err := s.checkPolicy(ctx, author, append(reviewers, chosen...)); err != nil {
+		return nil, err
+	}
+
+	return chosen[0], nil
+}
+
+// checkPolicy returns ErrReviewPolicy when the reviewers do not satisfy the
+// review policy of the author's team.
+func (s *PRService) checkPolicy(ctx context.Context, author *models.User, reviewers []*models.PRReviewer) error {
+	policy, err := s.reviewPolicy(ctx, *author.TeamID)
+	if err != nil || policy == nil {
+		return err
+	}
+
+	roles := make([]models.Role, 0, len(reviewers))
+	for _, r := range reviewers {
+		u, err := s.userRepo.GetUserByID(ctx, r.ID)
+		if err != nil {
+			return err
+		}
+		roles = append(roles, u.Role)
+	}
+
+	if !policy.SatisfiedBy(roles...) {
+		return fmt.Errorf("%w: no %s or above among the reviewers", ErrReviewPolicy, policy.MinRole)
+	}
+	return nil
+}
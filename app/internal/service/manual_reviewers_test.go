@@ -0,0 +1,199 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/repository/memory"
+	"pr-service/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPRService_ManualReviewers(t *testing.T) {
+	ctx := t.Context()
+	svc := newMemoryPRService()
+
+	team := &models.Team{
+		Name: "backend",
+		Members: []*models.User{
+			{Name: "author", IsActive: true},
+			{Name: "r1", IsActive: true},
+			{Name: "r2", IsActive: true},
+			{Name: "inactive", IsActive: false},
+		},
+	}
+	require.NoError(t, svc.TeamAdd(ctx, team))
+	author, r1, r2, inactive := team.Members[0], team.Members[1], team.Members[2], team.Members[3]
+
+	web := &models.Team{Name: "web", Members: []*models.User{{Name: "w1", IsActive: true}}}
+	require.NoError(t, svc.TeamAdd(ctx, web))
+	platform := &models.Team{Name: "platform", Members: []*models.User{{Name: "p1", IsActive: true}}}
+	require.NoError(t, svc.TeamAdd(ctx, platform))
+	outsider, p1 := web.Members[0], platform.Members[0]
+
+	_, err := svc.TeamSetFallbackPools(ctx, "backend", []string{"platform"})
+	require.NoError(t, err)
+
+	pr := &models.PullRequest{ID: uuid.New(), Name: "change", AuthorID: author.ID, Status: string(models.PRStatusOpen)}
+	require.NoError(t, svc.CreatePR(ctx, pr))
+	require.ElementsMatch(t, []uuid.UUID{r1.ID, r2.ID}, reviewerIDs(pr.Reviewers))
+
+	// reviewers assigned at the same moment are listed in no particular order
+	t.Run("invalid reviewers", func(t *testing.T) {
+		for _, ids := range [][]uuid.UUID{
+			{r1.ID, r1.ID},
+			{author.ID},
+			{inactive.ID},
+			{outsider.ID},
+			{uuid.New()},
+		} {
+			_, err := svc.PRSetReviewers(ctx, pr.ID, ids)
+			require.ErrorIs(t, err, service.ErrInvalidReviewer, ids)
+		}
+
+		_, err := svc.PRAddReviewers(ctx, pr.ID, []uuid.UUID{r1.ID})
+		require.ErrorIs(t, err, service.ErrInvalidReviewer, "already assigned")
+
+		_, err = svc.PRRemoveReviewers(ctx, pr.ID, []uuid.UUID{outsider.ID})
+		require.ErrorIs(t, err, service.ErrNotAssinged)
+
+		_, err = svc.PRSetReviewers(ctx, uuid.New(), nil)
+		require.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run("set keeps the reviewers that stay", func(t *testing.T) {
+		got, err := svc.PRSetReviewers(ctx, pr.ID, []uuid.UUID{r2.ID, p1.ID})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uuid.UUID{r2.ID, p1.ID}, reviewerIDs(got.Reviewers))
+		require.Equal(t, p1.ID, got.Reviewers[1].ID, "added reviewers come last")
+		require.Equal(t, models.ReviewerPoolFallback, got.Reviewers[1].Pool)
+		require.Equal(t, "platform", got.Reviewers[1].PoolTeam)
+	})
+
+	t.Run("add and remove", func(t *testing.T) {
+		got, err := svc.PRAddReviewers(ctx, pr.ID, []uuid.UUID{r1.ID})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uuid.UUID{r2.ID, p1.ID, r1.ID}, reviewerIDs(got.Reviewers))
+		require.Equal(t, r1.ID, got.Reviewers[2].ID, "added reviewers come last")
+		require.Equal(t, models.ReviewerPoolTeam, got.Reviewers[2].Pool)
+
+		got, err = svc.PRRemoveReviewers(ctx, pr.ID, []uuid.UUID{r2.ID, p1.ID})
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{r1.ID}, reviewerIDs(got.Reviewers))
+	})
+
+	t.Run("reassign to a chosen or not excluded user", func(t *testing.T) {
+		for _, id := range []uuid.UUID{r1.ID, author.ID, inactive.ID, outsider.ID} {
+			_, _, err := svc.PRReassign(ctx, pr.ID, r1.ID, service.ReassignOptions{NewUserID: id})
+			require.ErrorIs(t, err, service.ErrInvalidReviewer, id)
+		}
+
+		_, _, err := svc.PRReassign(ctx, pr.ID, r1.ID, service.ReassignOptions{Exclude: []uuid.UUID{r2.ID, p1.ID}})
+		require.ErrorIs(t, err, service.ErrNoAvailableReviewer)
+
+		got, _, err := svc.PRReassign(ctx, pr.ID, r1.ID, service.ReassignOptions{Exclude: []uuid.UUID{r2.ID}})
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{p1.ID}, reviewerIDs(got.Reviewers))
+		require.Equal(t, models.ReviewerPoolFallback, got.Reviewers[0].Pool)
+
+		got, _, err = svc.PRReassign(ctx, pr.ID, p1.ID, service.ReassignOptions{NewUserID: r1.ID, Exclude: []uuid.UUID{r1.ID}})
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{r1.ID}, reviewerIDs(got.Reviewers))
+		require.Equal(t, models.ReviewerPoolTeam, got.Reviewers[0].Pool)
+	})
+
+	t.Run("review policy", func(t *testing.T) {
+		_, err := svc.TeamSetReviewPolicy(ctx, "backend", &models.ReviewPolicy{MinRole: models.RoleMaintainer})
+		require.NoError(t, err)
+		_, err = svc.UsersSetRole(ctx, r2.ID, models.RoleMaintainer)
+		require.NoError(t, err)
+
+		_, err = svc.PRSetReviewers(ctx, pr.ID, []uuid.UUID{p1.ID})
+		require.ErrorIs(t, err, service.ErrReviewPolicy)
+
+		got, err := svc.PRAddReviewers(ctx, pr.ID, []uuid.UUID{r2.ID})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uuid.UUID{r1.ID, r2.ID}, reviewerIDs(got.Reviewers))
+	})
+
+	t.Run("merged", func(t *testing.T) {
+		_, err := svc.PRMerge(ctx, pr.ID)
+		require.NoError(t, err)
+
+		_, err = svc.PRRemoveReviewers(ctx, pr.ID, []uuid.UUID{r1.ID})
+		require.ErrorIs(t, err, service.ErrCanNotReassing)
+	})
+}
+
+func TestPRService_ManualReviewerSources(t *testing.T) {
+	ctx := t.Context()
+	store := memory.NewStore()
+	svc := service.NewPRService(
+		memory.NewTeamRepository(store),
+		memory.NewUserRepository(store),
+		memory.NewPRRepository(store, clk),
+		nil,
+		memory.NewTxManager(store),
+		clk,
+		zap.NewNop(),
+	)
+
+	team := &models.Team{
+		Name: "backend",
+		Members: []*models.User{
+			{Name: "author", IsActive: true},
+			{Name: "away", IsActive: true},
+			{Name: "mate", IsActive: true},
+		},
+	}
+	require.NoError(t, svc.TeamAdd(ctx, team))
+	author, away, mate := team.Members[0], team.Members[1], team.Members[2]
+
+	docs := &models.Team{Name: "docs", Members: []*models.User{{Name: "writer", IsActive: true}}}
+	require.NoError(t, svc.TeamAdd(ctx, docs))
+	web := &models.Team{Name: "web", Members: []*models.User{{Name: "w1", IsActive: true}}}
+	require.NoError(t, svc.TeamAdd(ctx, web))
+	writer, outsider := docs.Members[0], web.Members[0]
+
+	_, err := svc.TeamSetCodeOwners(ctx, "backend", "docs/ @"+writer.ID.String())
+	require.NoError(t, err)
+
+	now := clk.Now()
+	require.NoError(t, memory.NewAvailabilityRepository(store).Create(ctx, &models.Availability{
+		UserID:   away.ID,
+		StartsAt: now.Add(-time.Hour),
+		EndsAt:   now.Add(time.Hour),
+	}))
+
+	pr := &models.PullRequest{ID: uuid.New(), Name: "change", AuthorID: author.ID, Status: string(models.PRStatusOpen)}
+	require.NoError(t, svc.CreatePR(ctx, pr))
+	require.Equal(t, []uuid.UUID{mate.ID}, reviewerIDs(pr.Reviewers))
+
+	_, err = svc.PRAddReviewers(ctx, pr.ID, []uuid.UUID{away.ID})
+	require.ErrorIs(t, err, service.ErrInvalidReviewer, "out of office")
+
+	_, err = svc.PRAddReviewers(ctx, pr.ID, []uuid.UUID{outsider.ID})
+	require.ErrorIs(t, err, service.ErrInvalidReviewer, "not a code owner")
+
+	// an owner from another team can be chosen like a picked one
+	got, err := svc.PRAddReviewers(ctx, pr.ID, []uuid.UUID{writer.ID})
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{mate.ID, writer.ID}, reviewerIDs(got.Reviewers))
+	require.Equal(t, models.ReviewerPoolCodeOwners, got.Reviewers[1].Pool)
+
+	_, _, err = svc.PRReassign(ctx, pr.ID, mate.ID, service.ReassignOptions{NewUserID: away.ID})
+	require.ErrorIs(t, err, service.ErrInvalidReviewer, "out of office")
+}
+
+func reviewerIDs(reviewers []*models.PRReviewer) []uuid.UUID {
+	ids := make([]uuid.UUID, len(reviewers))
+	for i, r := range reviewers {
+		ids[i] = r.ID
+	}
+	return ids
+}
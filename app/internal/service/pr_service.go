@@ -5,7 +5,10 @@ package service
 import (
 	"context"
 	"errors"
+	"slices"
+	"time"
 
+	"pr-service/internal/clock"
 	"pr-service/internal/models"
 	"pr-service/internal/repository"
 
@@ -22,6 +25,36 @@ type TeamRepository interface {
 
 	// Получить команду по имени
 	GetByName(ctx context.Context, name string) (*models.Team, error)
+
+	// Установить SLA ревью команды, nil — без SLA
+	SetReviewSLA(ctx context.Context, id uuid.UUID, sla *models.ReviewSLA) error
+
+	// Установить расписание дайджеста команды, nil — без дайджеста
+	SetDigestSchedule(ctx context.Context, id uuid.UUID, d *models.DigestSchedule) error
+
+	// Заменить правила владения путями (CODEOWNERS) команды
+	SetCodeOwners(ctx context.Context, id uuid.UUID, rules []models.CodeOwnerRule) error
+
+	// Получить правила владения путями команды в порядке CODEOWNERS
+	GetCodeOwners(ctx context.Context, id uuid.UUID) ([]models.CodeOwnerRule, error)
+
+	// Заменить резервные команды, к которым обращаются по порядку, когда в команде некому ревьюить
+	SetFallbackPools(ctx context.Context, id uuid.UUID, poolIDs []uuid.UUID) error
+
+	// Получить резервные команды (ID и имя) по порядку
+	GetFallbackPools(ctx context.Context, id uuid.UUID) ([]*models.Team, error)
+
+	// Установить политику ревью команды, nil — без политики
+	SetReviewPolicy(ctx context.Context, id uuid.UUID, p *models.ReviewPolicy) error
+
+	// Установить родительскую команду, nil — команда верхнего уровня
+	SetParent(ctx context.Context, id uuid.UUID, parentID *uuid.UUID) error
+
+	// Заблокировать иерархию команд до конца транзакции
+	LockHierarchy(ctx context.Context) error
+
+	// Получить команду и все команды под ней (ID, имя, родитель), родителей раньше детей
+	GetSubtree(ctx context.Context, id uuid.UUID) ([]*models.Team, error)
 }
 
 type UserRepository interface {
@@ -31,14 +64,26 @@ type UserRepository interface {
 	// Получить всех активных пользователей команды
 	GetActiveByTeam(ctx context.Context, teamID uuid.UUID) ([]*models.User, error)
 
+	// Получить активных пользователей команды, не отсутствующих в момент at
+	GetAvailableByTeam(ctx context.Context, teamID uuid.UUID, at time.Time) ([]*models.User, error)
+
 	// Получить всех пользователей команды
 	GetByTeam(ctx context.Context, teamID uuid.UUID) ([]*models.User, error)
 
+	// Получить всех пользователей команды и её подкоманд
+	GetBySubtree(ctx context.Context, teamID uuid.UUID) ([]*models.User, error)
+
+	// Получить пользователей с email, без учёта регистра
+	ListByEmail(ctx context.Context, email string) ([]*models.User, error)
+
 	// Создать нового пользователя
 	Create(ctx context.Context, user *models.User) error
 
 	// Обновить активность пользователя
 	UpdateActive(ctx context.Context, id uuid.UUID, active bool) error
+
+	// Обновить роль пользователя
+	UpdateRole(ctx context.Context, id uuid.UUID, role models.Role) error
 }
 
 type PRRepository interface {
@@ -48,11 +93,29 @@ type PRRepository interface {
 	// Получить пулл-реквест по ID
 	GetByID(ctx context.Context, id uuid.UUID) (*models.PullRequest, error)
 
-	// Назначить ревьюеров
-	AssignReviewers(ctx context.Context, prID uuid.UUID, reviewers []uuid.UUID) error
+	// Получить пулл-реквест по ID и заблокировать его строку до конца транзакции
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*models.PullRequest, error)
+
+	// Назначить ревьюеров, вместе с пулом, из которого они выбраны
+	AssignReviewers(ctx context.Context, prID uuid.UUID, reviewers []*models.PRReviewer) error
 
 	// Заменить одного ревьюера другим
-	ReplaceReviewer(ctx context.Context, prID, oldID, newID uuid.UUID) error
+	ReplaceReviewer(ctx context.Context, prID, oldID uuid.UUID, reviewer *models.PRReviewer) error
+
+	// Заменить ревьюера, отказавшегося от ревью, с записью причины отказа; reviewer nil — снять без замены
+	DeclineReviewer(ctx context.Context, prID, oldID uuid.UUID, reviewer *models.PRReviewer, reason models.DeclineReason) error
+
+	// Получить пользователей, отказавшихся от ревью пулл-реквеста
+	ListDeclined(ctx context.Context, prID uuid.UUID) ([]uuid.UUID, error)
+
+	// Добавить ревьюера, ErrDuplicate — если он уже назначен
+	AddReviewer(ctx context.Context, prID uuid.UUID, reviewer *models.PRReviewer) error
+
+	// Снять ревьюера с пулл-реквеста без записи о переназначении
+	RemoveReviewer(ctx context.Context, prID, id uuid.UUID) error
+
+	// Увеличить версию пулл-реквеста, если она всё ещё равна version; ErrNotFound — если нет
+	BumpVersion(ctx context.Context, id uuid.UUID, version int64) error
 
 	// Замерджить пулл-реквест
 	Merge(ctx context.Context, id uuid.UUID) error
@@ -65,14 +128,22 @@ type TxManager interface {
 	Do(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
+type ReviewerPublisher interface {
+	// Сообщить об изменении ревьюеров PR, вызывается после коммита
+	PublishReviewersChanged(ctx context.Context, change *models.ReviewersChange)
+}
+
 type PRService struct {
 	teamRepo TeamRepository
 	userRepo UserRepository
 	prRepo   PRRepository
 
+	reviewers ReviewerPublisher // nil when reviewer changes are not published
+
 	trManager TxManager
 
-	log *zap.Logger
+	clock clock.Clock
+	log   *zap.Logger
 }
 
 type TxManagerStub struct{}
@@ -85,19 +156,27 @@ func NewPRService(
 	teamRepo TeamRepository,
 	userRepo UserRepository,
 	prRepo PRRepository,
+	reviewers ReviewerPublisher,
 	trManager TxManager,
+	clk clock.Clock,
 	log *zap.Logger,
 ) *PRService {
 	return &PRService{
 		teamRepo:  teamRepo,
 		userRepo:  userRepo,
 		prRepo:    prRepo,
+		reviewers: reviewers,
 		trManager: trManager,
+		clock:     clk,
 		log:       log,
 	}
 }
 
 func (s *PRService) CreatePR(ctx context.Context, pr *models.PullRequest) error {
+	if pr.CreatedAt.IsZero() {
+		pr.CreatedAt = s.clock.Now()
+	}
+
 	return s.trManager.Do(ctx, func(ctx context.Context) error {
 		err := s.prRepo.Create(ctx, pr)
 		if err != nil {
@@ -117,27 +196,29 @@ func (s *PRService) CreatePR(ctx context.Context, pr *models.PullRequest) error
 			return err
 		}
 
-		activeUsers, err := s.userRepo.GetActiveByTeam(ctx, *author.TeamID)
+		reviewers, err := s.pickReviewers(ctx, pr, author, s.clock.Now())
 		if err != nil {
-			s.log.Error("failed to get active users",
+			if errors.Is(err, ErrReviewPolicy) {
+				s.log.Warn("review policy not satisfied",
+					zap.Error(err),
+					zap.String("pr_id", pr.ID.String()),
+				)
+				return err
+			}
+			s.log.Error("failed to pick reviewers",
 				zap.Error(err),
 				zap.String("pr_id", pr.ID.String()),
 			)
 			return err
 		}
 
-		// slice to 2
-		if len(activeUsers) > 2 {
-			activeUsers = activeUsers[:2]
-		}
-
-		uuids := make([]uuid.UUID, len(activeUsers))
-
-		for i, reviewer := range activeUsers {
-			uuids[i] = reviewer.ID
+		if len(reviewers) == 0 {
+			s.log.Warn("no reviewers available for PR",
+				zap.String("pr_id", pr.ID.String()),
+			)
 		}
 
-		err = s.prRepo.AssignReviewers(ctx, pr.ID, uuids)
+		err = s.prRepo.AssignReviewers(ctx, pr.ID, reviewers)
 		if err != nil {
 			s.log.Error("failed to assign reviewers",
 				zap.Error(err),
@@ -146,6 +227,13 @@ func (s *PRService) CreatePR(ctx context.Context, pr *models.PullRequest) error
 			return err
 		}
 
+		pr.Reviewers = reviewers
+		added := make([]uuid.UUID, len(reviewers))
+		for i, r := range reviewers {
+			added[i] = r.ID
+		}
+		s.publishReviewers(ctx, &models.ReviewersChange{PRID: pr.ID, Added: added})
+
 		s.log.Info("PR created, reviewers assigned",
 			zap.String("pr_id", pr.ID.String()),
 		)
@@ -158,7 +246,7 @@ func (s *PRService) PRMerge(ctx context.Context, id uuid.UUID) (*models.PullRequ
 	pr := &models.PullRequest{}
 	txErr := s.trManager.Do(ctx, func(ctx context.Context) error {
 		var err error
-		pr, err = s.prRepo.GetByID(ctx, id)
+		pr, err = s.prRepo.GetByIDForUpdate(ctx, id)
 		if err != nil {
 			s.log.Error("failed to get PR",
 				zap.Error(err),
@@ -171,7 +259,11 @@ func (s *PRService) PRMerge(ctx context.Context, id uuid.UUID) (*models.PullRequ
 			s.log.Info("PR already merged",
 				zap.String("pr_id", id.String()),
 			)
-			return nil
+			return checkVersion(ctx, pr)
+		}
+
+		if err := s.claimVersion(ctx, pr); err != nil {
+			return err
 		}
 
 		err = s.prRepo.Merge(ctx, id)
@@ -183,6 +275,16 @@ func (s *PRService) PRMerge(ctx context.Context, id uuid.UUID) (*models.PullRequ
 			return err
 		}
 
+		// re-read for the merge time and the bumped version
+		pr, err = s.prRepo.GetByID(ctx, id)
+		if err != nil {
+			s.log.Error("failed to get merged PR",
+				zap.Error(err),
+				zap.String("pr_id", id.String()),
+			)
+			return err
+		}
+
 		s.log.Info("PR merged",
 			zap.String("pr_id", id.String()),
 		)
@@ -196,11 +298,34 @@ func (s *PRService) PRMerge(ctx context.Context, id uuid.UUID) (*models.PullRequ
 	return pr, nil
 }
 
-func (s *PRService) PRReassign(ctx context.Context, prID uuid.UUID, oldUserID uuid.UUID) (*models.PullRequest, error) {
+// ReassignOptions narrow down who replaces the reviewer in PRReassign.
+type ReassignOptions struct {
+	NewUserID uuid.UUID   // the replacement; uuid.Nil picks one
+	Exclude   []uuid.UUID // users never picked as the replacement
+}
+
+// PRReassign replaces the reviewer with opts.NewUserID, checked like a manual
+// reviewer, or with a picked teammate that is not in opts.Exclude and has
+// not declined the PR. It returns the PR and the new reviewer.
+func (s *PRService) PRReassign(ctx context.Context, prID uuid.UUID, oldUserID uuid.UUID, opts ReassignOptions) (*models.PullRequest, *models.PRReviewer, error) {
+	return s.reassign(ctx, prID, oldUserID, opts, "")
+}
+
+// reassign is PRReassign that records the replacement as a decline of the
+// old reviewer when declineReason is set. The replacement is nil when the
+// declined reviewer is removed without one.
+func (s *PRService) reassign(
+	ctx context.Context,
+	prID uuid.UUID,
+	oldUserID uuid.UUID,
+	opts ReassignOptions,
+	declineReason models.DeclineReason,
+) (*models.PullRequest, *models.PRReviewer, error) {
 	pr := &models.PullRequest{}
+	var replacement *models.PRReviewer
 	trErr := s.trManager.Do(ctx, func(ctx context.Context) error {
 		var err error
-		pr, err = s.prRepo.GetByID(ctx, prID)
+		pr, err = s.prRepo.GetByIDForUpdate(ctx, prID)
 		if err != nil {
 			s.log.Error("failed to get PR",
 				zap.Error(err),
@@ -232,6 +357,10 @@ func (s *PRService) PRReassign(ctx context.Context, prID uuid.UUID, oldUserID uu
 			return ErrNotAssinged
 		}
 
+		if err := s.claimVersion(ctx, pr); err != nil {
+			return err
+		}
+
 		author, err := s.userRepo.GetUserByID(ctx, pr.AuthorID)
 		if err != nil {
 			s.log.Error("failed to get author",
@@ -241,45 +370,58 @@ func (s *PRService) PRReassign(ctx context.Context, prID uuid.UUID, oldUserID uu
 			return err
 		}
 
-		users, err := s.userRepo.GetActiveByTeam(ctx, *author.TeamID)
+		if opts.NewUserID != uuid.Nil {
+			replacement, err = s.chosenReplacement(ctx, pr, author, oldUserID, opts.NewUserID)
+		} else {
+			var declined []uuid.UUID
+			declined, err = s.prRepo.ListDeclined(ctx, prID)
+			if err != nil {
+				s.log.Error("failed to get declined reviewers",
+					zap.Error(err),
+					zap.String("pr_id", prID.String()),
+				)
+				return err
+			}
+
+			exclude := append(slices.Clone(opts.Exclude), declined...)
+			replacement, err = s.pickReplacement(ctx, pr, author, oldUserID, exclude, s.clock.Now())
+		}
+		if declineReason != "" && opts.NewUserID == uuid.Nil && (err == nil && replacement == nil || errors.Is(err, ErrReviewPolicy)) {
+			// nobody can take over, the decline stands anyway
+			replacement = nil
+			return s.declineUnreplaced(ctx, pr, oldUserID, declineReason)
+		}
 		if err != nil {
-			s.log.Error("failed to get active users",
+			if errors.Is(err, ErrInvalidReviewer) {
+				return err
+			}
+			if errors.Is(err, ErrReviewPolicy) {
+				s.log.Warn("review policy not satisfied",
+					zap.Error(err),
+					zap.String("pr_id", prID.String()),
+				)
+				return err
+			}
+			s.log.Error("failed to pick replacement reviewer",
 				zap.Error(err),
 				zap.String("pr_id", prID.String()),
 			)
 			return err
 		}
 
-		// Search for another active user
-		var newUserID uuid.UUID
-		for _, u := range users {
-			if u.ID != oldUserID {
-				alreadyReviewerinPR := false
-
-				for _, r := range pr.Reviewers {
-					if r.ID == u.ID {
-						alreadyReviewerinPR = true
-						break
-					}
-				}
-
-				if alreadyReviewerinPR {
-					continue
-				}
-
-				newUserID = u.ID
-				break
-			}
-		}
-
-		if newUserID == uuid.Nil {
+		if replacement == nil {
 			s.log.Warn("no replacement reviewer found",
 				zap.String("pr_id", prID.String()),
 			)
 			return ErrNoAvailableReviewer
 		}
+		newUserID := replacement.ID
 
-		err = s.prRepo.ReplaceReviewer(ctx, prID, oldUserID, newUserID)
+		if declineReason != "" {
+			err = s.prRepo.DeclineReviewer(ctx, prID, oldUserID, replacement, declineReason)
+		} else {
+			err = s.prRepo.ReplaceReviewer(ctx, prID, oldUserID, replacement)
+		}
 		if err != nil {
 			s.log.Error("failed to replace reviewer",
 				zap.Error(err),
@@ -297,11 +439,13 @@ func (s *PRService) PRReassign(ctx context.Context, prID uuid.UUID, oldUserID uu
 			}
 		}
 
-		newReviewers = append(newReviewers, &models.PRReviewer{
-			ID:   newUserID,
-			PRID: pr.ID,
+		pr.Reviewers = append(newReviewers, replacement)
+
+		s.publishReviewers(ctx, &models.ReviewersChange{
+			PRID:    pr.ID,
+			Added:   []uuid.UUID{newUserID},
+			Removed: []uuid.UUID{oldUserID},
 		})
-		pr.Reviewers = newReviewers
 
 		s.log.Info("reviewer replaced successfully",
 			zap.String("pr_id", prID.String()),
@@ -313,14 +457,70 @@ func (s *PRService) PRReassign(ctx context.Context, prID uuid.UUID, oldUserID uu
 	})
 
 	if trErr != nil {
-		return nil, trErr
+		return nil, nil, trErr
 	}
 
-	return pr, nil
+	return pr, replacement, nil
+}
+
+// declineUnreplaced removes the reviewer who declined the PR when nobody
+// can take over, recording the decline.
+func (s *PRService) declineUnreplaced(ctx context.Context, pr *models.PullRequest, userID uuid.UUID, reason models.DeclineReason) error {
+	if err := s.prRepo.DeclineReviewer(ctx, pr.ID, userID, nil, reason); err != nil {
+		s.log.Error("failed to remove declined reviewer",
+			zap.Error(err),
+			zap.String("pr_id", pr.ID.String()),
+			zap.String("user_id", userID.String()),
+		)
+		return err
+	}
+
+	pr.Reviewers = slices.DeleteFunc(pr.Reviewers, func(r *models.PRReviewer) bool { return r.ID == userID })
+
+	s.publishReviewers(ctx, &models.ReviewersChange{
+		PRID:    pr.ID,
+		Removed: []uuid.UUID{userID},
+	})
+
+	s.log.Warn("no replacement reviewer found, declined reviewer removed",
+		zap.String("pr_id", pr.ID.String()),
+		zap.String("user_id", userID.String()),
+	)
+
+	return nil
+}
+
+// publishReviewers hands the change to the publisher once the transaction
+// commits, so a rolled back change is never published.
+func (s *PRService) publishReviewers(ctx context.Context, change *models.ReviewersChange) {
+	if s.reviewers == nil || len(change.Added)+len(change.Removed) == 0 {
+		return
+	}
+
+	AfterCommit(ctx, func() {
+		s.reviewers.PublishReviewersChanged(context.WithoutCancel(ctx), change)
+	})
 }
 
 func (s *PRService) TeamAdd(ctx context.Context, team *models.Team) error {
+	if err := validateSLA(team.ReviewSLA); err != nil {
+		return err
+	}
+	if err := validateDigest(team.Digest); err != nil {
+		return err
+	}
+	if err := validatePolicy(team.Policy); err != nil {
+		return err
+	}
+	if err := validateRoles(team.Members); err != nil {
+		return err
+	}
+
 	return s.trManager.Do(ctx, func(ctx context.Context) error {
+		if err := s.resolveParent(ctx, team); err != nil {
+			return err
+		}
+
 		err := s.teamRepo.Create(ctx, team)
 		if err != nil {
 			if errors.Is(err, repository.ErrDuplicate) {
@@ -358,9 +558,7 @@ func (s *PRService) TeamAdd(ctx context.Context, team *models.Team) error {
 }
 
 func (s *PRService) TeamGet(ctx context.Context, teamName string) (*models.Team, error) {
-	team := &models.Team{}
-	var err error
-	team, err = s.teamRepo.GetByName(ctx, teamName)
+	team, err := s.loadTeam(ctx, teamName)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			s.log.Warn("team not found",
@@ -368,33 +566,163 @@ func (s *PRService) TeamGet(ctx context.Context, teamName string) (*models.Team,
 			)
 			return nil, ErrNotFound
 		}
-		s.log.Error("failed to get team",
+		return nil, err
+	}
+
+	s.log.Info("team found",
+		zap.String("team_name", teamName),
+		zap.String("team_id", team.ID.String()),
+	)
+
+	return team, nil
+}
+
+// loadTeam returns the team with its members, fallback pools and parent.
+func (s *PRService) loadTeam(ctx context.Context, teamName string) (*models.Team, error) {
+	team, err := s.teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			s.log.Error("failed to get team",
+				zap.Error(err),
+				zap.String("team_name", teamName),
+			)
+		}
+		return nil, err
+	}
+
+	team.Members, err = s.userRepo.GetByTeam(ctx, team.ID)
+	if err != nil {
+		s.log.Error("failed to get team members",
 			zap.Error(err),
 			zap.String("team_name", teamName),
+			zap.String("team_id", team.ID.String()),
 		)
 		return nil, err
 	}
 
-	members, err := s.userRepo.GetByTeam(ctx, team.ID)
+	team.Fallback, err = s.fallbackNames(ctx, team.ID)
 	if err != nil {
-		s.log.Error("failed to get team members",
+		s.log.Error("failed to get team fallback pools",
 			zap.Error(err),
 			zap.String("team_name", teamName),
-			zap.String("team_id", team.ID.String()),
 		)
 		return nil, err
 	}
 
-	team.Members = members
+	team.Parent, err = s.parentName(ctx, team)
+	if err != nil {
+		s.log.Error("failed to get parent team",
+			zap.Error(err),
+			zap.String("team_name", teamName),
+		)
+		return nil, err
+	}
 
-	s.log.Info("team found",
+	return team, nil
+}
+
+// TeamSetReviewSLA sets the review SLA of the team; nil removes it.
+func (s *PRService) TeamSetReviewSLA(ctx context.Context, teamName string, sla *models.ReviewSLA) (*models.Team, error) {
+	if err := validateSLA(sla); err != nil {
+		return nil, err
+	}
+
+	var team *models.Team
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		team, err = s.loadTeam(ctx, teamName)
+		if err != nil {
+			return err
+		}
+
+		if err := s.teamRepo.SetReviewSLA(ctx, team.ID, sla); err != nil {
+			s.log.Error("failed to set team review sla",
+				zap.Error(err),
+				zap.String("team_name", teamName),
+			)
+			return err
+		}
+		team.ReviewSLA = sla
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("team review sla updated",
 		zap.String("team_name", teamName),
-		zap.String("team_id", team.ID.String()),
+		zap.Bool("enabled", sla != nil),
 	)
 
 	return team, nil
 }
 
+// validateSLA accepts no SLA or a whole number of seconds with a known action.
+func validateSLA(sla *models.ReviewSLA) error {
+	if sla == nil {
+		return nil
+	}
+	if sla.Timeout < time.Second || sla.Timeout%time.Second != 0 {
+		return ErrInvalidSLA
+	}
+	if sla.Action != models.SLAActionReassign && sla.Action != models.SLAActionEscalate {
+		return ErrInvalidSLA
+	}
+	return nil
+}
+
+// TeamSetDigestSchedule sets when reviewers of the team get their digest; nil turns it off.
+func (s *PRService) TeamSetDigestSchedule(ctx context.Context, teamName string, d *models.DigestSchedule) (*models.Team, error) {
+	if err := validateDigest(d); err != nil {
+		return nil, err
+	}
+
+	var team *models.Team
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		team, err = s.loadTeam(ctx, teamName)
+		if err != nil {
+			return err
+		}
+
+		if err := s.teamRepo.SetDigestSchedule(ctx, team.ID, d); err != nil {
+			s.log.Error("failed to set team digest schedule",
+				zap.Error(err),
+				zap.String("team_name", teamName),
+			)
+			return err
+		}
+		team.Digest = d
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("team digest schedule updated",
+		zap.String("team_name", teamName),
+		zap.Bool("enabled", d != nil),
+	)
+
+	return team, nil
+}
+
+// validateDigest accepts no schedule or a whole minute of the day in a named time zone.
+func validateDigest(d *models.DigestSchedule) error {
+	if d == nil {
+		return nil
+	}
+	if d.At < 0 || d.At >= 24*time.Hour || d.At%time.Minute != 0 {
+		return ErrInvalidSchedule
+	}
+	if d.Location == nil || d.Location == time.Local {
+		return ErrInvalidSchedule
+	}
+	return nil
+}
+
 func (s *PRService) TeamGetByID(ctx context.Context, teamID uuid.UUID) (*models.Team, error) {
 	return s.teamRepo.GetByID(ctx, teamID)
 }
@@ -0,0 +1,105 @@
+package service_test
+
+import (
+	"testing"
+
+	"pr-service/internal/models"
+	"pr-service/internal/repository/memory"
+	"pr-service/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newMemoryPRService() *service.PRService {
+	store := memory.NewStore()
+
+	return service.NewPRService(
+		memory.NewTeamRepository(store),
+		memory.NewUserRepository(store),
+		memory.NewPRRepository(store, clk),
+		nil,
+		memory.NewTxManager(store),
+		clk,
+		zap.NewNop(),
+	)
+}
+
+func TestPRService_MemoryFlow(t *testing.T) {
+	ctx := t.Context()
+	svc := newMemoryPRService()
+
+	team := &models.Team{
+		Name: "backend",
+		Members: []*models.User{
+			{Name: "rev1", IsActive: true},
+			{Name: "rev2", IsActive: true},
+			{Name: "rev3", IsActive: true},
+			{Name: "writer", IsActive: true}, // listed by name after the reviewers
+			{Name: "inactive", IsActive: false},
+		},
+	}
+	require.NoError(t, svc.TeamAdd(ctx, team))
+	rev1, rev2, rev3, author := team.Members[0], team.Members[1], team.Members[2], team.Members[3]
+
+	t.Run("duplicate team is rolled back", func(t *testing.T) {
+		err := svc.TeamAdd(ctx, &models.Team{Name: "backend", Members: []*models.User{{Name: "x"}}})
+		require.ErrorIs(t, err, service.ErrTeamAlreadyExists)
+
+		got, err := svc.TeamGet(ctx, "backend")
+		require.NoError(t, err)
+		require.Len(t, got.Members, len(team.Members))
+	})
+
+	pr := &models.PullRequest{
+		ID:       uuid.New(),
+		Name:     "Add search",
+		AuthorID: author.ID,
+		Status:   string(models.PRStatusOpen),
+	}
+	require.NoError(t, svc.CreatePR(ctx, pr))
+
+	t.Run("create assigns the first active teammates", func(t *testing.T) {
+		for _, u := range []*models.User{rev1, rev2} {
+			prs, err := svc.UsersGetReview(ctx, u.ID)
+			require.NoError(t, err)
+			require.Len(t, prs, 1)
+		}
+	})
+
+	t.Run("reassign picks an active teammate", func(t *testing.T) {
+		stored, _, err := svc.PRReassign(ctx, pr.ID, rev1.ID, service.ReassignOptions{})
+		require.NoError(t, err)
+
+		ids := make([]uuid.UUID, 0, len(stored.Reviewers))
+		for _, r := range stored.Reviewers {
+			ids = append(ids, r.ID)
+		}
+		require.ElementsMatch(t, []uuid.UUID{rev2.ID, rev3.ID}, ids)
+	})
+
+	t.Run("no candidate left", func(t *testing.T) {
+		for _, u := range []*models.User{rev1, author} {
+			_, err := svc.UsersSetIsActive(ctx, u.ID, false)
+			require.NoError(t, err)
+		}
+
+		_, _, err := svc.PRReassign(ctx, pr.ID, rev3.ID, service.ReassignOptions{})
+		require.ErrorIs(t, err, service.ErrNoAvailableReviewer)
+	})
+
+	t.Run("merge is idempotent and blocks reassign", func(t *testing.T) {
+		_, err := svc.PRMerge(ctx, pr.ID)
+		require.NoError(t, err)
+
+		merged, err := svc.PRMerge(ctx, pr.ID)
+		require.NoError(t, err)
+		require.Equal(t, string(models.PRStatusMerged), merged.Status)
+		require.NotNil(t, merged.MergedAt)
+		require.Len(t, merged.Reviewers, 2)
+
+		_, _, err = svc.PRReassign(ctx, pr.ID, rev3.ID, service.ReassignOptions{})
+		require.ErrorIs(t, err, service.ErrCanNotReassing)
+	})
+}
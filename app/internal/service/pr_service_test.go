@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"pr-service/internal/clock/clocktest"
 	"pr-service/internal/mocks"
 	"pr-service/internal/models"
 	"pr-service/internal/repository"
@@ -17,6 +18,8 @@ import (
 	"go.uber.org/zap"
 )
 
+var clk = clocktest.NewFake(time.Date(2025, 10, 24, 12, 0, 0, 0, time.UTC))
+
 func TestPRService_CreatePR(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -30,7 +33,9 @@ func TestPRService_CreatePR(t *testing.T) {
 		teamRepo,
 		userRepo,
 		prRepo,
+		nil,
 		tx,
+		clk,
 		zap.NewNop(),
 	)
 
@@ -73,7 +78,7 @@ func TestPRService_CreatePR(t *testing.T) {
 			GetUserByID(ctx, authorID).
 			Return(&models.User{ID: authorID, TeamID: &teamID}, nil)
 		userRepo.EXPECT().
-			GetActiveByTeam(ctx, teamID).
+			GetAvailableByTeam(ctx, teamID, clk.Now()).
 			Return(nil, errors.New("db error"))
 
 		err := svc.CreatePR(ctx, newPR)
@@ -88,11 +93,14 @@ func TestPRService_CreatePR(t *testing.T) {
 			GetUserByID(ctx, authorID).
 			Return(&models.User{ID: authorID, TeamID: &teamID}, nil)
 		userRepo.EXPECT().
-			GetActiveByTeam(ctx, teamID).
+			GetAvailableByTeam(ctx, teamID, clk.Now()).
 			Return([]*models.User{
 				{ID: uuid.New(), TeamID: &teamID, IsActive: true},
 				{ID: uuid.New(), TeamID: &teamID, IsActive: true},
 			}, nil)
+		teamRepo.EXPECT().
+			GetByID(ctx, teamID).
+			Return(&models.Team{ID: teamID, Name: "backend"}, nil)
 		prRepo.EXPECT().
 			AssignReviewers(ctx, prID, gomock.Any()).
 			Return(errors.New("assign error"))
@@ -114,14 +122,18 @@ func TestPRService_CreatePR(t *testing.T) {
 			GetUserByID(ctx, authorID).
 			Return(&models.User{ID: authorID, TeamID: &teamID}, nil)
 		userRepo.EXPECT().
-			GetActiveByTeam(ctx, teamID).
+			GetAvailableByTeam(ctx, teamID, clk.Now()).
 			Return(activeUsers, nil)
+		teamRepo.EXPECT().
+			GetByID(ctx, teamID).
+			Return(&models.Team{ID: teamID, Name: "backend"}, nil)
 		prRepo.EXPECT().
 			AssignReviewers(ctx, prID, gomock.Any()).
 			Return(nil)
 
 		err := svc.CreatePR(ctx, newPR)
 		require.NoError(t, err)
+		require.Equal(t, clk.Now(), newPR.CreatedAt)
 	})
 }
 
@@ -138,15 +150,17 @@ func TestPRService_PRMerge(t *testing.T) {
 		teamRepo,
 		userRepo,
 		prRepo,
+		nil,
 		tx,
+		clk,
 		zap.NewNop(),
 	)
 	ctx := t.Context()
 	prID := uuid.New()
 
-	t.Run("GetByID error", func(t *testing.T) {
+	t.Run("GetByIDForUpdate error", func(t *testing.T) {
 		prRepo.EXPECT().
-			GetByID(ctx, prID).
+			GetByIDForUpdate(ctx, prID).
 			Return(nil, errors.New("db error"))
 		_, err := svc.PRMerge(ctx, prID)
 		require.Error(t, err)
@@ -156,7 +170,7 @@ func TestPRService_PRMerge(t *testing.T) {
 	t.Run("already merged", func(t *testing.T) {
 		pr := &models.PullRequest{ID: prID, Status: string(models.PRStatusMerged)}
 		prRepo.EXPECT().
-			GetByID(ctx, prID).
+			GetByIDForUpdate(ctx, prID).
 			Return(pr, nil)
 		result, err := svc.PRMerge(ctx, prID)
 		require.NoError(t, err)
@@ -164,10 +178,13 @@ func TestPRService_PRMerge(t *testing.T) {
 	})
 
 	t.Run("merge fails", func(t *testing.T) {
-		pr := &models.PullRequest{ID: prID, Status: string(models.PRStatusOpen)}
+		pr := &models.PullRequest{ID: prID, Status: string(models.PRStatusOpen), Version: 1}
 		prRepo.EXPECT().
-			GetByID(ctx, prID).
+			GetByIDForUpdate(ctx, prID).
 			Return(pr, nil)
+		prRepo.EXPECT().
+			BumpVersion(ctx, prID, int64(1)).
+			Return(nil)
 		prRepo.EXPECT().
 			Merge(ctx, prID).
 			Return(errors.New("merge failed"))
@@ -178,16 +195,26 @@ func TestPRService_PRMerge(t *testing.T) {
 	})
 
 	t.Run("success merge", func(t *testing.T) {
-		pr := &models.PullRequest{ID: prID, Status: string(models.PRStatusOpen)}
-		prRepo.EXPECT().
-			GetByID(ctx, prID).
-			Return(pr, nil)
-		prRepo.EXPECT().
-			Merge(ctx, prID).
-			Return(nil)
+		pr := &models.PullRequest{ID: prID, Status: string(models.PRStatusOpen), Version: 1}
+		mergedAt := clk.Now()
+		merged := &models.PullRequest{ID: prID, Status: string(models.PRStatusMerged), MergedAt: &mergedAt, Version: 2}
+		gomock.InOrder(
+			prRepo.EXPECT().
+				GetByIDForUpdate(ctx, prID).
+				Return(pr, nil),
+			prRepo.EXPECT().
+				BumpVersion(ctx, prID, int64(1)).
+				Return(nil),
+			prRepo.EXPECT().
+				Merge(ctx, prID).
+				Return(nil),
+			prRepo.EXPECT().
+				GetByID(ctx, prID).
+				Return(merged, nil),
+		)
 		result, err := svc.PRMerge(ctx, prID)
 		require.NoError(t, err)
-		require.Equal(t, pr, result)
+		require.Equal(t, merged, result)
 	})
 }
 
@@ -204,7 +231,9 @@ func TestPRService_PRReassign(t *testing.T) {
 		teamRepo,
 		userRepo,
 		prRepo,
+		nil,
 		tx,
+		clk,
 		zap.NewNop(),
 	)
 
@@ -220,14 +249,14 @@ func TestPRService_PRReassign(t *testing.T) {
 		AuthorID: authorID,
 		Status:   string(models.PRStatusOpen),
 		Reviewers: []*models.PRReviewer{
-			{ID: oldUserID, PRID: prID, AssignedAt: time.Now()},
+			{ID: oldUserID, PRID: prID, AssignedAt: clk.Now()},
 		},
 	}
 
 	t.Run("PR not found", func(t *testing.T) {
-		prRepo.EXPECT().GetByID(ctx, prID).Return(nil, repository.ErrNotFound)
+		prRepo.EXPECT().GetByIDForUpdate(ctx, prID).Return(nil, repository.ErrNotFound)
 
-		pr, err := svc.PRReassign(ctx, prID, oldUserID)
+		pr, _, err := svc.PRReassign(ctx, prID, oldUserID, service.ReassignOptions{})
 		require.Nil(t, pr)
 		require.ErrorIs(t, err, repository.ErrNotFound)
 	})
@@ -235,9 +264,9 @@ func TestPRService_PRReassign(t *testing.T) {
 	t.Run("PR already merged", func(t *testing.T) {
 		mergedPR := *basePR
 		mergedPR.Status = string(models.PRStatusMerged)
-		prRepo.EXPECT().GetByID(ctx, prID).Return(&mergedPR, nil)
+		prRepo.EXPECT().GetByIDForUpdate(ctx, prID).Return(&mergedPR, nil)
 
-		pr, err := svc.PRReassign(ctx, prID, oldUserID)
+		pr, _, err := svc.PRReassign(ctx, prID, oldUserID, service.ReassignOptions{})
 		require.Nil(t, pr)
 		require.ErrorIs(t, err, service.ErrCanNotReassing)
 	})
@@ -245,35 +274,56 @@ func TestPRService_PRReassign(t *testing.T) {
 	t.Run("old reviewer not assigned", func(t *testing.T) {
 		prWithoutOld := *basePR
 		prWithoutOld.Reviewers = []*models.PRReviewer{}
-		prRepo.EXPECT().GetByID(ctx, prID).Return(&prWithoutOld, nil)
+		prRepo.EXPECT().GetByIDForUpdate(ctx, prID).Return(&prWithoutOld, nil)
 
-		pr, err := svc.PRReassign(ctx, prID, oldUserID)
+		pr, _, err := svc.PRReassign(ctx, prID, oldUserID, service.ReassignOptions{})
 		require.Nil(t, pr)
 		require.ErrorIs(t, err, service.ErrNotAssinged)
 	})
 
 	t.Run("no replacement reviewer available", func(t *testing.T) {
-		prRepo.EXPECT().GetByID(ctx, prID).Return(basePR, nil)
+		prRepo.EXPECT().GetByIDForUpdate(ctx, prID).Return(basePR, nil)
+		prRepo.EXPECT().BumpVersion(ctx, prID, basePR.Version).Return(nil)
 		userRepo.EXPECT().GetUserByID(ctx, authorID).Return(&models.User{ID: authorID, TeamID: &teamID}, nil)
-		userRepo.EXPECT().GetActiveByTeam(ctx, teamID).Return([]*models.User{
+		prRepo.EXPECT().ListDeclined(ctx, prID).Return([]uuid.UUID{}, nil)
+		userRepo.EXPECT().GetAvailableByTeam(ctx, teamID, clk.Now()).Return([]*models.User{
 			{ID: oldUserID, TeamID: &teamID, IsActive: true},
 		}, nil)
+		teamRepo.EXPECT().GetFallbackPools(ctx, teamID).Return([]*models.Team{}, nil)
+		teamRepo.EXPECT().GetByID(ctx, teamID).Return(&models.Team{ID: teamID, Name: "backend"}, nil).Times(2)
 
-		pr, err := svc.PRReassign(ctx, prID, oldUserID)
+		pr, _, err := svc.PRReassign(ctx, prID, oldUserID, service.ReassignOptions{})
 		require.Nil(t, pr)
 		require.ErrorIs(t, err, service.ErrNoAvailableReviewer)
 	})
 
+	t.Run("changed concurrently", func(t *testing.T) {
+		prRepo.EXPECT().GetByIDForUpdate(ctx, prID).Return(basePR, nil)
+		prRepo.EXPECT().BumpVersion(ctx, prID, basePR.Version).Return(repository.ErrNotFound)
+
+		pr, _, err := svc.PRReassign(ctx, prID, oldUserID, service.ReassignOptions{})
+		require.Nil(t, pr)
+		require.ErrorIs(t, err, service.ErrVersionConflict)
+	})
+
 	t.Run("success", func(t *testing.T) {
-		prRepo.EXPECT().GetByID(ctx, prID).Return(basePR, nil)
+		prRepo.EXPECT().GetByIDForUpdate(ctx, prID).Return(basePR, nil)
+		prRepo.EXPECT().BumpVersion(ctx, prID, basePR.Version).Return(nil)
 		userRepo.EXPECT().GetUserByID(ctx, authorID).Return(&models.User{ID: authorID, TeamID: &teamID}, nil)
-		userRepo.EXPECT().GetActiveByTeam(ctx, teamID).Return([]*models.User{
+		prRepo.EXPECT().ListDeclined(ctx, prID).Return([]uuid.UUID{}, nil)
+		userRepo.EXPECT().GetAvailableByTeam(ctx, teamID, clk.Now()).Return([]*models.User{
 			{ID: oldUserID, TeamID: &teamID, IsActive: true},
 			{ID: newUserID, TeamID: &teamID, IsActive: true},
 		}, nil)
-		prRepo.EXPECT().ReplaceReviewer(ctx, prID, oldUserID, newUserID).Return(nil)
-
-		result, err := svc.PRReassign(ctx, prID, oldUserID)
+		teamRepo.EXPECT().GetByID(ctx, teamID).Return(&models.Team{ID: teamID, Name: "backend"}, nil)
+		prRepo.EXPECT().ReplaceReviewer(ctx, prID, oldUserID, &models.PRReviewer{
+			ID:         newUserID,
+			PRID:       prID,
+			AssignedAt: clk.Now(),
+			Pool:       models.ReviewerPoolTeam,
+		}).Return(nil)
+
+		result, _, err := svc.PRReassign(ctx, prID, oldUserID, service.ReassignOptions{})
 		require.NoError(t, err)
 		require.Equal(t, basePR.ID, result.ID)
 		require.Contains(t, func() []uuid.UUID {
@@ -286,6 +336,92 @@ func TestPRService_PRReassign(t *testing.T) {
 	})
 }
 
+func TestPRService_SkipsAuthor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	prRepo := mocks.NewMockPRRepository(ctrl)
+	tx := service.TxManagerStub{}
+
+	svc := service.NewPRService(
+		teamRepo,
+		userRepo,
+		prRepo,
+		nil,
+		tx,
+		clk,
+		zap.NewNop(),
+	)
+
+	ctx := context.Background()
+	prID := uuid.New()
+	authorID := uuid.New()
+	teamID := uuid.New()
+	first := uuid.New()
+	second := uuid.New()
+
+	t.Run("create", func(t *testing.T) {
+		newPR := &models.PullRequest{ID: prID, AuthorID: authorID}
+		prRepo.EXPECT().Create(ctx, newPR).Return(nil)
+		userRepo.EXPECT().
+			GetUserByID(ctx, authorID).
+			Return(&models.User{ID: authorID, TeamID: &teamID}, nil)
+		userRepo.EXPECT().
+			GetAvailableByTeam(ctx, teamID, clk.Now()).
+			Return([]*models.User{
+				{ID: authorID, TeamID: &teamID, IsActive: true},
+				{ID: first, TeamID: &teamID, IsActive: true},
+				{ID: second, TeamID: &teamID, IsActive: true},
+			}, nil)
+		teamRepo.EXPECT().
+			GetByID(ctx, teamID).
+			Return(&models.Team{ID: teamID, Name: "backend"}, nil)
+		prRepo.EXPECT().
+			AssignReviewers(ctx, prID, []*models.PRReviewer{
+				{ID: first, PRID: prID, AssignedAt: clk.Now(), Pool: models.ReviewerPoolTeam},
+				{ID: second, PRID: prID, AssignedAt: clk.Now(), Pool: models.ReviewerPoolTeam},
+			}).
+			Return(nil)
+
+		err := svc.CreatePR(ctx, newPR)
+		require.NoError(t, err)
+		require.Len(t, newPR.Reviewers, 2)
+		require.Equal(t, first, newPR.Reviewers[0].ID)
+		require.Equal(t, second, newPR.Reviewers[1].ID)
+	})
+
+	t.Run("reassign", func(t *testing.T) {
+		pr := &models.PullRequest{
+			ID:       prID,
+			AuthorID: authorID,
+			Status:   string(models.PRStatusOpen),
+			Reviewers: []*models.PRReviewer{
+				{ID: first, PRID: prID, AssignedAt: clk.Now()},
+			},
+		}
+		prRepo.EXPECT().GetByIDForUpdate(ctx, prID).Return(pr, nil)
+		prRepo.EXPECT().BumpVersion(ctx, prID, pr.Version).Return(nil)
+		userRepo.EXPECT().
+			GetUserByID(ctx, authorID).
+			Return(&models.User{ID: authorID, TeamID: &teamID}, nil)
+		prRepo.EXPECT().ListDeclined(ctx, prID).Return([]uuid.UUID{}, nil)
+		userRepo.EXPECT().
+			GetAvailableByTeam(ctx, teamID, clk.Now()).
+			Return([]*models.User{
+				{ID: authorID, TeamID: &teamID, IsActive: true},
+				{ID: first, TeamID: &teamID, IsActive: true},
+			}, nil)
+		teamRepo.EXPECT().GetFallbackPools(ctx, teamID).Return([]*models.Team{}, nil)
+		teamRepo.EXPECT().GetByID(ctx, teamID).Return(&models.Team{ID: teamID, Name: "backend"}, nil).Times(2)
+
+		result, _, err := svc.PRReassign(ctx, prID, first, service.ReassignOptions{})
+		require.Nil(t, result)
+		require.ErrorIs(t, err, service.ErrNoAvailableReviewer)
+	})
+}
+
 func TestPRService_TeamAdd(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -301,7 +437,9 @@ func TestPRService_TeamAdd(t *testing.T) {
 		teamRepo,
 		userRepo,
 		prRepo,
+		nil,
 		tx,
+		clk,
 		logger,
 	)
 	ctx := t.Context()
@@ -359,6 +497,124 @@ func TestPRService_TeamAdd(t *testing.T) {
 	})
 }
 
+func TestPRService_TeamSetReviewSLA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	prRepo := mocks.NewMockPRRepository(ctrl)
+
+	svc := service.NewPRService(
+		teamRepo,
+		userRepo,
+		prRepo,
+		nil,
+		service.TxManagerStub{},
+		clk,
+		zap.NewNop(),
+	)
+	ctx := t.Context()
+
+	teamID := uuid.New()
+	sla := &models.ReviewSLA{Timeout: 24 * time.Hour, Action: models.SLAActionReassign}
+
+	t.Run("success", func(t *testing.T) {
+		members := []*models.User{{ID: uuid.New(), TeamID: &teamID}}
+
+		teamRepo.EXPECT().GetByName(ctx, "team1").Return(&models.Team{ID: teamID, Name: "team1"}, nil)
+		teamRepo.EXPECT().SetReviewSLA(ctx, teamID, sla).Return(nil)
+		userRepo.EXPECT().GetByTeam(ctx, teamID).Return(members, nil)
+		teamRepo.EXPECT().GetFallbackPools(ctx, teamID).Return([]*models.Team{}, nil)
+
+		team, err := svc.TeamSetReviewSLA(ctx, "team1", sla)
+		require.NoError(t, err)
+		require.Equal(t, sla, team.ReviewSLA)
+		require.Equal(t, members, team.Members)
+	})
+
+	t.Run("team not found", func(t *testing.T) {
+		teamRepo.EXPECT().GetByName(ctx, "missing").Return(nil, repository.ErrNotFound)
+
+		_, err := svc.TeamSetReviewSLA(ctx, "missing", nil)
+		require.ErrorIs(t, err, service.ErrNotFound)
+	})
+
+	t.Run("invalid sla", func(t *testing.T) {
+		for _, invalid := range []*models.ReviewSLA{
+			{Timeout: 0, Action: models.SLAActionEscalate},
+			{Timeout: 1500 * time.Millisecond, Action: models.SLAActionEscalate},
+			{Timeout: time.Hour, Action: "ignore"},
+		} {
+			_, err := svc.TeamSetReviewSLA(ctx, "team1", invalid)
+			require.ErrorIs(t, err, service.ErrInvalidSLA)
+
+			err = svc.TeamAdd(ctx, &models.Team{Name: "team2", ReviewSLA: invalid})
+			require.ErrorIs(t, err, service.ErrInvalidSLA)
+		}
+	})
+}
+
+func TestPRService_TeamSetDigestSchedule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	prRepo := mocks.NewMockPRRepository(ctrl)
+
+	svc := service.NewPRService(
+		teamRepo,
+		userRepo,
+		prRepo,
+		nil,
+		service.TxManagerStub{},
+		clk,
+		zap.NewNop(),
+	)
+	ctx := t.Context()
+
+	teamID := uuid.New()
+	d := &models.DigestSchedule{At: 9*time.Hour + 30*time.Minute, Location: time.UTC}
+
+	t.Run("success", func(t *testing.T) {
+		members := []*models.User{{ID: uuid.New(), TeamID: &teamID}}
+
+		teamRepo.EXPECT().GetByName(ctx, "team1").Return(&models.Team{ID: teamID, Name: "team1"}, nil)
+		teamRepo.EXPECT().SetDigestSchedule(ctx, teamID, d).Return(nil)
+		userRepo.EXPECT().GetByTeam(ctx, teamID).Return(members, nil)
+		teamRepo.EXPECT().GetFallbackPools(ctx, teamID).Return([]*models.Team{}, nil)
+
+		team, err := svc.TeamSetDigestSchedule(ctx, "team1", d)
+		require.NoError(t, err)
+		require.Equal(t, d, team.Digest)
+		require.Equal(t, members, team.Members)
+	})
+
+	t.Run("team not found", func(t *testing.T) {
+		teamRepo.EXPECT().GetByName(ctx, "missing").Return(nil, repository.ErrNotFound)
+
+		_, err := svc.TeamSetDigestSchedule(ctx, "missing", nil)
+		require.ErrorIs(t, err, service.ErrNotFound)
+	})
+
+	t.Run("invalid schedule", func(t *testing.T) {
+		for _, invalid := range []*models.DigestSchedule{
+			{At: -time.Minute, Location: time.UTC},
+			{At: 24 * time.Hour, Location: time.UTC},
+			{At: 9*time.Hour + 30*time.Second, Location: time.UTC},
+			{At: 9 * time.Hour},
+			{At: 9 * time.Hour, Location: time.Local},
+		} {
+			_, err := svc.TeamSetDigestSchedule(ctx, "team1", invalid)
+			require.ErrorIs(t, err, service.ErrInvalidSchedule)
+
+			err = svc.TeamAdd(ctx, &models.Team{Name: "team2", Digest: invalid})
+			require.ErrorIs(t, err, service.ErrInvalidSchedule)
+		}
+	})
+}
+
 func TestPRService_TeamGet(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -374,7 +630,9 @@ func TestPRService_TeamGet(t *testing.T) {
 		teamRepo,
 		userRepo,
 		prRepo,
+		nil,
 		tx,
+		clk,
 		logger,
 	)
 	ctx := t.Context()
@@ -397,12 +655,16 @@ func TestPRService_TeamGet(t *testing.T) {
 		userRepo.EXPECT().
 			GetByTeam(ctx, teamID).
 			Return(members, nil)
+		teamRepo.EXPECT().
+			GetFallbackPools(ctx, teamID).
+			Return([]*models.Team{{ID: uuid.New(), Name: "platform"}}, nil)
 
 		result, err := svc.TeamGet(ctx, teamName)
 		require.NoError(t, err)
 		require.Equal(t, team.ID, result.ID)
 		require.Equal(t, team.Name, result.Name)
 		require.Len(t, result.Members, 2)
+		require.Equal(t, []string{"platform"}, result.Fallback)
 	})
 
 	t.Run("team not found", func(t *testing.T) {
@@ -459,7 +721,9 @@ func TestPRService_UsersGetReview(t *testing.T) {
 		teamRepo,
 		userRepo,
 		prRepo,
+		nil,
 		tx,
+		clk,
 		logger,
 	)
 	ctx := t.Context()
@@ -507,7 +771,9 @@ func TestPRService_UsersSetIsActive(t *testing.T) {
 		teamRepo,
 		userRepo,
 		prRepo,
+		nil,
 		tx,
+		clk,
 		logger,
 	)
 	ctx := t.Context()
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+type expectedVersionKey struct{}
+
+// WithExpectedVersion makes the PR changes made with ctx fail with
+// ErrVersionConflict unless the PR is still at version, like an HTTP If-Match.
+func WithExpectedVersion(ctx context.Context, version int64) context.Context {
+	return context.WithValue(ctx, expectedVersionKey{}, version)
+}
+
+// claimVersion bumps the version of the PR read in this transaction before
+// it is changed. A concurrent change of the PR since the read, or a version
+// other than the one expected by the caller, is ErrVersionConflict.
+func (s *PRService) claimVersion(ctx context.Context, pr *models.PullRequest) error {
+	if err := checkVersion(ctx, pr); err != nil {
+		return err
+	}
+
+	if err := s.prRepo.BumpVersion(ctx, pr.ID, pr.Version); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			s.log.Warn("PR changed concurrently",
+				zap.String("pr_id", pr.ID.String()),
+			)
+			return fmt.Errorf("%w concurrently", ErrVersionConflict)
+		}
+		s.log.Error("failed to bump PR version",
+			zap.Error(err),
+			zap.String("pr_id", pr.ID.String()),
+		)
+		return err
+	}
+
+	pr.Version++
+	return nil
+}
+
+// checkVersion fails with ErrVersionConflict unless the PR is at the version
+// expected by the caller, if any.
+func checkVersion(ctx context.Context, pr *models.PullRequest) error {
+	if version, ok := ctx.Value(expectedVersionKey{}).(int64); ok && version != pr.Version {
+		return fmt.Errorf("%w: version is %d, not %d", ErrVersionConflict, pr.Version, version)
+	}
+	return nil
+}
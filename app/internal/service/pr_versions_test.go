@@ -0,0 +1,147 @@
+package service_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"pr-service/internal/models"
+	"pr-service/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPRService_Versions(t *testing.T) {
+	ctx := t.Context()
+	svc := newMemoryPRService()
+
+	team := &models.Team{
+		Name: "backend",
+		Members: []*models.User{
+			{Name: "author", IsActive: true},
+			{Name: "r1", IsActive: true},
+			{Name: "r2", IsActive: true},
+			{Name: "r3", IsActive: true},
+			{Name: "r4", IsActive: true},
+		},
+	}
+	require.NoError(t, svc.TeamAdd(ctx, team))
+	author, reviewers := team.Members[0], team.Members[1:]
+
+	create := func(t *testing.T) *models.PullRequest {
+		t.Helper()
+		pr := &models.PullRequest{ID: uuid.New(), Name: "change", AuthorID: author.ID, Status: string(models.PRStatusOpen)}
+		require.NoError(t, svc.CreatePR(ctx, pr))
+		require.Equal(t, int64(1), pr.Version)
+		return pr
+	}
+
+	t.Run("every change bumps the version", func(t *testing.T) {
+		pr := create(t)
+
+		got, _, err := svc.PRReassign(ctx, pr.ID, pr.Reviewers[0].ID, service.ReassignOptions{})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), got.Version)
+
+		got, err = svc.PRRemoveReviewers(ctx, pr.ID, reviewerIDs(got.Reviewers)[:1])
+		require.NoError(t, err)
+		require.Equal(t, int64(3), got.Version)
+
+		got, err = svc.PRMerge(ctx, pr.ID)
+		require.NoError(t, err)
+		require.Equal(t, int64(4), got.Version)
+
+		got, err = svc.PRMerge(ctx, pr.ID)
+		require.NoError(t, err)
+		require.Equal(t, int64(4), got.Version, "merging a merged PR changes nothing")
+	})
+
+	t.Run("expected version", func(t *testing.T) {
+		pr := create(t)
+
+		_, err := svc.PRMerge(service.WithExpectedVersion(ctx, 2), pr.ID)
+		require.ErrorIs(t, err, service.ErrVersionConflict)
+
+		got, err := svc.PRMerge(service.WithExpectedVersion(ctx, 1), pr.ID)
+		require.NoError(t, err)
+		require.Equal(t, string(models.PRStatusMerged), got.Status)
+		require.Equal(t, int64(2), got.Version)
+
+		// merging again is idempotent only at the current version
+		_, err = svc.PRMerge(service.WithExpectedVersion(ctx, 1), pr.ID)
+		require.ErrorIs(t, err, service.ErrVersionConflict)
+
+		got, err = svc.PRMerge(service.WithExpectedVersion(ctx, 2), pr.ID)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), got.Version)
+	})
+
+	t.Run("one of concurrent writers of a version wins", func(t *testing.T) {
+		pr := create(t)
+		ctx := service.WithExpectedVersion(ctx, pr.Version)
+
+		var wg sync.WaitGroup
+		errs := make([]error, len(reviewers))
+		for i, r := range reviewers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, errs[i] = svc.PRSetReviewers(ctx, pr.ID, []uuid.UUID{r.ID})
+			}()
+		}
+		wg.Wait()
+
+		winner := -1
+		for i, err := range errs {
+			if err == nil {
+				require.Equal(t, -1, winner, "only one writer succeeds")
+				winner = i
+				continue
+			}
+			require.ErrorIs(t, err, service.ErrVersionConflict)
+		}
+		require.NotEqual(t, -1, winner)
+
+		got, err := svc.PRMerge(service.WithExpectedVersion(t.Context(), 2), pr.ID)
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{reviewers[winner].ID}, reviewerIDs(got.Reviewers))
+	})
+
+	t.Run("merge racing reassigns", func(t *testing.T) {
+		pr := create(t)
+
+		var wg sync.WaitGroup
+		errs := make([]error, 4)
+		for i := range errs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _, errs[i] = svc.PRReassign(ctx, pr.ID, pr.Reviewers[0].ID, service.ReassignOptions{})
+			}()
+		}
+		var mergeErr error
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, mergeErr = svc.PRMerge(ctx, pr.ID)
+		}()
+		wg.Wait()
+		require.NoError(t, mergeErr)
+
+		reassigned := 0
+		for _, err := range errs {
+			if err == nil {
+				reassigned++
+				continue
+			}
+			require.True(t, errors.Is(err, service.ErrCanNotReassing) || errors.Is(err, service.ErrNotAssinged), err)
+		}
+		require.LessOrEqual(t, reassigned, 1, "the reviewer is replaced once")
+
+		got, err := svc.PRMerge(ctx, pr.ID)
+		require.NoError(t, err)
+		require.Equal(t, string(models.PRStatusMerged), got.Status)
+		require.Equal(t, int64(2+reassigned), got.Version, "the merge and each reassign bumped the version")
+	})
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"pr-service/internal/models"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// PRDecline replaces the reviewer, who declined the PR for the reason, the
+// way PRReassign does, or just removes them when nobody can take over, in
+// which case the returned replacement is nil. The decline is stored with the
+// reassignment, and the reviewer is never assigned to the PR again.
+func (s *PRService) PRDecline(ctx context.Context, prID, userID uuid.UUID, reason models.DeclineReason) (*models.PullRequest, *models.PRReviewer, error) {
+	if !reason.Valid() {
+		return nil, nil, fmt.Errorf("%w: %q", ErrInvalidDecline, reason)
+	}
+
+	pr, replacement, err := s.reassign(ctx, prID, userID, ReassignOptions{}, reason)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.log.Info("reviewer declined",
+		zap.String("pr_id", prID.String()),
+		zap.String("user_id", userID.String()),
+		zap.String("reason", string(reason)),
+	)
+
+	return pr, replacement, nil
+}
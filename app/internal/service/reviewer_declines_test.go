@@ -0,0 +1,94 @@
+package service_test
+
+import (
+	"slices"
+	"testing"
+
+	"pr-service/internal/models"
+	"pr-service/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPRService_Decline(t *testing.T) {
+	ctx := t.Context()
+	svc := newMemoryPRService()
+
+	team := &models.Team{
+		Name: "backend",
+		Members: []*models.User{
+			{Name: "author", IsActive: true},
+			{Name: "r1", IsActive: true},
+			{Name: "r2", IsActive: true},
+			{Name: "r3", IsActive: true},
+		},
+	}
+	require.NoError(t, svc.TeamAdd(ctx, team))
+	author, r1, r2, r3 := team.Members[0], team.Members[1], team.Members[2], team.Members[3]
+
+	pr := &models.PullRequest{ID: uuid.New(), Name: "change", AuthorID: author.ID, Status: string(models.PRStatusOpen)}
+	require.NoError(t, svc.CreatePR(ctx, pr))
+	require.Len(t, pr.Reviewers, 2)
+	declining, staying := pr.Reviewers[0].ID, pr.Reviewers[1].ID
+
+	// the only member left to pick
+	var spare uuid.UUID
+	for _, u := range []*models.User{r1, r2, r3} {
+		if !slices.Contains(reviewerIDs(pr.Reviewers), u.ID) {
+			spare = u.ID
+		}
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := svc.PRDecline(ctx, pr.ID, declining, "bored")
+		require.ErrorIs(t, err, service.ErrInvalidDecline)
+
+		_, _, err = svc.PRDecline(ctx, pr.ID, author.ID, models.DeclineBusy)
+		require.ErrorIs(t, err, service.ErrNotAssinged)
+	})
+
+	t.Run("replaces the reviewer", func(t *testing.T) {
+		got, replacement, err := svc.PRDecline(ctx, pr.ID, declining, models.DeclineWrongExpertise)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uuid.UUID{staying, spare}, reviewerIDs(got.Reviewers))
+		require.Equal(t, spare, replacement.ID)
+	})
+
+	t.Run("declined reviewer is not picked again", func(t *testing.T) {
+		_, _, err := svc.PRReassign(ctx, pr.ID, spare, service.ReassignOptions{})
+		require.ErrorIs(t, err, service.ErrNoAvailableReviewer)
+
+		// nor chosen by hand
+		_, _, err = svc.PRReassign(ctx, pr.ID, spare, service.ReassignOptions{NewUserID: declining})
+		require.ErrorIs(t, err, service.ErrInvalidReviewer)
+
+		_, err = svc.PRAddReviewers(ctx, pr.ID, []uuid.UUID{declining})
+		require.ErrorIs(t, err, service.ErrInvalidReviewer)
+
+		_, err = svc.PRSetReviewers(ctx, pr.ID, []uuid.UUID{staying, declining})
+		require.ErrorIs(t, err, service.ErrInvalidReviewer)
+	})
+
+	t.Run("decline stands without a replacement", func(t *testing.T) {
+		got, replacement, err := svc.PRDecline(ctx, pr.ID, spare, models.DeclineBusy)
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{staying}, reviewerIDs(got.Reviewers))
+		require.Nil(t, replacement)
+
+		prs, err := svc.UsersGetReview(ctx, spare)
+		require.NoError(t, err)
+		require.Empty(t, prs)
+
+		_, err = svc.PRAddReviewers(ctx, pr.ID, []uuid.UUID{spare})
+		require.ErrorIs(t, err, service.ErrInvalidReviewer)
+	})
+
+	t.Run("merged", func(t *testing.T) {
+		_, err := svc.PRMerge(ctx, pr.ID)
+		require.NoError(t, err)
+
+		_, _, err = svc.PRDecline(ctx, pr.ID, staying, models.DeclineConflict)
+		require.ErrorIs(t, err, service.ErrCanNotReassing)
+	})
+}
@@ -0,0 +1,373 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxReviewers is how many reviewers a new PR gets.
+const maxReviewers = 2
+
+// TeamSetFallbackPools replaces the teams asked, in order, for reviewers of
+// the team once its own members run out. An empty list removes them.
+func (s *PRService) TeamSetFallbackPools(ctx context.Context, teamName string, poolNames []string) (*models.Team, error) {
+	for i, name := range poolNames {
+		if name == teamName {
+			return nil, fmt.Errorf("%w: a team can not fall back to itself", ErrInvalidFallback)
+		}
+		if slices.Contains(poolNames[:i], name) {
+			return nil, fmt.Errorf("%w: %s is listed twice", ErrInvalidFallback, name)
+		}
+	}
+
+	var team *models.Team
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		team, err = s.loadTeam(ctx, teamName)
+		if err != nil {
+			return err
+		}
+
+		poolIDs := make([]uuid.UUID, len(poolNames))
+		for i, name := range poolNames {
+			pool, err := s.teamRepo.GetByName(ctx, name)
+			if err != nil {
+				if errors.Is(err, repository.ErrNotFound) {
+					return fmt.Errorf("%w: unknown team %s", ErrInvalidFallback, name)
+				}
+				return err
+			}
+			poolIDs[i] = pool.ID
+		}
+
+		if err := s.teamRepo.SetFallbackPools(ctx, team.ID, poolIDs); err != nil {
+			s.log.Error("failed to set team fallback pools",
+				zap.Error(err),
+				zap.String("team_name", teamName),
+			)
+			return err
+		}
+		team.Fallback = poolNames
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("team fallback pools updated",
+		zap.String("team_name", teamName),
+		zap.Strings("fallback", poolNames),
+	)
+
+	return team, nil
+}
+
+// fallbackNames returns the names of the fallback teams of the team.
+func (s *PRService) fallbackNames(ctx context.Context, teamID uuid.UUID) ([]string, error) {
+	pools, err := s.teamRepo.GetFallbackPools(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(pools))
+	for i, p := range pools {
+		names[i] = p.Name
+	}
+	return names, nil
+}
+
+// candidates walks the available members of the author's team, then of its
+// fallback teams in order, then of its parent teams from the nearest up,
+// until yield returns false. Each step is only looked up once the previous
+// ones are exhausted. Parent teams are reported as fallback pools too.
+func (s *PRService) candidates(
+	ctx context.Context,
+	teamID uuid.UUID,
+	available func(teamID uuid.UUID) ([]*models.User, error),
+	yield func(u *models.User, pool models.ReviewerPool, poolTeam string) bool,
+) error {
+	users, err := available(teamID)
+	if err != nil {
+		return err
+	}
+	for _, u := range users {
+		if !yield(u, models.ReviewerPoolTeam, "") {
+			return nil
+		}
+	}
+
+	pools, err := s.teamRepo.GetFallbackPools(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	for _, p := range pools {
+		users, err := available(p.ID)
+		if err != nil {
+			return err
+		}
+		for _, u := range users {
+			if !yield(u, models.ReviewerPoolFallback, p.Name) {
+				return nil
+			}
+		}
+	}
+
+	team, err := s.teamRepo.GetByID(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	for seen := map[uuid.UUID]bool{teamID: true}; team.ParentID != nil && !seen[*team.ParentID]; {
+		if team, err = s.teamRepo.GetByID(ctx, *team.ParentID); err != nil {
+			return err
+		}
+		seen[team.ID] = true
+
+		users, err := available(team.ID)
+		if err != nil {
+			return err
+		}
+		for _, u := range users {
+			if !yield(u, models.ReviewerPoolFallback, team.Name) {
+				return nil
+			}
+		}
+	}
+
+	return nil
+}
+
+// availableCache memoizes the available members of teams at one moment.
+func (s *PRService) availableCache(ctx context.Context, now time.Time) func(teamID uuid.UUID) ([]*models.User, error) {
+	byTeam := make(map[uuid.UUID][]*models.User)
+
+	return func(teamID uuid.UUID) ([]*models.User, error) {
+		if users, ok := byTeam[teamID]; ok {
+			return users, nil
+		}
+		users, err := s.userRepo.GetAvailableByTeam(ctx, teamID, now)
+		if err != nil {
+			return nil, err
+		}
+		byTeam[teamID] = users
+		return users, nil
+	}
+}
+
+// ownerCandidates returns the candidates of every owner, in owner order:
+// the users of an owning team, or the owning user, among those users
+// returns for their team.
+func (s *PRService) ownerCandidates(
+	ctx context.Context,
+	owners []models.CodeOwner,
+	users func(teamID uuid.UUID) ([]*models.User, error),
+) ([][]*models.User, error) {
+	ownerCandidates := make([][]*models.User, 0, len(owners))
+	for _, o := range owners {
+		teamID := o.TeamID
+		if o.UserID != uuid.Nil {
+			u, err := s.userRepo.GetUserByID(ctx, o.UserID)
+			if errors.Is(err, repository.ErrNotFound) || (err == nil && u.TeamID == nil) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			teamID = *u.TeamID
+		}
+
+		members, err := users(teamID)
+		if err != nil {
+			return nil, err
+		}
+
+		var candidates []*models.User
+		for _, u := range members {
+			if o.UserID == uuid.Nil || u.ID == o.UserID {
+				candidates = append(candidates, u)
+			}
+		}
+		ownerCandidates = append(ownerCandidates, candidates)
+	}
+
+	return ownerCandidates, nil
+}
+
+// pickReviewers picks up to two available reviewers other than the author.
+// Owners of the changed files come first, one per owner before anyone gets
+// a second pick, even from other teams; the author's team and then its
+// fallback teams fill the rest. When the review policy of the author's team
+// is not met by the picks, the first candidate meeting it takes the last
+// place; ErrReviewPolicy when there is none.
+func (s *PRService) pickReviewers(ctx context.Context, pr *models.PullRequest, author *models.User, now time.Time) ([]*models.PRReviewer, error) {
+	available := s.availableCache(ctx, now)
+
+	picked := make([]*models.PRReviewer, 0, maxReviewers)
+	roles := make([]models.Role, 0, maxReviewers)
+	reviewer := func(u *models.User, pool models.ReviewerPool, poolTeam string) *models.PRReviewer {
+		return &models.PRReviewer{
+			ID:         u.ID,
+			PRID:       pr.ID,
+			AssignedAt: now,
+			Pool:       pool,
+			PoolTeam:   poolTeam,
+		}
+	}
+	pick := func(u *models.User, pool models.ReviewerPool, poolTeam string) {
+		if len(picked) < maxReviewers && u.ID != pr.AuthorID && !isReviewer(picked, u.ID) {
+			picked = append(picked, reviewer(u, pool, poolTeam))
+			roles = append(roles, u.Role)
+		}
+	}
+
+	owners, err := s.fileOwners(ctx, *author.TeamID, pr.Files)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerCandidates, err := s.ownerCandidates(ctx, owners, available)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, users := range ownerCandidates {
+		for _, u := range users {
+			if u.ID != pr.AuthorID && !isReviewer(picked, u.ID) {
+				pick(u, models.ReviewerPoolCodeOwners, "")
+				break
+			}
+		}
+	}
+	for _, users := range ownerCandidates {
+		for _, u := range users {
+			pick(u, models.ReviewerPoolCodeOwners, "")
+		}
+	}
+
+	if len(picked) < maxReviewers {
+		err = s.candidates(ctx, *author.TeamID, available, func(u *models.User, pool models.ReviewerPool, poolTeam string) bool {
+			pick(u, pool, poolTeam)
+			return len(picked) < maxReviewers
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	policy, err := s.reviewPolicy(ctx, *author.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil || policy.SatisfiedBy(roles...) {
+		return picked, nil
+	}
+
+	// owners first, then the author's team and the teams it falls back to
+	var required *models.PRReviewer
+	qualifies := func(u *models.User) bool {
+		return u.ID != pr.AuthorID && !isReviewer(picked, u.ID) && u.Role.AtLeast(policy.MinRole)
+	}
+	for _, users := range ownerCandidates {
+		if i := slices.IndexFunc(users, qualifies); i >= 0 {
+			required = reviewer(users[i], models.ReviewerPoolCodeOwners, "")
+			break
+		}
+	}
+	if required == nil {
+		err = s.candidates(ctx, *author.TeamID, available, func(u *models.User, pool models.ReviewerPool, poolTeam string) bool {
+			if qualifies(u) {
+				required = reviewer(u, pool, poolTeam)
+			}
+			return required == nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if required == nil {
+		return nil, fmt.Errorf("%w: no available %s or above", ErrReviewPolicy, policy.MinRole)
+	}
+
+	if len(picked) == maxReviewers {
+		picked[len(picked)-1] = required
+	} else {
+		picked = append(picked, required)
+	}
+	return picked, nil
+}
+
+// pickReplacement picks an available reviewer for the PR instead of oldID
+// from the author's team or its fallback teams, skipping the excluded users;
+// nil when there is nobody.
+// When the reviewers left without oldID do not meet the review policy of the
+// author's team, only candidates meeting it are considered and
+// ErrReviewPolicy is returned when there is none.
+func (s *PRService) pickReplacement(
+	ctx context.Context,
+	pr *models.PullRequest,
+	author *models.User,
+	oldID uuid.UUID,
+	exclude []uuid.UUID,
+	now time.Time,
+) (*models.PRReviewer, error) {
+	policy, err := s.reviewPolicy(ctx, *author.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	if policy != nil {
+		roles := make([]models.Role, 0, len(pr.Reviewers))
+		for _, r := range pr.Reviewers {
+			if r.ID == oldID {
+				continue
+			}
+			u, err := s.userRepo.GetUserByID(ctx, r.ID)
+			if err != nil {
+				return nil, err
+			}
+			roles = append(roles, u.Role)
+		}
+		if policy.SatisfiedBy(roles...) {
+			policy = nil
+		}
+	}
+
+	var replacement *models.PRReviewer
+	err = s.candidates(ctx, *author.TeamID, s.availableCache(ctx, now), func(u *models.User, pool models.ReviewerPool, poolTeam string) bool {
+		if u.ID == oldID || u.ID == pr.AuthorID || isReviewer(pr.Reviewers, u.ID) || slices.Contains(exclude, u.ID) {
+			return true
+		}
+		if policy != nil && !u.Role.AtLeast(policy.MinRole) {
+			return true
+		}
+
+		replacement = &models.PRReviewer{
+			ID:         u.ID,
+			PRID:       pr.ID,
+			AssignedAt: now,
+			Pool:       pool,
+			PoolTeam:   poolTeam,
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if replacement == nil && policy != nil {
+		return nil, fmt.Errorf("%w: no available %s or above", ErrReviewPolicy, policy.MinRole)
+	}
+	return replacement, nil
+}
+
+func isReviewer(reviewers []*models.PRReviewer, id uuid.UUID) bool {
+	return slices.ContainsFunc(reviewers, func(r *models.PRReviewer) bool { return r.ID == id })
+}
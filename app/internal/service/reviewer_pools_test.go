@@ -0,0 +1,114 @@
+package service_test
+
+import (
+	"testing"
+
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPRService_FallbackPools(t *testing.T) {
+	ctx := t.Context()
+	svc := newMemoryPRService()
+
+	small := &models.Team{
+		Name: "mobile",
+		Members: []*models.User{
+			{Name: "author", IsActive: true},
+			{Name: "mate", IsActive: true},
+		},
+	}
+	require.NoError(t, svc.TeamAdd(ctx, small))
+	author, mate := small.Members[0], small.Members[1]
+
+	sibling := &models.Team{Name: "web", Members: []*models.User{{Name: "inactive", IsActive: false}}}
+	require.NoError(t, svc.TeamAdd(ctx, sibling))
+
+	guild := &models.Team{
+		Name: "platform",
+		Members: []*models.User{
+			{Name: "p1", IsActive: true},
+			{Name: "p2", IsActive: true},
+		},
+	}
+	require.NoError(t, svc.TeamAdd(ctx, guild))
+	p1, p2 := guild.Members[0], guild.Members[1]
+
+	t.Run("invalid pools", func(t *testing.T) {
+		for _, pools := range [][]string{
+			{"mobile"},
+			{"platform", "platform"},
+			{"missing"},
+		} {
+			_, err := svc.TeamSetFallbackPools(ctx, "mobile", pools)
+			require.ErrorIs(t, err, service.ErrInvalidFallback, pools)
+		}
+
+		_, err := svc.TeamSetFallbackPools(ctx, "missing", nil)
+		require.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	create := func(t *testing.T) *models.PullRequest {
+		t.Helper()
+		pr := &models.PullRequest{
+			ID:       uuid.New(),
+			Name:     "change",
+			AuthorID: author.ID,
+			Status:   string(models.PRStatusOpen),
+		}
+		require.NoError(t, svc.CreatePR(ctx, pr))
+		return pr
+	}
+
+	t.Run("without pools the team is all there is", func(t *testing.T) {
+		pr := create(t)
+		require.Len(t, pr.Reviewers, 1)
+		require.Equal(t, mate.ID, pr.Reviewers[0].ID)
+
+		_, _, err := svc.PRReassign(ctx, pr.ID, mate.ID, service.ReassignOptions{})
+		require.ErrorIs(t, err, service.ErrNoAvailableReviewer)
+	})
+
+	team, err := svc.TeamSetFallbackPools(ctx, "mobile", []string{"web", "platform"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"web", "platform"}, team.Fallback)
+
+	got, err := svc.TeamGet(ctx, "mobile")
+	require.NoError(t, err)
+	require.Equal(t, []string{"web", "platform"}, got.Fallback)
+
+	t.Run("pools fill the rest, in order", func(t *testing.T) {
+		pr := create(t)
+		require.Len(t, pr.Reviewers, 2)
+		require.Equal(t, mate.ID, pr.Reviewers[0].ID)
+		require.Equal(t, models.ReviewerPoolTeam, pr.Reviewers[0].Pool)
+		require.Equal(t, p1.ID, pr.Reviewers[1].ID)
+		require.Equal(t, models.ReviewerPoolFallback, pr.Reviewers[1].Pool)
+		require.Equal(t, "platform", pr.Reviewers[1].PoolTeam)
+
+		reassigned, _, err := svc.PRReassign(ctx, pr.ID, mate.ID, service.ReassignOptions{})
+		require.NoError(t, err)
+
+		byID := make(map[uuid.UUID]*models.PRReviewer)
+		for _, r := range reassigned.Reviewers {
+			byID[r.ID] = r
+		}
+		require.Len(t, byID, 2)
+		require.Equal(t, models.ReviewerPoolFallback, byID[p1.ID].Pool)
+		require.Equal(t, models.ReviewerPoolFallback, byID[p2.ID].Pool)
+		require.Equal(t, "platform", byID[p2.ID].PoolTeam)
+	})
+
+	t.Run("empty list removes the pools", func(t *testing.T) {
+		team, err := svc.TeamSetFallbackPools(ctx, "mobile", nil)
+		require.NoError(t, err)
+		require.Empty(t, team.Fallback)
+
+		pr := create(t)
+		require.Len(t, pr.Reviewers, 1)
+	})
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// TeamSetReviewPolicy sets what the reviewers of every PR of the team must
+// satisfy; nil removes the policy. PRs that already have reviewers keep them.
+func (s *PRService) TeamSetReviewPolicy(ctx context.Context, teamName string, p *models.ReviewPolicy) (*models.Team, error) {
+	if err := validatePolicy(p); err != nil {
+		return nil, err
+	}
+
+	var team *models.Team
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		team, err = s.loadTeam(ctx, teamName)
+		if err != nil {
+			return err
+		}
+
+		if err := s.teamRepo.SetReviewPolicy(ctx, team.ID, p); err != nil {
+			s.log.Error("failed to set team review policy",
+				zap.Error(err),
+				zap.String("team_name", teamName),
+			)
+			return err
+		}
+		team.Policy = p
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("team review policy updated",
+		zap.String("team_name", teamName),
+		zap.Bool("enabled", p != nil),
+	)
+
+	return team, nil
+}
+
+// UsersSetRole changes the seniority of the user. Reviews already assigned
+// are not revisited.
+func (s *PRService) UsersSetRole(ctx context.Context, userID uuid.UUID, role models.Role) (*models.User, error) {
+	if !role.Valid() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidRole, role)
+	}
+
+	err := s.userRepo.UpdateRole(ctx, userID, role)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			s.log.Error("failed to update user role",
+				zap.Error(err),
+				zap.String("user_id", userID.String()),
+				zap.String("role", string(role)),
+			)
+		}
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		s.log.Error("failed to get user",
+			zap.Error(err),
+			zap.String("user_id", userID.String()),
+		)
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// validatePolicy accepts no policy or a known role.
+func validatePolicy(p *models.ReviewPolicy) error {
+	if p == nil {
+		return nil
+	}
+	if !p.MinRole.Valid() {
+		return fmt.Errorf("%w: %q", ErrInvalidRole, p.MinRole)
+	}
+	return nil
+}
+
+// validateRoles accepts members with a known role or none, which gets the default.
+func validateRoles(members []*models.User) error {
+	for _, u := range members {
+		if u.Role != "" && !u.Role.Valid() {
+			return fmt.Errorf("%w: %q", ErrInvalidRole, u.Role)
+		}
+	}
+	return nil
+}
+
+// reviewPolicy returns the review policy of the team, nil when it has none.
+func (s *PRService) reviewPolicy(ctx context.Context, teamID uuid.UUID) (*models.ReviewPolicy, error) {
+	team, err := s.teamRepo.GetByID(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	return team.Policy, nil
+}
@@ -0,0 +1,93 @@
+package service_test
+
+import (
+	"testing"
+
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPRService_ReviewPolicy(t *testing.T) {
+	ctx := t.Context()
+	svc := newMemoryPRService()
+
+	err := svc.TeamAdd(ctx, &models.Team{Name: "invalid", Members: []*models.User{{Name: "x", Role: "lead"}}})
+	require.ErrorIs(t, err, service.ErrInvalidRole)
+
+	team := &models.Team{
+		Name:   "payments",
+		Policy: &models.ReviewPolicy{MinRole: models.RoleMaintainer},
+		Members: []*models.User{
+			{Name: "author", IsActive: true},
+			{Name: "a-junior", Role: models.RoleJunior, IsActive: true},
+			{Name: "b-junior", Role: models.RoleJunior, IsActive: true},
+			{Name: "c-lead", Role: models.RoleJunior, IsActive: true},
+		},
+	}
+	require.NoError(t, svc.TeamAdd(ctx, team))
+	author, a, b, lead := team.Members[0], team.Members[1], team.Members[2], team.Members[3]
+	require.Equal(t, models.DefaultRole, author.Role)
+
+	t.Run("invalid roles", func(t *testing.T) {
+		_, err := svc.UsersSetRole(ctx, lead.ID, "lead")
+		require.ErrorIs(t, err, service.ErrInvalidRole)
+
+		_, err = svc.UsersSetRole(ctx, uuid.New(), models.RoleSenior)
+		require.ErrorIs(t, err, repository.ErrNotFound)
+
+		_, err = svc.TeamSetReviewPolicy(ctx, "payments", &models.ReviewPolicy{MinRole: "lead"})
+		require.ErrorIs(t, err, service.ErrInvalidRole)
+	})
+
+	create := func(t *testing.T) (*models.PullRequest, error) {
+		t.Helper()
+		pr := &models.PullRequest{
+			ID:       uuid.New(),
+			Name:     "change",
+			AuthorID: author.ID,
+			Status:   string(models.PRStatusOpen),
+		}
+		return pr, svc.CreatePR(ctx, pr)
+	}
+
+	t.Run("juniors only", func(t *testing.T) {
+		_, err := create(t)
+		require.ErrorIs(t, err, service.ErrReviewPolicy)
+	})
+
+	user, err := svc.UsersSetRole(ctx, lead.ID, models.RoleMaintainer)
+	require.NoError(t, err)
+	require.Equal(t, models.RoleMaintainer, user.Role)
+
+	t.Run("maintainer takes the last place", func(t *testing.T) {
+		pr, err := create(t)
+		require.NoError(t, err)
+		require.Len(t, pr.Reviewers, 2)
+		require.Equal(t, a.ID, pr.Reviewers[0].ID)
+		require.Equal(t, lead.ID, pr.Reviewers[1].ID)
+		require.Equal(t, models.ReviewerPoolTeam, pr.Reviewers[1].Pool)
+
+		_, _, err = svc.PRReassign(ctx, pr.ID, lead.ID, service.ReassignOptions{})
+		require.ErrorIs(t, err, service.ErrReviewPolicy)
+
+		reassigned, _, err := svc.PRReassign(ctx, pr.ID, a.ID, service.ReassignOptions{})
+		require.NoError(t, err)
+		require.Equal(t, b.ID, reassigned.Reviewers[len(reassigned.Reviewers)-1].ID)
+	})
+
+	t.Run("removed policy", func(t *testing.T) {
+		team, err := svc.TeamSetReviewPolicy(ctx, "payments", nil)
+		require.NoError(t, err)
+		require.Nil(t, team.Policy)
+
+		pr, err := create(t)
+		require.NoError(t, err)
+		require.Len(t, pr.Reviewers, 2)
+		require.Equal(t, a.ID, pr.Reviewers[0].ID)
+		require.Equal(t, b.ID, pr.Reviewers[1].ID)
+	})
+}
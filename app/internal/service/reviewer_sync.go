@@ -0,0 +1,177 @@
+//go:generate mockgen -source=reviewer_sync.go -destination=../mocks/reviewer_sync.go -package=mocks .
+
+package service
+
+import (
+	"context"
+	"errors"
+
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/retry"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type CodeHostClient interface {
+	// Запросить ревью PR у пользователей кодового хостинга
+	RequestReviewers(ctx context.Context, ref models.ExternalPR, logins []string) error
+
+	// Снять запрос ревью PR с пользователей кодового хостинга
+	RemoveReviewers(ctx context.Context, ref models.ExternalPR, logins []string) error
+}
+
+// reviewerSyncQueue bounds the changes waiting to be pushed.
+const reviewerSyncQueue = 1024
+
+// ReviewerSync pushes reviewer changes of linked PRs back to their code
+// hosts. Changes are queued after commit and pushed by Run, so a slow or
+// unavailable code host never holds up the request that changed reviewers.
+type ReviewerSync struct {
+	integrationRepo IntegrationRepository
+	clients         map[string]CodeHostClient // by provider
+	retrier         retry.Retrier
+
+	queue chan *models.ReviewersChange
+
+	log *zap.Logger
+}
+
+func NewReviewerSync(
+	integrationRepo IntegrationRepository,
+	clients map[string]CodeHostClient,
+	retrier retry.Retrier,
+	log *zap.Logger,
+) *ReviewerSync {
+	return &ReviewerSync{
+		integrationRepo: integrationRepo,
+		clients:         clients,
+		retrier:         retrier,
+		queue:           make(chan *models.ReviewersChange, reviewerSyncQueue),
+		log:             log,
+	}
+}
+
+// Name returns the worker name.
+func (s *ReviewerSync) Name() string {
+	return "reviewer-sync"
+}
+
+// PublishReviewersChanged queues the change; when the queue is full the
+// change is dropped rather than blocking the caller.
+func (s *ReviewerSync) PublishReviewersChanged(_ context.Context, change *models.ReviewersChange) {
+	select {
+	case s.queue <- change:
+	default:
+		s.log.Warn("reviewer sync queue is full, change dropped",
+			zap.String("pr_id", change.PRID.String()),
+		)
+	}
+}
+
+// Run pushes queued changes until ctx is cancelled. Changes still queued by
+// then get a single attempt each before Run returns.
+func (s *ReviewerSync) Run(ctx context.Context) error {
+	for {
+		select {
+		case change := <-s.queue:
+			s.sync(ctx, change, s.retrier)
+		case <-ctx.Done():
+			s.drain(context.WithoutCancel(ctx))
+			return nil
+		}
+	}
+}
+
+func (s *ReviewerSync) drain(ctx context.Context) {
+	for {
+		select {
+		case change := <-s.queue:
+			s.sync(ctx, change, retry.NoRetry())
+		default:
+			return
+		}
+	}
+}
+
+// sync pushes one change to every code host the PR is linked to. Reviewers
+// without a login on the code host are skipped. Failures are logged: the
+// code host is a mirror, our assignment stays authoritative.
+func (s *ReviewerSync) sync(ctx context.Context, change *models.ReviewersChange, retrier retry.Retrier) {
+	for provider, client := range s.clients {
+		ref, err := s.integrationRepo.GetExternalPR(ctx, provider, change.PRID)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			s.log.Error("failed to get linked PR",
+				zap.Error(err),
+				zap.String("provider", provider),
+				zap.String("pr_id", change.PRID.String()),
+			)
+			continue
+		}
+
+		removed, err := s.logins(ctx, provider, change.Removed)
+		if err == nil && len(removed) > 0 {
+			err = retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+				return client.RemoveReviewers(ctx, ref, removed)
+			})
+		}
+		if err != nil {
+			s.logSyncError(err, ref, change)
+			continue
+		}
+
+		added, err := s.logins(ctx, provider, change.Added)
+		if err == nil && len(added) > 0 {
+			err = retrier.DoWithInfo(ctx, func(ctx context.Context, _ retry.AttemptInfo) error {
+				return client.RequestReviewers(ctx, ref, added)
+			})
+		}
+		if err != nil {
+			s.logSyncError(err, ref, change)
+			continue
+		}
+
+		s.log.Info("reviewers pushed to code host",
+			zap.String("provider", provider),
+			zap.String("repository", ref.Repository),
+			zap.Int64("number", ref.Number),
+			zap.Strings("requested", added),
+			zap.Strings("removed", removed),
+		)
+	}
+}
+
+// logins resolves users to their logins on the provider.
+func (s *ReviewerSync) logins(ctx context.Context, provider string, userIDs []uuid.UUID) ([]string, error) {
+	logins := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		login, err := s.integrationRepo.GetLogin(ctx, provider, id)
+		if errors.Is(err, repository.ErrNotFound) {
+			s.log.Debug("reviewer has no code host login",
+				zap.String("provider", provider),
+				zap.String("user_id", id.String()),
+			)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		logins = append(logins, login)
+	}
+
+	return logins, nil
+}
+
+func (s *ReviewerSync) logSyncError(err error, ref models.ExternalPR, change *models.ReviewersChange) {
+	s.log.Error("failed to push reviewers to code host",
+		zap.Error(err),
+		zap.String("provider", ref.Provider),
+		zap.String("repository", ref.Repository),
+		zap.Int64("number", ref.Number),
+		zap.String("pr_id", change.PRID.String()),
+	)
+}
@@ -0,0 +1,163 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"pr-service/internal/clock/clocktest"
+	"pr-service/internal/codehost"
+	"pr-service/internal/models"
+	"pr-service/internal/repository/memory"
+	"pr-service/internal/retry"
+	"pr-service/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// githubCall is a request received by the fake GitHub API.
+type githubCall struct {
+	method, path string
+	reviewers    []string
+}
+
+func TestReviewerSync_GitHub(t *testing.T) {
+	calls := make(chan githubCall, 10)
+	var failures atomic.Int32 // upcoming requests answered with 502
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failures.Add(-1) >= 0 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		failures.Store(0)
+
+		var body struct {
+			Reviewers []string `json:"reviewers"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		calls <- githubCall{method: r.Method, path: r.URL.Path, reviewers: body.Reviewers}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	client, err := codehost.NewGitHubClient(codehost.GitHubConfig{BaseURL: srv.URL, Token: "ghp_test", Timeout: 5 * time.Second})
+	require.NoError(t, err)
+
+	store := memory.NewStore()
+	clk := clocktest.NewFake(time.Date(2025, 10, 24, 12, 0, 0, 0, time.UTC))
+	tx := service.NewCommitHooks(memory.NewTxManager(store))
+	userRepo := memory.NewUserRepository(store)
+	integrationRepo := memory.NewIntegrationRepository(store)
+
+	reviewerSync := service.NewReviewerSync(
+		integrationRepo,
+		map[string]service.CodeHostClient{models.ProviderGitHub: client},
+		retry.New(
+			retry.WithMaxAttempts(3),
+			retry.WithBackoff(retry.FixedBackoff{}),
+			retry.WithIsRetryableFunc(codehost.IsRetryable),
+		),
+		zap.NewNop(),
+	)
+	prRepo := memory.NewPRRepository(store, clk)
+	prService := service.NewPRService(memory.NewTeamRepository(store), userRepo, prRepo, reviewerSync, tx, clk, zap.NewNop())
+	integrationService := service.NewIntegrationService(integrationRepo, userRepo, prService, tx, zap.NewNop())
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() { done <- reviewerSync.Run(ctx) }()
+
+	team := &models.Team{
+		Name: "payments",
+		Members: []*models.User{
+			{Name: "Alice", IsActive: true},
+			{Name: "Bob", IsActive: true},
+			{Name: "Carol", IsActive: true},
+			{Name: "Dave", IsActive: true},
+		},
+	}
+	require.NoError(t, prService.TeamAdd(t.Context(), team))
+
+	alice, bob, carol, dave := team.Members[0], team.Members[1], team.Members[2], team.Members[3]
+
+	// Carol has no GitHub login
+	for _, u := range []*models.User{alice, bob, dave} {
+		require.NoError(t, integrationService.LinkUser(t.Context(), models.ProviderGitHub, "gh-"+strings.ToLower(u.Name), u.ID))
+	}
+
+	next := func(t *testing.T) githubCall {
+		t.Helper()
+
+		select {
+		case c := <-calls:
+			return c
+		case <-time.After(5 * time.Second):
+			t.Fatal("no request to github")
+			return githubCall{}
+		}
+	}
+	const path = "/repos/octo-org/payments-api/pulls/42/requested_reviewers"
+
+	var prID uuid.UUID
+	t.Run("created", func(t *testing.T) {
+		ref := models.ExternalPR{Provider: models.ProviderGitHub, Repository: "octo-org/payments-api", Number: 42}
+		result, err := integrationService.HandlePREvent(t.Context(), &models.PREvent{
+			Kind:   models.PREventOpened,
+			PR:     ref,
+			Title:  "Retry failed provider callbacks",
+			Author: "gh-alice",
+		})
+		require.NoError(t, err)
+		require.Equal(t, service.SyncCreated, result)
+
+		prID, err = integrationRepo.GetPRID(t.Context(), ref)
+		require.NoError(t, err)
+		pr, err := prRepo.GetByID(t.Context(), prID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uuid.UUID{bob.ID, carol.ID}, []uuid.UUID{pr.Reviewers[0].ID, pr.Reviewers[1].ID})
+
+		require.Equal(t, githubCall{method: http.MethodPost, path: path, reviewers: []string{"gh-bob"}}, next(t))
+	})
+
+	t.Run("reassigned after failures", func(t *testing.T) {
+		failures.Store(2)
+
+		_, _, err := prService.PRReassign(t.Context(), prID, bob.ID, service.ReassignOptions{})
+		require.NoError(t, err)
+
+		require.Equal(t, githubCall{method: http.MethodDelete, path: path, reviewers: []string{"gh-bob"}}, next(t))
+		require.Equal(t, githubCall{method: http.MethodPost, path: path, reviewers: []string{"gh-dave"}}, next(t))
+	})
+
+	t.Run("not linked", func(t *testing.T) {
+		// PRs created through the API are not mirrored
+		require.NoError(t, prService.CreatePR(t.Context(), &models.PullRequest{
+			ID:       uuid.New(),
+			Name:     "Local only",
+			AuthorID: alice.ID,
+			Status:   string(models.PRStatusOpen),
+		}))
+
+		select {
+		case c := <-calls:
+			t.Fatalf("unexpected request %v", c)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	cancel()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("reviewer sync did not stop")
+	}
+}
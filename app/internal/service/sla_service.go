@@ -0,0 +1,180 @@
+//go:generate mockgen -source=sla_service.go -destination=../mocks/sla_service.go -package=mocks .
+
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"time"
+
+	"pr-service/internal/clock"
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type SLARepository interface {
+	// Получить просроченные и ещё не эскалированные назначения на открытых PR
+	ListOverdue(ctx context.Context, at time.Time) ([]*models.OverdueReview, error)
+
+	// Сохранить эскалацию, ErrDuplicate если назначение уже эскалировано
+	RecordEscalation(ctx context.Context, e *models.ReviewEscalation) error
+}
+
+type Reassigner interface {
+	// Переназначить ревьюера на другого участника команды
+	PRReassign(ctx context.Context, prID uuid.UUID, oldUserID uuid.UUID, opts ReassignOptions) (*models.PullRequest, *models.PRReviewer, error)
+}
+
+type EscalationPublisher interface {
+	// Отправить событие эскалации
+	PublishEscalation(ctx context.Context, e *models.ReviewEscalation) error
+}
+
+// Escalation reasons.
+const (
+	EscalationReasonPolicy      = "team policy"
+	EscalationReasonNoCandidate = "no replacement reviewer"
+)
+
+// SLAReport counts what one SLA enforcement run did.
+type SLAReport struct {
+	Reassigned int
+	Escalated  int
+}
+
+type SLAService struct {
+	slaRepo    SLARepository
+	reassigner Reassigner
+	publisher  EscalationPublisher
+
+	trManager TxManager
+
+	clock clock.Clock
+	log   *zap.Logger
+}
+
+func NewSLAService(
+	slaRepo SLARepository,
+	reassigner Reassigner,
+	publisher EscalationPublisher,
+	trManager TxManager,
+	clk clock.Clock,
+	log *zap.Logger,
+) *SLAService {
+	return &SLAService{
+		slaRepo:    slaRepo,
+		reassigner: reassigner,
+		publisher:  publisher,
+		trManager:  trManager,
+		clock:      clk,
+		log:        log,
+	}
+}
+
+// Enforce handles every review that outlived its team SLA. Under the
+// reassign policy the reviewer is replaced through PRReassign; when that is
+// the team policy's choice or nobody can take over, an escalation is
+// recorded and published once per assignment. A failure on one review does
+// not stop the others; the first error is returned after the run.
+func (s *SLAService) Enforce(ctx context.Context) (SLAReport, error) {
+	var report SLAReport
+
+	now := s.clock.Now()
+	overdue, err := s.slaRepo.ListOverdue(ctx, now)
+	if err != nil {
+		s.log.Error("failed to list overdue reviews", zap.Error(err))
+		return report, err
+	}
+
+	var firstErr error
+	for _, o := range overdue {
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+
+		reason := EscalationReasonPolicy
+		if o.SLA.Action == models.SLAActionReassign {
+			_, _, err := s.reassigner.PRReassign(ctx, o.PRID, o.ReviewerID, ReassignOptions{})
+			switch {
+			case err == nil:
+				report.Reassigned++
+				s.log.Info("overdue review reassigned",
+					zap.String("pr_id", o.PRID.String()),
+					zap.String("user_id", o.ReviewerID.String()),
+					zap.Duration("sla", o.SLA.Timeout),
+				)
+				continue
+			case errors.Is(err, ErrNoAvailableReviewer), errors.Is(err, ErrReviewPolicy):
+				reason = EscalationReasonNoCandidate
+			case errors.Is(err, ErrNotAssinged), errors.Is(err, ErrCanNotReassing):
+				// the PR changed since it was listed; nothing is overdue anymore
+				continue
+			case errors.Is(err, ErrVersionConflict):
+				// the PR is being changed right now; the next run sees the outcome
+				continue
+			default:
+				firstErr = cmp.Or(firstErr, err)
+				continue
+			}
+		}
+
+		escalated, err := s.escalate(ctx, o, now, reason)
+		if err != nil {
+			firstErr = cmp.Or(firstErr, err)
+			continue
+		}
+		if escalated {
+			report.Escalated++
+		}
+	}
+
+	return report, firstErr
+}
+
+// escalate records the escalation and publishes it in one transaction, so a
+// failed publish is retried on the next run. Reports false if another
+// instance escalated the assignment first.
+func (s *SLAService) escalate(ctx context.Context, o *models.OverdueReview, now time.Time, reason string) (bool, error) {
+	e := &models.ReviewEscalation{
+		PRID:        o.PRID,
+		PRName:      o.PRName,
+		ReviewerID:  o.ReviewerID,
+		TeamName:    o.TeamName,
+		AssignedAt:  o.AssignedAt,
+		Deadline:    o.Deadline(),
+		EscalatedAt: now,
+		Reason:      reason,
+	}
+
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		if err := s.slaRepo.RecordEscalation(ctx, e); err != nil {
+			return err
+		}
+		return s.publisher.PublishEscalation(ctx, e)
+	})
+	if errors.Is(err, repository.ErrDuplicate) {
+		return false, nil
+	}
+	if err != nil {
+		s.log.Error("failed to escalate overdue review",
+			zap.Error(err),
+			zap.String("pr_id", o.PRID.String()),
+			zap.String("user_id", o.ReviewerID.String()),
+		)
+		return false, err
+	}
+
+	s.log.Info("overdue review escalated",
+		zap.String("pr_id", o.PRID.String()),
+		zap.String("user_id", o.ReviewerID.String()),
+		zap.String("team_name", o.TeamName),
+		zap.String("reason", reason),
+		zap.Duration("overdue", now.Sub(e.Deadline)),
+	)
+
+	return true, nil
+}
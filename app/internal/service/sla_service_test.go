@@ -0,0 +1,122 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"pr-service/internal/mocks"
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestSLAService_Enforce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	slaRepo := mocks.NewMockSLARepository(ctrl)
+	reassigner := mocks.NewMockReassigner(ctrl)
+	publisher := mocks.NewMockEscalationPublisher(ctrl)
+	svc := service.NewSLAService(slaRepo, reassigner, publisher, service.TxManagerStub{}, clk, zap.NewNop())
+
+	ctx := t.Context()
+	now := clk.Now()
+
+	overdue := func(action models.SLAAction) *models.OverdueReview {
+		return &models.OverdueReview{
+			PRID:       uuid.New(),
+			PRName:     "pr",
+			ReviewerID: uuid.New(),
+			AssignedAt: now.Add(-25 * time.Hour),
+			TeamName:   "backend",
+			SLA:        models.ReviewSLA{Timeout: 24 * time.Hour, Action: action},
+		}
+	}
+
+	expectEscalation := func(o *models.OverdueReview, reason string) {
+		want := &models.ReviewEscalation{
+			PRID:        o.PRID,
+			PRName:      o.PRName,
+			ReviewerID:  o.ReviewerID,
+			TeamName:    o.TeamName,
+			AssignedAt:  o.AssignedAt,
+			Deadline:    now.Add(-time.Hour),
+			EscalatedAt: now,
+			Reason:      reason,
+		}
+		slaRepo.EXPECT().RecordEscalation(ctx, want).Return(nil)
+		publisher.EXPECT().PublishEscalation(ctx, want).Return(nil)
+	}
+
+	t.Run("reassign policy", func(t *testing.T) {
+		reassigned := overdue(models.SLAActionReassign)
+		noCandidate := overdue(models.SLAActionReassign)
+		raced := overdue(models.SLAActionReassign)
+		concurrent := overdue(models.SLAActionReassign)
+
+		slaRepo.EXPECT().ListOverdue(ctx, now).Return([]*models.OverdueReview{reassigned, noCandidate, raced, concurrent}, nil)
+		reassigner.EXPECT().PRReassign(ctx, reassigned.PRID, reassigned.ReviewerID, service.ReassignOptions{}).Return(&models.PullRequest{}, nil, nil)
+		reassigner.EXPECT().PRReassign(ctx, noCandidate.PRID, noCandidate.ReviewerID, service.ReassignOptions{}).Return(nil, nil, service.ErrNoAvailableReviewer)
+		expectEscalation(noCandidate, service.EscalationReasonNoCandidate)
+		reassigner.EXPECT().PRReassign(ctx, raced.PRID, raced.ReviewerID, service.ReassignOptions{}).Return(nil, nil, service.ErrCanNotReassing)
+		reassigner.EXPECT().PRReassign(ctx, concurrent.PRID, concurrent.ReviewerID, service.ReassignOptions{}).Return(nil, nil, service.ErrVersionConflict)
+
+		report, err := svc.Enforce(ctx)
+		require.NoError(t, err)
+		require.Equal(t, service.SLAReport{Reassigned: 1, Escalated: 1}, report)
+	})
+
+	t.Run("escalate policy", func(t *testing.T) {
+		o := overdue(models.SLAActionEscalate)
+
+		slaRepo.EXPECT().ListOverdue(ctx, now).Return([]*models.OverdueReview{o}, nil)
+		expectEscalation(o, service.EscalationReasonPolicy)
+
+		report, err := svc.Enforce(ctx)
+		require.NoError(t, err)
+		require.Equal(t, service.SLAReport{Escalated: 1}, report)
+	})
+
+	t.Run("already escalated by another instance", func(t *testing.T) {
+		o := overdue(models.SLAActionEscalate)
+
+		slaRepo.EXPECT().ListOverdue(ctx, now).Return([]*models.OverdueReview{o}, nil)
+		slaRepo.EXPECT().RecordEscalation(ctx, gomock.Any()).Return(repository.ErrDuplicate)
+
+		report, err := svc.Enforce(ctx)
+		require.NoError(t, err)
+		require.Zero(t, report.Escalated)
+	})
+
+	t.Run("failure does not stop the run", func(t *testing.T) {
+		failed := overdue(models.SLAActionEscalate)
+		next := overdue(models.SLAActionEscalate)
+		publishErr := errors.New("publish failed")
+
+		slaRepo.EXPECT().ListOverdue(ctx, now).Return([]*models.OverdueReview{failed, next}, nil)
+		slaRepo.EXPECT().RecordEscalation(ctx, gomock.Any()).Return(nil)
+		publisher.EXPECT().PublishEscalation(ctx, gomock.Any()).Return(publishErr)
+		expectEscalation(next, service.EscalationReasonPolicy)
+
+		report, err := svc.Enforce(ctx)
+		require.ErrorIs(t, err, publishErr)
+		require.Equal(t, 1, report.Escalated)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		slaRepo.EXPECT().ListOverdue(ctx, now).Return([]*models.OverdueReview{overdue(models.SLAActionReassign)}, nil)
+
+		_, err := svc.Enforce(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,264 @@
+//go:generate mockgen -source=stats_service.go -destination=../mocks/stats_service.go -package=mocks .
+
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"pr-service/internal/clock"
+	"pr-service/internal/models"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DefaultStatsWindow is the window length used when only one bound is given.
+const DefaultStatsWindow = 30 * 24 * time.Hour
+
+// Во всех методах scope — команда, статистика которой считается вместе с
+// её подкомандами; uuid.Nil — все команды.
+type StatsRepository interface {
+	// Получить нагрузку каждого пользователя за окно [from, to)
+	ReviewerStats(ctx context.Context, from, to time.Time, scope uuid.UUID) ([]*models.ReviewerStats, error)
+
+	// Получить нагрузку каждой команды за окно [from, to), собственную и вместе с подкомандами
+	TeamReviewStats(ctx context.Context, from, to time.Time, scope uuid.UUID) ([]*models.TeamReviewStats, error)
+
+	// Получить циклы PR, созданных в окне [from, to), в порядке создания
+	ListPRCycles(ctx context.Context, from, to time.Time, scope uuid.UUID) ([]*models.PRCycle, error)
+
+	// Передать в fn по одному циклы PR, созданных в окне [from, to), в порядке создания.
+	// Без повторных попыток: отданные строки нельзя вернуть
+	StreamPRCycles(ctx context.Context, from, to time.Time, scope uuid.UUID, fn func(*models.PRCycle) error) error
+}
+
+type StatsService struct {
+	statsRepo StatsRepository
+	teamRepo  TeamRepository
+
+	clock clock.Clock
+	log   *zap.Logger
+}
+
+func NewStatsService(statsRepo StatsRepository, teamRepo TeamRepository, clk clock.Clock, log *zap.Logger) *StatsService {
+	return &StatsService{
+		statsRepo: statsRepo,
+		teamRepo:  teamRepo,
+		clock:     clk,
+		log:       log,
+	}
+}
+
+// ReviewerStats returns the review load per user and per team within [from, to).
+// Zero bounds default to the DefaultStatsWindow ending now. A non-empty
+// teamName limits the report to that team and its sub-teams.
+func (s *StatsService) ReviewerStats(
+	ctx context.Context,
+	from, to time.Time,
+	teamName string,
+) (*models.ReviewStatsReport, error) {
+	from, to, err := s.window(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, err := s.scope(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.statsRepo.ReviewerStats(ctx, from, to, scope)
+	if err != nil {
+		s.log.Error("failed to get reviewer stats", zap.Error(err))
+		return nil, err
+	}
+
+	teams, err := s.statsRepo.TeamReviewStats(ctx, from, to, scope)
+	if err != nil {
+		s.log.Error("failed to get team review stats", zap.Error(err))
+		return nil, err
+	}
+
+	return &models.ReviewStatsReport{
+		From:  from,
+		To:    to,
+		Users: users,
+		Teams: teams,
+	}, nil
+}
+
+// PRStats returns cycle-time percentiles of pull requests created within
+// [from, to), grouped by the author's team and the ISO week of creation.
+// Zero bounds and teamName work like in ReviewerStats.
+func (s *StatsService) PRStats(
+	ctx context.Context,
+	from, to time.Time,
+	teamName string,
+) (*models.PRStatsReport, error) {
+	from, to, err := s.window(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, err := s.scope(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	cycles, err := s.statsRepo.ListPRCycles(ctx, from, to, scope)
+	if err != nil {
+		s.log.Error("failed to list PR cycles", zap.Error(err))
+		return nil, err
+	}
+
+	asOf := s.clock.Now()
+
+	type groupKey struct {
+		team string
+		week time.Time
+	}
+
+	type samples struct {
+		group                       *models.PRCycleGroup
+		firstAssignment, merge, age []time.Duration
+	}
+
+	groups := make(map[groupKey]*samples)
+	for _, c := range cycles {
+		key := groupKey{team: c.TeamName, week: weekStart(c.CreatedAt)}
+
+		g, ok := groups[key]
+		if !ok {
+			g = &samples{group: &models.PRCycleGroup{TeamName: key.team, WeekStart: key.week}}
+			groups[key] = g
+		}
+
+		g.group.Created++
+		if c.FirstAssignedAt != nil {
+			g.firstAssignment = append(g.firstAssignment, c.FirstAssignedAt.Sub(c.CreatedAt))
+		}
+
+		switch {
+		case c.MergedAt != nil:
+			g.group.Merged++
+			g.merge = append(g.merge, c.MergedAt.Sub(c.CreatedAt))
+		case c.Status == string(models.PRStatusOpen):
+			g.group.Open++
+			g.age = append(g.age, asOf.Sub(c.CreatedAt))
+		}
+	}
+
+	report := &models.PRStatsReport{
+		From:   from,
+		To:     to,
+		AsOf:   asOf,
+		Groups: make([]*models.PRCycleGroup, 0, len(groups)),
+	}
+
+	for _, g := range groups {
+		g.group.TimeToFirstAssignment = percentiles(g.firstAssignment)
+		g.group.TimeToMerge = percentiles(g.merge)
+		g.group.OpenAge = percentiles(g.age)
+		report.Groups = append(report.Groups, g.group)
+	}
+
+	slices.SortFunc(report.Groups, func(a, b *models.PRCycleGroup) int {
+		return cmp.Or(cmp.Compare(a.TeamName, b.TeamName), a.WeekStart.Compare(b.WeekStart))
+	})
+
+	return report, nil
+}
+
+// ExportPRs streams the pull requests created within [from, to) to fn in
+// creation order. Zero bounds and teamName work like in ReviewerStats.
+func (s *StatsService) ExportPRs(
+	ctx context.Context,
+	from, to time.Time,
+	teamName string,
+	fn func(*models.PRCycle) error,
+) error {
+	from, to, err := s.window(from, to)
+	if err != nil {
+		return err
+	}
+
+	scope, err := s.scope(ctx, teamName)
+	if err != nil {
+		return err
+	}
+
+	if err := s.statsRepo.StreamPRCycles(ctx, from, to, scope, fn); err != nil {
+		s.log.Error("failed to export PR cycles", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// scope resolves the team whose subtree the stats are limited to, uuid.Nil
+// for every team.
+func (s *StatsService) scope(ctx context.Context, teamName string) (uuid.UUID, error) {
+	if teamName == "" {
+		return uuid.Nil, nil
+	}
+
+	team, err := s.teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			s.log.Error("failed to get team", zap.Error(err))
+		}
+		return uuid.Nil, err
+	}
+	return team.ID, nil
+}
+
+// weekStart returns Monday 00:00 UTC of the week containing t.
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+// percentiles returns p50, p75 and p90 of ds with linear interpolation,
+// like percentile_cont, or nil when ds is empty. ds is sorted in place.
+func percentiles(ds []time.Duration) *models.DurationPercentiles {
+	if len(ds) == 0 {
+		return nil
+	}
+
+	slices.Sort(ds)
+
+	at := func(p float64) time.Duration {
+		pos := p * float64(len(ds)-1)
+		lo := int(pos)
+		if lo == len(ds)-1 {
+			return ds[lo]
+		}
+		return ds[lo] + time.Duration((pos-float64(lo))*float64(ds[lo+1]-ds[lo]))
+	}
+
+	return &models.DurationPercentiles{
+		Count: len(ds),
+		P50:   at(0.5),
+		P75:   at(0.75),
+		P90:   at(0.9),
+	}
+}
+
+// window fills in missing bounds and rejects empty windows.
+func (s *StatsService) window(from, to time.Time) (time.Time, time.Time, error) {
+	if to.IsZero() {
+		to = s.clock.Now()
+	}
+	if from.IsZero() {
+		from = to.Add(-DefaultStatsWindow)
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, ErrInvalidWindow
+	}
+	return from, to, nil
+}
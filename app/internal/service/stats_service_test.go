@@ -0,0 +1,223 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"pr-service/internal/mocks"
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestStatsService_ReviewerStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	statsRepo := mocks.NewMockStatsRepository(ctrl)
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+	svc := service.NewStatsService(statsRepo, teamRepo, clk, zap.NewNop())
+
+	ctx := t.Context()
+	now := clk.Now()
+
+	t.Run("default window ends now", func(t *testing.T) {
+		from := now.Add(-service.DefaultStatsWindow)
+		users := []*models.ReviewerStats{{Username: "alice"}}
+		teams := []*models.TeamReviewStats{{TeamName: "backend"}}
+
+		statsRepo.EXPECT().ReviewerStats(ctx, from, now, uuid.Nil).Return(users, nil)
+		statsRepo.EXPECT().TeamReviewStats(ctx, from, now, uuid.Nil).Return(teams, nil)
+
+		report, err := svc.ReviewerStats(ctx, time.Time{}, time.Time{}, "")
+		require.NoError(t, err)
+		require.Equal(t, &models.ReviewStatsReport{
+			From:  from,
+			To:    now,
+			Users: users,
+			Teams: teams,
+		}, report)
+	})
+
+	t.Run("missing from counts back from to", func(t *testing.T) {
+		to := now.Add(-time.Hour)
+		from := to.Add(-service.DefaultStatsWindow)
+
+		statsRepo.EXPECT().ReviewerStats(ctx, from, to, uuid.Nil).Return(nil, nil)
+		statsRepo.EXPECT().TeamReviewStats(ctx, from, to, uuid.Nil).Return(nil, nil)
+
+		report, err := svc.ReviewerStats(ctx, time.Time{}, to, "")
+		require.NoError(t, err)
+		require.Equal(t, from, report.From)
+	})
+
+	t.Run("empty window", func(t *testing.T) {
+		_, err := svc.ReviewerStats(ctx, now, now, "")
+		require.ErrorIs(t, err, service.ErrInvalidWindow)
+
+		_, err = svc.ReviewerStats(ctx, now, now.Add(-time.Hour), "")
+		require.ErrorIs(t, err, service.ErrInvalidWindow)
+	})
+
+	t.Run("team subtree", func(t *testing.T) {
+		from := now.Add(-service.DefaultStatsWindow)
+		dept := &models.Team{ID: uuid.New(), Name: "engineering"}
+
+		teamRepo.EXPECT().GetByName(ctx, "engineering").Return(dept, nil)
+		statsRepo.EXPECT().ReviewerStats(ctx, from, now, dept.ID).Return(nil, nil)
+		statsRepo.EXPECT().TeamReviewStats(ctx, from, now, dept.ID).Return(nil, nil)
+
+		_, err := svc.ReviewerStats(ctx, time.Time{}, time.Time{}, "engineering")
+		require.NoError(t, err)
+
+		teamRepo.EXPECT().GetByName(ctx, "missing").Return(nil, repository.ErrNotFound)
+
+		_, err = svc.ReviewerStats(ctx, time.Time{}, time.Time{}, "missing")
+		require.ErrorIs(t, err, service.ErrNotFound)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		dbErr := errors.New("db error")
+		statsRepo.EXPECT().ReviewerStats(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, dbErr)
+
+		_, err := svc.ReviewerStats(ctx, time.Time{}, time.Time{}, "")
+		require.ErrorIs(t, err, dbErr)
+	})
+}
+
+func TestStatsService_PRStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	statsRepo := mocks.NewMockStatsRepository(ctrl)
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+	svc := service.NewStatsService(statsRepo, teamRepo, clk, zap.NewNop())
+
+	ctx := t.Context()
+	now := clk.Now() // Friday, 2025-10-24 12:00 UTC
+	monday := time.Date(2025, 10, 20, 0, 0, 0, 0, time.UTC)
+	prevMonday := monday.AddDate(0, 0, -7)
+
+	at := func(d time.Duration) *time.Time {
+		t := monday.Add(d)
+		return &t
+	}
+	merged := func(created, firstAssignment, merge time.Duration) *models.PRCycle {
+		return &models.PRCycle{
+			TeamName:        "backend",
+			Status:          string(models.PRStatusMerged),
+			CreatedAt:       monday.Add(created),
+			FirstAssignedAt: at(created + firstAssignment),
+			MergedAt:        at(created + merge),
+		}
+	}
+
+	cycles := []*models.PRCycle{
+		merged(time.Hour, 0, time.Hour),
+		merged(2*time.Hour, time.Minute, 3*time.Hour),
+		merged(3*time.Hour, 2*time.Minute, 5*time.Hour),
+		{
+			TeamName:        "backend",
+			Status:          string(models.PRStatusOpen),
+			CreatedAt:       now.Add(-2 * time.Hour),
+			FirstAssignedAt: &now,
+		},
+		{
+			TeamName:  "backend",
+			Status:    string(models.PRStatusOpen),
+			CreatedAt: prevMonday.Add(time.Hour), // Sunday belongs to the previous week
+		},
+		{
+			TeamName:  "",
+			Status:    string(models.PRStatusOpen),
+			CreatedAt: monday.Add(-time.Minute),
+		},
+	}
+
+	from := now.Add(-service.DefaultStatsWindow)
+	statsRepo.EXPECT().ListPRCycles(ctx, from, now, uuid.Nil).Return(cycles, nil)
+
+	report, err := svc.PRStats(ctx, time.Time{}, time.Time{}, "")
+	require.NoError(t, err)
+	require.Equal(t, now, report.AsOf)
+	require.Len(t, report.Groups, 3)
+
+	noTeam, backendPrev, backend := report.Groups[0], report.Groups[1], report.Groups[2]
+
+	require.Equal(t, "", noTeam.TeamName)
+	require.Equal(t, prevMonday, noTeam.WeekStart)
+
+	require.Equal(t, "backend", backendPrev.TeamName)
+	require.Equal(t, prevMonday, backendPrev.WeekStart)
+	require.Nil(t, backendPrev.TimeToFirstAssignment)
+	require.Nil(t, backendPrev.TimeToMerge)
+
+	require.Equal(t, monday, backend.WeekStart)
+	require.Equal(t, 4, backend.Created)
+	require.Equal(t, 3, backend.Merged)
+	require.Equal(t, 1, backend.Open)
+	require.Equal(t, &models.DurationPercentiles{
+		Count: 4,
+		P50:   90 * time.Second,
+		P75:   31*time.Minute + 30*time.Second,
+		P90:   84*time.Minute + 36*time.Second,
+	}, backend.TimeToFirstAssignment)
+	require.Equal(t, &models.DurationPercentiles{
+		Count: 3,
+		P50:   3 * time.Hour,
+		P75:   4 * time.Hour,
+		P90:   4*time.Hour + 36*time.Minute,
+	}, backend.TimeToMerge)
+	require.Equal(t, &models.DurationPercentiles{
+		Count: 1,
+		P50:   2 * time.Hour,
+		P75:   2 * time.Hour,
+		P90:   2 * time.Hour,
+	}, backend.OpenAge)
+
+	t.Run("empty window", func(t *testing.T) {
+		_, err := svc.PRStats(ctx, now, now, "")
+		require.ErrorIs(t, err, service.ErrInvalidWindow)
+	})
+}
+
+func TestStatsService_ExportPRs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	statsRepo := mocks.NewMockStatsRepository(ctrl)
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+	svc := service.NewStatsService(statsRepo, teamRepo, clk, zap.NewNop())
+
+	ctx := t.Context()
+	now := clk.Now()
+
+	t.Run("streams", func(t *testing.T) {
+		row := &models.PRCycle{Name: "pr"}
+		statsRepo.EXPECT().
+			StreamPRCycles(ctx, now.Add(-service.DefaultStatsWindow), now, uuid.Nil, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _, _ time.Time, _ uuid.UUID, fn func(*models.PRCycle) error) error {
+				return fn(row)
+			})
+
+		var got []*models.PRCycle
+		err := svc.ExportPRs(ctx, time.Time{}, time.Time{}, "", func(c *models.PRCycle) error {
+			got = append(got, c)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*models.PRCycle{row}, got)
+	})
+
+	t.Run("empty window", func(t *testing.T) {
+		err := svc.ExportPRs(ctx, now, now, "", func(*models.PRCycle) error { return nil })
+		require.ErrorIs(t, err, service.ErrInvalidWindow)
+	})
+}
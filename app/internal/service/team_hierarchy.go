@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// TeamSetParent moves the team under the parent team; an empty parentName
+// makes it a top-level team. A team can not be moved under itself or under
+// one of its own sub-teams.
+func (s *PRService) TeamSetParent(ctx context.Context, teamName, parentName string) (*models.Team, error) {
+	if parentName == teamName {
+		return nil, fmt.Errorf("%w: a team can not be its own parent", ErrInvalidParent)
+	}
+
+	var team *models.Team
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		// the cycle check below reads parents another move may be changing
+		if err := s.teamRepo.LockHierarchy(ctx); err != nil {
+			s.log.Error("failed to lock team hierarchy",
+				zap.Error(err),
+				zap.String("team_name", teamName),
+			)
+			return err
+		}
+
+		var err error
+		team, err = s.loadTeam(ctx, teamName)
+		if err != nil {
+			return err
+		}
+
+		team.Parent = parentName
+		if err := s.resolveParent(ctx, team); err != nil {
+			return err
+		}
+
+		if team.ParentID != nil {
+			subtree, err := s.teamRepo.GetSubtree(ctx, team.ID)
+			if err != nil {
+				s.log.Error("failed to get team subtree",
+					zap.Error(err),
+					zap.String("team_name", teamName),
+				)
+				return err
+			}
+			if slices.ContainsFunc(subtree, func(t *models.Team) bool { return t.ID == *team.ParentID }) {
+				return fmt.Errorf("%w: %s is a sub-team of %s", ErrInvalidParent, parentName, teamName)
+			}
+		}
+
+		if err := s.teamRepo.SetParent(ctx, team.ID, team.ParentID); err != nil {
+			s.log.Error("failed to set parent team",
+				zap.Error(err),
+				zap.String("team_name", teamName),
+			)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("team parent updated",
+		zap.String("team_name", teamName),
+		zap.String("parent_team", parentName),
+	)
+
+	return team, nil
+}
+
+// TeamGetSubtree returns the team like TeamGet, with all teams below it
+// nested in SubTeams, each with its own members.
+func (s *PRService) TeamGetSubtree(ctx context.Context, teamName string) (*models.Team, error) {
+	team, err := s.TeamGet(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	subtree, err := s.teamRepo.GetSubtree(ctx, team.ID)
+	if err != nil {
+		s.log.Error("failed to get team subtree",
+			zap.Error(err),
+			zap.String("team_name", teamName),
+		)
+		return nil, err
+	}
+
+	members, err := s.userRepo.GetBySubtree(ctx, team.ID)
+	if err != nil {
+		s.log.Error("failed to get team subtree members",
+			zap.Error(err),
+			zap.String("team_name", teamName),
+		)
+		return nil, err
+	}
+
+	// the subtree lists parents before their children
+	team.SubTeams = make([]*models.Team, 0)
+	byID := map[uuid.UUID]*models.Team{team.ID: team}
+	for _, t := range subtree {
+		parent, ok := byID[derefTeamID(t.ParentID)]
+		if t.ID == team.ID || !ok {
+			continue
+		}
+
+		t.Parent = parent.Name
+		t.Members = make([]*models.User, 0)
+		t.SubTeams = make([]*models.Team, 0)
+		parent.SubTeams = append(parent.SubTeams, t)
+		byID[t.ID] = t
+	}
+
+	for _, u := range members {
+		if t, ok := byID[derefTeamID(u.TeamID)]; ok && t != team {
+			t.Members = append(t.Members, u)
+		}
+	}
+
+	return team, nil
+}
+
+// resolveParent sets team.ParentID from the team.Parent name.
+func (s *PRService) resolveParent(ctx context.Context, team *models.Team) error {
+	team.ParentID = nil
+	if team.Parent == "" {
+		return nil
+	}
+
+	parent, err := s.teamRepo.GetByName(ctx, team.Parent)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("%w: unknown team %s", ErrInvalidParent, team.Parent)
+		}
+		s.log.Error("failed to get parent team",
+			zap.Error(err),
+			zap.String("parent_team", team.Parent),
+		)
+		return err
+	}
+
+	team.ParentID = &parent.ID
+	return nil
+}
+
+// parentName returns the name of the team's parent, empty for a top-level team.
+func (s *PRService) parentName(ctx context.Context, team *models.Team) (string, error) {
+	if team.ParentID == nil {
+		return "", nil
+	}
+
+	parent, err := s.teamRepo.GetByID(ctx, *team.ParentID)
+	if err != nil {
+		return "", err
+	}
+	return parent.Name, nil
+}
+
+func derefTeamID(id *uuid.UUID) uuid.UUID {
+	if id == nil {
+		return uuid.Nil
+	}
+	return *id
+}
@@ -0,0 +1,136 @@
+package service_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"pr-service/internal/models"
+	"pr-service/internal/repository"
+	"pr-service/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPRService_TeamHierarchy(t *testing.T) {
+	ctx := t.Context()
+	svc := newMemoryPRService()
+
+	err := svc.TeamAdd(ctx, &models.Team{Name: "orphan", Parent: "missing"})
+	require.ErrorIs(t, err, service.ErrInvalidParent)
+
+	dept := &models.Team{
+		Name:    "engineering",
+		Members: []*models.User{{Name: "manager", IsActive: true}},
+	}
+	require.NoError(t, svc.TeamAdd(ctx, dept))
+	manager := dept.Members[0]
+
+	mobile := &models.Team{
+		Name:    "mobile",
+		Parent:  "engineering",
+		Members: []*models.User{{Name: "author", IsActive: true}},
+	}
+	require.NoError(t, svc.TeamAdd(ctx, mobile))
+	author := mobile.Members[0]
+
+	ios := &models.Team{Name: "ios", Parent: "mobile", Members: []*models.User{{Name: "dev", IsActive: true}}}
+	require.NoError(t, svc.TeamAdd(ctx, ios))
+
+	t.Run("invalid parents", func(t *testing.T) {
+		for _, parent := range []string{"engineering", "mobile", "ios", "missing"} {
+			_, err := svc.TeamSetParent(ctx, "engineering", parent)
+			require.ErrorIs(t, err, service.ErrInvalidParent, parent)
+		}
+
+		_, err := svc.TeamSetParent(ctx, "missing", "engineering")
+		require.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		team, err := svc.TeamGet(ctx, "mobile")
+		require.NoError(t, err)
+		require.Equal(t, "engineering", team.Parent)
+		require.Nil(t, team.SubTeams)
+
+		team, err = svc.TeamGetSubtree(ctx, "engineering")
+		require.NoError(t, err)
+		require.Empty(t, team.Parent)
+		require.Len(t, team.Members, 1)
+		require.Equal(t, manager.ID, team.Members[0].ID)
+
+		require.Len(t, team.SubTeams, 1)
+		sub := team.SubTeams[0]
+		require.Equal(t, "mobile", sub.Name)
+		require.Equal(t, "engineering", sub.Parent)
+		require.Len(t, sub.Members, 1)
+		require.Equal(t, author.ID, sub.Members[0].ID)
+
+		require.Len(t, sub.SubTeams, 1)
+		require.Equal(t, "ios", sub.SubTeams[0].Name)
+		require.Empty(t, sub.SubTeams[0].SubTeams)
+	})
+
+	create := func(t *testing.T) *models.PullRequest {
+		t.Helper()
+		pr := &models.PullRequest{
+			ID:       uuid.New(),
+			Name:     "change",
+			AuthorID: author.ID,
+			Status:   string(models.PRStatusOpen),
+		}
+		require.NoError(t, svc.CreatePR(ctx, pr))
+		return pr
+	}
+
+	t.Run("escalates to the parent team", func(t *testing.T) {
+		pr := create(t)
+		require.Len(t, pr.Reviewers, 1)
+		require.Equal(t, manager.ID, pr.Reviewers[0].ID)
+		require.Equal(t, models.ReviewerPoolFallback, pr.Reviewers[0].Pool)
+		require.Equal(t, "engineering", pr.Reviewers[0].PoolTeam)
+	})
+
+	t.Run("top-level team has no one to escalate to", func(t *testing.T) {
+		team, err := svc.TeamSetParent(ctx, "mobile", "")
+		require.NoError(t, err)
+		require.Empty(t, team.Parent)
+		require.Len(t, team.Members, 1)
+
+		pr := create(t)
+		require.Empty(t, pr.Reviewers)
+	})
+}
+
+func TestPRService_TeamSetParent_Concurrent(t *testing.T) {
+	ctx := t.Context()
+	svc := newMemoryPRService()
+
+	for i := range 20 {
+		a, b := fmt.Sprintf("a%d", i), fmt.Sprintf("b%d", i)
+		require.NoError(t, svc.TeamAdd(ctx, &models.Team{Name: a}))
+		require.NoError(t, svc.TeamAdd(ctx, &models.Team{Name: b}))
+
+		var (
+			wg   sync.WaitGroup
+			errs [2]error
+		)
+		for j, move := range [][2]string{{a, b}, {b, a}} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, errs[j] = svc.TeamSetParent(ctx, move[0], move[1])
+			}()
+		}
+		wg.Wait()
+
+		// one move wins, the other would close a cycle
+		if errs[0] == nil {
+			require.ErrorIs(t, errs[1], service.ErrInvalidParent)
+		} else {
+			require.ErrorIs(t, errs[0], service.ErrInvalidParent)
+			require.NoError(t, errs[1])
+		}
+	}
+}
@@ -1 +1 @@
-DROP EXTENSION "uuid-ossp";
\ No newline at end of file
+DROP EXTENSION IF EXISTS "uuid-ossp";
\ No newline at end of file
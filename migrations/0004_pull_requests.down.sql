@@ -1,2 +1,2 @@
-DROP TABLE pull_requests;
-DROP TYPE pr_status;
\ No newline at end of file
+DROP TABLE IF EXISTS pull_requests;
+DROP TYPE IF EXISTS pr_status;
\ No newline at end of file
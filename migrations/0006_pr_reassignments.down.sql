@@ -0,0 +1 @@
+DROP TABLE IF EXISTS pr_reassignments;
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE pr_reassignments (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id UUID NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    from_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    to_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    from_assigned_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    reassigned_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX pr_reassignments_from_user_id_idx ON pr_reassignments(from_user_id);
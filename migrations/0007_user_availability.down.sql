@@ -0,0 +1 @@
+DROP TABLE IF EXISTS user_availability;
\ No newline at end of file
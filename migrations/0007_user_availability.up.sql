@@ -0,0 +1,13 @@
+CREATE TABLE user_availability (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    restored_at TIMESTAMP WITH TIME ZONE,
+    CHECK (starts_at < ends_at)
+);
+
+CREATE INDEX user_availability_user_id_idx ON user_availability(user_id, starts_at);
+CREATE INDEX user_availability_pending_idx ON user_availability(ends_at) WHERE restored_at IS NULL;
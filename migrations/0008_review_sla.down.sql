@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS review_escalations;
+
+ALTER TABLE teams
+    DROP COLUMN IF EXISTS review_sla_action,
+    DROP COLUMN IF EXISTS review_sla_seconds;
\ No newline at end of file
@@ -0,0 +1,14 @@
+ALTER TABLE teams
+    ADD COLUMN review_sla_seconds BIGINT CHECK (review_sla_seconds > 0),
+    ADD COLUMN review_sla_action TEXT NOT NULL DEFAULT 'escalate'
+        CHECK (review_sla_action IN ('reassign', 'escalate'));
+
+CREATE TABLE review_escalations (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id UUID NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    reviewer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    assigned_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    escalated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    UNIQUE (pull_request_id, reviewer_id, assigned_at)
+);
@@ -0,0 +1,7 @@
+ALTER TABLE teams
+    DROP COLUMN IF EXISTS digest_sent_on,
+    DROP COLUMN IF EXISTS digest_timezone,
+    DROP COLUMN IF EXISTS digest_minute;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS email;
@@ -0,0 +1,7 @@
+ALTER TABLE users
+    ADD COLUMN email TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE teams
+    ADD COLUMN digest_minute INTEGER CHECK (digest_minute >= 0 AND digest_minute < 1440),
+    ADD COLUMN digest_timezone TEXT NOT NULL DEFAULT 'UTC',
+    ADD COLUMN digest_sent_on DATE;
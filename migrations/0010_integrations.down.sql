@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS external_pull_requests;
+DROP TABLE IF EXISTS external_users;
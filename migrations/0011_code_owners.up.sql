@@ -0,0 +1,12 @@
+-- one row per owner of a rule; a rule without owners has a single row with no owner
+CREATE TABLE code_owner_rules (
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    owner_position INTEGER NOT NULL,
+    pattern TEXT NOT NULL,
+    owner_ref TEXT,
+    owner_user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    owner_team_id UUID REFERENCES teams(id) ON DELETE CASCADE,
+    PRIMARY KEY (team_id, position, owner_position),
+    CHECK (owner_user_id IS NULL OR owner_team_id IS NULL)
+);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS team_fallback_pools;
+
+ALTER TABLE pr_reviewers
+    DROP COLUMN IF EXISTS pool_team,
+    DROP COLUMN IF EXISTS pool;
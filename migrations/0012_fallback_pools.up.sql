@@ -0,0 +1,15 @@
+-- teams whose members review for the team when it has no candidate left
+CREATE TABLE team_fallback_pools (
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    pool_team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    PRIMARY KEY (team_id, position),
+    UNIQUE (team_id, pool_team_id),
+    CHECK (team_id <> pool_team_id)
+);
+
+-- where the reviewer was picked from; pool_team is the name of the fallback team
+ALTER TABLE pr_reviewers
+    ADD COLUMN pool TEXT NOT NULL DEFAULT 'team'
+        CHECK (pool IN ('team', 'codeowners', 'fallback')),
+    ADD COLUMN pool_team TEXT;
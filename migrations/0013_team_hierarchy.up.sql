@@ -0,0 +1,4 @@
+-- teams form a tree, e.g. department -> team; removing a parent detaches its children
+ALTER TABLE teams ADD COLUMN parent_id UUID REFERENCES teams(id) ON DELETE SET NULL;
+
+CREATE INDEX teams_parent_id_idx ON teams (parent_id);
@@ -0,0 +1,3 @@
+ALTER TABLE teams DROP COLUMN IF EXISTS review_policy_role;
+
+ALTER TABLE users DROP COLUMN IF EXISTS role;
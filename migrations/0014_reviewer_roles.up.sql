@@ -0,0 +1,7 @@
+-- seniority of a reviewer, from lowest to highest
+ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'senior'
+    CHECK (role IN ('junior', 'senior', 'maintainer'));
+
+-- every PR of the team needs a reviewer of at least this role; NULL when the team has no policy
+ALTER TABLE teams ADD COLUMN review_policy_role TEXT
+    CHECK (review_policy_role IN ('junior', 'senior', 'maintainer'));
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS pr_reassignments_declined_idx;
+
+ALTER TABLE pr_reassignments DROP COLUMN IF EXISTS decline_reason;
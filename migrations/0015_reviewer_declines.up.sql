@@ -0,0 +1,6 @@
+-- why the reviewer declined, for reassignments caused by a decline; NULL otherwise
+ALTER TABLE pr_reassignments ADD COLUMN decline_reason TEXT
+    CHECK (decline_reason IN ('busy', 'wrong_expertise', 'conflict'));
+
+CREATE INDEX pr_reassignments_declined_idx ON pr_reassignments (pull_request_id)
+    WHERE decline_reason IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS user_availability_started_idx;
+ALTER TABLE user_availability DROP COLUMN IF EXISTS deactivated_at;
@@ -0,0 +1,4 @@
+-- set when the window switched the user's is_active off; only such windows switch it back on
+ALTER TABLE user_availability ADD COLUMN deactivated_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX user_availability_started_idx ON user_availability(starts_at) WHERE deactivated_at IS NULL AND restored_at IS NULL;
@@ -0,0 +1,7 @@
+-- digests delivered to a reviewer, recorded in the same transaction as the send
+CREATE TABLE digest_deliveries (
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    PRIMARY KEY (team_id, user_id, day)
+);
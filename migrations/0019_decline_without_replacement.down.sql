@@ -0,0 +1,3 @@
+DELETE FROM pr_reassignments WHERE to_user_id IS NULL;
+
+ALTER TABLE pr_reassignments ALTER COLUMN to_user_id SET NOT NULL;
@@ -0,0 +1,2 @@
+-- a reviewer may decline with nobody to take over; to_user_id is NULL then
+ALTER TABLE pr_reassignments ALTER COLUMN to_user_id DROP NOT NULL;